@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/usecase"
+)
+
+// runCreateAdminCommand creates the first admin, e.g. `app create-admin -email admin@mail.com -password secret123`.
+// If the identifier is registered, the user is promoted and the password is not changed.
+func runCreateAdminCommand(ctx context.Context, rbacUsecase usecase.RbacUsecaseInterface, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the admin")
+	phoneNumber := flags.String("phone", "", "phone number of the admin")
+	password := flags.String("password", "", "password of the admin (mandatory)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	req := &entity.BootstrapAdminRequest{Password: *password}
+	switch {
+	case *email != "" && *phoneNumber != "":
+		flags.Usage()
+		return errors.New("error create admin: only one of email or phone is allowed")
+	case *email != "":
+		req.IdentifierType, req.Identifier = entity.IdentifierTypeEmail, *email
+	case *phoneNumber != "":
+		req.IdentifierType, req.Identifier = entity.IdentifierTypePhoneNumber, *phoneNumber
+	default:
+		flags.Usage()
+		return errors.New("error create admin: email or phone is mandatory")
+	}
+
+	userId, err := rbacUsecase.BootstrapAdmin(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("admin '%s' is created with user id '%s'", req.Identifier, userId)
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/usecase"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	importJobPollInterval = time.Second
+)
+
+// runImportCommand imports catalog file, e.g. `app import -file products.csv -dry-run`.
+// It waits until the import job is done and prints the progress.
+func runImportCommand(ctx context.Context, catalogUsecase usecase.CatalogUsecaseInterface, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	filePath := flags.String("file", "", "path of csv or jsonl file (mandatory)")
+	format := flags.String("format", "", "csv or jsonl, default is taken from the file extension")
+	dryRun := flags.Bool("dry-run", false, "only validate the rows and print the report")
+	actorId := flags.String("actor", "", "user id that is recorded as the actor of price changes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *filePath == "" {
+		flags.Usage()
+		return errors.New("error import: file is mandatory")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*filePath)), ".")
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		return fmt.Errorf("error import: %v", err.Error())
+	}
+	defer file.Close()
+
+	job, err := catalogUsecase.ImportCatalog(ctx, &entity.ImportCatalogRequest{
+		Format:  *format,
+		DryRun:  *dryRun,
+		Reader:  file,
+		ActorId: *actorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	for !job.IsDone() {
+		log.Printf("import job '%s' is %s: %d/%d rows processed", job.Id, job.Status, job.ProcessedRows, job.TotalRows)
+		time.Sleep(importJobPollInterval)
+
+		job, err = catalogUsecase.GetImportJob(ctx, job.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	report, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error import in marshal report: %v", err.Error())
+	}
+	fmt.Println(string(report))
+
+	if job.Status == entity.CatalogImportJobStatusFailed {
+		return fmt.Errorf("error import: %d of %d rows are failed", job.FailedRows, job.TotalRows)
+	}
+
+	return nil
+}
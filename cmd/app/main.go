@@ -1,16 +1,30 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
-	"mfawzanid/warehouse-commerce/core/repository"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/config"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/metrics"
+	"mfawzanid/warehouse-commerce/core/notifier"
+	"mfawzanid/warehouse-commerce/core/tracing"
 	"mfawzanid/warehouse-commerce/core/usecase"
 	"mfawzanid/warehouse-commerce/generated"
 	"mfawzanid/warehouse-commerce/handler"
+	lifecycleutil "mfawzanid/warehouse-commerce/utils/lifecycle"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	traceutil "mfawzanid/warehouse-commerce/utils/trace"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
-	"github.com/redis/go-redis/v9"
 
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -18,48 +32,238 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// tracerShutdownTimeout bounds the export of the last spans, the shutdown timeout may be used up by then
+const tracerShutdownTimeout = 5 * time.Second
+
 func main() {
-	e := echo.New()
+	// the flags are before the subcommand, e.g. `app --storage=memory --admin-email admin@mail.com --admin-password secret123`
+	storageFlag := flag.String("storage", "", "storage of the data, postgres or memory (it overrides STORAGE)")
+	adminEmail := flag.String("admin-email", "", "email of the admin that is created on startup, only for memory storage")
+	adminPassword := flag.String("admin-password", "", "password of the admin that is created on startup, only for memory storage")
+	flag.Parse()
+	args := flag.Args()
+
+	// the config file is optional, env overrides it and the flags override the env
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"), config.WithStorage(*storageFlag))
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	dbDSN := os.Getenv("DATABASE_URL")
-	db, err := sql.Open("postgres", dbDSN)
+	// the logger is injected, it is the default too so the log of the commands and the libraries are structured
+	logger, err := logutil.New(cfg.Log.Format, cfg.Log.Level)
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
-	log.Printf("Connected to database: %v", dbDSN)
-	defer db.Close()
+	slog.SetDefault(logger)
+	logger.Info("loaded config:\n" + cfg.String())
 
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     os.Getenv("REDIS_ADDR"),
-		Protocol: 2, // connection protocol
+	// background tasks are tracked by the lifecycle, so the shutdown waits for them before closing redis and the database
+	lifecycle := lifecycleutil.NewManager(logger)
+
+	// the spans of the background tasks are flushed too, since the tracer is the first to register so it is the last to close
+	shutdownTracer, err := traceutil.New(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.Endpoint, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+	if err != nil {
+		log.Fatal(err)
+	}
+	lifecycle.OnStop("tracer", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+		defer cancel()
+		return shutdownTracer(ctx)
 	})
 
-	// repository
-	inventoryRepo := repository.NewInventoryRepository(db)
-	transactionRepo := repository.NewTransactionRepository(db)
-	redisRepo := repository.NewRedisRepository(redisClient)
-	userRepo := repository.NewUserRepository(db)
+	appMetrics := metrics.NewMetrics()
+
+	var store *storage
+	switch cfg.Storage {
+	case config.StorageMemory:
+		migrationVersion, err := latestMigrationVersion(cfg.Database.MigrationsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		store = newMemoryStorage(cfg, migrationVersion)
+		logger.Warn("using memory storage, the data is gone when the server stops")
+	default:
+		if store, err = newPostgresStorage(cfg, appMetrics, lifecycle, logger); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// the repositories are wrapped to record the metrics and the spans, so the usecases do not know about them
+	inventoryRepo := appMetrics.WrapInventoryRepository(tracing.WrapInventoryRepository(store.inventoryRepo))
+	transactionRepo := appMetrics.WrapTransactionRepository(tracing.WrapTransactionRepository(store.transactionRepo))
+	redisRepo := appMetrics.WrapRedisRepository(tracing.WrapRedisRepository(store.redisRepo))
+	userRepo := tracing.WrapUserRepository(store.userRepo)
+	priceRepo := store.priceRepo
+	catalogRepo := store.catalogRepo
+	authRepo := store.authRepo
+	rbacRepo := store.rbacRepo
+	membershipRepo := store.membershipRepo
+	apiKeyRepo := store.apiKeyRepo
+	privacyRepo := store.privacyRepo
+	rateLimitRepo := appMetrics.WrapRateLimitRepository(store.rateLimitRepo)
+	healthRepo := store.healthRepo
+	unitOfWork := appMetrics.WrapUnitOfWork(tracing.WrapUnitOfWork(store.unitOfWork))
+	appMetrics.RegisterState(transactionRepo, redisRepo, logger)
+
+	// otp codes and shop invitations are written to the file (or the log if it is not set) since there is no email or sms provider yet
+	messageNotifier := notifier.NewLogNotifier(cfg.Notifier.OtpFile, logger)
+
+	tokenConfig, err := loadTokenConfig(cfg.Token)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// usecase
-	authUsecase := usecase.NewAuthUsecase()
-	userUsecase := usecase.NewUserUsecase(userRepo, authUsecase)
-	inventoryUsecase := usecase.NewInventoryUsecase(inventoryRepo)
-	transactionUsecase := usecase.NewTransactionUsecase(inventoryRepo, transactionRepo, redisRepo)
+	authUsecase := usecase.NewAuthUsecase(authRepo, rbacRepo, redisRepo, unitOfWork, tokenConfig, logger)
+	userUsecase := usecase.NewUserUsecase(userRepo, unitOfWork, authUsecase, messageNotifier, logger)
+	inventoryUsecase := usecase.NewInventoryUsecase(inventoryRepo, priceRepo, membershipRepo, unitOfWork, logger)
+	priceUsecase := usecase.NewPriceUsecase(priceRepo, membershipRepo, unitOfWork, logger)
+	transactionUsecase := appMetrics.WrapTransactionUsecase(usecase.NewTransactionUsecase(inventoryRepo, transactionRepo, redisRepo, priceUsecase, userRepo, unitOfWork, cfg.Order.ExpireTime, lifecycle, logger))
+	catalogUsecase := usecase.NewCatalogUsecase(inventoryRepo, catalogRepo, priceRepo, unitOfWork, lifecycle, logger)
+	rbacUsecase := usecase.NewRbacUsecase(rbacRepo, userRepo, unitOfWork, logger)
+	membershipUsecase := usecase.NewMembershipUsecase(membershipRepo, userRepo, unitOfWork, messageNotifier, logger)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepo, logger)
+	privacyUsecase := usecase.NewPrivacyUsecase(privacyRepo, userRepo, unitOfWork, authUsecase, logger)
+	rateLimitUsecase := usecase.NewRateLimitUsecase(rateLimitRepo, logger)
+
+	// subcommand
+	if len(args) > 0 {
+		// ctrl+c cancels the work of the command
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		var commandErr error
+		switch {
+		case cfg.Storage == config.StorageMemory:
+			// the data of a command would be gone before the server could use it
+			commandErr = fmt.Errorf("command '%s' is not available with memory storage", args[0])
+		case args[0] == "migrate":
+			commandErr = runMigrateCommand(cfg.Database, args[1:])
+		case args[0] == "import":
+			commandErr = runImportCommand(ctx, catalogUsecase, args[1:])
+		case args[0] == "create-admin":
+			commandErr = runCreateAdminCommand(ctx, rbacUsecase, args[1:])
+		case args[0] == "export-user":
+			commandErr = runExportUserCommand(ctx, privacyUsecase, args[1:])
+		case args[0] == "delete-user":
+			commandErr = runDeleteUserCommand(ctx, privacyUsecase, args[1:])
+		default:
+			commandErr = fmt.Errorf("unknown command '%s', available commands: migrate, import, create-admin, export-user, delete-user", args[0])
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.TaskShutdownTimeout)
+		defer cancel()
+		if err := lifecycle.Shutdown(shutdownCtx); err != nil {
+			logger.Error("error shutdown", "error", err)
+		}
+		if commandErr != nil {
+			log.Fatal(commandErr)
+		}
+		return
+	}
+
+	if cfg.Database.MigrateOnStartup && cfg.Storage == config.StoragePostgres {
+		if err := runMigrations(cfg.Database); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// the memory storage starts empty, so the admin is created on every startup instead of by create-admin
+	if *adminEmail != "" || *adminPassword != "" {
+		if cfg.Storage != config.StorageMemory {
+			log.Fatal("error create admin: --admin-email and --admin-password are only for memory storage, use create-admin command instead")
+		}
+		userId, err := rbacUsecase.BootstrapAdmin(context.Background(), &entity.BootstrapAdminRequest{
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     *adminEmail,
+			Password:       *adminPassword,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		logger.Info("created admin", "userId", userId, "email", *adminEmail)
+	}
+
+	// the server is not ready until the database is migrated to its last migration
+	migrationVersion, err := latestMigrationVersion(cfg.Database.MigrationsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	healthUsecase := usecase.NewHealthUsecase(healthRepo, migrationVersion, cfg.Server.HealthCheckTimeout, logger)
 
 	// handler
-	authHandler := handler.NewAuthHandler(authUsecase)
-	serverHandler := handler.NewServer(userUsecase, inventoryUsecase, transactionUsecase)
+	authHandler := handler.NewAuthHandler(authUsecase, apiKeyUsecase, logger)
+	rateLimitHandler := handler.NewRateLimitHandler(rateLimitUsecase, routeRateLimits(cfg.RateLimit))
+	timeoutHandler := handler.NewTimeoutHandler(cfg.Server.RequestTimeout)
+	errorHandler := handler.NewErrorHandler(logger)
+	logHandler := handler.NewLogHandler(logger)
+	traceHandler := handler.NewTraceHandler()
+	metricsHandler := handler.NewMetricsHandler(appMetrics)
+	serverHandler := handler.NewServer(authUsecase, userUsecase, inventoryUsecase, transactionUsecase, priceUsecase, catalogUsecase, rbacUsecase, membershipUsecase, apiKeyUsecase, privacyUsecase, healthUsecase, logger)
 	var server generated.ServerInterface = serverHandler
 
+	e := echo.New()
+	// the client ip is taken from X-Forwarded-For only if the request comes from a configured trusted proxy
+	e.IPExtractor = ipExtractor(cfg.Server.TrustedProxies)
+	// handlers and middlewares return the error, it is responded as problem+json with the request id
+	e.HTTPErrorHandler = errorHandler.HandleError
+	e.HideBanner = true
+	// the request id and the trace id are in the logs of the request and of its background work (e.g. post payment actions)
+	e.Use(logHandler.RequestId, traceHandler.Trace, logHandler.AccessLog)
+	e.Use(metricsHandler.Metrics)
+	// a request is cancelled if the client is gone or its deadline is exceeded
+	e.Use(timeoutHandler.Timeout)
+
 	// protected routes
 	protectedGroup := e.Group("")
-	protectedGroup.Use(authHandler.VerifyToken)
+	protectedGroup.Use(authHandler.VerifyToken, authHandler.Authorize, rateLimitHandler.RateLimit)
 	generated.RegisterHandlers(protectedGroup, server)
 
 	// public routes
 	e.GET("/health", serverHandler.GetHealth)
-	e.POST("/user/register", serverHandler.RegisterUser)
-	e.POST("/user/login", serverHandler.Login)
+	e.GET("/health/live", serverHandler.GetHealthLive)
+	e.GET("/health/ready", serverHandler.GetHealthReady)
+	e.POST("/user/otp", serverHandler.RequestOtp, rateLimitHandler.RateLimit)
+	e.POST("/user/register", serverHandler.RegisterUser, rateLimitHandler.RateLimit)
+	e.POST("/user/login", serverHandler.Login, rateLimitHandler.RateLimit)
+	e.POST("/user/refresh", serverHandler.RefreshToken)
+	e.GET("/.well-known/jwks.json", serverHandler.GetJwks)
+
+	metricsServer := newMetricsServer(cfg.Server.MetricsPort, appMetrics.Registry)
+
+	go func() {
+		if err := e.Start(fmt.Sprintf(":%d", cfg.Server.Port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("error start server: %v", err.Error())
+		}
+	}()
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("error start metrics server: %v", err.Error())
+		}
+	}()
+
+	// SIGTERM is sent by the deployment, a second signal kills the server right away
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-signalCtx.Done()
+	stop()
+	logger.Info("shutting down the server", "timeout", cfg.Server.ShutdownTimeout, "taskTimeout", cfg.Server.TaskShutdownTimeout)
 
-	e.Logger.Fatal(e.Start(":1323"))
+	// stop accepting requests and drain the in-flight requests, then wait for the background tasks and close the resources.
+	// The tasks have their own timeout, so slow requests do not use up the time of the tasks.
+	serverCtx, cancelServer := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancelServer()
+	if err := e.Shutdown(serverCtx); err != nil {
+		logger.Error("error shutdown server", "error", err)
+	}
+	// the metrics are scraped until the requests are drained
+	if err := metricsServer.Shutdown(serverCtx); err != nil {
+		logger.Error("error shutdown metrics server", "error", err)
+	}
+
+	taskCtx, cancelTask := context.WithTimeout(context.Background(), cfg.Server.TaskShutdownTimeout)
+	defer cancelTask()
+	if err := lifecycle.Shutdown(taskCtx); err != nil {
+		logger.Error("error shutdown", "error", err)
+	}
+	logger.Info("server is stopped")
 }
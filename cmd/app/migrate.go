@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"mfawzanid/warehouse-commerce/config"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// migrateLogger prints the progress of golang-migrate to the log
+type migrateLogger struct{}
+
+func (l *migrateLogger) Printf(format string, v ...any) {
+	log.Printf("migrate: "+format, v...)
+}
+
+func (l *migrateLogger) Verbose() bool {
+	return false
+}
+
+// newMigrate opens its own connection to the database, so closing it does not close the connection of the server
+func newMigrate(cfg config.DatabaseConfig) (*migrate.Migrate, error) {
+	m, err := migrate.New("file://"+cfg.MigrationsPath, cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error open migrations: %v", err.Error())
+	}
+	m.Log = &migrateLogger{}
+	return m, nil
+}
+
+// runMigrations runs the pending up migrations, it is used on startup if it is enabled in the config
+func runMigrations(cfg config.DatabaseConfig) error {
+	m, err := newMigrate(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("error migrate up: %v", err.Error())
+	}
+
+	version, _, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("error migrate up: %v", err.Error())
+	}
+	log.Printf("database schema is at version %d", version)
+	return nil
+}
+
+// runMigrateCommand changes the schema version of the database:
+//   - `app migrate up`: runs all pending migrations
+//   - `app migrate down -steps 1`: reverts the last migrations, `-all` reverts all of them
+//   - `app migrate to -version 2`: migrates up or down to the version
+//   - `app migrate force -version 2`: sets the version without running the migration, to fix a dirty version by hand
+//   - `app migrate status`: prints the migrations and the version of the database
+func runMigrateCommand(cfg config.DatabaseConfig, args []string) error {
+	if len(args) == 0 {
+		return errors.New("error migrate: action is mandatory, available actions: up, down, to, force, status")
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	steps := flags.Int("steps", 1, "number of migrations to revert")
+	all := flags.Bool("all", false, "revert all migrations")
+	version := flags.Int("version", -1, "target version (mandatory for to and force)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	m, err := newMigrate(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch action {
+	case "up":
+		err = m.Up()
+	case "down":
+		if *all {
+			err = m.Down()
+		} else if *steps < 1 {
+			return errors.New("error migrate down: steps must be positive")
+		} else {
+			err = m.Steps(-*steps)
+		}
+	case "to":
+		if *version < 1 {
+			flags.Usage()
+			return errors.New("error migrate to: version is mandatory, use `down -all` to revert all migrations")
+		}
+		err = m.Migrate(uint(*version))
+	case "force":
+		if *version < 0 {
+			flags.Usage()
+			return errors.New("error migrate force: version is mandatory")
+		}
+		err = m.Force(*version)
+	case "status":
+		return printMigrationStatus(m, cfg.MigrationsPath)
+	default:
+		return fmt.Errorf("error migrate: unknown action '%s', available actions: up, down, to, force, status", action)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Printf("no migration to run")
+	} else if err != nil {
+		return fmt.Errorf("error migrate %s: %v", action, err.Error())
+	}
+
+	return printMigrationStatus(m, cfg.MigrationsPath)
+}
+
+// latestMigrationVersion returns the version of the last migration file, the schema of the database must be at it
+func latestMigrationVersion(migrationsPath string) (uint, error) {
+	src, err := source.Open("file://" + migrationsPath)
+	if err != nil {
+		return 0, fmt.Errorf("error read migrations: %v", err.Error())
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("error read migrations: %v", err.Error())
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("error read migrations: %v", err.Error())
+		}
+		version = next
+	}
+}
+
+func printMigrationStatus(m *migrate.Migrate, migrationsPath string) error {
+	currentVersion, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("error migrate status: %v", err.Error())
+	}
+
+	src, err := source.Open("file://" + migrationsPath)
+	if err != nil {
+		return fmt.Errorf("error migrate status: %v", err.Error())
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	for err == nil {
+		body, identifier, readErr := src.ReadUp(version)
+		if readErr != nil {
+			return fmt.Errorf("error migrate status: %v", readErr.Error())
+		}
+		body.Close()
+
+		status := "pending"
+		if version <= currentVersion {
+			status = "applied"
+		}
+		if version == currentVersion && dirty {
+			status = "dirty"
+		}
+		fmt.Fprintf(os.Stdout, "%06d %-50s %s\n", version, identifier, status)
+
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error migrate status: %v", err.Error())
+	}
+
+	fmt.Fprintf(os.Stdout, "database version: %d, dirty: %t\n", currentVersion, dirty)
+	return nil
+}
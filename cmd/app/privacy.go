@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/usecase"
+	"os"
+)
+
+// runExportUserCommand exports the data of a user to answer a data subject request,
+// e.g. `app export-user -user USR-xxx -out user.json`. The archive is printed if the output file is not set.
+func runExportUserCommand(ctx context.Context, privacyUsecase usecase.PrivacyUsecaseInterface, args []string) error {
+	flags := flag.NewFlagSet("export-user", flag.ContinueOnError)
+	userId := flags.String("user", "", "id of the user (mandatory)")
+	outPath := flags.String("out", "", "path of the json archive, default is stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *userId == "" {
+		flags.Usage()
+		return errors.New("error export user: user is mandatory")
+	}
+
+	export, err := privacyUsecase.ExportUserData(ctx, &entity.UserDataRequest{
+		UserId:  *userId,
+		Channel: entity.PrivacyRequestChannelCLI,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			return fmt.Errorf("error export user: %v", err.Error())
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("error export user: %v", err.Error())
+	}
+
+	if *outPath != "" {
+		log.Printf("data of user '%s' is exported to '%s'", *userId, *outPath)
+	}
+	return nil
+}
+
+// runDeleteUserCommand anonymizes a user to answer a data subject request, e.g. `app delete-user -user USR-xxx -confirm`.
+// Orders and payments of the user are kept for accounting.
+func runDeleteUserCommand(ctx context.Context, privacyUsecase usecase.PrivacyUsecaseInterface, args []string) error {
+	flags := flag.NewFlagSet("delete-user", flag.ContinueOnError)
+	userId := flags.String("user", "", "id of the user (mandatory)")
+	confirm := flags.Bool("confirm", false, "confirm the deletion, it can not be undone")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *userId == "" {
+		flags.Usage()
+		return errors.New("error delete user: user is mandatory")
+	}
+	if !*confirm {
+		flags.Usage()
+		return errors.New("error delete user: the deletion can not be undone, run it with -confirm")
+	}
+
+	if err := privacyUsecase.DeleteUserData(ctx, &entity.UserDataRequest{
+		UserId:  *userId,
+		Channel: entity.PrivacyRequestChannelCLI,
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("user '%s' is deleted", *userId)
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"mfawzanid/warehouse-commerce/config"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsReadHeaderTimeout bounds a scrape that does not send its headers, the listener has no request timeout middleware
+const metricsReadHeaderTimeout = 5 * time.Second
+
+// ipExtractor reads the ip of the caller from X-Forwarded-For only if the request comes from a trusted proxy,
+// without any trusted proxy it is the remote address. The cidrs are already validated by the config.
+func ipExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	// the defaults of echo trust any loopback, link-local and private address, only the configured proxies are trusted
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// routeRateLimits groups the configured rules by their route, in the order of the config
+func routeRateLimits(cfg config.RateLimitConfig) map[string][]entity.RateLimitRule {
+	rules := map[string][]entity.RateLimitRule{}
+	for _, rule := range cfg.Rules {
+		rules[rule.Route] = append(rules[rule.Route], entity.RateLimitRule{
+			KeyBy:  rule.KeyBy,
+			Limit:  rule.Limit,
+			Window: rule.Window,
+		})
+	}
+	return rules
+}
+
+// newMetricsServer serves /metrics on the metrics port, it has no authentication so the port is only reachable
+// from the internal network (e.g. the scraper), the public port does not serve the metrics
+func newMetricsServer(port int, registry *prometheus.Registry) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: metricsReadHeaderTimeout,
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"database/sql"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/config"
+	"mfawzanid/warehouse-commerce/core/metrics"
+	"mfawzanid/warehouse-commerce/core/repository"
+	clockutil "mfawzanid/warehouse-commerce/utils/clock"
+	lifecycleutil "mfawzanid/warehouse-commerce/utils/lifecycle"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// storage is the repositories of the configured storage, main wraps them with the metrics and the tracing
+type storage struct {
+	inventoryRepo   repository.InventoryRepositoryInterface
+	transactionRepo repository.TransactionRepositoryInterface
+	redisRepo       repository.RedisRepositoryInterface
+	userRepo        repository.UserRepositoryInterface
+	priceRepo       repository.PriceRepositoryInterface
+	catalogRepo     repository.CatalogRepositoryInterface
+	authRepo        repository.AuthRepositoryInterface
+	rbacRepo        repository.RbacRepositoryInterface
+	membershipRepo  repository.MembershipRepositoryInterface
+	apiKeyRepo      repository.APIKeyRepositoryInterface
+	privacyRepo     repository.PrivacyRepositoryInterface
+	rateLimitRepo   repository.RateLimitRepositoryInterface
+	healthRepo      repository.HealthRepositoryInterface
+	unitOfWork      repository.UnitOfWorkInterface
+}
+
+// newPostgresStorage connects to the database and redis, they are closed by the lifecycle
+func newPostgresStorage(cfg *config.Config, appMetrics *metrics.Metrics, lifecycle *lifecycleutil.Manager, logger *slog.Logger) (*storage, error) {
+	db, err := sql.Open("postgres", cfg.Database.URL)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("connected to database", "url", cfg.Redacted().Database.URL)
+	lifecycle.OnStop("database", db.Close)
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		Protocol: 2, // connection protocol
+	})
+	lifecycle.OnStop("redis", redisClient.Close)
+
+	appMetrics.RegisterDB(db)
+
+	return &storage{
+		inventoryRepo:   repository.NewInventoryRepository(db),
+		transactionRepo: repository.NewTransactionRepository(db),
+		redisRepo:       repository.NewRedisRepository(redisClient, cfg.Order.ExpireTime),
+		userRepo:        repository.NewUserRepository(db),
+		priceRepo:       repository.NewPriceRepository(db),
+		catalogRepo:     repository.NewCatalogRepository(db),
+		authRepo:        repository.NewAuthRepository(db),
+		rbacRepo:        repository.NewRbacRepository(db),
+		membershipRepo:  repository.NewMembershipRepository(db),
+		apiKeyRepo:      repository.NewAPIKeyRepository(db),
+		privacyRepo:     repository.NewPrivacyRepository(db),
+		rateLimitRepo:   repository.NewRedisRateLimitRepository(redisClient),
+		healthRepo:      repository.NewHealthRepository(db, redisClient),
+		unitOfWork:      repository.NewUnitOfWork(db, logger),
+	}, nil
+}
+
+// newMemoryStorage keeps the data in the process, it is for the local development without postgres and redis.
+// The schema of the memory store is always the latest migration.
+func newMemoryStorage(cfg *config.Config, migrationVersion uint) *storage {
+	store := repository.NewMemoryStore(clockutil.Real())
+
+	return &storage{
+		inventoryRepo:   repository.NewMemoryInventoryRepository(store),
+		transactionRepo: repository.NewMemoryTransactionRepository(store),
+		redisRepo:       repository.NewMemoryRedisRepository(clockutil.Real(), cfg.Order.ExpireTime),
+		userRepo:        repository.NewMemoryUserRepository(store),
+		priceRepo:       repository.NewMemoryPriceRepository(store),
+		catalogRepo:     repository.NewMemoryCatalogRepository(store),
+		authRepo:        repository.NewMemoryAuthRepository(store),
+		rbacRepo:        repository.NewMemoryRbacRepository(store),
+		membershipRepo:  repository.NewMemoryMembershipRepository(store),
+		apiKeyRepo:      repository.NewMemoryAPIKeyRepository(store),
+		privacyRepo:     repository.NewMemoryPrivacyRepository(store),
+		rateLimitRepo:   repository.NewMemoryRateLimitRepository(),
+		healthRepo:      repository.NewMemoryHealthRepository(migrationVersion),
+		unitOfWork:      repository.NewMemoryUnitOfWork(store),
+	}
+}
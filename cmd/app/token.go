@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"mfawzanid/warehouse-commerce/config"
+	"mfawzanid/warehouse-commerce/core/entity"
+	keyutil "mfawzanid/warehouse-commerce/utils/key"
+)
+
+const (
+	secretKeyId       = "secret"
+	developmentSecret = "token_secret"
+)
+
+// loadTokenConfig loads the signing keys of the token config:
+//   - keys: comma separated `keyId=path` of rsa or ed25519 pem files, public key files are only used to verify
+//   - secret: HS256 secret, it is loaded with key id `secret`
+//   - active key id: key that signs new tokens, default is the first key
+//
+// Without any key it falls back to development secret, the config validation only allows it in development env.
+func loadTokenConfig(cfg config.TokenConfig) (*entity.TokenConfig, error) {
+	keys, err := keyutil.LoadPEMKeys(cfg.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := cfg.Secret
+	if secret == "" && len(keys) == 0 {
+		log.Printf("JWT_KEYS and JWT_SECRET are not set, tokens are signed with development secret")
+		secret = developmentSecret
+	}
+	if secret != "" {
+		keys = append(keys, keyutil.NewHMACKey(secretKeyId, []byte(secret)))
+	}
+
+	activeKeyId := cfg.ActiveKeyId
+	if activeKeyId == "" {
+		activeKeyId = keys[0].Id
+	}
+
+	keySet, err := keyutil.NewKeySet(activeKeyId, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TokenConfig{
+		Issuer:          cfg.Issuer,
+		Audience:        cfg.Audience,
+		KeySet:          keySet,
+		AccessTokenTTL:  cfg.AccessTokenTTL,
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
+	}, nil
+}
@@ -0,0 +1,417 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvDevelopment = "development"
+
+	StoragePostgres = "postgres" // postgres and redis
+	StorageMemory   = "memory"   // in-memory repositories, the data is gone when the server stops
+
+	minSecretLength = 32
+	redacted        = "*****"
+)
+
+// Config is loaded once on startup: defaults, then the yaml file (if any), then env
+type Config struct {
+	Env       string          `yaml:"env"`
+	Storage   string          `yaml:"storage"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Redis     RedisConfig     `yaml:"redis"`
+	Token     TokenConfig     `yaml:"token"`
+	Order     OrderConfig     `yaml:"order"`
+	Notifier  NotifierConfig  `yaml:"notifier"`
+	Log       LogConfig       `yaml:"log"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	RateLimit RateLimitConfig `yaml:"rateLimit"`
+}
+
+type ServerConfig struct {
+	Port            int           `yaml:"port"`
+	MetricsPort     int           `yaml:"metricsPort"`     // serves /metrics on its own listener, only the port is published
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"` // to drain the in-flight requests on shutdown
+	// TaskShutdownTimeout is the time to wait for the background tasks after the requests are drained,
+	// both timeouts and the cancel grace period of the tasks (5s) are shorter than the grace period of the deployment
+	TaskShutdownTimeout time.Duration `yaml:"taskShutdownTimeout"`
+	RequestTimeout      time.Duration `yaml:"requestTimeout"` // deadline of a request, some routes (e.g. export) have their own
+	// HealthCheckTimeout is the deadline of each dependency in the readiness, shorter than the timeout of the probe
+	HealthCheckTimeout time.Duration `yaml:"healthCheckTimeout"`
+	// TrustedProxies are the cidrs of the proxies (e.g. the load balancer) that set X-Forwarded-For,
+	// the ip of the caller is the remote address if the request does not come from one of them
+	TrustedProxies []string `yaml:"trustedProxies"`
+}
+
+type DatabaseConfig struct {
+	URL              string `yaml:"url"` // secret, the password is in the url
+	MigrationsPath   string `yaml:"migrationsPath"`
+	MigrateOnStartup bool   `yaml:"migrateOnStartup"` // runs the pending up migrations before the server starts
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"` // secret
+}
+
+type TokenConfig struct {
+	Keys            string        `yaml:"keys"`   // comma separated `keyId=path` of pem files
+	Secret          string        `yaml:"secret"` // secret, HS256 secret
+	ActiveKeyId     string        `yaml:"activeKeyId"`
+	Issuer          string        `yaml:"issuer"`
+	Audience        string        `yaml:"audience"`
+	AccessTokenTTL  time.Duration `yaml:"accessTokenTTL"`
+	RefreshTokenTTL time.Duration `yaml:"refreshTokenTTL"`
+}
+
+type OrderConfig struct {
+	ExpireTime time.Duration `yaml:"expireTime"` // stock of a pending order is reserved until it expires
+}
+
+type NotifierConfig struct {
+	OtpFile string `yaml:"otpFile"`
+}
+
+type LogConfig struct {
+	Format string `yaml:"format"` // json for the deployment, text for the local development
+	Level  string `yaml:"level"`  // debug, info, warn or error
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`    // none, stdout or otlp
+	Endpoint    string  `yaml:"endpoint"`    // otlp http endpoint, e.g. http://localhost:4318, OTEL_EXPORTER_OTLP_ENDPOINT if it is empty
+	ServiceName string  `yaml:"serviceName"` // name of the server in the traces
+	SampleRatio float64 `yaml:"sampleRatio"` // ratio of the traces that are started by the server and recorded, 0 to 1
+}
+
+// keys that a rate limit rule counts the requests by
+var rateLimitKeys = []string{"ip", "user", "apiKey", "identifier"}
+
+type RateLimitConfig struct {
+	Rules []RateLimitRuleConfig `yaml:"rules"` // the rules of the file replace all the default rules
+}
+
+// RateLimitRuleConfig allows the limit of requests to the route in the window for each key,
+// the first rule of a route is the one in the rate limit headers, so it is the tightest one
+type RateLimitRuleConfig struct {
+	Route  string        `yaml:"route"` // method and path of the route, e.g. POST /user/login
+	KeyBy  string        `yaml:"keyBy"` // ip, user, apiKey or identifier (email or phone number of the body)
+	Limit  int           `yaml:"limit"`
+	Window time.Duration `yaml:"window"`
+}
+
+// Default returns the config of the local development, except the env that has no default
+// so a deployment that misses it does not run with the development secret
+func Default() *Config {
+	return &Config{
+		Storage:  StoragePostgres,
+		Server:   ServerConfig{Port: 1323, MetricsPort: 9090, ShutdownTimeout: 10 * time.Second, TaskShutdownTimeout: 10 * time.Second, RequestTimeout: 10 * time.Second, HealthCheckTimeout: 2 * time.Second},
+		Database: DatabaseConfig{MigrationsPath: "migrations"},
+		Token: TokenConfig{
+			Issuer:          "warehouse-commerce",
+			Audience:        "warehouse-commerce",
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+		Order:   OrderConfig{ExpireTime: time.Minute},
+		Log:     LogConfig{Format: "text", Level: "info"},
+		Tracing: TracingConfig{Exporter: "none", ServiceName: "warehouse-commerce", SampleRatio: 1},
+		RateLimit: RateLimitConfig{Rules: []RateLimitRuleConfig{
+			{Route: "POST /user/otp", KeyBy: "ip", Limit: 10, Window: time.Minute},
+			{Route: "POST /user/register", KeyBy: "ip", Limit: 5, Window: 10 * time.Minute},
+			{Route: "POST /user/login", KeyBy: "ip", Limit: 10, Window: time.Minute},
+			{Route: "POST /user/login", KeyBy: "ip", Limit: 50, Window: time.Hour},
+			{Route: "POST /user/login", KeyBy: "identifier", Limit: 5, Window: 15 * time.Minute}, // guessing the password of an account from many ips
+			{Route: "POST /api/v1/shop/:shopId/order", KeyBy: "user", Limit: 10, Window: time.Minute},
+			{Route: "POST /api/v1/catalog/import", KeyBy: "apiKey", Limit: 5, Window: time.Minute},
+		}},
+	}
+}
+
+// Override sets a field after the file and the env, e.g. from a command line flag
+type Override func(c *Config)
+
+// WithStorage overrides the storage if it is set
+func WithStorage(storage string) Override {
+	return func(c *Config) {
+		if storage != "" {
+			c.Storage = storage
+		}
+	}
+}
+
+// Load loads the config from the yaml file in path (it is optional), the env and the overrides, then validates it
+func Load(path string, overrides ...Override) (*Config, error) {
+	return load(path, os.LookupEnv, overrides...)
+}
+
+func load(path string, lookupEnv func(key string) (string, bool), overrides ...Override) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		file, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error load config: %v", err.Error())
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(file))
+		decoder.KnownFields(true) // a typo in the file must not fall back to the default silently
+		if err := decoder.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("error load config file '%s': %v", path, err.Error())
+		}
+	}
+
+	if err := cfg.applyEnv(lookupEnv); err != nil {
+		return nil, err
+	}
+	for _, override := range overrides {
+		override(cfg)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) applyEnv(lookupEnv func(key string) (string, bool)) error {
+	stringFields := map[string]*string{
+		"APP_ENV":              &c.Env,
+		"STORAGE":              &c.Storage,
+		"DATABASE_URL":         &c.Database.URL,
+		"REDIS_ADDR":           &c.Redis.Addr,
+		"REDIS_PASSWORD":       &c.Redis.Password,
+		"MIGRATIONS_PATH":      &c.Database.MigrationsPath,
+		"JWT_KEYS":             &c.Token.Keys,
+		"JWT_SECRET":           &c.Token.Secret,
+		"JWT_ACTIVE_KEY_ID":    &c.Token.ActiveKeyId,
+		"JWT_ISSUER":           &c.Token.Issuer,
+		"JWT_AUDIENCE":         &c.Token.Audience,
+		"OTP_NOTIFIER_FILE":    &c.Notifier.OtpFile,
+		"LOG_FORMAT":           &c.Log.Format,
+		"LOG_LEVEL":            &c.Log.Level,
+		"TRACING_EXPORTER":     &c.Tracing.Exporter,
+		"TRACING_ENDPOINT":     &c.Tracing.Endpoint,
+		"TRACING_SERVICE_NAME": &c.Tracing.ServiceName,
+	}
+	for key, field := range stringFields {
+		if value, ok := lookupEnv(key); ok && value != "" {
+			*field = value
+		}
+	}
+
+	if value, ok := lookupEnv("TRUSTED_PROXIES"); ok && value != "" {
+		c.Server.TrustedProxies = nil
+		for _, cidr := range strings.Split(value, ",") {
+			c.Server.TrustedProxies = append(c.Server.TrustedProxies, strings.TrimSpace(cidr))
+		}
+	}
+
+	if value, ok := lookupEnv("SERVER_PORT"); ok && value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("error load config: SERVER_PORT '%s' is not a number", value)
+		}
+		c.Server.Port = port
+	}
+
+	if value, ok := lookupEnv("METRICS_PORT"); ok && value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("error load config: METRICS_PORT '%s' is not a number", value)
+		}
+		c.Server.MetricsPort = port
+	}
+
+	if value, ok := lookupEnv("MIGRATE_ON_STARTUP"); ok && value != "" {
+		migrateOnStartup, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("error load config: MIGRATE_ON_STARTUP '%s' is not a boolean", value)
+		}
+		c.Database.MigrateOnStartup = migrateOnStartup
+	}
+
+	if value, ok := lookupEnv("TRACING_SAMPLE_RATIO"); ok && value != "" {
+		sampleRatio, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("error load config: TRACING_SAMPLE_RATIO '%s' is not a number", value)
+		}
+		c.Tracing.SampleRatio = sampleRatio
+	}
+
+	durations := map[string]*time.Duration{
+		"SHUTDOWN_TIMEOUT":      &c.Server.ShutdownTimeout,
+		"TASK_SHUTDOWN_TIMEOUT": &c.Server.TaskShutdownTimeout,
+		"REQUEST_TIMEOUT":       &c.Server.RequestTimeout,
+		"HEALTH_CHECK_TIMEOUT":  &c.Server.HealthCheckTimeout,
+		"JWT_ACCESS_TOKEN_TTL":  &c.Token.AccessTokenTTL,
+		"JWT_REFRESH_TOKEN_TTL": &c.Token.RefreshTokenTTL,
+		"ORDER_EXPIRE_TIME":     &c.Order.ExpireTime,
+	}
+	for key, field := range durations {
+		if value, ok := lookupEnv(key); ok && value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("error load config: %s '%s' is not a duration, e.g. 15m", key, value)
+			}
+			*field = duration
+		}
+	}
+
+	return nil
+}
+
+// Validate returns all invalid fields at once, so a deployment can be fixed in one go
+func (c *Config) Validate() error {
+	errs := []error{}
+
+	if c.Env == "" {
+		errs = append(errs, errors.New("env is mandatory, e.g. development or production"))
+	}
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server port %d is not valid", c.Server.Port))
+	}
+	if c.Server.MetricsPort < 1 || c.Server.MetricsPort > 65535 {
+		errs = append(errs, fmt.Errorf("metrics port %d is not valid", c.Server.MetricsPort))
+	} else if c.Server.MetricsPort == c.Server.Port {
+		errs = append(errs, fmt.Errorf("metrics port %d must not be the server port", c.Server.MetricsPort))
+	}
+	if c.Server.ShutdownTimeout <= 0 || c.Server.TaskShutdownTimeout <= 0 || c.Server.RequestTimeout <= 0 {
+		errs = append(errs, errors.New("shutdown, task shutdown and request timeout must be positive"))
+	}
+	if c.Server.HealthCheckTimeout <= 0 {
+		errs = append(errs, errors.New("health check timeout must be positive"))
+	}
+	for _, cidr := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fmt.Errorf("trusted proxy '%s' is not a cidr, e.g. 10.0.0.0/8", cidr))
+		}
+	}
+
+	switch c.Storage {
+	case StoragePostgres:
+		if c.Database.URL == "" {
+			errs = append(errs, errors.New("database url is mandatory"))
+		} else if _, err := url.Parse(c.Database.URL); err != nil {
+			errs = append(errs, errors.New("database url is not valid"))
+		}
+		if c.Redis.Addr == "" {
+			errs = append(errs, errors.New("redis addr is mandatory"))
+		}
+	case StorageMemory:
+		// the data is not kept and not shared between the instances, so it is only for the local development
+		if c.Env != EnvDevelopment {
+			errs = append(errs, fmt.Errorf("memory storage is only for '%s' env", EnvDevelopment))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage '%s' should be 'postgres' or 'memory'", c.Storage))
+	}
+	if c.Database.MigrationsPath == "" {
+		errs = append(errs, errors.New("migrations path is mandatory"))
+	}
+
+	// development falls back to the development secret, other envs must set their own keys
+	if c.Env != EnvDevelopment {
+		if c.Token.Keys == "" && c.Token.Secret == "" {
+			errs = append(errs, fmt.Errorf("token keys or secret is mandatory in '%s' env", c.Env))
+		}
+		if c.Token.Secret != "" && len(c.Token.Secret) < minSecretLength {
+			errs = append(errs, fmt.Errorf("token secret must be at least %d characters in '%s' env", minSecretLength, c.Env))
+		}
+	}
+	if c.Token.Issuer == "" || c.Token.Audience == "" {
+		errs = append(errs, errors.New("token issuer and audience are mandatory"))
+	}
+	if c.Token.AccessTokenTTL <= 0 {
+		errs = append(errs, errors.New("access token ttl must be positive"))
+	}
+	if c.Token.RefreshTokenTTL <= c.Token.AccessTokenTTL {
+		errs = append(errs, errors.New("refresh token ttl must be longer than access token ttl"))
+	}
+
+	if c.Order.ExpireTime <= 0 {
+		errs = append(errs, errors.New("order expire time must be positive"))
+	}
+
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		errs = append(errs, fmt.Errorf("log format '%s' should be 'json' or 'text'", c.Log.Format))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("log level '%s' should be 'debug', 'info', 'warn' or 'error'", c.Log.Level))
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("tracing exporter '%s' should be 'none', 'stdout' or 'otlp'", c.Tracing.Exporter))
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, errors.New("tracing service name is mandatory"))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing sample ratio %v should be between 0 and 1", c.Tracing.SampleRatio))
+	}
+
+	for _, rule := range c.RateLimit.Rules {
+		method, path, ok := strings.Cut(rule.Route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("rate limit route '%s' should be the method and the path, e.g. POST /user/login", rule.Route))
+		}
+		if !slices.Contains(rateLimitKeys, rule.KeyBy) {
+			errs = append(errs, fmt.Errorf("rate limit key '%s' of route '%s' should be one of %v", rule.KeyBy, rule.Route, rateLimitKeys))
+		}
+		if rule.Limit <= 0 || rule.Window <= 0 {
+			errs = append(errs, fmt.Errorf("rate limit limit and window of route '%s' must be positive", rule.Route))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("error config validation: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Redacted returns a copy of the config without the secrets, it is the one to print
+func (c *Config) Redacted() *Config {
+	redactedConfig := *c
+
+	// a `key=value` dsn is not an url, so all of it is redacted
+	if u, err := url.Parse(c.Database.URL); err == nil && u.Scheme != "" {
+		redactedConfig.Database.URL = u.Redacted()
+	} else if c.Database.URL != "" {
+		redactedConfig.Database.URL = redacted
+	}
+	if c.Redis.Password != "" {
+		redactedConfig.Redis.Password = redacted
+	}
+	if c.Token.Secret != "" {
+		redactedConfig.Token.Secret = redacted
+	}
+
+	return &redactedConfig
+}
+
+// String prints the config as yaml without the secrets
+func (c *Config) String() string {
+	out, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("error print config: %v", err.Error())
+	}
+	return string(out)
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"strings"
+	"time"
+)
+
+// Address is an address in the address book of a user, it is soft deleted since orders refer it
+type Address struct {
+	Id            string    `json:"id"`
+	UserId        string    `json:"-"`
+	Label         string    `json:"label"` // e.g. home, office
+	RecipientName string    `json:"recipientName"`
+	PhoneNumber   string    `json:"phoneNumber"`
+	Street        string    `json:"street"`
+	City          string    `json:"city"`
+	Province      string    `json:"province"`
+	PostalCode    string    `json:"postalCode"`
+	Country       string    `json:"country"`
+	IsDefault     bool      `json:"isDefault"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// UpsertAddressRequest creates an address, or updates it if the id is set
+type UpsertAddressRequest struct {
+	Id            string `json:"-"`
+	UserId        string `json:"-"`
+	Label         string `json:"label"`
+	RecipientName string `json:"recipientName"`
+	PhoneNumber   string `json:"phoneNumber"`
+	Street        string `json:"street"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postalCode"`
+	Country       string `json:"country"`
+	IsDefault     bool   `json:"isDefault"` // other addresses of the user are not default anymore
+}
+
+func (r *UpsertAddressRequest) Validate() error {
+	if r.UserId == "" {
+		return errorutil.NewFieldValidationError("error upsert address validation", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+
+	mandatoryFields := []struct {
+		field, name, value string
+	}{
+		{"recipientName", "recipient name", r.RecipientName},
+		{"phoneNumber", "phone number", r.PhoneNumber},
+		{"street", "street", r.Street},
+		{"city", "city", r.City},
+		{"postalCode", "postal code", r.PostalCode},
+		{"country", "country", r.Country},
+	}
+	fields := []*errorutil.FieldError{}
+	for _, mandatory := range mandatoryFields {
+		if mandatory.value == "" {
+			fields = append(fields, &errorutil.FieldError{Field: mandatory.field, Message: mandatory.name + " is mandatory"})
+		}
+	}
+	if len(fields) > 0 {
+		// all missing fields are returned, so the client can show them at once
+		messages := make([]string, len(fields))
+		for i, field := range fields {
+			messages[i] = field.Message
+		}
+		return errorutil.NewValidationError(fmt.Errorf("error upsert address validation: %s", strings.Join(messages, ", ")), fields...)
+	}
+	return nil
+}
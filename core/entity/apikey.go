@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"time"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+
+	ContextAPIKeyId        = "apiKeyId"
+	ContextAPIKeyCreatedBy = "apiKeyCreatedBy"
+)
+
+// APIKeyScopes are the permissions that can be granted to an api key, the keys are for services that call the
+// inventory endpoints so user permissions like ordering or assigning roles are not granted
+var APIKeyScopes = []string{
+	PermissionWarehouseRead,
+	PermissionWarehouseWrite,
+	PermissionShopRead,
+	PermissionProductRead,
+	PermissionProductWrite,
+	PermissionStockWrite,
+	PermissionCatalogImport,
+	PermissionCatalogExport,
+}
+
+type APIKey struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // first characters of the key to recognize it, the key itself is not stored
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"createdBy"`
+	ExpiredAt  *time.Time `json:"expiredAt,omitempty"` // nil never expires
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiredAt == nil || now.Before(*k.ExpiredAt))
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiredAt *time.Time `json:"expiredAt"`
+	ActorId   string     `json:"-"`
+}
+
+func (r *CreateAPIKeyRequest) Validate(now time.Time) error {
+	if r.Name == "" {
+		return errorutil.NewFieldValidationError("error create api key validation", &errorutil.FieldError{Field: "name", Message: "name is mandatory"})
+	}
+	if len(r.Scopes) == 0 {
+		return errorutil.NewFieldValidationError("error create api key validation", &errorutil.FieldError{Field: "scopes", Message: "scopes are mandatory"})
+	}
+	for _, scope := range r.Scopes {
+		if !slices.Contains(APIKeyScopes, scope) {
+			return errorutil.NewFieldValidationError("error create api key validation", &errorutil.FieldError{Field: "scopes", Message: fmt.Sprintf("scope '%s' is not allowed", scope)})
+		}
+	}
+	if r.ExpiredAt != nil && !r.ExpiredAt.After(now) {
+		return errorutil.NewFieldValidationError("error create api key validation", &errorutil.FieldError{Field: "expiredAt", Message: "expired at must be in the future"})
+	}
+	return nil
+}
+
+// CreateAPIKeyResponse has the key, it is only returned once when the key is created
+type CreateAPIKeyResponse struct {
+	*APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	keyutil "mfawzanid/warehouse-commerce/utils/key"
+	"time"
+)
+
+const (
+	ContextSessionId = "sessionId"
+)
+
+// TokenConfig is the issuer and audience of access tokens, tokens issued for other audience are rejected
+type TokenConfig struct {
+	Issuer          string
+	Audience        string
+	KeySet          *keyutil.KeySet
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// AuthToken is returned on register, login and refresh, the refresh token is used once to get a new pair
+type AuthToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiredIn    int // access token lifetime in seconds
+}
+
+type TokenClaims struct {
+	UserId      string
+	SessionId   string
+	Roles       []string
+	Permissions []string
+}
+
+// Session is a login of a user, all refresh tokens rotated from the login belong to the same session
+type Session struct {
+	Id        string
+	UserId    string
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// RefreshToken is stored as hash, a used refresh token is kept to detect reuse
+type RefreshToken struct {
+	Id        string
+	SessionId string
+	TokenHash string
+	ExpiredAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type RefreshSessionRequest struct {
+	RefreshToken string
+}
+
+func (r *RefreshSessionRequest) Validate() error {
+	if r.RefreshToken == "" {
+		return errorutil.NewFieldValidationError("error refresh session validation", &errorutil.FieldError{Field: "refreshToken", Message: "refresh token is mandatory"})
+	}
+	return nil
+}
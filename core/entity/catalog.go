@@ -0,0 +1,122 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+const (
+	CatalogFormatCSV   = "csv"
+	CatalogFormatJSONL = "jsonl"
+
+	CatalogImportJobStatusRunning   = "running"
+	CatalogImportJobStatusSucceeded = "succeeded"
+	CatalogImportJobStatusFailed    = "failed"
+
+	MaxCatalogImportRows = 10000
+	MaxCatalogSkuLength  = 50
+)
+
+func IsValidCatalogFormat(format string) bool {
+	return format == CatalogFormatCSV || format == CatalogFormatJSONL
+}
+
+// CatalogRow is a product stock in a warehouse, a product that is stocked in some warehouses has a row for each warehouse
+type CatalogRow struct {
+	Line        int    `json:"-"`
+	Sku         string `json:"sku"`
+	Name        string `json:"name"`
+	Price       int    `json:"price"`
+	WarehouseId string `json:"warehouseId"`
+	TotalStock  int    `json:"totalStock"`
+}
+
+func (r *CatalogRow) Validate() error {
+	if r.Sku == "" {
+		return errors.New("sku is mandatory")
+	}
+	if len(r.Sku) > MaxCatalogSkuLength {
+		return fmt.Errorf("sku must be at most %d characters", MaxCatalogSkuLength)
+	}
+	if r.Name == "" {
+		return errors.New("name is mandatory")
+	}
+	if r.Price <= 0 {
+		return errors.New("price must be more than zero")
+	}
+	if r.WarehouseId == "" {
+		return errors.New("warehouse id is mandatory")
+	}
+	if r.TotalStock < 0 {
+		return errors.New("total stock must not be negative")
+	}
+	return nil
+}
+
+type CatalogRowError struct {
+	Line  int    `json:"line"`
+	Sku   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
+
+type ImportCatalogRequest struct {
+	Format  string
+	DryRun  bool
+	Reader  io.Reader
+	ActorId string
+}
+
+func (r *ImportCatalogRequest) Validate() error {
+	if !IsValidCatalogFormat(r.Format) {
+		return errorutil.NewFieldValidationError("error import catalog validation", &errorutil.FieldError{Field: "format", Message: fmt.Sprintf("format '%s' is invalid, must be csv or jsonl", r.Format)})
+	}
+	if r.Reader == nil {
+		return errorutil.NewFieldValidationError("error import catalog validation", &errorutil.FieldError{Field: "content", Message: "content is mandatory"})
+	}
+	return nil
+}
+
+// CatalogImportJob tracks an import, a dry run or an import with invalid rows is reported without job id since it is not run
+type CatalogImportJob struct {
+	Id            string             `json:"id,omitempty"`
+	Status        string             `json:"status"`
+	Format        string             `json:"format"`
+	DryRun        bool               `json:"dryRun"`
+	TotalRows     int                `json:"totalRows"`
+	ProcessedRows int                `json:"processedRows"`
+	SucceededRows int                `json:"succeededRows"`
+	FailedRows    int                `json:"failedRows"`
+	Errors        []*CatalogRowError `json:"errors"`
+	ActorId       string             `json:"-"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
+}
+
+func (j *CatalogImportJob) IsDone() bool {
+	return j.Status == CatalogImportJobStatusSucceeded || j.Status == CatalogImportJobStatusFailed
+}
+
+type ExportCatalogRequest struct {
+	Format string
+	Writer io.Writer
+}
+
+func (r *ExportCatalogRequest) Validate() error {
+	if !IsValidCatalogFormat(r.Format) {
+		return errorutil.NewFieldValidationError("error export catalog validation", &errorutil.FieldError{Field: "format", Message: fmt.Sprintf("format '%s' is invalid, must be csv or jsonl", r.Format)})
+	}
+	return nil
+}
+
+// CatalogExportRow is a product stock in a warehouse, warehouse id is empty if the product has no stock in any warehouse
+type CatalogExportRow struct {
+	ProductId   string `json:"productId"`
+	Sku         string `json:"sku"`
+	Name        string `json:"name"`
+	Price       int    `json:"price"`
+	WarehouseId string `json:"warehouseId"`
+	TotalStock  int    `json:"totalStock"`
+}
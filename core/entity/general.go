@@ -1,31 +1,67 @@
 package entity
 
-import "math"
+import (
+	"fmt"
+	"math"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"strings"
+)
 
 type Pagination struct {
-	Page      int `json:"page"`
-	PageSize  int `json:"pageSize"`
-	TotalPage int `json:"totalPage"`
-	Total     int `json:"total"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"pageSize"`
+	TotalPage  int    `json:"totalPage"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+
+	Cursor    string `json:"-"` // opaque cursor from previous response, if any then it uses keyset instead of offset
+	SkipTotal bool   `json:"-"` // skip counting total rows, total & total page are set to TotalUnknown
 }
 
 const (
 	defaultPage     = 1
 	defaultPageSize = 10
 	maxPageSize     = 100
+
+	TotalUnknown = -1
 )
 
-func ParseToPagination(page, pageSize int) *Pagination {
-	return &Pagination{
-		Page:      page,
+func ParseToPagination(page *int, pageSize int) *Pagination {
+	p := &Pagination{
+		Page:      defaultPage,
 		PageSize:  pageSize,
 		TotalPage: 0,
 		Total:     0,
 	}
+	if page != nil {
+		p.Page = *page
+	}
+	return p
+}
+
+// SetCursor sets cursor pagination request values
+func (p *Pagination) SetCursor(cursor *string, skipTotal *bool) {
+	if cursor != nil {
+		p.Cursor = *cursor
+	}
+	if skipTotal != nil {
+		p.SkipTotal = *skipTotal
+	}
+}
+
+// IsCursor returns true if pagination uses cursor (keyset) instead of offset
+func (p *Pagination) IsCursor() bool {
+	return p.Cursor != ""
 }
 
 // ValidatePagination validates pagination values request
 func (p *Pagination) Validate() {
+	// page is not used by cursor pagination, so it should not reset the page size
+	if p.IsCursor() && p.Page <= 0 {
+		p.Page = defaultPage
+	}
+
 	if p.Page <= 0 || p.PageSize <= 0 {
 		p.SetToDefault()
 		return
@@ -50,8 +86,106 @@ func (p *Pagination) GetOffset() int {
 
 // SetPagination sets pagination response
 func (p *Pagination) SetPagination() {
+	if p.SkipTotal {
+		p.Total, p.TotalPage = TotalUnknown, TotalUnknown
+		return
+	}
+
 	if p.Total > 0 && p.Total < p.PageSize {
 		p.PageSize = p.Total
 	}
 	p.TotalPage = int(math.Ceil(float64(p.Total) / float64(p.PageSize)))
 }
+
+const (
+	SortDirectionAsc  = "asc"
+	SortDirectionDesc = "desc"
+
+	sortSeparator       = ","
+	sortDescPrefix      = "-"
+	filterSeparator     = ":"
+	filterValueSplitter = "|"
+)
+
+// Sort is a requested ordering of a list by one field
+type Sort struct {
+	Field     string
+	Direction string
+}
+
+// ParseToSorts parses sort query param like `-price,name` into sorts
+func ParseToSorts(sort *string) ([]*Sort, error) {
+	if sort == nil || strings.TrimSpace(*sort) == "" {
+		return nil, nil
+	}
+
+	var sorts []*Sort
+	for _, field := range strings.Split(*sort, sortSeparator) {
+		field = strings.TrimSpace(field)
+
+		direction := SortDirectionAsc
+		if strings.HasPrefix(field, sortDescPrefix) {
+			direction = SortDirectionDesc
+			field = strings.TrimPrefix(field, sortDescPrefix)
+		}
+
+		if field == "" {
+			return nil, errorutil.NewFieldValidationError("error parse sort", &errorutil.FieldError{Field: "sort", Message: fmt.Sprintf("sort field is empty in '%s'", *sort)})
+		}
+
+		sorts = append(sorts, &Sort{
+			Field:     field,
+			Direction: direction,
+		})
+	}
+
+	return sorts, nil
+}
+
+const (
+	FilterOperatorEq       = "eq"
+	FilterOperatorNe       = "ne"
+	FilterOperatorGt       = "gt"
+	FilterOperatorGte      = "gte"
+	FilterOperatorLt       = "lt"
+	FilterOperatorLte      = "lte"
+	FilterOperatorPrefix   = "prefix"
+	FilterOperatorContains = "contains"
+	FilterOperatorIn       = "in"
+)
+
+// Filter is a requested condition on one field of a list
+type Filter struct {
+	Field    string
+	Operator string
+	Values   []string // only `in` operator has more than one value
+}
+
+// ParseToFilters parses filter query params like `price:gte:1000` into filters
+func ParseToFilters(filters *[]string) ([]*Filter, error) {
+	if filters == nil {
+		return nil, nil
+	}
+
+	var result []*Filter
+	for _, filter := range *filters {
+		// value may contain the separator, so only split into 3 parts
+		parts := strings.SplitN(filter, filterSeparator, 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, errorutil.NewFieldValidationError("error parse filter", &errorutil.FieldError{Field: "filter", Message: fmt.Sprintf("'%s' should be in 'field:operator:value' format", filter)})
+		}
+
+		values := []string{parts[2]}
+		if parts[1] == FilterOperatorIn {
+			values = strings.Split(parts[2], filterValueSplitter)
+		}
+
+		result = append(result, &Filter{
+			Field:    parts[0],
+			Operator: parts[1],
+			Values:   values,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+const (
+	HealthStatusOk       = "ok"
+	HealthStatusDegraded = "degraded" // the server works with a fallback, e.g. the revocation list is checked in the database without redis
+	HealthStatusDown     = "down"
+)
+
+// dependencies that are checked by the readiness
+const (
+	HealthDependencyDatabase  = "database"
+	HealthDependencyMigration = "migration"
+	HealthDependencyRedis     = "redis"
+)
+
+// HealthReport is the readiness of the server, it is ready unless the status is down
+type HealthReport struct {
+	Status       string                       `json:"status"`
+	Dependencies map[string]*DependencyHealth `json:"dependencies"`
+	CheckedAt    time.Time                    `json:"checkedAt"`
+}
+
+type DependencyHealth struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"-"` // only logged, the error of a driver may have the address or the user of the dependency
+}
+
+// MigrationVersion is the schema version of the database, dirty if the last migration failed in the middle
+type MigrationVersion struct {
+	Version uint
+	Dirty   bool
+}
+
+func (r *HealthReport) IsReady() bool {
+	return r.Status != HealthStatusDown
+}
@@ -2,8 +2,8 @@ package entity
 
 import (
 	"errors"
-	"fmt"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
 )
 
 type CreateWarehouseRequest struct {
@@ -12,7 +12,7 @@ type CreateWarehouseRequest struct {
 
 func (r *CreateWarehouseRequest) Validate() error {
 	if r.Name == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error create warehouse request validation: name is mandantory"))
+		return errorutil.NewFieldValidationError("error create warehouse request validation", &errorutil.FieldError{Field: "name", Message: "name is mandatory"})
 	}
 	return nil
 }
@@ -30,13 +30,15 @@ type UpdateWarehouseStatusRequest struct {
 
 func (r UpdateWarehouseStatusRequest) Validate() error {
 	if r.Id == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error update warehouse status validation: id is mandantory"))
+		return errorutil.NewFieldValidationError("error update warehouse status validation", &errorutil.FieldError{Field: "warehouseId", Message: "warehouse id is mandatory"})
 	}
 	return nil
 }
 
 type GetWarehousesRequest struct {
 	Pagination *Pagination
+	Sorts      []*Sort
+	Filters    []*Filter
 	Enabled    *bool
 	Ids        []string
 	Name       string
@@ -55,23 +57,27 @@ type GetWarehousesResponse struct {
 }
 
 type CreateShopRequest struct {
-	Name string
+	Name    string
+	OwnerId string `json:"-"`
 }
 
 func (r *CreateShopRequest) Validate() error {
 	if r.Name == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error create shop request validation: name is mandantory"))
+		return errorutil.NewFieldValidationError("error create shop request validation", &errorutil.FieldError{Field: "name", Message: "name is mandatory"})
 	}
 	return nil
 }
 
 type Shop struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	OwnerId string `json:"-"`
 }
 
 type GetShopsRequest struct {
 	Pagination *Pagination
+	Sorts      []*Sort
+	Filters    []*Filter
 	Ids        []string
 	Name       string
 }
@@ -89,9 +95,10 @@ type GetShopsResponse struct {
 }
 
 type UpsertShopToWarehousesRequest struct {
-	ShopId       string   `json:"shopId"`
-	WarehouseIds []string `json:"warehousesIds"`
-	Enabled      bool     `json:"enabled"`
+	ShopId       string    `json:"shopId"`
+	WarehouseIds []string  `json:"warehousesIds"`
+	Enabled      bool      `json:"enabled"`
+	Actor        ShopActor `json:"-"`
 }
 
 type CreateProductRequest struct {
@@ -99,26 +106,34 @@ type CreateProductRequest struct {
 	Price       int
 	TotalStock  int
 	WarehouseId string
+	ActorId     string
 }
 
 func (r *CreateProductRequest) Validate() error {
 	if r.Name == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error create product request validation: name is mandantory"))
+		return errorutil.NewFieldValidationError("error create product request validation", &errorutil.FieldError{Field: "name", Message: "name is mandatory"})
 	}
 	if r.Price <= 0 {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error create product request validation: price must be more than zero"))
+		return errorutil.NewFieldValidationError("error create product request validation", &errorutil.FieldError{Field: "price", Message: "price must be more than zero"})
 	}
 	if r.TotalStock <= 0 {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error create product request validation: total stock must be more than zero"))
+		return errorutil.NewFieldValidationError("error create product request validation", &errorutil.FieldError{Field: "totalStock", Message: "total stock must be more than zero"})
 	}
 	if r.WarehouseId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error create product request validation: name is mandantory"))
+		return errorutil.NewFieldValidationError("error create product request validation", &errorutil.FieldError{Field: "warehouseId", Message: "warehouse id is mandatory"})
 	}
 	return nil
 }
 
+// ErrProductNameIsUsed and ErrProductSkuIsUsed are wrapped by the unique violation error of inserting a product
+var (
+	ErrProductNameIsUsed = errors.New("name is used by another product")
+	ErrProductSkuIsUsed  = errors.New("sku is used by another product")
+)
+
 type Product struct {
 	Id    string `json:"id"`
+	Sku   string `json:"sku,omitempty"` // empty if the product is not created by catalog import
 	Name  string `json:"name"`
 	Price int    `json:"price"`
 }
@@ -131,20 +146,21 @@ type ProductWarehouse struct {
 
 func (pw ProductWarehouse) Validate() error {
 	if pw.ProductId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, errors.New("error product warehouse validation: product id is mandatory"))
+		return errorutil.NewFieldValidationError("error product warehouse validation", &errorutil.FieldError{Field: "productId", Message: "product id is mandatory"})
 	}
 	if pw.WarehouseId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, errors.New("error product warehouse validation: warehouse id is mandatory"))
+		return errorutil.NewFieldValidationError("error product warehouse validation", &errorutil.FieldError{Field: "warehouseId", Message: "warehouse id is mandatory"})
 	}
 	return nil
 }
 
 type ProductDetail struct {
-	ProductId   string `json:"productId"`
-	Name        string `json:"name"`
-	Price       int    `json:"price"`
-	TotalStock  int    `json:"totalStock"`
-	WarehouseId string `json:"warehouseId"`
+	ProductId     string         `json:"productId"`
+	Name          string         `json:"name"`
+	Price         int            `json:"price"`
+	TotalStock    int            `json:"totalStock"`
+	WarehouseId   string         `json:"warehouseId"`
+	ResolvedPrice *ResolvedPrice `json:"-"` // set if price is resolved
 }
 
 type GetProductWarehousesByQueryRequest struct {
@@ -154,7 +170,7 @@ type GetProductWarehousesByQueryRequest struct {
 
 func (r *GetProductWarehousesByQueryRequest) Validate() error {
 	if len(r.ProductIds) == 0 {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error get product warehouse request validation: product id is mandatory"))
+		return errorutil.NewFieldValidationError("error get product warehouse request validation", &errorutil.FieldError{Field: "productIds", Message: "product id is mandatory"})
 	}
 	return nil
 }
@@ -162,12 +178,15 @@ func (r *GetProductWarehousesByQueryRequest) Validate() error {
 type GetProductDetailsByShopIdRequest struct {
 	ShopId     string
 	ProductIds []string
+	At         time.Time // price is the one that is sold by the shop at the time, it is also the price that is sorted and filtered
 	Pagination *Pagination
+	Sorts      []*Sort
+	Filters    []*Filter
 }
 
 func (r GetProductDetailsByShopIdRequest) Validate() error {
 	if r.ShopId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, errors.New("error get product details by shop id request validation: shop id is mandatory"))
+		return errorutil.NewFieldValidationError("error get product details by shop id request validation", &errorutil.FieldError{Field: "shopId", Message: "shop id is mandatory"})
 	}
 
 	// to avoid get all products
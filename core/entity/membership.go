@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+// roles of a shop member, a higher role can do everything a lower role can
+const (
+	ShopMemberRoleOwner   = "owner"   // user that creates the shop
+	ShopMemberRoleManager = "manager" // bind warehouses, invite and remove staff
+	ShopMemberRoleStaff   = "staff"   // manage prices and see the members
+)
+
+var shopMemberRoleLevels = map[string]int{
+	ShopMemberRoleStaff:   1,
+	ShopMemberRoleManager: 2,
+	ShopMemberRoleOwner:   3,
+}
+
+// IsShopMemberRoleAtLeast returns true if the role is the minimum role or higher
+func IsShopMemberRoleAtLeast(role, minRole string) bool {
+	return shopMemberRoleLevels[role] > 0 && shopMemberRoleLevels[role] >= shopMemberRoleLevels[minRole]
+}
+
+// IsShopMemberRoleHigher returns true if the role is higher than the other role, a member only manages lower members
+func IsShopMemberRoleHigher(role, otherRole string) bool {
+	return shopMemberRoleLevels[role] > shopMemberRoleLevels[otherRole]
+}
+
+type ShopMember struct {
+	ShopId    string    `json:"shopId"`
+	UserId    string    `json:"userId"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ShopActor is the user that calls a shop-scoped usecase, it is a member of the shop or can manage any shop
+type ShopActor struct {
+	UserId      string
+	Permissions []string
+}
+
+// AuthorizeShopMemberRequest checks whether the user is a member of the shop with the minimum role
+type AuthorizeShopMemberRequest struct {
+	ShopId  string
+	Actor   ShopActor
+	MinRole string
+}
+
+func (r *AuthorizeShopMemberRequest) Validate() error {
+	if r.ShopId == "" {
+		return errorutil.NewFieldValidationError("error authorize shop member validation", &errorutil.FieldError{Field: "shopId", Message: "shop id is mandatory"})
+	}
+	if _, ok := shopMemberRoleLevels[r.MinRole]; !ok {
+		return errorutil.NewFieldValidationError("error authorize shop member validation", &errorutil.FieldError{Field: "role", Message: fmt.Sprintf("role '%s' is not valid", r.MinRole)})
+	}
+	return nil
+}
+
+type ShopInvitation struct {
+	Id             string
+	ShopId         string
+	IdentifierType string
+	Identifier     string
+	Role           string
+	InvitedBy      string
+	ExpiredAt      time.Time
+	AcceptedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+func (i *ShopInvitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiredAt)
+}
+
+type InviteShopMemberRequest struct {
+	ShopId         string    `json:"-"`
+	IdentifierType string    `json:"identifierType"` // "email" or "phoneNumber"
+	Identifier     string    `json:"identifier"`
+	Role           string    `json:"role"` // "manager" or "staff"
+	Actor          ShopActor `json:"-"`
+}
+
+func (r *InviteShopMemberRequest) Validate() error {
+	if r.ShopId == "" {
+		return errorutil.NewFieldValidationError("error invite shop member validation", &errorutil.FieldError{Field: "shopId", Message: "shop id is mandatory"})
+	}
+	if field := validateIdentifier(r.IdentifierType, r.Identifier); field != nil {
+		return errorutil.NewFieldValidationError("error invite shop member validation", field)
+	}
+	if r.Role != ShopMemberRoleManager && r.Role != ShopMemberRoleStaff {
+		return errorutil.NewFieldValidationError("error invite shop member validation", &errorutil.FieldError{Field: "role", Message: fmt.Sprintf("role must be '%s' or '%s'", ShopMemberRoleManager, ShopMemberRoleStaff)})
+	}
+	return nil
+}
+
+type AcceptShopInvitationRequest struct {
+	InvitationId string
+	UserId       string
+}
+
+func (r *AcceptShopInvitationRequest) Validate() error {
+	if r.InvitationId == "" {
+		return errorutil.NewFieldValidationError("error accept shop invitation validation", &errorutil.FieldError{Field: "invitationId", Message: "invitation id is mandatory"})
+	}
+	if r.UserId == "" {
+		return errorutil.NewFieldValidationError("error accept shop invitation validation", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+	return nil
+}
+
+type GetShopMembersRequest struct {
+	ShopId string
+	Actor  ShopActor
+}
+
+type RemoveShopMemberRequest struct {
+	ShopId string
+	UserId string
+	Actor  ShopActor
+}
+
+func (r *RemoveShopMemberRequest) Validate() error {
+	if r.ShopId == "" {
+		return errorutil.NewFieldValidationError("error remove shop member validation", &errorutil.FieldError{Field: "shopId", Message: "shop id is mandatory"})
+	}
+	if r.UserId == "" {
+		return errorutil.NewFieldValidationError("error remove shop member validation", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+	return nil
+}
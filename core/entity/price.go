@@ -0,0 +1,188 @@
+package entity
+
+import (
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+const (
+	PriceSourceBase      = "base"      // products.price
+	PriceSourceShop      = "shop"      // shop_product_prices override
+	PriceSourcePriceList = "priceList" // active price list of the shop
+)
+
+type ShopProductPrice struct {
+	ShopId    string `json:"shopId"`
+	ProductId string `json:"productId"`
+	Price     int    `json:"price"`
+}
+
+type UpsertShopProductPriceRequest struct {
+	ShopId    string
+	ProductId string
+	Price     int `json:"price"`
+	ActorId   string
+	Actor     ShopActor `json:"-"`
+}
+
+func (r *UpsertShopProductPriceRequest) Validate() error {
+	if r.ShopId == "" {
+		return errorutil.NewFieldValidationError("error upsert shop product price validation", &errorutil.FieldError{Field: "shopId", Message: "shop id is mandatory"})
+	}
+	if r.ProductId == "" {
+		return errorutil.NewFieldValidationError("error upsert shop product price validation", &errorutil.FieldError{Field: "productId", Message: "product id is mandatory"})
+	}
+	if r.Price <= 0 {
+		return errorutil.NewFieldValidationError("error upsert shop product price validation", &errorutil.FieldError{Field: "price", Message: "price must be more than zero"})
+	}
+	return nil
+}
+
+type UpdateProductPriceRequest struct {
+	ProductId string
+	Price     int `json:"price"`
+	ActorId   string
+}
+
+func (r *UpdateProductPriceRequest) Validate() error {
+	if r.ProductId == "" {
+		return errorutil.NewFieldValidationError("error update product price validation", &errorutil.FieldError{Field: "productId", Message: "product id is mandatory"})
+	}
+	if r.Price <= 0 {
+		return errorutil.NewFieldValidationError("error update product price validation", &errorutil.FieldError{Field: "price", Message: "price must be more than zero"})
+	}
+	return nil
+}
+
+// PriceHistory records a change of base price (ShopId is empty) or shop override price
+type PriceHistory struct {
+	Id        string
+	ProductId string
+	ShopId    string
+	Source    string // PriceSourceBase or PriceSourceShop
+	Price     int
+	ActorId   string
+	CreatedAt time.Time
+}
+
+// PriceList is a set of product prices of a shop that is only active between start & end time (e.g. weekend sale)
+type PriceList struct {
+	Id      string           `json:"id"`
+	ShopId  string           `json:"shopId"`
+	Name    string           `json:"name"`
+	StartAt time.Time        `json:"startAt"`
+	EndAt   *time.Time       `json:"endAt,omitempty"` // nil means no end
+	Items   []*PriceListItem `json:"items"`
+}
+
+type PriceListItem struct {
+	PriceListId string `json:"-"`
+	ProductId   string `json:"productId"`
+	Price       int    `json:"price"`
+}
+
+type CreatePriceListRequest struct {
+	ShopId  string
+	Name    string           `json:"name"`
+	StartAt time.Time        `json:"startAt"`
+	EndAt   *time.Time       `json:"endAt"`
+	Items   []*PriceListItem `json:"items"`
+	Actor   ShopActor        `json:"-"`
+}
+
+func (r *CreatePriceListRequest) Validate() error {
+	if r.ShopId == "" {
+		return errorutil.NewFieldValidationError("error create price list validation", &errorutil.FieldError{Field: "shopId", Message: "shop id is mandatory"})
+	}
+	if r.Name == "" {
+		return errorutil.NewFieldValidationError("error create price list validation", &errorutil.FieldError{Field: "name", Message: "name is mandatory"})
+	}
+	if r.StartAt.IsZero() {
+		return errorutil.NewFieldValidationError("error create price list validation", &errorutil.FieldError{Field: "startAt", Message: "start at is mandatory"})
+	}
+	if r.EndAt != nil && !r.EndAt.After(r.StartAt) {
+		return errorutil.NewFieldValidationError("error create price list validation", &errorutil.FieldError{Field: "endAt", Message: "end at must be after start at"})
+	}
+	if len(r.Items) == 0 {
+		return errorutil.NewFieldValidationError("error create price list validation", &errorutil.FieldError{Field: "items", Message: "items are mandatory"})
+	}
+
+	productIds := make(map[string]bool)
+	for i, item := range r.Items {
+		if item.ProductId == "" {
+			return errorutil.NewFieldValidationError("error create price list validation", &errorutil.FieldError{Field: itemField(i, "productId"), Message: "product id is mandatory"})
+		}
+		if item.Price <= 0 {
+			return errorutil.NewFieldValidationError("error create price list validation", &errorutil.FieldError{Field: itemField(i, "price"), Message: fmt.Sprintf("price of product '%s' must be more than zero", item.ProductId)})
+		}
+		if productIds[item.ProductId] {
+			return errorutil.NewFieldValidationError("error create price list validation", &errorutil.FieldError{Field: itemField(i, "productId"), Message: fmt.Sprintf("product '%s' is duplicated", item.ProductId)})
+		}
+		productIds[item.ProductId] = true
+	}
+
+	return nil
+}
+
+type GetPriceListsRequest struct {
+	ShopId     string
+	ActiveFrom time.Time // only returns price lists that are not ended yet at this time
+	Actor      ShopActor
+}
+
+type GetPriceListsResponse struct {
+	PriceLists []*PriceList `json:"priceLists"`
+}
+
+type GetProductPricesRequest struct {
+	ShopId     string
+	ProductIds []string
+	At         time.Time
+}
+
+// ProductPrice contains all price candidates of a product in a shop at a time,
+// the history ids are empty if the price has no history record (set before price history exists)
+type ProductPrice struct {
+	ProductId          string
+	BasePrice          int
+	BasePriceHistoryId string
+	ShopPrice          *int
+	ShopPriceHistoryId string
+	PriceListId        string
+	PriceListPrice     *int
+}
+
+// ResolvedPrice is the price that is used to sell a product in a shop at a time
+type ResolvedPrice struct {
+	ProductId      string
+	Price          int
+	Source         string // PriceSourceBase, PriceSourceShop or PriceSourcePriceList
+	PriceHistoryId string // price record of base & shop price
+	PriceListId    string // price record of price list price
+}
+
+type GetProductPriceAtRequest struct {
+	ProductId string
+	ShopId    string // empty means base price
+	At        *time.Time
+}
+
+func (r *GetProductPriceAtRequest) Validate() error {
+	if r.ProductId == "" {
+		return errorutil.NewFieldValidationError("error get product price validation", &errorutil.FieldError{Field: "productId", Message: "product id is mandatory"})
+	}
+	return nil
+}
+
+type GetProductPriceAtResponse struct {
+	ProductId      string     `json:"productId"`
+	ShopId         string     `json:"shopId,omitempty"`
+	At             time.Time  `json:"at"`
+	Price          int        `json:"price"`
+	Source         string     `json:"source"`
+	PriceHistoryId string     `json:"priceHistoryId,omitempty"`
+	PriceListId    string     `json:"priceListId,omitempty"`
+	ChangedBy      string     `json:"changedBy,omitempty"`
+	ChangedAt      *time.Time `json:"changedAt,omitempty"`
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+// types of a data subject request
+const (
+	PrivacyRequestTypeExport   = "export"
+	PrivacyRequestTypeDeletion = "deletion"
+)
+
+// channels where a data subject request comes from
+const (
+	PrivacyRequestChannelAPI = "api" // the user requests it
+	PrivacyRequestChannelCLI = "cli" // an operator requests it on behalf of the user
+)
+
+// PrivacyRequest is the audit record of a data subject request, it is kept after the user is deleted
+type PrivacyRequest struct {
+	Id          string
+	UserId      string
+	Type        string
+	Channel     string
+	RequestedBy string // user id of the requester, empty if an operator runs the cli
+	CreatedAt   time.Time
+	CompletedAt *time.Time // nil if the request fails
+}
+
+// UserDataRequest requests to export or delete the data of the user
+type UserDataRequest struct {
+	UserId      string
+	Channel     string
+	RequestedBy string
+}
+
+func (r *UserDataRequest) Validate() error {
+	if r.UserId == "" {
+		return errorutil.NewFieldValidationError("error user data request validation", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+	if r.Channel != PrivacyRequestChannelAPI && r.Channel != PrivacyRequestChannelCLI {
+		return errorutil.NewFieldValidationError("error user data request validation", &errorutil.FieldError{Field: "channel", Message: fmt.Sprintf("channel '%s' is not valid", r.Channel)})
+	}
+	return nil
+}
+
+// UserDataExport is the archive of everything tied to the user
+type UserDataExport struct {
+	ExportedAt time.Time          `json:"exportedAt"`
+	Profile    *User              `json:"profile"`
+	Addresses  []*Address         `json:"addresses"` // include the deleted addresses that orders refer
+	Orders     []*UserOrderExport `json:"orders"`
+}
+
+type UserOrderExport struct {
+	*Order
+	Items    []*OrderItem `json:"items"`
+	Payments []*Payment   `json:"payments"`
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+// keys that a rate limit counts the requests by
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"   // user id of the token, or the api key or ip if there is no user
+	RateLimitKeyAPIKey = "apiKey" // api key id, or the ip if the request does not use an api key
+	// RateLimitKeyIdentifier is the email or phone number in the body of an auth request, or the ip if it has none
+	RateLimitKeyIdentifier = "identifier"
+)
+
+// RateLimitRule allows the limit of requests in the sliding window for each key
+type RateLimitRule struct {
+	KeyBy  string
+	Limit  int
+	Window time.Duration
+}
+
+// RateLimitRequest counts a request of the caller to the route
+type RateLimitRequest struct {
+	Route      string
+	Rule       RateLimitRule
+	IP         string
+	UserId     string
+	APIKeyId   string
+	Identifier string
+}
+
+func (r *RateLimitRequest) Validate() error {
+	if r.Route == "" {
+		return errorutil.NewFieldValidationError("error rate limit validation", &errorutil.FieldError{Field: "route", Message: "route is mandatory"})
+	}
+	if r.Rule.KeyBy != RateLimitKeyIP && r.Rule.KeyBy != RateLimitKeyUser && r.Rule.KeyBy != RateLimitKeyAPIKey && r.Rule.KeyBy != RateLimitKeyIdentifier {
+		return errorutil.NewFieldValidationError("error rate limit validation", &errorutil.FieldError{Field: "keyBy", Message: fmt.Sprintf("key '%s' is not valid", r.Rule.KeyBy)})
+	}
+	if r.Rule.Limit <= 0 {
+		return errorutil.NewFieldValidationError("error rate limit validation", &errorutil.FieldError{Field: "limit", Message: "limit must be positive"})
+	}
+	if r.Rule.Window <= 0 {
+		return errorutil.NewFieldValidationError("error rate limit validation", &errorutil.FieldError{Field: "window", Message: "window must be positive"})
+	}
+	return nil
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // time until the oldest request leaves the window, zero if it is allowed
+}
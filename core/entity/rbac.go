@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+)
+
+const (
+	ContextRoles       = "roles"
+	ContextPermissions = "permissions"
+)
+
+// roles and permissions are stored in db, the constants are the ones that are referred by code
+const (
+	RoleAdmin             = "admin"
+	RoleMerchant          = "merchant"
+	RoleWarehouseOperator = "warehouse_operator"
+	RoleCustomer          = "customer"
+
+	DefaultRole = RoleCustomer // role of registered user
+)
+
+const (
+	PermissionWarehouseRead  = "warehouse:read"
+	PermissionWarehouseWrite = "warehouse:write"
+	PermissionShopRead       = "shop:read"
+	PermissionShopWrite      = "shop:write"
+	PermissionShopManageAny  = "shop:manage_any" // manage shops that are owned by other users
+	PermissionProductRead    = "product:read"
+	PermissionProductWrite   = "product:write"
+	PermissionStockWrite     = "stock:write"
+	PermissionPriceWrite     = "price:write"
+	PermissionCatalogImport  = "catalog:import"
+	PermissionCatalogExport  = "catalog:export"
+	PermissionOrderCreate    = "order:create"
+	PermissionOrderPay       = "order:pay"
+	PermissionRoleAssign     = "role:assign"
+	PermissionAPIKeyManage   = "api_key:manage"
+)
+
+// UserRoles is the roles of a user and the permissions of the roles
+type UserRoles struct {
+	Roles       []string
+	Permissions []string
+}
+
+func HasPermission(permissions []string, permission string) bool {
+	return slices.Contains(permissions, permission)
+}
+
+type AssignRolesRequest struct {
+	UserId  string   `json:"-"`
+	Roles   []string `json:"roles"`
+	ActorId string   `json:"-"`
+}
+
+func (r *AssignRolesRequest) Validate() error {
+	if r.UserId == "" {
+		return errorutil.NewFieldValidationError("error assign roles validation", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+	if len(r.Roles) == 0 {
+		return errorutil.NewFieldValidationError("error assign roles validation", &errorutil.FieldError{Field: "roles", Message: "roles are mandatory"})
+	}
+	for i, role := range r.Roles {
+		if role == "" {
+			return errorutil.NewFieldValidationError("error assign roles validation", &errorutil.FieldError{Field: fmt.Sprintf("roles[%d]", i), Message: "role must not be empty"})
+		}
+	}
+	return nil
+}
+
+// BootstrapAdminRequest creates the first admin, or promotes the user if the identifier is registered
+type BootstrapAdminRequest struct {
+	IdentifierType string
+	Identifier     string
+	Password       string
+}
+
+func (r *BootstrapAdminRequest) Validate() error {
+	if field := validateIdentifier(r.IdentifierType, r.Identifier); field != nil {
+		return errorutil.NewFieldValidationError("error bootstrap admin validation", field)
+	}
+	if field := validatePassword(r.Password); field != nil {
+		return errorutil.NewFieldValidationError("error bootstrap admin validation", field)
+	}
+	return nil
+}
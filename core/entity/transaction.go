@@ -1,7 +1,7 @@
 package entity
 
 import (
-	"fmt"
+	"errors"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
 	"time"
 )
@@ -10,37 +10,42 @@ const (
 	OrderStatusPending   = "pending"
 	OrderStatusSucceeded = "succeeded"
 
-	OrderExpireTimeInMinute = 1
+	MaxPendingOrdersPerUser = 3 // pending orders reserve stock, so a user can not hold too many of them
 
 	PaymentStatusPaid = "paid"
 )
 
+// ErrInsufficientStock is wrapped by the bad request error of an order that can not reserve the stock
+var ErrInsufficientStock = errors.New("stock is not sufficient")
+
 type OrderProductItem struct {
 	ProductId string `json:"productId"`
 	Quantity  int    `json:"quantity"`
 }
 
 type OrderProductsRequest struct {
-	Items  []*OrderProductItem `json:"items"`
-	UserId string
-	ShopId string `json:"shopId"`
+	Items             []*OrderProductItem `json:"items"`
+	UserId            string
+	ShopId            string `json:"shopId"`
+	ShippingAddressId string `json:"shippingAddressId"` // address in the address book of the user, optional
 }
 
 func (r *OrderProductsRequest) Validate() error {
 	if len(r.Items) == 0 {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error validate order products request: items are mandatory"))
+		return errorutil.NewFieldValidationError("error validate order products request", &errorutil.FieldError{Field: "items", Message: "items are mandatory"})
 	}
 	return nil
 }
 
 type Order struct {
-	Id        string
-	UserId    string
-	ShopId    string
-	Amount    int
-	Status    string
-	CreatedAt time.Time
-	ExpiredAt time.Time
+	Id                string    `json:"id"`
+	UserId            string    `json:"userId"`
+	ShopId            string    `json:"shopId"`
+	ShippingAddressId string    `json:"shippingAddressId,omitempty"`
+	Amount            int       `json:"amount"`
+	Status            string    `json:"status"`
+	CreatedAt         time.Time `json:"createdAt"`
+	ExpiredAt         time.Time `json:"expiredAt"`
 }
 
 type LockOrderProductRequest struct {
@@ -57,11 +62,12 @@ type PayOrderRequest struct {
 }
 
 type Payment struct {
-	Id      string
-	OrderId string
-	Amount  int
-	Status  string
-	UserId  string
+	Id        string    `json:"id"`
+	OrderId   string    `json:"orderId"`
+	Amount    int       `json:"amount"`
+	Status    string    `json:"status"`
+	UserId    string    `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type UpdateOrderRequest struct {
@@ -70,12 +76,15 @@ type UpdateOrderRequest struct {
 }
 
 type OrderItem struct {
-	OrderId     string
-	ProductId   string
-	ShopId      string
-	WarehouseId string
-	Quantity    int
-	UnitPrice   int
+	OrderId        string `json:"orderId"`
+	ProductId      string `json:"productId"`
+	ShopId         string `json:"shopId"`
+	WarehouseId    string `json:"warehouseId"`
+	Quantity       int    `json:"quantity"`
+	UnitPrice      int    `json:"unitPrice"`
+	PriceSource    string `json:"priceSource"`
+	PriceHistoryId string `json:"priceHistoryId,omitempty"` // price record that is used if price source is base or shop
+	PriceListId    string `json:"priceListId,omitempty"`    // price record that is used if price source is price list
 }
 
 type UpdateProductWarehouseTotalStockRequest struct {
@@ -86,10 +95,10 @@ type UpdateProductWarehouseTotalStockRequest struct {
 
 func (r *UpdateProductWarehouseTotalStockRequest) Validate() error {
 	if r.ProductId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error validate update product stock request: product id is mandatory"))
+		return errorutil.NewFieldValidationError("error validate update product stock request", &errorutil.FieldError{Field: "productId", Message: "product id is mandatory"})
 	}
 	if r.WarehouseId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error validate update product stock request: warehouse id is mandatory"))
+		return errorutil.NewFieldValidationError("error validate update product stock request", &errorutil.FieldError{Field: "warehouseId", Message: "warehouse id is mandatory"})
 	}
 	return nil
 }
@@ -103,16 +112,16 @@ type TransferProductRequest struct {
 
 func (r *TransferProductRequest) Validate() error {
 	if r.ProductId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error transfer product request: product id is mandatory"))
+		return errorutil.NewFieldValidationError("error transfer product request", &errorutil.FieldError{Field: "productId", Message: "product id is mandatory"})
 	}
 	if r.SourceWarehouseId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error transfer product request: source warehouse id is mandatory"))
+		return errorutil.NewFieldValidationError("error transfer product request", &errorutil.FieldError{Field: "sourceWarehouseId", Message: "source warehouse id is mandatory"})
 	}
 	if r.DestinationWarehouseId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error transfer product request: destination warehouse id is mandatory"))
+		return errorutil.NewFieldValidationError("error transfer product request", &errorutil.FieldError{Field: "destinationWarehouseId", Message: "destination warehouse id is mandatory"})
 	}
 	if r.TotalStock <= 0 {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error transfer product request: total stock must be more than 0"))
+		return errorutil.NewFieldValidationError("error transfer product request", &errorutil.FieldError{Field: "totalStock", Message: "total stock must be more than 0"})
 	}
 	return nil
 }
@@ -3,6 +3,7 @@ package entity
 import (
 	"fmt"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
 )
 
 const (
@@ -15,38 +16,126 @@ const (
 	IdentifierTypePhoneNumber = "phoneNumber"
 )
 
+const (
+	OtpPurposeRegister = "register"
+	OtpPurposeLogin    = "login"
+	OtpPurposeVerify   = "verify" // verify new identifier of a registered user
+
+	MinPasswordLength = 8
+	MaxPasswordLength = 72 // bcrypt only uses the first 72 bytes
+
+	MaxNameLength = 100
+)
+
+func validateIdentifier(identifierType, identifier string) *errorutil.FieldError {
+	if identifierType != IdentifierTypeEmail && identifierType != IdentifierTypePhoneNumber {
+		return &errorutil.FieldError{Field: "identifierType", Message: "identifier type should be 'email' or 'phoneNumber'"}
+	}
+	if identifier == "" {
+		return &errorutil.FieldError{Field: "identifier", Message: "identifier is mandatory"}
+	}
+	return nil
+}
+
+func validatePassword(password string) *errorutil.FieldError {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return &errorutil.FieldError{Field: "password", Message: fmt.Sprintf("password must be %d to %d characters", MinPasswordLength, MaxPasswordLength)}
+	}
+	return nil
+}
+
+// itemField is the field of an item in the items of a request, e.g. items[0].price
+func itemField(index int, field string) string {
+	return fmt.Sprintf("items[%d].%s", index, field)
+}
+
+// RegisterUserRequest registers the identifier that is verified by the otp code sent with register purpose
 type RegisterUserRequest struct {
 	IdentifierType string // "email" or "phoneNumber"
 	Identifier     string // email or phoneNumber value
+	Password       string
+	OtpCode        string
 }
 
 func (r *RegisterUserRequest) Validate() error {
-	if r.IdentifierType != IdentifierTypeEmail && r.IdentifierType != IdentifierTypePhoneNumber {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error register user validation: identifier type should be 'email' or 'phoneNumber'"))
+	if field := validateIdentifier(r.IdentifierType, r.Identifier); field != nil {
+		return errorutil.NewFieldValidationError("error register user validation", field)
+	}
+	if field := validatePassword(r.Password); field != nil {
+		return errorutil.NewFieldValidationError("error register user validation", field)
 	}
-	if r.Identifier == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error register user validation: identifier is mandatory"))
+	if r.OtpCode == "" {
+		return errorutil.NewFieldValidationError("error register user validation", &errorutil.FieldError{Field: "otpCode", Message: "otp code is mandatory, request it with register purpose"})
 	}
 	return nil
 }
 
+// User is the profile of a user, an identifier is verified if its otp is verified
 type User struct {
-	Id          string
-	Email       string
-	PhoneNumber string
+	Id                  string    `json:"id"`
+	Name                string    `json:"name"`
+	Email               string    `json:"email,omitempty"`
+	EmailVerified       bool      `json:"emailVerified"`
+	PhoneNumber         string    `json:"phoneNumber,omitempty"`
+	PhoneNumberVerified bool      `json:"phoneNumberVerified"`
+	PasswordHash        string    `json:"-"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+type UpdateProfileRequest struct {
+	UserId string `json:"-"`
+	Name   string `json:"name"`
+}
+
+func (r *UpdateProfileRequest) Validate() error {
+	if r.UserId == "" {
+		return errorutil.NewFieldValidationError("error update profile validation", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+	if len(r.Name) > MaxNameLength {
+		return errorutil.NewFieldValidationError("error update profile validation", &errorutil.FieldError{Field: "name", Message: fmt.Sprintf("name must be at most %d characters", MaxNameLength)})
+	}
+	return nil
+}
+
+// ChangeIdentifierRequest sets the email or phone number of the user, the new identifier is verified by the otp
+// code sent with verify purpose
+type ChangeIdentifierRequest struct {
+	UserId         string `json:"-"`
+	IdentifierType string `json:"identifierType"`
+	Identifier     string `json:"identifier"`
+	OtpCode        string `json:"otpCode"`
+}
+
+func (r *ChangeIdentifierRequest) Validate() error {
+	if r.UserId == "" {
+		return errorutil.NewFieldValidationError("error change identifier validation", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+	if field := validateIdentifier(r.IdentifierType, r.Identifier); field != nil {
+		return errorutil.NewFieldValidationError("error change identifier validation", field)
+	}
+	if r.OtpCode == "" {
+		return errorutil.NewFieldValidationError("error change identifier validation", &errorutil.FieldError{Field: "otpCode", Message: "otp code is mandatory, request it with verify purpose"})
+	}
+	return nil
 }
 
+// LoginRequest logs in with password or otp code that is sent with login purpose
 type LoginRequest struct {
 	IdentifierType string // "email" or "phoneNumber"
 	Identifier     string // email or phoneNumber value
+	Password       string
+	OtpCode        string
 }
 
 func (r *LoginRequest) Validate() error {
-	if r.IdentifierType != IdentifierTypeEmail && r.IdentifierType != IdentifierTypePhoneNumber {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error login: identifier type should be 'email' or 'phoneNumber'"))
+	if field := validateIdentifier(r.IdentifierType, r.Identifier); field != nil {
+		return errorutil.NewFieldValidationError("error login", field)
 	}
-	if r.Identifier == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error login: identifier is mandatory"))
+	if (r.Password == "") == (r.OtpCode == "") {
+		message := "either password or otp code is mandatory"
+		return errorutil.NewValidationError(fmt.Errorf("error login: %s", message),
+			&errorutil.FieldError{Field: "password", Message: message}, &errorutil.FieldError{Field: "otpCode", Message: message})
 	}
 	return nil
 }
@@ -58,8 +147,43 @@ type GetUserRequest struct {
 }
 
 func (r *GetUserRequest) Validate() error {
-	if r.Email == "" && r.PhoneNumber == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error get user request validation: email or phone number is mandatory"))
+	if r.Id == "" && r.Email == "" && r.PhoneNumber == "" {
+		message := "id, email or phone number is mandatory"
+		return errorutil.NewValidationError(fmt.Errorf("error get user request validation: %s", message),
+			&errorutil.FieldError{Field: "id", Message: message}, &errorutil.FieldError{Field: "email", Message: message},
+			&errorutil.FieldError{Field: "phoneNumber", Message: message})
+	}
+	return nil
+}
+
+type RequestOtpRequest struct {
+	IdentifierType string // "email" or "phoneNumber"
+	Identifier     string // email or phoneNumber value
+	Purpose        string // "register", "login" or "verify"
+}
+
+func (r *RequestOtpRequest) Validate() error {
+	if field := validateIdentifier(r.IdentifierType, r.Identifier); field != nil {
+		return errorutil.NewFieldValidationError("error request otp validation", field)
+	}
+	if r.Purpose != OtpPurposeRegister && r.Purpose != OtpPurposeLogin && r.Purpose != OtpPurposeVerify {
+		return errorutil.NewFieldValidationError("error request otp validation", &errorutil.FieldError{Field: "purpose", Message: "purpose should be 'register', 'login' or 'verify'"})
 	}
 	return nil
 }
+
+// Otp is one time password of an identifier, only the hash of the code is stored
+type Otp struct {
+	Id         string
+	Identifier string
+	Purpose    string
+	CodeHash   string
+	Attempts   int
+	ExpiredAt  time.Time
+	ConsumedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (o *Otp) IsUsable(timeNow time.Time) bool {
+	return o.ConsumedAt == nil && timeNow.Before(o.ExpiredAt)
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/repository"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectTimeout bounds the queries of a scrape, a slow database or redis must not hang the scrape
+const collectTimeout = 5 * time.Second
+
+// stateCollector reads the state of the orders and the reservations at scrape time,
+// so the values are right after a restart and for all instances of the server
+type stateCollector struct {
+	transactionRepo repository.TransactionRepositoryInterface
+	redisRepo       repository.RedisRepositoryInterface
+	logger          *slog.Logger
+
+	ordersExpired *prometheus.Desc
+	reservedUnits *prometheus.Desc
+}
+
+// RegisterState exposes the expired orders and the reserved units per warehouse
+func (m *Metrics) RegisterState(transactionRepo repository.TransactionRepositoryInterface, redisRepo repository.RedisRepositoryInterface, logger *slog.Logger) {
+	m.Registry.MustRegister(&stateCollector{
+		transactionRepo: transactionRepo,
+		redisRepo:       redisRepo,
+		logger:          logger,
+
+		ordersExpired: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "orders_expired_total"),
+			"Orders that are expired without being paid, their reserved stock is released.",
+			nil, nil,
+		),
+		reservedUnits: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "reserved_units"),
+			"Units of the products that are reserved by the pending orders by the warehouse.",
+			[]string{"warehouse_id"}, nil,
+		),
+	})
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ordersExpired
+	ch <- c.reservedUnits
+}
+
+// Collect skips the metric that can not be read, the other metrics are still exposed
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	expired, err := c.transactionRepo.CountExpiredOrders(ctx)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "error collect metrics: error count expired orders", "error", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.ordersExpired, prometheus.CounterValue, float64(expired))
+	}
+
+	reserved, err := c.redisRepo.GetReservedQuantityPerWarehouse(ctx)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "error collect metrics: error get reserved quantity", "error", err)
+		return
+	}
+	for warehouseId, quantity := range reserved {
+		ch <- prometheus.MustNewConstMetric(c.reservedUnits, prometheus.GaugeValue, float64(quantity), warehouseId)
+	}
+}
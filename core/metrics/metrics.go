@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const (
+	namespace = "warehouse"
+
+	resultOk    = "ok"
+	resultError = "error"
+
+	ReasonInsufficientStock = "insufficient_stock"
+)
+
+// Metrics are the metrics of the server, the repositories and the usecases are wrapped to record them
+// so they do not know about the metrics
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	HTTPRequestDuration *prometheus.HistogramVec
+	RedisDuration       *prometheus.HistogramVec
+
+	OrdersCreated            prometheus.Counter
+	OrdersPaid               prometheus.Counter
+	OrderReservationFailures *prometheus.CounterVec
+	PostPaymentFailures      *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+
+		HTTPRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of the http requests by the route and the status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		RedisDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "redis_operation_duration_seconds",
+			Help:      "Latency of the redis operations of the repositories.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"operation", "result"}),
+
+		OrdersCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_created_total",
+			Help:      "Orders that are created, their stock is reserved until they are paid or expired.",
+		}),
+		OrdersPaid: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_paid_total",
+			Help:      "Orders that are paid.",
+		}),
+		OrderReservationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "order_reservation_failures_total",
+			Help:      "Orders that can not reserve the stock by the reason.",
+		}, []string{"reason"}),
+		PostPaymentFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "order_post_payment_failures_total",
+			Help:      "Failed operations of the async post payment action, the reservation or the stock of a paid order is not updated.",
+		}, []string{"operation"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequestDuration,
+		m.RedisDuration,
+		m.OrdersCreated,
+		m.OrdersPaid,
+		m.OrderReservationFailures,
+		m.PostPaymentFailures,
+	)
+
+	return m
+}
+
+// RegisterDB exposes the stats of the connection pool, e.g. in use, idle and wait count
+func (m *Metrics) RegisterDB(db *sql.DB) {
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultError
+	}
+	return resultOk
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/mocks"
+	"mfawzanid/warehouse-commerce/core/repository"
+	"mfawzanid/warehouse-commerce/core/usecase"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	lifecycleutil "mfawzanid/warehouse-commerce/utils/lifecycle"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	// doOrder inserts and pays an order in the unit of work, the transaction is committed if commit is true
+	doOrder := func(m *Metrics, commit bool) error {
+		transactionRepo := &mocks.TransactionRepositoryInterface{}
+		transactionRepo.On("InsertOrder", mock.Anything, mock.Anything).Return(nil)
+		transactionRepo.On("UpdateOrder", mock.Anything, mock.Anything).Return(nil)
+
+		uow := &mocks.UnitOfWorkInterface{}
+		uow.On("Do", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+			if err := fn(&repository.Repositories{Transaction: transactionRepo}); err != nil {
+				return err
+			}
+			if !commit {
+				return errors.New("error db in committing transaction")
+			}
+			return nil
+		})
+
+		return m.WrapUnitOfWork(uow).Do(context.Background(), func(repos *repository.Repositories) error {
+			if err := repos.Transaction.InsertOrder(context.Background(), &entity.Order{Id: "ORD-1"}); err != nil {
+				return err
+			}
+			return repos.Transaction.UpdateOrder(context.Background(), &entity.UpdateOrderRequest{OrderId: "ORD-1", Status: entity.OrderStatusSucceeded})
+		})
+	}
+
+	t.Run("Do_committed_then count the orders", func(t *testing.T) {
+		m := NewMetrics()
+
+		err := doOrder(m, true)
+
+		assert.Nil(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.OrdersCreated))
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.OrdersPaid))
+	})
+	t.Run("Do_commit fails_then do not count the orders", func(t *testing.T) {
+		m := NewMetrics()
+
+		err := doOrder(m, false)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, float64(0), testutil.ToFloat64(m.OrdersCreated))
+		assert.Equal(t, float64(0), testutil.ToFloat64(m.OrdersPaid))
+	})
+}
+
+func TestTransactionUsecase(t *testing.T) {
+	t.Run("OrderProducts_insufficient stock_then count the reservation failure", func(t *testing.T) {
+		m := NewMetrics()
+		transactionUsecase := &mocks.TransactionUsecaseInterface{}
+		transactionUsecase.On("OrderProducts", mock.Anything, mock.Anything).Return("", errorutil.NewErrorCode(errorutil.ErrBadRequest,
+			fmt.Errorf("error order products: product 'PRD-1' %w", entity.ErrInsufficientStock))).Once()
+		transactionUsecase.On("OrderProducts", mock.Anything, mock.Anything).Return("", errorutil.NewErrorCode(errorutil.ErrBadRequest,
+			errors.New("error order products: shipping address is not found"))).Once()
+
+		_, _ = m.WrapTransactionUsecase(transactionUsecase).OrderProducts(context.Background(), &entity.OrderProductsRequest{})
+		_, _ = m.WrapTransactionUsecase(transactionUsecase).OrderProducts(context.Background(), &entity.OrderProductsRequest{})
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.OrderReservationFailures.WithLabelValues(ReasonInsufficientStock)))
+	})
+}
+
+func TestPostPaymentFailures(t *testing.T) {
+	t.Run("InsertProductWarehouse_fails in post payment action_then count the failure", func(t *testing.T) {
+		m := NewMetrics()
+		inventoryRepo := &mocks.InventoryRepositoryInterface{}
+		inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.Anything).Return(errors.New("error repo insert product warehouse"))
+		repo := m.WrapInventoryRepository(inventoryRepo)
+
+		// the same failure of a request is not of the post payment action
+		_ = repo.InsertProductWarehouse(context.Background(), &entity.ProductWarehouse{})
+
+		manager := lifecycleutil.NewManager(logutil.Discard())
+		manager.Go(context.Background(), usecase.TaskPayOrderPostAction, func(ctx context.Context) {
+			_ = repo.InsertProductWarehouse(ctx, &entity.ProductWarehouse{})
+		})
+		assert.Nil(t, manager.Shutdown(context.Background()))
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.PostPaymentFailures.WithLabelValues("update_stock")))
+	})
+}
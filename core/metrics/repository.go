@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"context"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	"mfawzanid/warehouse-commerce/core/usecase"
+	lifecycleutil "mfawzanid/warehouse-commerce/utils/lifecycle"
+	"time"
+)
+
+// redisRepository records the latency of the redis calls
+type redisRepository struct {
+	repository.RedisRepositoryInterface
+	metrics *Metrics
+}
+
+func (m *Metrics) WrapRedisRepository(repo repository.RedisRepositoryInterface) repository.RedisRepositoryInterface {
+	return &redisRepository{
+		RedisRepositoryInterface: repo,
+		metrics:                  m,
+	}
+}
+
+func (r *redisRepository) LockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
+	start := time.Now()
+	err := r.RedisRepositoryInterface.LockOrderProduct(ctx, req)
+	r.metrics.observeRedis("lock_order_product", start, err)
+	return err
+}
+
+func (r *redisRepository) InvalidateLockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
+	start := time.Now()
+	err := r.RedisRepositoryInterface.InvalidateLockOrderProduct(ctx, req)
+	r.metrics.observeRedis("invalidate_lock_order_product", start, err)
+	r.metrics.observePostPayment(ctx, "invalidate_reservation", err)
+	return err
+}
+
+func (r *redisRepository) GetReservedProductQuantity(ctx context.Context, productId, warehouseId string) (int, error) {
+	start := time.Now()
+	quantity, err := r.RedisRepositoryInterface.GetReservedProductQuantity(ctx, productId, warehouseId)
+	r.metrics.observeRedis("get_reserved_product_quantity", start, err)
+	return quantity, err
+}
+
+func (r *redisRepository) GetReservedQuantityPerWarehouse(ctx context.Context) (map[string]int, error) {
+	start := time.Now()
+	quantities, err := r.RedisRepositoryInterface.GetReservedQuantityPerWarehouse(ctx)
+	r.metrics.observeRedis("get_reserved_quantity_per_warehouse", start, err)
+	return quantities, err
+}
+
+func (r *redisRepository) RevokeSession(ctx context.Context, sessionId string, expiration time.Duration) error {
+	start := time.Now()
+	err := r.RedisRepositoryInterface.RevokeSession(ctx, sessionId, expiration)
+	r.metrics.observeRedis("revoke_session", start, err)
+	return err
+}
+
+func (r *redisRepository) IsSessionRevoked(ctx context.Context, sessionId string) (bool, error) {
+	start := time.Now()
+	revoked, err := r.RedisRepositoryInterface.IsSessionRevoked(ctx, sessionId)
+	r.metrics.observeRedis("is_session_revoked", start, err)
+	return revoked, err
+}
+
+// rateLimitRepository records the latency of the rate limit script
+type rateLimitRepository struct {
+	repository.RateLimitRepositoryInterface
+	metrics *Metrics
+}
+
+func (m *Metrics) WrapRateLimitRepository(repo repository.RateLimitRepositoryInterface) repository.RateLimitRepositoryInterface {
+	return &rateLimitRepository{
+		RateLimitRepositoryInterface: repo,
+		metrics:                      m,
+	}
+}
+
+func (r *rateLimitRepository) AllowRequest(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*entity.RateLimitResult, error) {
+	start := time.Now()
+	result, err := r.RateLimitRepositoryInterface.AllowRequest(ctx, key, limit, window, now)
+	r.metrics.observeRedis("allow_request", start, err)
+	return result, err
+}
+
+// transactionRepository counts the failures of the post payment action, the action runs out of the transaction
+type transactionRepository struct {
+	repository.TransactionRepositoryInterface
+	metrics *Metrics
+}
+
+func (m *Metrics) WrapTransactionRepository(repo repository.TransactionRepositoryInterface) repository.TransactionRepositoryInterface {
+	return &transactionRepository{
+		TransactionRepositoryInterface: repo,
+		metrics:                        m,
+	}
+}
+
+func (r *transactionRepository) GetOrderItemsByOrderId(ctx context.Context, orderId string) ([]*entity.OrderItem, error) {
+	items, err := r.TransactionRepositoryInterface.GetOrderItemsByOrderId(ctx, orderId)
+	r.metrics.observePostPayment(ctx, "get_order_items", err)
+	return items, err
+}
+
+// inventoryRepository counts the failures of the stock update of the post payment action
+type inventoryRepository struct {
+	repository.InventoryRepositoryInterface
+	metrics *Metrics
+}
+
+func (m *Metrics) WrapInventoryRepository(repo repository.InventoryRepositoryInterface) repository.InventoryRepositoryInterface {
+	return &inventoryRepository{
+		InventoryRepositoryInterface: repo,
+		metrics:                      m,
+	}
+}
+
+func (r *inventoryRepository) InsertProductWarehouse(ctx context.Context, pw *entity.ProductWarehouse) error {
+	err := r.InventoryRepositoryInterface.InsertProductWarehouse(ctx, pw)
+	r.metrics.observePostPayment(ctx, "update_stock", err)
+	return err
+}
+
+// unitOfWork counts the orders that are created or paid, they are counted only if the transaction is committed
+type unitOfWork struct {
+	repository.UnitOfWorkInterface
+	metrics *Metrics
+}
+
+func (m *Metrics) WrapUnitOfWork(uow repository.UnitOfWorkInterface) repository.UnitOfWorkInterface {
+	return &unitOfWork{
+		UnitOfWorkInterface: uow,
+		metrics:             m,
+	}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	events := &orderEvents{}
+	err := u.UnitOfWorkInterface.Do(ctx, func(repos *repository.Repositories) error {
+		wrapped := *repos
+		wrapped.Transaction = &orderEventRepository{
+			TransactionRepositoryInterface: repos.Transaction,
+			events:                         events,
+		}
+		return fn(&wrapped)
+	})
+	if err != nil {
+		return err
+	}
+
+	u.metrics.OrdersCreated.Add(float64(events.created))
+	u.metrics.OrdersPaid.Add(float64(events.paid))
+
+	return nil
+}
+
+type orderEvents struct {
+	created int
+	paid    int
+}
+
+// orderEventRepository keeps the order events of a transaction until it is committed
+type orderEventRepository struct {
+	repository.TransactionRepositoryInterface
+	events *orderEvents
+}
+
+func (r *orderEventRepository) InsertOrder(ctx context.Context, order *entity.Order) error {
+	if err := r.TransactionRepositoryInterface.InsertOrder(ctx, order); err != nil {
+		return err
+	}
+
+	r.events.created++
+
+	return nil
+}
+
+func (r *orderEventRepository) UpdateOrder(ctx context.Context, req *entity.UpdateOrderRequest) error {
+	if err := r.TransactionRepositoryInterface.UpdateOrder(ctx, req); err != nil {
+		return err
+	}
+
+	if req.Status == entity.OrderStatusSucceeded {
+		r.events.paid++
+	}
+
+	return nil
+}
+
+func (m *Metrics) observeRedis(operation string, start time.Time, err error) {
+	m.RedisDuration.WithLabelValues(operation, result(err)).Observe(time.Since(start).Seconds())
+}
+
+// observePostPayment counts the failure only if it is of the post payment action, the repositories are shared with the requests
+func (m *Metrics) observePostPayment(ctx context.Context, operation string, err error) {
+	if err == nil || lifecycleutil.TaskName(ctx) != usecase.TaskPayOrderPostAction {
+		return
+	}
+	m.PostPaymentFailures.WithLabelValues(operation).Inc()
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/usecase"
+)
+
+// transactionUsecase counts the orders that can not reserve the stock
+type transactionUsecase struct {
+	usecase.TransactionUsecaseInterface
+	metrics *Metrics
+}
+
+func (m *Metrics) WrapTransactionUsecase(uc usecase.TransactionUsecaseInterface) usecase.TransactionUsecaseInterface {
+	return &transactionUsecase{
+		TransactionUsecaseInterface: uc,
+		metrics:                     m,
+	}
+}
+
+func (u *transactionUsecase) OrderProducts(ctx context.Context, req *entity.OrderProductsRequest) (string, error) {
+	orderId, err := u.TransactionUsecaseInterface.OrderProducts(ctx, req)
+	if errors.Is(err, entity.ErrInsufficientStock) {
+		u.metrics.OrderReservationFailures.WithLabelValues(ReasonInsufficientStock).Inc()
+	}
+
+	return orderId, err
+}
@@ -2,34 +2,110 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+	keyutil "mfawzanid/warehouse-commerce/utils/key"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // AuthUsecaseInterface is an autogenerated mock type for the AuthUsecaseInterface type
 type AuthUsecaseInterface struct {
 	mock.Mock
 }
 
-// CreateToken provides a mock function with given fields: username
-func (_m *AuthUsecaseInterface) CreateToken(username string) (string, error) {
-	ret := _m.Called(username)
+// AddToRevocationList provides a mock function with given fields: ctx, sessionIds
+func (_m *AuthUsecaseInterface) AddToRevocationList(ctx context.Context, sessionIds []string) error {
+	ret := _m.Called(ctx, sessionIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToRevocationList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, sessionIds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSession provides a mock function with given fields: ctx, userId
+func (_m *AuthUsecaseInterface) CreateSession(ctx context.Context, userId string) (*entity.AuthToken, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSession")
+	}
+
+	var r0 *entity.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.AuthToken, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.AuthToken); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetJSONWebKeys provides a mock function with no fields
+func (_m *AuthUsecaseInterface) GetJSONWebKeys() []keyutil.JSONWebKey {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJSONWebKeys")
+	}
+
+	var r0 []keyutil.JSONWebKey
+	if rf, ok := ret.Get(0).(func() []keyutil.JSONWebKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]keyutil.JSONWebKey)
+		}
+	}
+
+	return r0
+}
+
+// RefreshSession provides a mock function with given fields: ctx, req
+func (_m *AuthUsecaseInterface) RefreshSession(ctx context.Context, req *entity.RefreshSessionRequest) (*entity.AuthToken, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateToken")
+		panic("no return value specified for RefreshSession")
 	}
 
-	var r0 string
+	var r0 *entity.AuthToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return rf(username)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RefreshSessionRequest) (*entity.AuthToken, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(username)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RefreshSessionRequest) *entity.AuthToken); ok {
+		r0 = rf(ctx, req)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AuthToken)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(username)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.RefreshSessionRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -37,27 +113,47 @@ func (_m *AuthUsecaseInterface) CreateToken(username string) (string, error) {
 	return r0, r1
 }
 
-// VerifyToken provides a mock function with given fields: tokenString
-func (_m *AuthUsecaseInterface) VerifyToken(tokenString string) (string, error) {
-	ret := _m.Called(tokenString)
+// RevokeSession provides a mock function with given fields: ctx, sessionId
+func (_m *AuthUsecaseInterface) RevokeSession(ctx context.Context, sessionId string) error {
+	ret := _m.Called(ctx, sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, sessionId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyToken provides a mock function with given fields: ctx, tokenString
+func (_m *AuthUsecaseInterface) VerifyToken(ctx context.Context, tokenString string) (*entity.TokenClaims, error) {
+	ret := _m.Called(ctx, tokenString)
 
 	if len(ret) == 0 {
 		panic("no return value specified for VerifyToken")
 	}
 
-	var r0 string
+	var r0 *entity.TokenClaims
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return rf(tokenString)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.TokenClaims, error)); ok {
+		return rf(ctx, tokenString)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(tokenString)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.TokenClaims); ok {
+		r0 = rf(ctx, tokenString)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TokenClaims)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(tokenString)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenString)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,179 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CatalogRepositoryInterface is an autogenerated mock type for the CatalogRepositoryInterface type
+type CatalogRepositoryInterface struct {
+	mock.Mock
+}
+
+// GetImportJobById provides a mock function with given fields: ctx, id
+func (_m *CatalogRepositoryInterface) GetImportJobById(ctx context.Context, id string) (*entity.CatalogImportJob, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImportJobById")
+	}
+
+	var r0 *entity.CatalogImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.CatalogImportJob, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.CatalogImportJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CatalogImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProductBySku provides a mock function with given fields: ctx, sku
+func (_m *CatalogRepositoryInterface) GetProductBySku(ctx context.Context, sku string) (*entity.Product, error) {
+	ret := _m.Called(ctx, sku)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductBySku")
+	}
+
+	var r0 *entity.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Product, error)); ok {
+		return rf(ctx, sku)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Product); ok {
+		r0 = rf(ctx, sku)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, sku)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertImportJob provides a mock function with given fields: ctx, job
+func (_m *CatalogRepositoryInterface) InsertImportJob(ctx context.Context, job *entity.CatalogImportJob) error {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertImportJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CatalogImportJob) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IterateCatalog provides a mock function with given fields: ctx, fn
+func (_m *CatalogRepositoryInterface) IterateCatalog(ctx context.Context, fn func(*entity.CatalogExportRow) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterateCatalog")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(*entity.CatalogExportRow) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetProductWarehouseStock provides a mock function with given fields: ctx, pw
+func (_m *CatalogRepositoryInterface) SetProductWarehouseStock(ctx context.Context, pw *entity.ProductWarehouse) error {
+	ret := _m.Called(ctx, pw)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetProductWarehouseStock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ProductWarehouse) error); ok {
+		r0 = rf(ctx, pw)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateImportJob provides a mock function with given fields: ctx, job
+func (_m *CatalogRepositoryInterface) UpdateImportJob(ctx context.Context, job *entity.CatalogImportJob) error {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateImportJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CatalogImportJob) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, product
+func (_m *CatalogRepositoryInterface) UpdateProduct(ctx context.Context, product *entity.Product) error {
+	ret := _m.Called(ctx, product)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Product) error); ok {
+		r0 = rf(ctx, product)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCatalogRepositoryInterface creates a new instance of CatalogRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCatalogRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CatalogRepositoryInterface {
+	mock := &CatalogRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
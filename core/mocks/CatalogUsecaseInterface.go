@@ -0,0 +1,107 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CatalogUsecaseInterface is an autogenerated mock type for the CatalogUsecaseInterface type
+type CatalogUsecaseInterface struct {
+	mock.Mock
+}
+
+// ExportCatalog provides a mock function with given fields: ctx, req
+func (_m *CatalogUsecaseInterface) ExportCatalog(ctx context.Context, req *entity.ExportCatalogRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportCatalog")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ExportCatalogRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetImportJob provides a mock function with given fields: ctx, id
+func (_m *CatalogUsecaseInterface) GetImportJob(ctx context.Context, id string) (*entity.CatalogImportJob, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImportJob")
+	}
+
+	var r0 *entity.CatalogImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.CatalogImportJob, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.CatalogImportJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CatalogImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportCatalog provides a mock function with given fields: ctx, req
+func (_m *CatalogUsecaseInterface) ImportCatalog(ctx context.Context, req *entity.ImportCatalogRequest) (*entity.CatalogImportJob, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportCatalog")
+	}
+
+	var r0 *entity.CatalogImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ImportCatalogRequest) (*entity.CatalogImportJob, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ImportCatalogRequest) *entity.CatalogImportJob); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CatalogImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.ImportCatalogRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCatalogUsecaseInterface creates a new instance of CatalogUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCatalogUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CatalogUsecaseInterface {
+	mock := &CatalogUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,61 +0,0 @@
-// Code generated by mockery v2.50.1. DO NOT EDIT.
-
-package mocks
-
-import (
-	mock "github.com/stretchr/testify/mock"
-
-	sql "database/sql"
-)
-
-// Execer is an autogenerated mock type for the Execer type
-type Execer struct {
-	mock.Mock
-}
-
-// Exec provides a mock function with given fields: query, args
-func (_m *Execer) Exec(query string, args ...interface{}) (sql.Result, error) {
-	var _ca []interface{}
-	_ca = append(_ca, query)
-	_ca = append(_ca, args...)
-	ret := _m.Called(_ca...)
-
-	if len(ret) == 0 {
-		panic("no return value specified for Exec")
-	}
-
-	var r0 sql.Result
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string, ...interface{}) (sql.Result, error)); ok {
-		return rf(query, args...)
-	}
-	if rf, ok := ret.Get(0).(func(string, ...interface{}) sql.Result); ok {
-		r0 = rf(query, args...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(sql.Result)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(string, ...interface{}) error); ok {
-		r1 = rf(query, args...)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// NewExecer creates a new instance of Execer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewExecer(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *Execer {
-	mock := &Execer{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
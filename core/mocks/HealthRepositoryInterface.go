@@ -0,0 +1,95 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthRepositoryInterface is an autogenerated mock type for the HealthRepositoryInterface type
+type HealthRepositoryInterface struct {
+	mock.Mock
+}
+
+// GetMigrationVersion provides a mock function with given fields: ctx
+func (_m *HealthRepositoryInterface) GetMigrationVersion(ctx context.Context) (*entity.MigrationVersion, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMigrationVersion")
+	}
+
+	var r0 *entity.MigrationVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*entity.MigrationVersion, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.MigrationVersion); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.MigrationVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PingDatabase provides a mock function with given fields: ctx
+func (_m *HealthRepositoryInterface) PingDatabase(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PingDatabase")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PingRedis provides a mock function with given fields: ctx
+func (_m *HealthRepositoryInterface) PingRedis(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PingRedis")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewHealthRepositoryInterface creates a new instance of HealthRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthRepositoryInterface {
+	mock := &HealthRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
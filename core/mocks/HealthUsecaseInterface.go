@@ -0,0 +1,49 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthUsecaseInterface is an autogenerated mock type for the HealthUsecaseInterface type
+type HealthUsecaseInterface struct {
+	mock.Mock
+}
+
+// CheckReadiness provides a mock function with given fields: ctx
+func (_m *HealthUsecaseInterface) CheckReadiness(ctx context.Context) *entity.HealthReport {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckReadiness")
+	}
+
+	var r0 *entity.HealthReport
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.HealthReport); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.HealthReport)
+		}
+	}
+
+	return r0
+}
+
+// NewHealthUsecaseInterface creates a new instance of HealthUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthUsecaseInterface {
+	mock := &HealthUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
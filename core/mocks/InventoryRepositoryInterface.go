@@ -3,11 +3,10 @@
 package mocks
 
 import (
+	context "context"
 	entity "mfawzanid/warehouse-commerce/core/entity"
 
 	mock "github.com/stretchr/testify/mock"
-
-	sql "database/sql"
 )
 
 // InventoryRepositoryInterface is an autogenerated mock type for the InventoryRepositoryInterface type
@@ -15,29 +14,9 @@ type InventoryRepositoryInterface struct {
 	mock.Mock
 }
 
-// GetDb provides a mock function with no fields
-func (_m *InventoryRepositoryInterface) GetDb() *sql.DB {
-	ret := _m.Called()
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetDb")
-	}
-
-	var r0 *sql.DB
-	if rf, ok := ret.Get(0).(func() *sql.DB); ok {
-		r0 = rf()
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*sql.DB)
-		}
-	}
-
-	return r0
-}
-
-// GetProductByName provides a mock function with given fields: name
-func (_m *InventoryRepositoryInterface) GetProductByName(name string) (*entity.Product, error) {
-	ret := _m.Called(name)
+// GetProductByName provides a mock function with given fields: ctx, name
+func (_m *InventoryRepositoryInterface) GetProductByName(ctx context.Context, name string) (*entity.Product, error) {
+	ret := _m.Called(ctx, name)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetProductByName")
@@ -45,19 +24,19 @@ func (_m *InventoryRepositoryInterface) GetProductByName(name string) (*entity.P
 
 	var r0 *entity.Product
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*entity.Product, error)); ok {
-		return rf(name)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Product, error)); ok {
+		return rf(ctx, name)
 	}
-	if rf, ok := ret.Get(0).(func(string) *entity.Product); ok {
-		r0 = rf(name)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Product); ok {
+		r0 = rf(ctx, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Product)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(name)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -65,9 +44,9 @@ func (_m *InventoryRepositoryInterface) GetProductByName(name string) (*entity.P
 	return r0, r1
 }
 
-// GetProductDetailsByShopId provides a mock function with given fields: req
-func (_m *InventoryRepositoryInterface) GetProductDetailsByShopId(req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
-	ret := _m.Called(req)
+// GetProductDetailsByShopId provides a mock function with given fields: ctx, req
+func (_m *InventoryRepositoryInterface) GetProductDetailsByShopId(ctx context.Context, req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetProductDetailsByShopId")
@@ -75,19 +54,19 @@ func (_m *InventoryRepositoryInterface) GetProductDetailsByShopId(req *entity.Ge
 
 	var r0 *entity.GetProductDetailsByShopIdResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.GetProductDetailsByShopIdRequest) *entity.GetProductDetailsByShopIdResponse); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductDetailsByShopIdRequest) *entity.GetProductDetailsByShopIdResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.GetProductDetailsByShopIdResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.GetProductDetailsByShopIdRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetProductDetailsByShopIdRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -95,9 +74,9 @@ func (_m *InventoryRepositoryInterface) GetProductDetailsByShopId(req *entity.Ge
 	return r0, r1
 }
 
-// GetProductWarehousesByQuery provides a mock function with given fields: req
-func (_m *InventoryRepositoryInterface) GetProductWarehousesByQuery(req *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error) {
-	ret := _m.Called(req)
+// GetProductWarehousesByQuery provides a mock function with given fields: ctx, req
+func (_m *InventoryRepositoryInterface) GetProductWarehousesByQuery(ctx context.Context, req *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetProductWarehousesByQuery")
@@ -105,19 +84,19 @@ func (_m *InventoryRepositoryInterface) GetProductWarehousesByQuery(req *entity.
 
 	var r0 []*entity.ProductWarehouse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.GetProductWarehousesByQueryRequest) []*entity.ProductWarehouse); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductWarehousesByQueryRequest) []*entity.ProductWarehouse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*entity.ProductWarehouse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.GetProductWarehousesByQueryRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetProductWarehousesByQueryRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -125,9 +104,9 @@ func (_m *InventoryRepositoryInterface) GetProductWarehousesByQuery(req *entity.
 	return r0, r1
 }
 
-// GetShops provides a mock function with given fields: req
-func (_m *InventoryRepositoryInterface) GetShops(req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
-	ret := _m.Called(req)
+// GetShops provides a mock function with given fields: ctx, req
+func (_m *InventoryRepositoryInterface) GetShops(ctx context.Context, req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetShops")
@@ -135,19 +114,19 @@ func (_m *InventoryRepositoryInterface) GetShops(req *entity.GetShopsRequest) (*
 
 	var r0 *entity.GetShopsResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.GetShopsRequest) (*entity.GetShopsResponse, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetShopsRequest) (*entity.GetShopsResponse, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.GetShopsRequest) *entity.GetShopsResponse); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetShopsRequest) *entity.GetShopsResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.GetShopsResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.GetShopsRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetShopsRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -155,9 +134,9 @@ func (_m *InventoryRepositoryInterface) GetShops(req *entity.GetShopsRequest) (*
 	return r0, r1
 }
 
-// GetWarehouses provides a mock function with given fields: req
-func (_m *InventoryRepositoryInterface) GetWarehouses(req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
-	ret := _m.Called(req)
+// GetWarehouses provides a mock function with given fields: ctx, req
+func (_m *InventoryRepositoryInterface) GetWarehouses(ctx context.Context, req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetWarehouses")
@@ -165,19 +144,19 @@ func (_m *InventoryRepositoryInterface) GetWarehouses(req *entity.GetWarehousesR
 
 	var r0 *entity.GetWarehousesResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.GetWarehousesRequest) *entity.GetWarehousesResponse); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetWarehousesRequest) *entity.GetWarehousesResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.GetWarehousesResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.GetWarehousesRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetWarehousesRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -185,17 +164,17 @@ func (_m *InventoryRepositoryInterface) GetWarehouses(req *entity.GetWarehousesR
 	return r0, r1
 }
 
-// InsertProduct provides a mock function with given fields: tx, product
-func (_m *InventoryRepositoryInterface) InsertProduct(tx *sql.Tx, product *entity.Product) error {
-	ret := _m.Called(tx, product)
+// InsertProduct provides a mock function with given fields: ctx, product
+func (_m *InventoryRepositoryInterface) InsertProduct(ctx context.Context, product *entity.Product) error {
+	ret := _m.Called(ctx, product)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertProduct")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, *entity.Product) error); ok {
-		r0 = rf(tx, product)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Product) error); ok {
+		r0 = rf(ctx, product)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -203,35 +182,17 @@ func (_m *InventoryRepositoryInterface) InsertProduct(tx *sql.Tx, product *entit
 	return r0
 }
 
-// InsertProductWarehouse provides a mock function with given fields: pw
-func (_m *InventoryRepositoryInterface) InsertProductWarehouse(pw *entity.ProductWarehouse) error {
-	ret := _m.Called(pw)
+// InsertProductWarehouse provides a mock function with given fields: ctx, pw
+func (_m *InventoryRepositoryInterface) InsertProductWarehouse(ctx context.Context, pw *entity.ProductWarehouse) error {
+	ret := _m.Called(ctx, pw)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertProductWarehouse")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.ProductWarehouse) error); ok {
-		r0 = rf(pw)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// InsertProductWarehouseTx provides a mock function with given fields: tx, pw
-func (_m *InventoryRepositoryInterface) InsertProductWarehouseTx(tx *sql.Tx, pw *entity.ProductWarehouse) error {
-	ret := _m.Called(tx, pw)
-
-	if len(ret) == 0 {
-		panic("no return value specified for InsertProductWarehouseTx")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, *entity.ProductWarehouse) error); ok {
-		r0 = rf(tx, pw)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ProductWarehouse) error); ok {
+		r0 = rf(ctx, pw)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -239,17 +200,17 @@ func (_m *InventoryRepositoryInterface) InsertProductWarehouseTx(tx *sql.Tx, pw
 	return r0
 }
 
-// InsertShop provides a mock function with given fields: shop
-func (_m *InventoryRepositoryInterface) InsertShop(shop *entity.Shop) error {
-	ret := _m.Called(shop)
+// InsertShop provides a mock function with given fields: ctx, shop
+func (_m *InventoryRepositoryInterface) InsertShop(ctx context.Context, shop *entity.Shop) error {
+	ret := _m.Called(ctx, shop)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertShop")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.Shop) error); ok {
-		r0 = rf(shop)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Shop) error); ok {
+		r0 = rf(ctx, shop)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -257,17 +218,17 @@ func (_m *InventoryRepositoryInterface) InsertShop(shop *entity.Shop) error {
 	return r0
 }
 
-// InsertShopWarehouses provides a mock function with given fields: req
-func (_m *InventoryRepositoryInterface) InsertShopWarehouses(req *entity.UpsertShopToWarehousesRequest) error {
-	ret := _m.Called(req)
+// InsertShopWarehouses provides a mock function with given fields: ctx, req
+func (_m *InventoryRepositoryInterface) InsertShopWarehouses(ctx context.Context, req *entity.UpsertShopToWarehousesRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertShopWarehouses")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.UpsertShopToWarehousesRequest) error); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpsertShopToWarehousesRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -275,17 +236,17 @@ func (_m *InventoryRepositoryInterface) InsertShopWarehouses(req *entity.UpsertS
 	return r0
 }
 
-// InsertWarehouse provides a mock function with given fields: warehouse
-func (_m *InventoryRepositoryInterface) InsertWarehouse(warehouse *entity.Warehouse) error {
-	ret := _m.Called(warehouse)
+// InsertWarehouse provides a mock function with given fields: ctx, warehouse
+func (_m *InventoryRepositoryInterface) InsertWarehouse(ctx context.Context, warehouse *entity.Warehouse) error {
+	ret := _m.Called(ctx, warehouse)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertWarehouse")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.Warehouse) error); ok {
-		r0 = rf(warehouse)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Warehouse) error); ok {
+		r0 = rf(ctx, warehouse)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -293,17 +254,17 @@ func (_m *InventoryRepositoryInterface) InsertWarehouse(warehouse *entity.Wareho
 	return r0
 }
 
-// UpdateProductWarehouseTotalStock provides a mock function with given fields: req
-func (_m *InventoryRepositoryInterface) UpdateProductWarehouseTotalStock(req *entity.UpdateProductWarehouseTotalStockRequest) error {
-	ret := _m.Called(req)
+// UpdateProductWarehouseTotalStock provides a mock function with given fields: ctx, req
+func (_m *InventoryRepositoryInterface) UpdateProductWarehouseTotalStock(ctx context.Context, req *entity.UpdateProductWarehouseTotalStockRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateProductWarehouseTotalStock")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.UpdateProductWarehouseTotalStockRequest) error); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpdateProductWarehouseTotalStockRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -311,17 +272,17 @@ func (_m *InventoryRepositoryInterface) UpdateProductWarehouseTotalStock(req *en
 	return r0
 }
 
-// UpdateWarehouseStatus provides a mock function with given fields: req
-func (_m *InventoryRepositoryInterface) UpdateWarehouseStatus(req *entity.UpdateWarehouseStatusRequest) error {
-	ret := _m.Called(req)
+// UpdateWarehouseStatus provides a mock function with given fields: ctx, req
+func (_m *InventoryRepositoryInterface) UpdateWarehouseStatus(ctx context.Context, req *entity.UpdateWarehouseStatusRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateWarehouseStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.UpdateWarehouseStatusRequest) error); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpdateWarehouseStatusRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	entity "mfawzanid/warehouse-commerce/core/entity"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,9 +14,9 @@ type InventoryUsecaseInterface struct {
 	mock.Mock
 }
 
-// CreateProduct provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) CreateProduct(req *entity.CreateProductRequest) (string, error) {
-	ret := _m.Called(req)
+// CreateProduct provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) CreateProduct(ctx context.Context, req *entity.CreateProductRequest) (string, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateProduct")
@@ -23,17 +24,17 @@ func (_m *InventoryUsecaseInterface) CreateProduct(req *entity.CreateProductRequ
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.CreateProductRequest) (string, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CreateProductRequest) (string, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.CreateProductRequest) string); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CreateProductRequest) string); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.CreateProductRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.CreateProductRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,9 +42,9 @@ func (_m *InventoryUsecaseInterface) CreateProduct(req *entity.CreateProductRequ
 	return r0, r1
 }
 
-// CreateShop provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) CreateShop(req *entity.CreateShopRequest) (string, error) {
-	ret := _m.Called(req)
+// CreateShop provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) CreateShop(ctx context.Context, req *entity.CreateShopRequest) (string, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateShop")
@@ -51,17 +52,17 @@ func (_m *InventoryUsecaseInterface) CreateShop(req *entity.CreateShopRequest) (
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.CreateShopRequest) (string, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CreateShopRequest) (string, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.CreateShopRequest) string); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CreateShopRequest) string); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.CreateShopRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.CreateShopRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,9 +70,9 @@ func (_m *InventoryUsecaseInterface) CreateShop(req *entity.CreateShopRequest) (
 	return r0, r1
 }
 
-// CreateWarehouse provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) CreateWarehouse(req *entity.CreateWarehouseRequest) (string, error) {
-	ret := _m.Called(req)
+// CreateWarehouse provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) CreateWarehouse(ctx context.Context, req *entity.CreateWarehouseRequest) (string, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateWarehouse")
@@ -79,17 +80,17 @@ func (_m *InventoryUsecaseInterface) CreateWarehouse(req *entity.CreateWarehouse
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.CreateWarehouseRequest) (string, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CreateWarehouseRequest) (string, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.CreateWarehouseRequest) string); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CreateWarehouseRequest) string); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.CreateWarehouseRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.CreateWarehouseRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -97,9 +98,9 @@ func (_m *InventoryUsecaseInterface) CreateWarehouse(req *entity.CreateWarehouse
 	return r0, r1
 }
 
-// GetShops provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) GetShops(req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
-	ret := _m.Called(req)
+// GetShops provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) GetShops(ctx context.Context, req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetShops")
@@ -107,19 +108,19 @@ func (_m *InventoryUsecaseInterface) GetShops(req *entity.GetShopsRequest) (*ent
 
 	var r0 *entity.GetShopsResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.GetShopsRequest) (*entity.GetShopsResponse, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetShopsRequest) (*entity.GetShopsResponse, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.GetShopsRequest) *entity.GetShopsResponse); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetShopsRequest) *entity.GetShopsResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.GetShopsResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.GetShopsRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetShopsRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -127,9 +128,9 @@ func (_m *InventoryUsecaseInterface) GetShops(req *entity.GetShopsRequest) (*ent
 	return r0, r1
 }
 
-// GetWarehouses provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) GetWarehouses(req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
-	ret := _m.Called(req)
+// GetWarehouses provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) GetWarehouses(ctx context.Context, req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetWarehouses")
@@ -137,19 +138,19 @@ func (_m *InventoryUsecaseInterface) GetWarehouses(req *entity.GetWarehousesRequ
 
 	var r0 *entity.GetWarehousesResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.GetWarehousesRequest) *entity.GetWarehousesResponse); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetWarehousesRequest) *entity.GetWarehousesResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.GetWarehousesResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.GetWarehousesRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetWarehousesRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -157,17 +158,17 @@ func (_m *InventoryUsecaseInterface) GetWarehouses(req *entity.GetWarehousesRequ
 	return r0, r1
 }
 
-// TransferProduct provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) TransferProduct(req *entity.TransferProductRequest) error {
-	ret := _m.Called(req)
+// TransferProduct provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) TransferProduct(ctx context.Context, req *entity.TransferProductRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TransferProduct")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.TransferProductRequest) error); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.TransferProductRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -175,17 +176,17 @@ func (_m *InventoryUsecaseInterface) TransferProduct(req *entity.TransferProduct
 	return r0
 }
 
-// UpdateProductStock provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) UpdateProductStock(req *entity.UpdateProductWarehouseTotalStockRequest) error {
-	ret := _m.Called(req)
+// UpdateProductStock provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) UpdateProductStock(ctx context.Context, req *entity.UpdateProductWarehouseTotalStockRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateProductStock")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.UpdateProductWarehouseTotalStockRequest) error); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpdateProductWarehouseTotalStockRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -193,17 +194,17 @@ func (_m *InventoryUsecaseInterface) UpdateProductStock(req *entity.UpdateProduc
 	return r0
 }
 
-// UpdateWarehouseStatus provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) UpdateWarehouseStatus(req *entity.UpdateWarehouseStatusRequest) error {
-	ret := _m.Called(req)
+// UpdateWarehouseStatus provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) UpdateWarehouseStatus(ctx context.Context, req *entity.UpdateWarehouseStatusRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateWarehouseStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.UpdateWarehouseStatusRequest) error); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpdateWarehouseStatusRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -211,17 +212,17 @@ func (_m *InventoryUsecaseInterface) UpdateWarehouseStatus(req *entity.UpdateWar
 	return r0
 }
 
-// UpsertShopToWarehouses provides a mock function with given fields: req
-func (_m *InventoryUsecaseInterface) UpsertShopToWarehouses(req *entity.UpsertShopToWarehousesRequest) error {
-	ret := _m.Called(req)
+// UpsertShopToWarehouses provides a mock function with given fields: ctx, req
+func (_m *InventoryUsecaseInterface) UpsertShopToWarehouses(ctx context.Context, req *entity.UpsertShopToWarehousesRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpsertShopToWarehouses")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.UpsertShopToWarehousesRequest) error); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpsertShopToWarehousesRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,193 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MembershipRepositoryInterface is an autogenerated mock type for the MembershipRepositoryInterface type
+type MembershipRepositoryInterface struct {
+	mock.Mock
+}
+
+// AcceptShopInvitation provides a mock function with given fields: ctx, id, acceptedAt
+func (_m *MembershipRepositoryInterface) AcceptShopInvitation(ctx context.Context, id string, acceptedAt time.Time) error {
+	ret := _m.Called(ctx, id, acceptedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptShopInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, acceptedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteShopMember provides a mock function with given fields: ctx, shopId, userId
+func (_m *MembershipRepositoryInterface) DeleteShopMember(ctx context.Context, shopId string, userId string) error {
+	ret := _m.Called(ctx, shopId, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteShopMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, shopId, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetShopInvitationById provides a mock function with given fields: ctx, id
+func (_m *MembershipRepositoryInterface) GetShopInvitationById(ctx context.Context, id string) (*entity.ShopInvitation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShopInvitationById")
+	}
+
+	var r0 *entity.ShopInvitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.ShopInvitation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.ShopInvitation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ShopInvitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShopMember provides a mock function with given fields: ctx, shopId, userId
+func (_m *MembershipRepositoryInterface) GetShopMember(ctx context.Context, shopId string, userId string) (*entity.ShopMember, error) {
+	ret := _m.Called(ctx, shopId, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShopMember")
+	}
+
+	var r0 *entity.ShopMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.ShopMember, error)); ok {
+		return rf(ctx, shopId, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.ShopMember); ok {
+		r0 = rf(ctx, shopId, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ShopMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, shopId, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShopMembers provides a mock function with given fields: ctx, shopId
+func (_m *MembershipRepositoryInterface) GetShopMembers(ctx context.Context, shopId string) ([]*entity.ShopMember, error) {
+	ret := _m.Called(ctx, shopId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShopMembers")
+	}
+
+	var r0 []*entity.ShopMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.ShopMember, error)); ok {
+		return rf(ctx, shopId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.ShopMember); ok {
+		r0 = rf(ctx, shopId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ShopMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, shopId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertShopInvitation provides a mock function with given fields: ctx, invitation
+func (_m *MembershipRepositoryInterface) InsertShopInvitation(ctx context.Context, invitation *entity.ShopInvitation) error {
+	ret := _m.Called(ctx, invitation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertShopInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ShopInvitation) error); ok {
+		r0 = rf(ctx, invitation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertShopMember provides a mock function with given fields: ctx, member
+func (_m *MembershipRepositoryInterface) InsertShopMember(ctx context.Context, member *entity.ShopMember) error {
+	ret := _m.Called(ctx, member)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertShopMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ShopMember) error); ok {
+		r0 = rf(ctx, member)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMembershipRepositoryInterface creates a new instance of MembershipRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMembershipRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MembershipRepositoryInterface {
+	mock := &MembershipRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
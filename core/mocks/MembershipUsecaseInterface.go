@@ -0,0 +1,151 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MembershipUsecaseInterface is an autogenerated mock type for the MembershipUsecaseInterface type
+type MembershipUsecaseInterface struct {
+	mock.Mock
+}
+
+// AcceptShopInvitation provides a mock function with given fields: ctx, req
+func (_m *MembershipUsecaseInterface) AcceptShopInvitation(ctx context.Context, req *entity.AcceptShopInvitationRequest) (string, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptShopInvitation")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AcceptShopInvitationRequest) (string, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AcceptShopInvitationRequest) string); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.AcceptShopInvitationRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthorizeShopMember provides a mock function with given fields: ctx, req
+func (_m *MembershipUsecaseInterface) AuthorizeShopMember(ctx context.Context, req *entity.AuthorizeShopMemberRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthorizeShopMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AuthorizeShopMemberRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetShopMembers provides a mock function with given fields: ctx, req
+func (_m *MembershipUsecaseInterface) GetShopMembers(ctx context.Context, req *entity.GetShopMembersRequest) ([]*entity.ShopMember, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShopMembers")
+	}
+
+	var r0 []*entity.ShopMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetShopMembersRequest) ([]*entity.ShopMember, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetShopMembersRequest) []*entity.ShopMember); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ShopMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetShopMembersRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InviteShopMember provides a mock function with given fields: ctx, req
+func (_m *MembershipUsecaseInterface) InviteShopMember(ctx context.Context, req *entity.InviteShopMemberRequest) (string, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InviteShopMember")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.InviteShopMemberRequest) (string, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.InviteShopMemberRequest) string); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.InviteShopMemberRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveShopMember provides a mock function with given fields: ctx, req
+func (_m *MembershipUsecaseInterface) RemoveShopMember(ctx context.Context, req *entity.RemoveShopMemberRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveShopMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RemoveShopMemberRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMembershipUsecaseInterface creates a new instance of MembershipUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMembershipUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MembershipUsecaseInterface {
+	mock := &MembershipUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
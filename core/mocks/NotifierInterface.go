@@ -0,0 +1,42 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// NotifierInterface is an autogenerated mock type for the NotifierInterface type
+type NotifierInterface struct {
+	mock.Mock
+}
+
+// Notify provides a mock function with given fields: channel, recipient, message
+func (_m *NotifierInterface) Notify(channel string, recipient string, message string) error {
+	ret := _m.Called(channel, recipient, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Notify")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(channel, recipient, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNotifierInterface creates a new instance of NotifierInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotifierInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotifierInterface {
+	mock := &NotifierInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
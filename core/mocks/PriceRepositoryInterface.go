@@ -0,0 +1,209 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PriceRepositoryInterface is an autogenerated mock type for the PriceRepositoryInterface type
+type PriceRepositoryInterface struct {
+	mock.Mock
+}
+
+// GetPriceHistoryById provides a mock function with given fields: ctx, id
+func (_m *PriceRepositoryInterface) GetPriceHistoryById(ctx context.Context, id string) (*entity.PriceHistory, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPriceHistoryById")
+	}
+
+	var r0 *entity.PriceHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.PriceHistory, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.PriceHistory); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PriceHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPriceLists provides a mock function with given fields: ctx, req
+func (_m *PriceRepositoryInterface) GetPriceLists(ctx context.Context, req *entity.GetPriceListsRequest) ([]*entity.PriceList, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPriceLists")
+	}
+
+	var r0 []*entity.PriceList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetPriceListsRequest) ([]*entity.PriceList, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetPriceListsRequest) []*entity.PriceList); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.PriceList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetPriceListsRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProductPrices provides a mock function with given fields: ctx, req
+func (_m *PriceRepositoryInterface) GetProductPrices(ctx context.Context, req *entity.GetProductPricesRequest) ([]*entity.ProductPrice, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductPrices")
+	}
+
+	var r0 []*entity.ProductPrice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductPricesRequest) ([]*entity.ProductPrice, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductPricesRequest) []*entity.ProductPrice); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ProductPrice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetProductPricesRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertPriceHistory provides a mock function with given fields: ctx, history
+func (_m *PriceRepositoryInterface) InsertPriceHistory(ctx context.Context, history *entity.PriceHistory) error {
+	ret := _m.Called(ctx, history)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertPriceHistory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.PriceHistory) error); ok {
+		r0 = rf(ctx, history)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertPriceList provides a mock function with given fields: ctx, priceList
+func (_m *PriceRepositoryInterface) InsertPriceList(ctx context.Context, priceList *entity.PriceList) error {
+	ret := _m.Called(ctx, priceList)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertPriceList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.PriceList) error); ok {
+		r0 = rf(ctx, priceList)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertPriceListItems provides a mock function with given fields: ctx, items
+func (_m *PriceRepositoryInterface) InsertPriceListItems(ctx context.Context, items []*entity.PriceListItem) error {
+	ret := _m.Called(ctx, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertPriceListItems")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.PriceListItem) error); ok {
+		r0 = rf(ctx, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProductPrice provides a mock function with given fields: ctx, productId, price
+func (_m *PriceRepositoryInterface) UpdateProductPrice(ctx context.Context, productId string, price int) error {
+	ret := _m.Called(ctx, productId, price)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProductPrice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, productId, price)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertShopProductPrice provides a mock function with given fields: ctx, price
+func (_m *PriceRepositoryInterface) UpsertShopProductPrice(ctx context.Context, price *entity.ShopProductPrice) error {
+	ret := _m.Called(ctx, price)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertShopProductPrice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ShopProductPrice) error); ok {
+		r0 = rf(ctx, price)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPriceRepositoryInterface creates a new instance of PriceRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPriceRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PriceRepositoryInterface {
+	mock := &PriceRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,185 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PriceUsecaseInterface is an autogenerated mock type for the PriceUsecaseInterface type
+type PriceUsecaseInterface struct {
+	mock.Mock
+}
+
+// CreatePriceList provides a mock function with given fields: ctx, req
+func (_m *PriceUsecaseInterface) CreatePriceList(ctx context.Context, req *entity.CreatePriceListRequest) (string, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePriceList")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CreatePriceListRequest) (string, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CreatePriceListRequest) string); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.CreatePriceListRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPriceLists provides a mock function with given fields: ctx, req
+func (_m *PriceUsecaseInterface) GetPriceLists(ctx context.Context, req *entity.GetPriceListsRequest) (*entity.GetPriceListsResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPriceLists")
+	}
+
+	var r0 *entity.GetPriceListsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetPriceListsRequest) (*entity.GetPriceListsResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetPriceListsRequest) *entity.GetPriceListsResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GetPriceListsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetPriceListsRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProductPriceAt provides a mock function with given fields: ctx, req
+func (_m *PriceUsecaseInterface) GetProductPriceAt(ctx context.Context, req *entity.GetProductPriceAtRequest) (*entity.GetProductPriceAtResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductPriceAt")
+	}
+
+	var r0 *entity.GetProductPriceAtResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductPriceAtRequest) (*entity.GetProductPriceAtResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductPriceAtRequest) *entity.GetProductPriceAtResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GetProductPriceAtResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetProductPriceAtRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolvePrices provides a mock function with given fields: ctx, shopId, productIds, at
+func (_m *PriceUsecaseInterface) ResolvePrices(ctx context.Context, shopId string, productIds []string, at time.Time) (map[string]*entity.ResolvedPrice, error) {
+	ret := _m.Called(ctx, shopId, productIds, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolvePrices")
+	}
+
+	var r0 map[string]*entity.ResolvedPrice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, time.Time) (map[string]*entity.ResolvedPrice, error)); ok {
+		return rf(ctx, shopId, productIds, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, time.Time) map[string]*entity.ResolvedPrice); ok {
+		r0 = rf(ctx, shopId, productIds, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*entity.ResolvedPrice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, time.Time) error); ok {
+		r1 = rf(ctx, shopId, productIds, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProductPrice provides a mock function with given fields: ctx, req
+func (_m *PriceUsecaseInterface) UpdateProductPrice(ctx context.Context, req *entity.UpdateProductPriceRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProductPrice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpdateProductPriceRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertShopProductPrice provides a mock function with given fields: ctx, req
+func (_m *PriceUsecaseInterface) UpsertShopProductPrice(ctx context.Context, req *entity.UpsertShopProductPriceRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertShopProductPrice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpsertShopProductPriceRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPriceUsecaseInterface creates a new instance of PriceUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPriceUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PriceUsecaseInterface {
+	mock := &PriceUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,281 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PrivacyRepositoryInterface is an autogenerated mock type for the PrivacyRepositoryInterface type
+type PrivacyRepositoryInterface struct {
+	mock.Mock
+}
+
+// AnonymizeAddresses provides a mock function with given fields: ctx, userId, deletedAt
+func (_m *PrivacyRepositoryInterface) AnonymizeAddresses(ctx context.Context, userId string, deletedAt time.Time) error {
+	ret := _m.Called(ctx, userId, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AnonymizeAddresses")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, userId, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnonymizeUser provides a mock function with given fields: ctx, userId, deletedAt
+func (_m *PrivacyRepositoryInterface) AnonymizeUser(ctx context.Context, userId string, deletedAt time.Time) error {
+	ret := _m.Called(ctx, userId, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AnonymizeUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, userId, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompletePrivacyRequest provides a mock function with given fields: ctx, id, completedAt
+func (_m *PrivacyRepositoryInterface) CompletePrivacyRequest(ctx context.Context, id string, completedAt time.Time) error {
+	ret := _m.Called(ctx, id, completedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompletePrivacyRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, completedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountOwnedShops provides a mock function with given fields: ctx, userId
+func (_m *PrivacyRepositoryInterface) CountOwnedShops(ctx context.Context, userId string) (int, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOwnedShops")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUserRecords provides a mock function with given fields: ctx, user, deletedAt
+func (_m *PrivacyRepositoryInterface) DeleteUserRecords(ctx context.Context, user *entity.User, deletedAt time.Time) ([]string, error) {
+	ret := _m.Called(ctx, user, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserRecords")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User, time.Time) ([]string, error)); ok {
+		return rf(ctx, user, deletedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User, time.Time) []string); ok {
+		r0 = rf(ctx, user, deletedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.User, time.Time) error); ok {
+		r1 = rf(ctx, user, deletedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllAddresses provides a mock function with given fields: ctx, userId
+func (_m *PrivacyRepositoryInterface) GetAllAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllAddresses")
+	}
+
+	var r0 []*entity.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.Address, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.Address); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderItemsByOrderIds provides a mock function with given fields: ctx, orderIds
+func (_m *PrivacyRepositoryInterface) GetOrderItemsByOrderIds(ctx context.Context, orderIds []string) ([]*entity.OrderItem, error) {
+	ret := _m.Called(ctx, orderIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderItemsByOrderIds")
+	}
+
+	var r0 []*entity.OrderItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*entity.OrderItem, error)); ok {
+		return rf(ctx, orderIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*entity.OrderItem); ok {
+		r0 = rf(ctx, orderIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.OrderItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, orderIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrdersByUserId provides a mock function with given fields: ctx, userId
+func (_m *PrivacyRepositoryInterface) GetOrdersByUserId(ctx context.Context, userId string) ([]*entity.Order, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrdersByUserId")
+	}
+
+	var r0 []*entity.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.Order, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.Order); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentsByOrderIds provides a mock function with given fields: ctx, orderIds
+func (_m *PrivacyRepositoryInterface) GetPaymentsByOrderIds(ctx context.Context, orderIds []string) ([]*entity.Payment, error) {
+	ret := _m.Called(ctx, orderIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentsByOrderIds")
+	}
+
+	var r0 []*entity.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*entity.Payment, error)); ok {
+		return rf(ctx, orderIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*entity.Payment); ok {
+		r0 = rf(ctx, orderIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, orderIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertPrivacyRequest provides a mock function with given fields: ctx, privacyRequest
+func (_m *PrivacyRepositoryInterface) InsertPrivacyRequest(ctx context.Context, privacyRequest *entity.PrivacyRequest) error {
+	ret := _m.Called(ctx, privacyRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertPrivacyRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.PrivacyRequest) error); ok {
+		r0 = rf(ctx, privacyRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPrivacyRepositoryInterface creates a new instance of PrivacyRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPrivacyRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PrivacyRepositoryInterface {
+	mock := &PrivacyRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
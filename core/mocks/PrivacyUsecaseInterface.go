@@ -0,0 +1,77 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PrivacyUsecaseInterface is an autogenerated mock type for the PrivacyUsecaseInterface type
+type PrivacyUsecaseInterface struct {
+	mock.Mock
+}
+
+// DeleteUserData provides a mock function with given fields: ctx, req
+func (_m *PrivacyUsecaseInterface) DeleteUserData(ctx context.Context, req *entity.UserDataRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UserDataRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportUserData provides a mock function with given fields: ctx, req
+func (_m *PrivacyUsecaseInterface) ExportUserData(ctx context.Context, req *entity.UserDataRequest) (*entity.UserDataExport, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 *entity.UserDataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UserDataRequest) (*entity.UserDataExport, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UserDataRequest) *entity.UserDataExport); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UserDataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.UserDataRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPrivacyUsecaseInterface creates a new instance of PrivacyUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPrivacyUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PrivacyUsecaseInterface {
+	mock := &PrivacyUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,61 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RateLimitRepositoryInterface is an autogenerated mock type for the RateLimitRepositoryInterface type
+type RateLimitRepositoryInterface struct {
+	mock.Mock
+}
+
+// AllowRequest provides a mock function with given fields: ctx, key, limit, window, now
+func (_m *RateLimitRepositoryInterface) AllowRequest(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*entity.RateLimitResult, error) {
+	ret := _m.Called(ctx, key, limit, window, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllowRequest")
+	}
+
+	var r0 *entity.RateLimitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Duration, time.Time) (*entity.RateLimitResult, error)); ok {
+		return rf(ctx, key, limit, window, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Duration, time.Time) *entity.RateLimitResult); ok {
+		r0 = rf(ctx, key, limit, window, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RateLimitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, time.Duration, time.Time) error); ok {
+		r1 = rf(ctx, key, limit, window, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRateLimitRepositoryInterface creates a new instance of RateLimitRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRateLimitRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RateLimitRepositoryInterface {
+	mock := &RateLimitRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RateLimitUsecaseInterface is an autogenerated mock type for the RateLimitUsecaseInterface type
+type RateLimitUsecaseInterface struct {
+	mock.Mock
+}
+
+// AllowRequest provides a mock function with given fields: ctx, req
+func (_m *RateLimitUsecaseInterface) AllowRequest(ctx context.Context, req *entity.RateLimitRequest) (*entity.RateLimitResult, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllowRequest")
+	}
+
+	var r0 *entity.RateLimitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RateLimitRequest) (*entity.RateLimitResult, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RateLimitRequest) *entity.RateLimitResult); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RateLimitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.RateLimitRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRateLimitUsecaseInterface creates a new instance of RateLimitUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRateLimitUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RateLimitUsecaseInterface {
+	mock := &RateLimitUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RbacRepositoryInterface is an autogenerated mock type for the RbacRepositoryInterface type
+type RbacRepositoryInterface struct {
+	mock.Mock
+}
+
+// CountUsersByRole provides a mock function with given fields: ctx, role
+func (_m *RbacRepositoryInterface) CountUsersByRole(ctx context.Context, role string) (int, error) {
+	ret := _m.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUsersByRole")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRoles provides a mock function with given fields: ctx, userId
+func (_m *RbacRepositoryInterface) GetUserRoles(ctx context.Context, userId string) (*entity.UserRoles, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRoles")
+	}
+
+	var r0 *entity.UserRoles
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.UserRoles, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.UserRoles); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UserRoles)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetUserRoles provides a mock function with given fields: ctx, userId, roles
+func (_m *RbacRepositoryInterface) SetUserRoles(ctx context.Context, userId string, roles []string) error {
+	ret := _m.Called(ctx, userId, roles)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserRoles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, userId, roles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRbacRepositoryInterface creates a new instance of RbacRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRbacRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RbacRepositoryInterface {
+	mock := &RbacRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,75 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "mfawzanid/warehouse-commerce/core/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RbacUsecaseInterface is an autogenerated mock type for the RbacUsecaseInterface type
+type RbacUsecaseInterface struct {
+	mock.Mock
+}
+
+// AssignRoles provides a mock function with given fields: ctx, req
+func (_m *RbacUsecaseInterface) AssignRoles(ctx context.Context, req *entity.AssignRolesRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignRoles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AssignRolesRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BootstrapAdmin provides a mock function with given fields: ctx, req
+func (_m *RbacUsecaseInterface) BootstrapAdmin(ctx context.Context, req *entity.BootstrapAdminRequest) (string, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BootstrapAdmin")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.BootstrapAdminRequest) (string, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.BootstrapAdminRequest) string); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.BootstrapAdminRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRbacUsecaseInterface creates a new instance of RbacUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRbacUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RbacUsecaseInterface {
+	mock := &RbacUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 	entity "mfawzanid/warehouse-commerce/core/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // RedisRepositoryInterface is an autogenerated mock type for the RedisRepositoryInterface type
@@ -42,6 +44,36 @@ func (_m *RedisRepositoryInterface) GetReservedProductQuantity(ctx context.Conte
 	return r0, r1
 }
 
+// GetReservedQuantityPerWarehouse provides a mock function with given fields: ctx
+func (_m *RedisRepositoryInterface) GetReservedQuantityPerWarehouse(ctx context.Context) (map[string]int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReservedQuantityPerWarehouse")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InvalidateLockOrderProduct provides a mock function with given fields: ctx, req
 func (_m *RedisRepositoryInterface) InvalidateLockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
 	ret := _m.Called(ctx, req)
@@ -60,6 +92,34 @@ func (_m *RedisRepositoryInterface) InvalidateLockOrderProduct(ctx context.Conte
 	return r0
 }
 
+// IsSessionRevoked provides a mock function with given fields: ctx, sessionId
+func (_m *RedisRepositoryInterface) IsSessionRevoked(ctx context.Context, sessionId string) (bool, error) {
+	ret := _m.Called(ctx, sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsSessionRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, sessionId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, sessionId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, sessionId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LockOrderProduct provides a mock function with given fields: ctx, req
 func (_m *RedisRepositoryInterface) LockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
 	ret := _m.Called(ctx, req)
@@ -78,6 +138,24 @@ func (_m *RedisRepositoryInterface) LockOrderProduct(ctx context.Context, req *e
 	return r0
 }
 
+// RevokeSession provides a mock function with given fields: ctx, sessionId, expiration
+func (_m *RedisRepositoryInterface) RevokeSession(ctx context.Context, sessionId string, expiration time.Duration) error {
+	ret := _m.Called(ctx, sessionId, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, sessionId, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRedisRepositoryInterface creates a new instance of RedisRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRedisRepositoryInterface(t interface {
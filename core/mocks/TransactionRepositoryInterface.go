@@ -3,11 +3,10 @@
 package mocks
 
 import (
+	context "context"
 	entity "mfawzanid/warehouse-commerce/core/entity"
 
 	mock "github.com/stretchr/testify/mock"
-
-	sql "database/sql"
 )
 
 // TransactionRepositoryInterface is an autogenerated mock type for the TransactionRepositoryInterface type
@@ -15,29 +14,65 @@ type TransactionRepositoryInterface struct {
 	mock.Mock
 }
 
-// GetDb provides a mock function with no fields
-func (_m *TransactionRepositoryInterface) GetDb() *sql.DB {
-	ret := _m.Called()
+// CountExpiredOrders provides a mock function with given fields: ctx
+func (_m *TransactionRepositoryInterface) CountExpiredOrders(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetDb")
+		panic("no return value specified for CountExpiredOrders")
 	}
 
-	var r0 *sql.DB
-	if rf, ok := ret.Get(0).(func() *sql.DB); ok {
-		r0 = rf()
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*sql.DB)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// GetOrderById provides a mock function with given fields: id, isActive
-func (_m *TransactionRepositoryInterface) GetOrderById(id string, isActive *bool) (*entity.Order, error) {
-	ret := _m.Called(id, isActive)
+// CountPendingOrders provides a mock function with given fields: ctx, userId
+func (_m *TransactionRepositoryInterface) CountPendingOrders(ctx context.Context, userId string) (int, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPendingOrders")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderById provides a mock function with given fields: ctx, id, isActive
+func (_m *TransactionRepositoryInterface) GetOrderById(ctx context.Context, id string, isActive *bool) (*entity.Order, error) {
+	ret := _m.Called(ctx, id, isActive)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrderById")
@@ -45,19 +80,19 @@ func (_m *TransactionRepositoryInterface) GetOrderById(id string, isActive *bool
 
 	var r0 *entity.Order
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, *bool) (*entity.Order, error)); ok {
-		return rf(id, isActive)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *bool) (*entity.Order, error)); ok {
+		return rf(ctx, id, isActive)
 	}
-	if rf, ok := ret.Get(0).(func(string, *bool) *entity.Order); ok {
-		r0 = rf(id, isActive)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *bool) *entity.Order); ok {
+		r0 = rf(ctx, id, isActive)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, *bool) error); ok {
-		r1 = rf(id, isActive)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *bool) error); ok {
+		r1 = rf(ctx, id, isActive)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -65,9 +100,9 @@ func (_m *TransactionRepositoryInterface) GetOrderById(id string, isActive *bool
 	return r0, r1
 }
 
-// GetOrderItemsByOrderId provides a mock function with given fields: orderId
-func (_m *TransactionRepositoryInterface) GetOrderItemsByOrderId(orderId string) ([]*entity.OrderItem, error) {
-	ret := _m.Called(orderId)
+// GetOrderItemsByOrderId provides a mock function with given fields: ctx, orderId
+func (_m *TransactionRepositoryInterface) GetOrderItemsByOrderId(ctx context.Context, orderId string) ([]*entity.OrderItem, error) {
+	ret := _m.Called(ctx, orderId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrderItemsByOrderId")
@@ -75,19 +110,19 @@ func (_m *TransactionRepositoryInterface) GetOrderItemsByOrderId(orderId string)
 
 	var r0 []*entity.OrderItem
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) ([]*entity.OrderItem, error)); ok {
-		return rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.OrderItem, error)); ok {
+		return rf(ctx, orderId)
 	}
-	if rf, ok := ret.Get(0).(func(string) []*entity.OrderItem); ok {
-		r0 = rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.OrderItem); ok {
+		r0 = rf(ctx, orderId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*entity.OrderItem)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(orderId)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -95,17 +130,17 @@ func (_m *TransactionRepositoryInterface) GetOrderItemsByOrderId(orderId string)
 	return r0, r1
 }
 
-// InsertOrder provides a mock function with given fields: order
-func (_m *TransactionRepositoryInterface) InsertOrder(order *entity.Order) error {
-	ret := _m.Called(order)
+// InsertOrder provides a mock function with given fields: ctx, order
+func (_m *TransactionRepositoryInterface) InsertOrder(ctx context.Context, order *entity.Order) error {
+	ret := _m.Called(ctx, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertOrder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.Order) error); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Order) error); ok {
+		r0 = rf(ctx, order)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -113,17 +148,17 @@ func (_m *TransactionRepositoryInterface) InsertOrder(order *entity.Order) error
 	return r0
 }
 
-// InsertOrderItems provides a mock function with given fields: items
-func (_m *TransactionRepositoryInterface) InsertOrderItems(items []*entity.OrderItem) error {
-	ret := _m.Called(items)
+// InsertOrderItems provides a mock function with given fields: ctx, items
+func (_m *TransactionRepositoryInterface) InsertOrderItems(ctx context.Context, items []*entity.OrderItem) error {
+	ret := _m.Called(ctx, items)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertOrderItems")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]*entity.OrderItem) error); ok {
-		r0 = rf(items)
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.OrderItem) error); ok {
+		r0 = rf(ctx, items)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -131,17 +166,35 @@ func (_m *TransactionRepositoryInterface) InsertOrderItems(items []*entity.Order
 	return r0
 }
 
-// InsertPayment provides a mock function with given fields: tx, req
-func (_m *TransactionRepositoryInterface) InsertPayment(tx *sql.Tx, req *entity.Payment) error {
-	ret := _m.Called(tx, req)
+// InsertPayment provides a mock function with given fields: ctx, req
+func (_m *TransactionRepositoryInterface) InsertPayment(ctx context.Context, req *entity.Payment) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertPayment")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, *entity.Payment) error); ok {
-		r0 = rf(tx, req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Payment) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LockPendingOrders provides a mock function with given fields: ctx, userId
+func (_m *TransactionRepositoryInterface) LockPendingOrders(ctx context.Context, userId string) error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockPendingOrders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userId)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -149,17 +202,17 @@ func (_m *TransactionRepositoryInterface) InsertPayment(tx *sql.Tx, req *entity.
 	return r0
 }
 
-// UpdateOrder provides a mock function with given fields: tx, req
-func (_m *TransactionRepositoryInterface) UpdateOrder(tx *sql.Tx, req *entity.UpdateOrderRequest) error {
-	ret := _m.Called(tx, req)
+// UpdateOrder provides a mock function with given fields: ctx, req
+func (_m *TransactionRepositoryInterface) UpdateOrder(ctx context.Context, req *entity.UpdateOrderRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateOrder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, *entity.UpdateOrderRequest) error); ok {
-		r0 = rf(tx, req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpdateOrderRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	entity "mfawzanid/warehouse-commerce/core/entity"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,9 +14,9 @@ type TransactionUsecaseInterface struct {
 	mock.Mock
 }
 
-// GetProductDetailsByShopId provides a mock function with given fields: req
-func (_m *TransactionUsecaseInterface) GetProductDetailsByShopId(req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
-	ret := _m.Called(req)
+// GetProductDetailsByShopId provides a mock function with given fields: ctx, req
+func (_m *TransactionUsecaseInterface) GetProductDetailsByShopId(ctx context.Context, req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetProductDetailsByShopId")
@@ -23,19 +24,19 @@ func (_m *TransactionUsecaseInterface) GetProductDetailsByShopId(req *entity.Get
 
 	var r0 *entity.GetProductDetailsByShopIdResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.GetProductDetailsByShopIdRequest) *entity.GetProductDetailsByShopIdResponse); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetProductDetailsByShopIdRequest) *entity.GetProductDetailsByShopIdResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.GetProductDetailsByShopIdResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.GetProductDetailsByShopIdRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetProductDetailsByShopIdRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,9 +44,9 @@ func (_m *TransactionUsecaseInterface) GetProductDetailsByShopId(req *entity.Get
 	return r0, r1
 }
 
-// OrderProducts provides a mock function with given fields: req
-func (_m *TransactionUsecaseInterface) OrderProducts(req *entity.OrderProductsRequest) (string, error) {
-	ret := _m.Called(req)
+// OrderProducts provides a mock function with given fields: ctx, req
+func (_m *TransactionUsecaseInterface) OrderProducts(ctx context.Context, req *entity.OrderProductsRequest) (string, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for OrderProducts")
@@ -53,17 +54,17 @@ func (_m *TransactionUsecaseInterface) OrderProducts(req *entity.OrderProductsRe
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.OrderProductsRequest) (string, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.OrderProductsRequest) (string, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.OrderProductsRequest) string); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.OrderProductsRequest) string); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.OrderProductsRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.OrderProductsRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -71,17 +72,17 @@ func (_m *TransactionUsecaseInterface) OrderProducts(req *entity.OrderProductsRe
 	return r0, r1
 }
 
-// PayOrder provides a mock function with given fields: req
-func (_m *TransactionUsecaseInterface) PayOrder(req *entity.PayOrderRequest) error {
-	ret := _m.Called(req)
+// PayOrder provides a mock function with given fields: ctx, req
+func (_m *TransactionUsecaseInterface) PayOrder(ctx context.Context, req *entity.PayOrderRequest) error {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PayOrder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.PayOrderRequest) error); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.PayOrderRequest) error); ok {
+		r0 = rf(ctx, req)
 	} else {
 		r0 = ret.Error(0)
 	}
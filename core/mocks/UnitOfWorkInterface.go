@@ -0,0 +1,47 @@
+// Code generated by mockery v2.50.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	repository "mfawzanid/warehouse-commerce/core/repository"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UnitOfWorkInterface is an autogenerated mock type for the UnitOfWorkInterface type
+type UnitOfWorkInterface struct {
+	mock.Mock
+}
+
+// Do provides a mock function with given fields: ctx, fn
+func (_m *UnitOfWorkInterface) Do(ctx context.Context, fn func(*repository.Repositories) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Do")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(*repository.Repositories) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUnitOfWorkInterface creates a new instance of UnitOfWorkInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUnitOfWorkInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UnitOfWorkInterface {
+	mock := &UnitOfWorkInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
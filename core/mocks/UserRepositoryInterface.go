@@ -3,9 +3,12 @@
 package mocks
 
 import (
+	context "context"
 	entity "mfawzanid/warehouse-commerce/core/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserRepositoryInterface is an autogenerated mock type for the UserRepositoryInterface type
@@ -13,9 +16,163 @@ type UserRepositoryInterface struct {
 	mock.Mock
 }
 
-// GetUser provides a mock function with given fields: req
-func (_m *UserRepositoryInterface) GetUser(req *entity.GetUserRequest) (*entity.User, error) {
-	ret := _m.Called(req)
+// ConsumeOtp provides a mock function with given fields: ctx, id, consumedAt
+func (_m *UserRepositoryInterface) ConsumeOtp(ctx context.Context, id string, consumedAt time.Time) error {
+	ret := _m.Called(ctx, id, consumedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeOtp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, consumedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountOtpsSince provides a mock function with given fields: ctx, identifier, since
+func (_m *UserRepositoryInterface) CountOtpsSince(ctx context.Context, identifier string, since time.Time) (int, error) {
+	ret := _m.Called(ctx, identifier, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOtpsSince")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int, error)); ok {
+		return rf(ctx, identifier, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int); ok {
+		r0 = rf(ctx, identifier, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, identifier, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAddress provides a mock function with given fields: ctx, userId, id, deletedAt
+func (_m *UserRepositoryInterface) DeleteAddress(ctx context.Context, userId string, id string, deletedAt time.Time) error {
+	ret := _m.Called(ctx, userId, id, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAddress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, userId, id, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAddress provides a mock function with given fields: ctx, userId, id
+func (_m *UserRepositoryInterface) GetAddress(ctx context.Context, userId string, id string) (*entity.Address, error) {
+	ret := _m.Called(ctx, userId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAddress")
+	}
+
+	var r0 *entity.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.Address, error)); ok {
+		return rf(ctx, userId, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.Address); ok {
+		r0 = rf(ctx, userId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAddresses provides a mock function with given fields: ctx, userId
+func (_m *UserRepositoryInterface) GetAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAddresses")
+	}
+
+	var r0 []*entity.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.Address, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.Address); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestOtp provides a mock function with given fields: ctx, identifier, purpose
+func (_m *UserRepositoryInterface) GetLatestOtp(ctx context.Context, identifier string, purpose string) (*entity.Otp, error) {
+	ret := _m.Called(ctx, identifier, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestOtp")
+	}
+
+	var r0 *entity.Otp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.Otp, error)); ok {
+		return rf(ctx, identifier, purpose)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.Otp); ok {
+		r0 = rf(ctx, identifier, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Otp)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, identifier, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: ctx, req
+func (_m *UserRepositoryInterface) GetUser(ctx context.Context, req *entity.GetUserRequest) (*entity.User, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUser")
@@ -23,19 +180,19 @@ func (_m *UserRepositoryInterface) GetUser(req *entity.GetUserRequest) (*entity.
 
 	var r0 *entity.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.GetUserRequest) (*entity.User, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetUserRequest) (*entity.User, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.GetUserRequest) *entity.User); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.GetUserRequest) *entity.User); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.GetUserRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.GetUserRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,17 +200,143 @@ func (_m *UserRepositoryInterface) GetUser(req *entity.GetUserRequest) (*entity.
 	return r0, r1
 }
 
-// InsertUser provides a mock function with given fields: user
-func (_m *UserRepositoryInterface) InsertUser(user *entity.User) error {
-	ret := _m.Called(user)
+// IncreaseOtpAttempts provides a mock function with given fields: ctx, id, maxAttempts
+func (_m *UserRepositoryInterface) IncreaseOtpAttempts(ctx context.Context, id string, maxAttempts int) error {
+	ret := _m.Called(ctx, id, maxAttempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncreaseOtpAttempts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, id, maxAttempts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertAddress provides a mock function with given fields: ctx, address
+func (_m *UserRepositoryInterface) InsertAddress(ctx context.Context, address *entity.Address) error {
+	ret := _m.Called(ctx, address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertAddress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Address) error); ok {
+		r0 = rf(ctx, address)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertOtp provides a mock function with given fields: ctx, otp
+func (_m *UserRepositoryInterface) InsertOtp(ctx context.Context, otp *entity.Otp) error {
+	ret := _m.Called(ctx, otp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOtp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Otp) error); ok {
+		r0 = rf(ctx, otp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertUser provides a mock function with given fields: ctx, user
+func (_m *UserRepositoryInterface) InsertUser(ctx context.Context, user *entity.User) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnsetDefaultAddress provides a mock function with given fields: ctx, userId
+func (_m *UserRepositoryInterface) UnsetDefaultAddress(ctx context.Context, userId string) error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnsetDefaultAddress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAddress provides a mock function with given fields: ctx, address
+func (_m *UserRepositoryInterface) UpdateAddress(ctx context.Context, address *entity.Address) error {
+	ret := _m.Called(ctx, address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAddress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Address) error); ok {
+		r0 = rf(ctx, address)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserIdentifier provides a mock function with given fields: ctx, userId, identifierType, identifier, updatedAt
+func (_m *UserRepositoryInterface) UpdateUserIdentifier(ctx context.Context, userId string, identifierType string, identifier string, updatedAt time.Time) error {
+	ret := _m.Called(ctx, userId, identifierType, identifier, updatedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserIdentifier")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) error); ok {
+		r0 = rf(ctx, userId, identifierType, identifier, updatedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserName provides a mock function with given fields: ctx, userId, name, updatedAt
+func (_m *UserRepositoryInterface) UpdateUserName(ctx context.Context, userId string, name string, updatedAt time.Time) error {
+	ret := _m.Called(ctx, userId, name, updatedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserName")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, userId, name, updatedAt)
 	} else {
 		r0 = ret.Error(0)
 	}
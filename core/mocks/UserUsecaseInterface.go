@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	entity "mfawzanid/warehouse-commerce/core/entity"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,27 +14,167 @@ type UserUsecaseInterface struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: req
-func (_m *UserUsecaseInterface) Login(req *entity.LoginRequest) (string, error) {
-	ret := _m.Called(req)
+// ChangeIdentifier provides a mock function with given fields: ctx, req
+func (_m *UserUsecaseInterface) ChangeIdentifier(ctx context.Context, req *entity.ChangeIdentifierRequest) (*entity.User, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeIdentifier")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ChangeIdentifierRequest) (*entity.User, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ChangeIdentifierRequest) *entity.User); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.ChangeIdentifierRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAddress provides a mock function with given fields: ctx, req
+func (_m *UserUsecaseInterface) CreateAddress(ctx context.Context, req *entity.UpsertAddressRequest) (*entity.Address, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAddress")
+	}
+
+	var r0 *entity.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpsertAddressRequest) (*entity.Address, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpsertAddressRequest) *entity.Address); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.UpsertAddressRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAddress provides a mock function with given fields: ctx, userId, addressId
+func (_m *UserUsecaseInterface) DeleteAddress(ctx context.Context, userId string, addressId string) error {
+	ret := _m.Called(ctx, userId, addressId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAddress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, addressId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAddresses provides a mock function with given fields: ctx, userId
+func (_m *UserUsecaseInterface) GetAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAddresses")
+	}
+
+	var r0 []*entity.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.Address, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.Address); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfile provides a mock function with given fields: ctx, userId
+func (_m *UserUsecaseInterface) GetProfile(ctx context.Context, userId string) (*entity.User, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, req
+func (_m *UserUsecaseInterface) Login(ctx context.Context, req *entity.LoginRequest) (*entity.AuthToken, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 string
+	var r0 *entity.AuthToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.LoginRequest) (string, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.LoginRequest) (*entity.AuthToken, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.LoginRequest) string); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.LoginRequest) *entity.AuthToken); ok {
+		r0 = rf(ctx, req)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AuthToken)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.LoginRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.LoginRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,27 +182,107 @@ func (_m *UserUsecaseInterface) Login(req *entity.LoginRequest) (string, error)
 	return r0, r1
 }
 
-// RegisterUser provides a mock function with given fields: req
-func (_m *UserUsecaseInterface) RegisterUser(req *entity.RegisterUserRequest) (string, error) {
-	ret := _m.Called(req)
+// RegisterUser provides a mock function with given fields: ctx, req
+func (_m *UserUsecaseInterface) RegisterUser(ctx context.Context, req *entity.RegisterUserRequest) (*entity.AuthToken, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RegisterUser")
 	}
 
-	var r0 string
+	var r0 *entity.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RegisterUserRequest) (*entity.AuthToken, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RegisterUserRequest) *entity.AuthToken); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.RegisterUserRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestOtp provides a mock function with given fields: ctx, req
+func (_m *UserUsecaseInterface) RequestOtp(ctx context.Context, req *entity.RequestOtpRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestOtp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RequestOtpRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAddress provides a mock function with given fields: ctx, req
+func (_m *UserUsecaseInterface) UpdateAddress(ctx context.Context, req *entity.UpsertAddressRequest) (*entity.Address, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAddress")
+	}
+
+	var r0 *entity.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpsertAddressRequest) (*entity.Address, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpsertAddressRequest) *entity.Address); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.UpsertAddressRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, req
+func (_m *UserUsecaseInterface) UpdateProfile(ctx context.Context, req *entity.UpdateProfileRequest) (*entity.User, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 *entity.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.RegisterUserRequest) (string, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpdateProfileRequest) (*entity.User, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.RegisterUserRequest) string); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UpdateProfileRequest) *entity.User); ok {
+		r0 = rf(ctx, req)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.RegisterUserRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.UpdateProfileRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
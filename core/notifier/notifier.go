@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// NotifierInterface sends message to the recipient through the channel, e.g. email or phone number (sms)
+type NotifierInterface interface {
+	Notify(channel, recipient, message string) error
+}
+
+type logNotifier struct {
+	filePath string
+	mu       sync.Mutex
+	logger   *slog.Logger
+}
+
+// NewLogNotifier returns notifier for local use, it appends the messages to the file
+// or writes them to the log if the file path is empty
+func NewLogNotifier(filePath string, logger *slog.Logger) NotifierInterface {
+	return &logNotifier{
+		filePath: filePath,
+		logger:   logger,
+	}
+}
+
+func (n *logNotifier) Notify(channel, recipient, message string) error {
+	if n.filePath == "" {
+		n.logger.Info("notify", "channel", channel, "recipient", recipient, "message", message)
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("error notify: %v", err.Error())
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "%s %s '%s': %s\n", time.Now().Format(time.RFC3339), channel, recipient, message); err != nil {
+		return fmt.Errorf("error notify: %v", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryAPIKeyRepository struct {
+	db memoryDB
+}
+
+func NewMemoryAPIKeyRepository(store *MemoryStore) APIKeyRepositoryInterface {
+	return &memoryAPIKeyRepository{
+		db: store,
+	}
+}
+
+func (r *memoryAPIKeyRepository) InsertAPIKey(ctx context.Context, apiKey *entity.APIKey) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.apiKeys[apiKey.Id]; ok {
+			return fmt.Errorf("error repo insert api key: api key id '%s' already exists", apiKey.Id)
+		}
+		for _, existing := range t.apiKeys {
+			if existing.KeyHash == apiKey.KeyHash {
+				return fmt.Errorf("error repo insert api key: api key hash already exists")
+			}
+		}
+		if _, ok := t.users[apiKey.CreatedBy]; !ok {
+			return fmt.Errorf("error repo insert api key: user '%s' is not found", apiKey.CreatedBy)
+		}
+
+		t.apiKeys[apiKey.Id] = entity.APIKey{
+			Id:        apiKey.Id,
+			Name:      apiKey.Name,
+			Prefix:    apiKey.Prefix,
+			KeyHash:   apiKey.KeyHash,
+			Scopes:    slices.Clone(apiKey.Scopes),
+			CreatedBy: apiKey.CreatedBy,
+			ExpiredAt: timePtr(apiKey.ExpiredAt),
+			CreatedAt: apiKey.CreatedAt,
+		}
+		return nil
+	})
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	var apiKey *entity.APIKey
+	err := r.db.run(func(t *memoryTables) error {
+		for _, existing := range t.apiKeys {
+			if existing.KeyHash == keyHash {
+				apiKey = copyMemoryAPIKey(existing)
+				return nil
+			}
+		}
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get api key: api key is not found"))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	apiKeys := []*entity.APIKey{}
+	err := r.db.run(func(t *memoryTables) error {
+		for _, existing := range t.apiKeys {
+			apiKeys = append(apiKeys, copyMemoryAPIKey(existing))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the newest first
+	slices.SortFunc(apiKeys, func(a, b *entity.APIKey) int {
+		if result := b.CreatedAt.Compare(a.CreatedAt); result != 0 {
+			return result
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return apiKeys, nil
+}
+
+func (r *memoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		apiKey, ok := t.apiKeys[id]
+		if !ok || apiKey.RevokedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo revoke api key: active api key '%s' is not found", id))
+		}
+
+		apiKey.RevokedAt = &revokedAt
+		t.apiKeys[id] = apiKey
+		return nil
+	})
+}
+
+func (r *memoryAPIKeyRepository) UpdateAPIKeyLastUsedAt(ctx context.Context, id string, usedAt, notUsedSince time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		if apiKey, ok := t.apiKeys[id]; ok && (apiKey.LastUsedAt == nil || apiKey.LastUsedAt.Before(notUsedSince)) {
+			apiKey.LastUsedAt = &usedAt
+			t.apiKeys[id] = apiKey
+		}
+		return nil
+	})
+}
+
+// copyMemoryAPIKey copies the stored api key, so the caller can not change it
+func copyMemoryAPIKey(apiKey entity.APIKey) *entity.APIKey {
+	apiKey.Scopes = slices.Clone(apiKey.Scopes)
+	apiKey.ExpiredAt = timePtr(apiKey.ExpiredAt)
+	apiKey.LastUsedAt = timePtr(apiKey.LastUsedAt)
+	apiKey.RevokedAt = timePtr(apiKey.RevokedAt)
+	return &apiKey
+}
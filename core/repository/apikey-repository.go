@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type APIKeyRepositoryInterface interface {
+	InsertAPIKey(ctx context.Context, apiKey *entity.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	GetAPIKeys(ctx context.Context) ([]*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error
+	// UpdateAPIKeyLastUsedAt only updates the key that is not used since the time, so a busy key is not updated on every request
+	UpdateAPIKeyLastUsedAt(ctx context.Context, id string, usedAt, notUsedSince time.Time) error
+}
+
+type apiKeyRepository struct {
+	db DBTX
+}
+
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepositoryInterface {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, created_by, expired_at, last_used_at, revoked_at, created_at`
+
+func (r *apiKeyRepository) InsertAPIKey(ctx context.Context, apiKey *entity.APIKey) error {
+	query := `INSERT INTO api_keys (id, name, prefix, key_hash, scopes, created_by, expired_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	var expiredAt sql.NullTime
+	if apiKey.ExpiredAt != nil {
+		expiredAt = sql.NullTime{Time: *apiKey.ExpiredAt, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, query, apiKey.Id, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, pq.Array(apiKey.Scopes),
+		apiKey.CreatedBy, expiredAt, apiKey.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error repo insert api key: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	apiKey, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get api key: api key is not found"))
+		}
+		return nil, fmt.Errorf("error repo get api key: %v", err.Error())
+	}
+
+	return apiKey, nil
+}
+
+func (r *apiKeyRepository) GetAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error repo get api keys: %v", err.Error())
+	}
+	defer rows.Close()
+
+	apiKeys := []*entity.APIKey{}
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("error repo get api keys: %v", err.Error())
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo get api keys: %v", err.Error())
+	}
+
+	return apiKeys, nil
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	query := `UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, revokedAt, id)
+	if err != nil {
+		return fmt.Errorf("error repo revoke api key: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo revoke api key: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo revoke api key: active api key '%s' is not found", id))
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) UpdateAPIKeyLastUsedAt(ctx context.Context, id string, usedAt, notUsedSince time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $1 WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3)`
+
+	_, err := r.db.ExecContext(ctx, query, usedAt, id, notUsedSince)
+	if err != nil {
+		return fmt.Errorf("error repo update api key last used at: %v", err.Error())
+	}
+
+	return nil
+}
+
+// scanAPIKey scans the api key columns with the scan function of a row or rows
+func scanAPIKey(scan func(dest ...any) error) (*entity.APIKey, error) {
+	apiKey := &entity.APIKey{}
+	var expiredAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := scan(&apiKey.Id, &apiKey.Name, &apiKey.Prefix, &apiKey.KeyHash, pq.Array(&apiKey.Scopes),
+		&apiKey.CreatedBy, &expiredAt, &lastUsedAt, &revokedAt, &apiKey.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiredAt.Valid {
+		apiKey.ExpiredAt = &expiredAt.Time
+	}
+	if lastUsedAt.Valid {
+		apiKey.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		apiKey.RevokedAt = &revokedAt.Time
+	}
+
+	return apiKey, nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RevokeSession adds the session to the revocation list, the expiration only needs to cover the lifetime of access token
+func (r *redisRepository) RevokeSession(ctx context.Context, sessionId string, expiration time.Duration) error {
+	if err := r.redisClient.Set(ctx, generateRevokedSessionKey(sessionId), 1, expiration).Err(); err != nil {
+		return fmt.Errorf("error cache repo revoke session: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *redisRepository) IsSessionRevoked(ctx context.Context, sessionId string) (bool, error) {
+	count, err := r.redisClient.Exists(ctx, generateRevokedSessionKey(sessionId)).Result()
+	if err != nil {
+		return false, fmt.Errorf("error cache repo check revoked session: %v", err.Error())
+	}
+
+	return count > 0, nil
+}
+
+func generateRevokedSessionKey(sessionId string) string {
+	return fmt.Sprintf("revoked-session:%s", sessionId)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+type memoryAuthRepository struct {
+	db memoryDB
+}
+
+func NewMemoryAuthRepository(store *MemoryStore) AuthRepositoryInterface {
+	return &memoryAuthRepository{
+		db: store,
+	}
+}
+
+func (r *memoryAuthRepository) InsertSession(ctx context.Context, session *entity.Session) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.sessions[session.Id]; ok {
+			return fmt.Errorf("error repo insert session: session id '%s' already exists", session.Id)
+		}
+		if _, ok := t.users[session.UserId]; !ok {
+			return fmt.Errorf("error repo insert session: user '%s' is not found", session.UserId)
+		}
+
+		t.sessions[session.Id] = entity.Session{Id: session.Id, UserId: session.UserId, CreatedAt: session.CreatedAt}
+		return nil
+	})
+}
+
+func (r *memoryAuthRepository) GetSessionById(ctx context.Context, id string) (*entity.Session, error) {
+	var session entity.Session
+	err := r.db.run(func(t *memoryTables) error {
+		existing, ok := t.sessions[id]
+		if !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get session: session id '%s' is not found", id))
+		}
+		session = existing
+		session.RevokedAt = timePtr(existing.RevokedAt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// RevokeSession revokes the session, the revoked time of already revoked session is kept
+func (r *memoryAuthRepository) RevokeSession(ctx context.Context, id string, revokedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		if session, ok := t.sessions[id]; ok && session.RevokedAt == nil {
+			session.RevokedAt = &revokedAt
+			t.sessions[id] = session
+		}
+		return nil
+	})
+}
+
+func (r *memoryAuthRepository) InsertRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.refreshTokens[token.Id]; ok {
+			return fmt.Errorf("error repo insert refresh token: refresh token id '%s' already exists", token.Id)
+		}
+		for _, existing := range t.refreshTokens {
+			if existing.TokenHash == token.TokenHash {
+				return fmt.Errorf("error repo insert refresh token: refresh token hash already exists")
+			}
+		}
+		if _, ok := t.sessions[token.SessionId]; !ok {
+			return fmt.Errorf("error repo insert refresh token: session '%s' is not found", token.SessionId)
+		}
+
+		t.refreshTokens[token.Id] = entity.RefreshToken{Id: token.Id, SessionId: token.SessionId, TokenHash: token.TokenHash,
+			ExpiredAt: token.ExpiredAt, CreatedAt: token.CreatedAt}
+		return nil
+	})
+}
+
+func (r *memoryAuthRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	var token *entity.RefreshToken
+	err := r.db.run(func(t *memoryTables) error {
+		for _, existing := range t.refreshTokens {
+			if existing.TokenHash == tokenHash {
+				token = &existing
+				token.UsedAt = timePtr(existing.UsedAt)
+				return nil
+			}
+		}
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get refresh token: refresh token is not found"))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// UseRefreshToken marks the refresh token as used, it returns unauthorized error if the token is already used,
+// e.g. by concurrent refresh with the same token
+func (r *memoryAuthRepository) UseRefreshToken(ctx context.Context, id string, usedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		token, ok := t.refreshTokens[id]
+		if !ok || token.UsedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrUnauthorized, fmt.Errorf("error repo use refresh token: refresh token '%s' is already used", id))
+		}
+
+		token.UsedAt = &usedAt
+		t.refreshTokens[id] = token
+		return nil
+	})
+}
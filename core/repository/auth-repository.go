@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+type AuthRepositoryInterface interface {
+	// session
+	InsertSession(ctx context.Context, session *entity.Session) error
+	GetSessionById(ctx context.Context, id string) (*entity.Session, error)
+	RevokeSession(ctx context.Context, id string, revokedAt time.Time) error
+
+	// refresh_token
+	InsertRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	UseRefreshToken(ctx context.Context, id string, usedAt time.Time) error
+}
+
+type authRepository struct {
+	db DBTX
+}
+
+func NewAuthRepository(db *sql.DB) AuthRepositoryInterface {
+	return &authRepository{
+		db: db,
+	}
+}
+
+func (r *authRepository) InsertSession(ctx context.Context, session *entity.Session) error {
+	query := `INSERT INTO user_sessions (id, user_id, created_at) VALUES ($1, $2, $3)`
+
+	_, err := r.db.ExecContext(ctx, query, session.Id, session.UserId, session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error repo insert session: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *authRepository) GetSessionById(ctx context.Context, id string) (*entity.Session, error) {
+	query := `SELECT id, user_id, revoked_at, created_at FROM user_sessions WHERE id = $1`
+
+	session := &entity.Session{}
+	var revokedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&session.Id, &session.UserId, &revokedAt, &session.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get session: session id '%s' is not found", id))
+		}
+		return nil, fmt.Errorf("error repo get session: %v", err.Error())
+	}
+
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return session, nil
+}
+
+// RevokeSession revokes the session, the revoked time of already revoked session is kept
+func (r *authRepository) RevokeSession(ctx context.Context, id string, revokedAt time.Time) error {
+	query := `UPDATE user_sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, revokedAt, id)
+	if err != nil {
+		return fmt.Errorf("error repo revoke session: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *authRepository) InsertRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, session_id, token_hash, expired_at, created_at) VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.db.ExecContext(ctx, query, token.Id, token.SessionId, token.TokenHash, token.ExpiredAt, token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error repo insert refresh token: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *authRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	query := `SELECT id, session_id, token_hash, expired_at, used_at, created_at FROM refresh_tokens WHERE token_hash = $1`
+
+	token := &entity.RefreshToken{}
+	var usedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&token.Id, &token.SessionId, &token.TokenHash, &token.ExpiredAt, &usedAt, &token.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get refresh token: refresh token is not found"))
+		}
+		return nil, fmt.Errorf("error repo get refresh token: %v", err.Error())
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return token, nil
+}
+
+// UseRefreshToken marks the refresh token as used, it returns unauthorized error if the token is already used,
+// e.g. by concurrent refresh with the same token
+func (r *authRepository) UseRefreshToken(ctx context.Context, id string, usedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("error repo use refresh token: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo use refresh token: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrUnauthorized, fmt.Errorf("error repo use refresh token: refresh token '%s' is already used", id))
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"strings"
+)
+
+type memoryCatalogRepository struct {
+	db memoryDB
+}
+
+func NewMemoryCatalogRepository(store *MemoryStore) CatalogRepositoryInterface {
+	return &memoryCatalogRepository{
+		db: store,
+	}
+}
+
+// GetProductBySku gets the product by the sku, the imports of the same sku are serialized by the lock of the unit of work
+func (r *memoryCatalogRepository) GetProductBySku(ctx context.Context, sku string) (*entity.Product, error) {
+	var product entity.Product
+	err := r.db.run(func(t *memoryTables) error {
+		for _, existing := range t.products {
+			if existing.Sku != "" && existing.Sku == sku {
+				product = existing
+				return nil
+			}
+		}
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get product by sku: sku '%s' is not found", sku))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+func (r *memoryCatalogRepository) UpdateProduct(ctx context.Context, product *entity.Product) error {
+	return r.db.run(func(t *memoryTables) error {
+		existing, ok := t.products[product.Id]
+		if !ok {
+			return nil
+		}
+		for _, other := range t.products {
+			if other.Id != product.Id && other.Name == product.Name {
+				return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo update product: name '%s' is used by another product", product.Name))
+			}
+		}
+
+		existing.Name, existing.Price = product.Name, product.Price
+		t.products[product.Id] = existing
+		return nil
+	})
+}
+
+// SetProductWarehouseStock sets total stock of the product in the warehouse, unlike InsertProductWarehouse that adds it
+func (r *memoryCatalogRepository) SetProductWarehouseStock(ctx context.Context, pw *entity.ProductWarehouse) error {
+	return r.db.run(func(t *memoryTables) error {
+		if err := checkMemoryProductWarehouse(t, pw); err != nil {
+			return fmt.Errorf("error repo set product warehouse stock: %v", err.Error())
+		}
+
+		t.productWarehouses[productWarehouseKey{pw.ProductId, pw.WarehouseId}] = pw.TotalStock
+		return nil
+	})
+}
+
+func (r *memoryCatalogRepository) InsertImportJob(ctx context.Context, job *entity.CatalogImportJob) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.importJobs[job.Id]; ok {
+			return fmt.Errorf("error repo insert import job: job id '%s' already exists", job.Id)
+		}
+
+		t.importJobs[job.Id] = *copyMemoryImportJob(*job)
+		return nil
+	})
+}
+
+func (r *memoryCatalogRepository) UpdateImportJob(ctx context.Context, job *entity.CatalogImportJob) error {
+	return r.db.run(func(t *memoryTables) error {
+		existing, ok := t.importJobs[job.Id]
+		if !ok {
+			return nil
+		}
+
+		// the format, the total rows, the actor and the created time are not updated
+		updated := *copyMemoryImportJob(*job)
+		updated.Format, updated.TotalRows, updated.ActorId, updated.CreatedAt = existing.Format, existing.TotalRows, existing.ActorId, existing.CreatedAt
+		t.importJobs[job.Id] = updated
+		return nil
+	})
+}
+
+func (r *memoryCatalogRepository) GetImportJobById(ctx context.Context, id string) (*entity.CatalogImportJob, error) {
+	var job *entity.CatalogImportJob
+	err := r.db.run(func(t *memoryTables) error {
+		existing, ok := t.importJobs[id]
+		if !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get import job: job id '%s' is not found", id))
+		}
+		job = copyMemoryImportJob(existing)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// copyMemoryImportJob copies the job with its errors, nil errors are stored as empty like the json column.
+// The dry run is not a column, so it is not stored.
+func copyMemoryImportJob(job entity.CatalogImportJob) *entity.CatalogImportJob {
+	rowErrors := make([]*entity.CatalogRowError, len(job.Errors))
+	for i, rowError := range job.Errors {
+		copied := *rowError
+		rowErrors[i] = &copied
+	}
+	job.Errors = rowErrors
+	job.DryRun = false
+	return &job
+}
+
+// IterateCatalog calls fn for each product stock in a warehouse, fn is called after the rows are read,
+// so a slow writer does not hold the store
+func (r *memoryCatalogRepository) IterateCatalog(ctx context.Context, fn func(row *entity.CatalogExportRow) error) error {
+	var rows []*entity.CatalogExportRow
+	err := r.db.run(func(t *memoryTables) error {
+		for _, product := range t.products {
+			stocked := false
+			for key, totalStock := range t.productWarehouses {
+				if key.productId == product.Id {
+					stocked = true
+					rows = append(rows, &entity.CatalogExportRow{ProductId: product.Id, Sku: product.Sku, Name: product.Name,
+						Price: product.Price, WarehouseId: key.warehouseId, TotalStock: totalStock})
+				}
+			}
+			// a product without stock is still exported, like the left join
+			if !stocked {
+				rows = append(rows, &entity.CatalogExportRow{ProductId: product.Id, Sku: product.Sku, Name: product.Name, Price: product.Price})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(rows, func(a, b *entity.CatalogExportRow) int {
+		if result := strings.Compare(a.ProductId, b.ProductId); result != 0 {
+			return result
+		}
+		return strings.Compare(a.WarehouseId, b.WarehouseId)
+	})
+
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
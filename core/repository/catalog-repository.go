@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+
+	"github.com/lib/pq"
+)
+
+type CatalogRepositoryInterface interface {
+	// product
+	GetProductBySku(ctx context.Context, sku string) (*entity.Product, error)
+	UpdateProduct(ctx context.Context, product *entity.Product) error
+	SetProductWarehouseStock(ctx context.Context, pw *entity.ProductWarehouse) error
+
+	// catalog_import_job
+	InsertImportJob(ctx context.Context, job *entity.CatalogImportJob) error
+	UpdateImportJob(ctx context.Context, job *entity.CatalogImportJob) error
+	GetImportJobById(ctx context.Context, id string) (*entity.CatalogImportJob, error)
+
+	// export
+	IterateCatalog(ctx context.Context, fn func(row *entity.CatalogExportRow) error) error
+}
+
+type catalogRepository struct {
+	db DBTX
+}
+
+func NewCatalogRepository(db *sql.DB) CatalogRepositoryInterface {
+	return &catalogRepository{
+		db: db,
+	}
+}
+
+// GetProductBySku locks the product row until the transaction ends, so concurrent imports of the same sku are serialized
+func (r *catalogRepository) GetProductBySku(ctx context.Context, sku string) (*entity.Product, error) {
+	query := `SELECT id, sku, name, price FROM products WHERE sku = $1 FOR UPDATE`
+
+	product := &entity.Product{}
+
+	err := r.db.QueryRowContext(ctx, query, sku).Scan(&product.Id, &product.Sku, &product.Name, &product.Price)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get product by sku: sku '%s' is not found", sku))
+		}
+		return nil, fmt.Errorf("error repo get product by sku: %v", err.Error())
+	}
+
+	return product, nil
+}
+
+func (r *catalogRepository) UpdateProduct(ctx context.Context, product *entity.Product) error {
+	query := `UPDATE products SET name = $1, price = $2 WHERE id = $3`
+
+	_, err := r.db.ExecContext(ctx, query, product.Name, product.Price, product.Id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationErrorCode {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo update product: name '%s' is used by another product", product.Name))
+		}
+		return fmt.Errorf("error repo update product: %v", err.Error())
+	}
+
+	return nil
+}
+
+// SetProductWarehouseStock sets total stock of the product in the warehouse, unlike InsertProductWarehouse that adds it
+func (r *catalogRepository) SetProductWarehouseStock(ctx context.Context, pw *entity.ProductWarehouse) error {
+	query := `INSERT INTO product_warehouses (product_id, warehouse_id, total_stock)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (product_id, warehouse_id)
+				DO UPDATE SET total_stock = EXCLUDED.total_stock`
+
+	_, err := r.db.ExecContext(ctx, query, pw.ProductId, pw.WarehouseId, pw.TotalStock)
+	if err != nil {
+		return fmt.Errorf("error repo set product warehouse stock: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *catalogRepository) InsertImportJob(ctx context.Context, job *entity.CatalogImportJob) error {
+	query := `INSERT INTO catalog_import_jobs (id, status, format, total_rows, processed_rows, succeeded_rows, failed_rows, errors, actor_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	rowErrors, err := marshalCatalogRowErrors(job.Errors)
+	if err != nil {
+		return fmt.Errorf("error repo insert import job: %v", err.Error())
+	}
+	actorId := sql.NullString{String: job.ActorId, Valid: job.ActorId != ""}
+
+	_, err = r.db.ExecContext(ctx, query, job.Id, job.Status, job.Format, job.TotalRows, job.ProcessedRows, job.SucceededRows, job.FailedRows, rowErrors, actorId, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error repo insert import job: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *catalogRepository) UpdateImportJob(ctx context.Context, job *entity.CatalogImportJob) error {
+	query := `UPDATE catalog_import_jobs
+				SET status = $1, processed_rows = $2, succeeded_rows = $3, failed_rows = $4, errors = $5, updated_at = $6
+				WHERE id = $7`
+
+	rowErrors, err := marshalCatalogRowErrors(job.Errors)
+	if err != nil {
+		return fmt.Errorf("error repo update import job: %v", err.Error())
+	}
+
+	_, err = r.db.ExecContext(ctx, query, job.Status, job.ProcessedRows, job.SucceededRows, job.FailedRows, rowErrors, job.UpdatedAt, job.Id)
+	if err != nil {
+		return fmt.Errorf("error repo update import job: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *catalogRepository) GetImportJobById(ctx context.Context, id string) (*entity.CatalogImportJob, error) {
+	query := `SELECT id, status, format, total_rows, processed_rows, succeeded_rows, failed_rows, errors, actor_id, created_at, updated_at
+				FROM catalog_import_jobs
+				WHERE id = $1`
+
+	job := &entity.CatalogImportJob{}
+	var rowErrors []byte
+	var actorId sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&job.Id, &job.Status, &job.Format, &job.TotalRows, &job.ProcessedRows, &job.SucceededRows, &job.FailedRows, &rowErrors, &actorId, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get import job: job id '%s' is not found", id))
+		}
+		return nil, fmt.Errorf("error repo get import job: %v", err.Error())
+	}
+
+	if err := json.Unmarshal(rowErrors, &job.Errors); err != nil {
+		return nil, fmt.Errorf("error repo get import job in unmarshal errors: %v", err.Error())
+	}
+	job.ActorId = actorId.String
+
+	return job, nil
+}
+
+// marshalCatalogRowErrors marshals the errors into json array, nil errors are marshaled as empty array
+func marshalCatalogRowErrors(rowErrors []*entity.CatalogRowError) ([]byte, error) {
+	if rowErrors == nil {
+		rowErrors = []*entity.CatalogRowError{}
+	}
+	return json.Marshal(rowErrors)
+}
+
+// IterateCatalog calls fn for each product stock in a warehouse without loading the whole catalog into memory
+func (r *catalogRepository) IterateCatalog(ctx context.Context, fn func(row *entity.CatalogExportRow) error) error {
+	query := `SELECT p.id, COALESCE(p.sku, ''), p.name, p.price, COALESCE(pw.warehouse_id, ''), COALESCE(pw.total_stock, 0)
+				FROM products p
+				LEFT JOIN product_warehouses pw
+				ON pw.product_id = p.id
+				ORDER BY p.id, pw.warehouse_id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("error repo iterate catalog: %v", err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &entity.CatalogExportRow{}
+		if err := rows.Scan(&row.ProductId, &row.Sku, &row.Name, &row.Price, &row.WarehouseId, &row.TotalStock); err != nil {
+			return fmt.Errorf("error repo iterate catalog: %v", err.Error())
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error repo iterate catalog: %v", err.Error())
+	}
+
+	return nil
+}
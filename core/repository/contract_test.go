@@ -0,0 +1,709 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	clockutil "mfawzanid/warehouse-commerce/utils/clock"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the contract is the behavior the usecases rely on, the memory repositories must keep it like the postgres and redis ones
+
+// contractOrderExpireTime is short, so the redis run does not wait long for the reservations to expire
+const contractOrderExpireTime = time.Second
+
+// contractRepositories are the repositories under the contract with the time of their storage
+type contractRepositories struct {
+	user        UserRepositoryInterface
+	inventory   InventoryRepositoryInterface
+	transaction TransactionRepositoryInterface
+	redis       RedisRepositoryInterface
+	price       PriceRepositoryInterface
+	privacy     PrivacyRepositoryInterface
+	apiKey      APIKeyRepositoryInterface
+	auth        AuthRepositoryInterface
+
+	now     func() time.Time
+	advance func(d time.Duration) // moves the time of the storage, e.g. so the redis keys expire
+}
+
+func TestMemoryRepositoryContract(t *testing.T) {
+	testRepositoryContract(t, func() *contractRepositories {
+		clock := clockutil.NewFake(time.Now().UTC().Truncate(time.Microsecond))
+		store := NewMemoryStore(clock)
+
+		return &contractRepositories{
+			user:        NewMemoryUserRepository(store),
+			inventory:   NewMemoryInventoryRepository(store),
+			transaction: NewMemoryTransactionRepository(store),
+			redis:       NewMemoryRedisRepository(clock, contractOrderExpireTime),
+			price:       NewMemoryPriceRepository(store),
+			privacy:     NewMemoryPrivacyRepository(store),
+			apiKey:      NewMemoryAPIKeyRepository(store),
+			auth:        NewMemoryAuthRepository(store),
+			now:         clock.Now,
+			advance:     clock.Advance,
+		}
+	})
+}
+
+// TestPostgresRepositoryContract runs the contract against the migrated database and redis of TEST_DATABASE_URL and TEST_REDIS_ADDR,
+// e.g. the ones of docker compose. The database is not cleaned, so the ids and the names are unique per run.
+func TestPostgresRepositoryContract(t *testing.T) {
+	databaseURL, redisAddr := os.Getenv("TEST_DATABASE_URL"), os.Getenv("TEST_REDIS_ADDR")
+	if testing.Short() || databaseURL == "" || redisAddr == "" {
+		t.Skip("Skip postgres contract test, TEST_DATABASE_URL and TEST_REDIS_ADDR are not set")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	redisClient := redis.NewClient(&redis.Options{Addr: redisAddr})
+	t.Cleanup(func() { redisClient.Close() })
+
+	testRepositoryContract(t, func() *contractRepositories {
+		return &contractRepositories{
+			user:        NewUserRepository(db),
+			inventory:   NewInventoryRepository(db),
+			transaction: NewTransactionRepository(db),
+			redis:       NewRedisRepository(redisClient, contractOrderExpireTime),
+			price:       NewPriceRepository(db),
+			privacy:     NewPrivacyRepository(db),
+			apiKey:      NewAPIKeyRepository(db),
+			auth:        NewAuthRepository(db),
+			// the columns are timestamps without time zone, the database is expected to be in utc
+			now:     func() time.Time { return time.Now().UTC().Truncate(time.Microsecond) },
+			advance: time.Sleep,
+		}
+	})
+}
+
+func testRepositoryContract(t *testing.T, newRepos func() *contractRepositories) {
+	t.Run("Inventory", func(t *testing.T) { testInventoryRepositoryContract(t, newRepos) })
+	t.Run("Transaction", func(t *testing.T) { testTransactionRepositoryContract(t, newRepos) })
+	t.Run("User", func(t *testing.T) { testUserRepositoryContract(t, newRepos) })
+	t.Run("Redis", func(t *testing.T) { testRedisRepositoryContract(t, newRepos) })
+	t.Run("Price", func(t *testing.T) { testPriceRepositoryContract(t, newRepos) })
+	t.Run("Privacy", func(t *testing.T) { testPrivacyRepositoryContract(t, newRepos) })
+}
+
+var contractIdSequence atomic.Int64
+
+// newContractId returns an id that is not used by the previous runs, it fits the id columns of 20 characters
+func newContractId(prefix string) string {
+	return fmt.Sprintf("%s%x", prefix, time.Now().UnixNano()+contractIdSequence.Add(1))
+}
+
+func testInventoryRepositoryContract(t *testing.T, newRepos func() *contractRepositories) {
+	ctx := context.Background()
+
+	t.Run("InsertWarehouse_name is used_then return unique violation", func(t *testing.T) {
+		repos := newRepos()
+		name := newContractId("W-")
+		require.NoError(t, repos.inventory.InsertWarehouse(ctx, &entity.Warehouse{Id: newContractId("W"), Name: name, Enabled: true}))
+
+		err := repos.inventory.InsertWarehouse(ctx, &entity.Warehouse{Id: newContractId("W"), Name: name, Enabled: true})
+
+		assert.True(t, errors.Is(err, errorutil.ErrUniqueViolation))
+	})
+	t.Run("GetWarehouses_filter by ids and status_then return the matched warehouses with total", func(t *testing.T) {
+		repos := newRepos()
+		enabled := &entity.Warehouse{Id: newContractId("W"), Name: newContractId("W-"), Enabled: true}
+		disabled := &entity.Warehouse{Id: newContractId("W"), Name: newContractId("W-"), Enabled: true}
+		require.NoError(t, repos.inventory.InsertWarehouse(ctx, enabled))
+		require.NoError(t, repos.inventory.InsertWarehouse(ctx, disabled))
+		require.NoError(t, repos.inventory.UpdateWarehouseStatus(ctx, &entity.UpdateWarehouseStatusRequest{Id: disabled.Id, Enabled: false}))
+
+		isEnabled := false
+		res, err := repos.inventory.GetWarehouses(ctx, &entity.GetWarehousesRequest{
+			Ids:        []string{enabled.Id, disabled.Id},
+			Enabled:    &isEnabled,
+			Pagination: &entity.Pagination{Page: 1, PageSize: 10},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.Warehouse{{Id: disabled.Id, Name: disabled.Name, Enabled: false}}, res.Warehouses)
+		assert.Equal(t, 1, res.Pagination.Total)
+	})
+	t.Run("GetShops_filter by name_then return the shop without owner", func(t *testing.T) {
+		repos := newRepos()
+		shop := &entity.Shop{Id: newContractId("S"), Name: newContractId("S-")}
+		require.NoError(t, repos.inventory.InsertShop(ctx, shop))
+
+		res, err := repos.inventory.GetShops(ctx, &entity.GetShopsRequest{Name: shop.Name})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.Shop{{Id: shop.Id, Name: shop.Name}}, res.Shops)
+	})
+	t.Run("InsertProduct_name is used_then return unique violation", func(t *testing.T) {
+		repos := newRepos()
+		name := newContractId("P-")
+		require.NoError(t, repos.inventory.InsertProduct(ctx, &entity.Product{Id: newContractId("P"), Name: name, Price: 1000}))
+
+		err := repos.inventory.InsertProduct(ctx, &entity.Product{Id: newContractId("P"), Name: name, Price: 2000})
+
+		assert.True(t, errors.Is(err, errorutil.ErrUniqueViolation))
+		assert.True(t, errors.Is(err, entity.ErrProductNameIsUsed))
+	})
+	t.Run("InsertProduct_sku is used_then return unique violation of the sku", func(t *testing.T) {
+		repos := newRepos()
+		sku := newContractId("SKU-")
+		require.NoError(t, repos.inventory.InsertProduct(ctx, &entity.Product{Id: newContractId("P"), Sku: sku, Name: newContractId("P-"), Price: 1000}))
+
+		err := repos.inventory.InsertProduct(ctx, &entity.Product{Id: newContractId("P"), Sku: sku, Name: newContractId("P-"), Price: 2000})
+
+		assert.True(t, errors.Is(err, errorutil.ErrUniqueViolation))
+		assert.True(t, errors.Is(err, entity.ErrProductSkuIsUsed))
+	})
+	t.Run("GetProductByName_product is not found_then return not found error", func(t *testing.T) {
+		repos := newRepos()
+
+		product, err := repos.inventory.GetProductByName(ctx, newContractId("P-"))
+
+		assert.Nil(t, product)
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+	})
+	t.Run("InsertProductWarehouse_stock is inserted twice_then add the stock", func(t *testing.T) {
+		repos := newRepos()
+		product := &entity.Product{Id: newContractId("P"), Name: newContractId("P-"), Price: 1000}
+		warehouse := &entity.Warehouse{Id: newContractId("W"), Name: newContractId("W-"), Enabled: true}
+		require.NoError(t, repos.inventory.InsertProduct(ctx, product))
+		require.NoError(t, repos.inventory.InsertWarehouse(ctx, warehouse))
+
+		assert.NoError(t, repos.inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{ProductId: product.Id, WarehouseId: warehouse.Id, TotalStock: 10}))
+		assert.NoError(t, repos.inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{ProductId: product.Id, WarehouseId: warehouse.Id, TotalStock: 5}))
+
+		pws, err := repos.inventory.GetProductWarehousesByQuery(ctx, &entity.GetProductWarehousesByQueryRequest{ProductIds: []string{product.Id}})
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.ProductWarehouse{{ProductId: product.Id, WarehouseId: warehouse.Id, TotalStock: 15}}, pws)
+	})
+	t.Run("InsertProductWarehouse_warehouse is not found_then return error", func(t *testing.T) {
+		repos := newRepos()
+		product := &entity.Product{Id: newContractId("P"), Name: newContractId("P-"), Price: 1000}
+		require.NoError(t, repos.inventory.InsertProduct(ctx, product))
+
+		err := repos.inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{ProductId: product.Id, WarehouseId: newContractId("W"), TotalStock: 10})
+
+		assert.Error(t, err)
+	})
+	t.Run("UpdateProductWarehouseTotalStock_stock exists_then set the stock", func(t *testing.T) {
+		repos := newRepos()
+		product := &entity.Product{Id: newContractId("P"), Name: newContractId("P-"), Price: 1000}
+		warehouse := &entity.Warehouse{Id: newContractId("W"), Name: newContractId("W-"), Enabled: true}
+		require.NoError(t, repos.inventory.InsertProduct(ctx, product))
+		require.NoError(t, repos.inventory.InsertWarehouse(ctx, warehouse))
+		require.NoError(t, repos.inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{ProductId: product.Id, WarehouseId: warehouse.Id, TotalStock: 10}))
+
+		err := repos.inventory.UpdateProductWarehouseTotalStock(ctx, &entity.UpdateProductWarehouseTotalStockRequest{ProductId: product.Id, WarehouseId: warehouse.Id, TotalStock: 3})
+
+		assert.NoError(t, err)
+		pws, err := repos.inventory.GetProductWarehousesByQuery(ctx, &entity.GetProductWarehousesByQueryRequest{ProductIds: []string{product.Id}, WarehouseIds: []string{warehouse.Id}})
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.ProductWarehouse{{ProductId: product.Id, WarehouseId: warehouse.Id, TotalStock: 3}}, pws)
+	})
+	t.Run("GetProductDetailsByShopId_warehouse of the shop is disabled_then return the stock of the enabled warehouse only", func(t *testing.T) {
+		repos := newRepos()
+		shop := &entity.Shop{Id: newContractId("S"), Name: newContractId("S-")}
+		product := &entity.Product{Id: newContractId("P"), Name: newContractId("P-"), Price: 1000}
+		enabled := &entity.Warehouse{Id: newContractId("W"), Name: newContractId("W-"), Enabled: true}
+		disabled := &entity.Warehouse{Id: newContractId("W"), Name: newContractId("W-"), Enabled: true}
+		require.NoError(t, repos.inventory.InsertShop(ctx, shop))
+		require.NoError(t, repos.inventory.InsertProduct(ctx, product))
+		for _, warehouse := range []*entity.Warehouse{enabled, disabled} {
+			require.NoError(t, repos.inventory.InsertWarehouse(ctx, warehouse))
+			require.NoError(t, repos.inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{ProductId: product.Id, WarehouseId: warehouse.Id, TotalStock: 10}))
+		}
+		require.NoError(t, repos.inventory.InsertShopWarehouses(ctx, &entity.UpsertShopToWarehousesRequest{ShopId: shop.Id, WarehouseIds: []string{enabled.Id}, Enabled: true}))
+		require.NoError(t, repos.inventory.InsertShopWarehouses(ctx, &entity.UpsertShopToWarehousesRequest{ShopId: shop.Id, WarehouseIds: []string{disabled.Id}, Enabled: false}))
+
+		res, err := repos.inventory.GetProductDetailsByShopId(ctx, &entity.GetProductDetailsByShopIdRequest{
+			ShopId:     shop.Id,
+			Pagination: &entity.Pagination{Page: 1, PageSize: 10},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.ProductDetail{{ProductId: product.Id, Name: product.Name, Price: product.Price, TotalStock: 10, WarehouseId: enabled.Id}}, res.ProductDetails)
+		assert.Equal(t, 1, res.Pagination.Total)
+	})
+	t.Run("GetProductDetailsByShopId_shop override and price list are set_then sort and filter by the price that is sold", func(t *testing.T) {
+		repos := newRepos()
+		now := repos.now()
+		shop := &entity.Shop{Id: newContractId("S"), Name: newContractId("S-")}
+		warehouse := &entity.Warehouse{Id: newContractId("W"), Name: newContractId("W-"), Enabled: true}
+		require.NoError(t, repos.inventory.InsertShop(ctx, shop))
+		require.NoError(t, repos.inventory.InsertWarehouse(ctx, warehouse))
+		require.NoError(t, repos.inventory.InsertShopWarehouses(ctx, &entity.UpsertShopToWarehousesRequest{ShopId: shop.Id, WarehouseIds: []string{warehouse.Id}, Enabled: true}))
+
+		// base prices are 1000, 2000 and 3000, the shop and the price list make them 2500, 2000 and 500
+		var products []*entity.Product
+		for _, price := range []int{1000, 2000, 3000} {
+			product := &entity.Product{Id: newContractId("P"), Name: newContractId("P-"), Price: price}
+			require.NoError(t, repos.inventory.InsertProduct(ctx, product))
+			require.NoError(t, repos.inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{ProductId: product.Id, WarehouseId: warehouse.Id, TotalStock: 1}))
+			products = append(products, product)
+		}
+		require.NoError(t, repos.price.UpsertShopProductPrice(ctx, &entity.ShopProductPrice{ShopId: shop.Id, ProductId: products[0].Id, Price: 2500}))
+		priceList := &entity.PriceList{Id: newContractId("PL"), ShopId: shop.Id, Name: "sale", StartAt: now.Add(-time.Hour)}
+		require.NoError(t, repos.price.InsertPriceList(ctx, priceList))
+		require.NoError(t, repos.price.InsertPriceListItems(ctx, []*entity.PriceListItem{{PriceListId: priceList.Id, ProductId: products[2].Id, Price: 500}}))
+
+		res, err := repos.inventory.GetProductDetailsByShopId(ctx, &entity.GetProductDetailsByShopIdRequest{
+			ShopId:     shop.Id,
+			At:         now,
+			Filters:    []*entity.Filter{{Field: "price", Operator: entity.FilterOperatorLte, Values: []string{"2000"}}},
+			Sorts:      []*entity.Sort{{Field: "price", Direction: entity.SortDirectionAsc}},
+			Pagination: &entity.Pagination{Page: 1, PageSize: 10},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.ProductDetail{
+			{ProductId: products[2].Id, Name: products[2].Name, Price: 500, TotalStock: 1, WarehouseId: warehouse.Id},
+			{ProductId: products[1].Id, Name: products[1].Name, Price: 2000, TotalStock: 1, WarehouseId: warehouse.Id},
+		}, res.ProductDetails)
+		assert.Equal(t, 2, res.Pagination.Total)
+	})
+}
+
+func testTransactionRepositoryContract(t *testing.T, newRepos func() *contractRepositories) {
+	ctx := context.Background()
+
+	// the margin of the expiration covers the time zone of the database
+	const expirationMargin = 48 * time.Hour
+
+	// insertOrderOwner inserts the user and the shop of the orders, like the foreign keys need
+	insertOrderOwner := func(t *testing.T, repos *contractRepositories) (userId, shopId string) {
+		userId, shopId = newContractId("U"), newContractId("S")
+		require.NoError(t, repos.user.InsertUser(ctx, &entity.User{Id: userId, Email: userId + "@mail.com", CreatedAt: repos.now(), UpdatedAt: repos.now()}))
+		require.NoError(t, repos.inventory.InsertShop(ctx, &entity.Shop{Id: shopId, Name: newContractId("S-")}))
+		return userId, shopId
+	}
+	newOrder := func(repos *contractRepositories, userId, shopId, status string, expiredIn time.Duration) *entity.Order {
+		return &entity.Order{
+			Id:        newContractId("O"),
+			UserId:    userId,
+			ShopId:    shopId,
+			Amount:    3000,
+			Status:    status,
+			CreatedAt: repos.now(),
+			ExpiredAt: repos.now().Add(expiredIn),
+		}
+	}
+
+	t.Run("GetOrderById_order is active or expired_then return the order by the activeness", func(t *testing.T) {
+		repos := newRepos()
+		userId, shopId := insertOrderOwner(t, repos)
+		active := newOrder(repos, userId, shopId, entity.OrderStatusPending, expirationMargin)
+		expired := newOrder(repos, userId, shopId, entity.OrderStatusPending, -expirationMargin)
+		require.NoError(t, repos.transaction.InsertOrder(ctx, active))
+		require.NoError(t, repos.transaction.InsertOrder(ctx, expired))
+
+		isActive, isNotActive := true, false
+
+		order, err := repos.transaction.GetOrderById(ctx, active.Id, &isActive)
+		assert.NoError(t, err)
+		assert.Equal(t, active.Id, order.Id)
+		assert.Equal(t, active.Amount, order.Amount)
+		assert.True(t, active.ExpiredAt.Equal(order.ExpiredAt))
+
+		_, err = repos.transaction.GetOrderById(ctx, expired.Id, &isActive)
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+
+		order, err = repos.transaction.GetOrderById(ctx, expired.Id, &isNotActive)
+		assert.NoError(t, err)
+		assert.Equal(t, expired.Id, order.Id)
+
+		order, err = repos.transaction.GetOrderById(ctx, expired.Id, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, expired.Id, order.Id)
+	})
+	t.Run("UpdateOrder_status is changed_then get the order with the status", func(t *testing.T) {
+		repos := newRepos()
+		userId, shopId := insertOrderOwner(t, repos)
+		order := newOrder(repos, userId, shopId, entity.OrderStatusPending, expirationMargin)
+		require.NoError(t, repos.transaction.InsertOrder(ctx, order))
+
+		err := repos.transaction.UpdateOrder(ctx, &entity.UpdateOrderRequest{OrderId: order.Id, Status: entity.OrderStatusSucceeded})
+
+		assert.NoError(t, err)
+		updated, err := repos.transaction.GetOrderById(ctx, order.Id, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.OrderStatusSucceeded, updated.Status)
+	})
+	t.Run("CountPendingOrders_orders are paid or expired_then count the active pending orders only", func(t *testing.T) {
+		repos := newRepos()
+		userId, shopId := insertOrderOwner(t, repos)
+		require.NoError(t, repos.transaction.InsertOrder(ctx, newOrder(repos, userId, shopId, entity.OrderStatusPending, expirationMargin)))
+		require.NoError(t, repos.transaction.InsertOrder(ctx, newOrder(repos, userId, shopId, entity.OrderStatusSucceeded, expirationMargin)))
+		require.NoError(t, repos.transaction.InsertOrder(ctx, newOrder(repos, userId, shopId, entity.OrderStatusPending, -expirationMargin)))
+
+		count, err := repos.transaction.CountPendingOrders(ctx, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+	t.Run("CountExpiredOrders_pending order is expired_then count it", func(t *testing.T) {
+		repos := newRepos()
+		userId, shopId := insertOrderOwner(t, repos)
+		// the count is of all orders, so only the change is asserted
+		before, err := repos.transaction.CountExpiredOrders(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, repos.transaction.InsertOrder(ctx, newOrder(repos, userId, shopId, entity.OrderStatusPending, -expirationMargin)))
+		require.NoError(t, repos.transaction.InsertOrder(ctx, newOrder(repos, userId, shopId, entity.OrderStatusSucceeded, -expirationMargin)))
+		require.NoError(t, repos.transaction.InsertOrder(ctx, newOrder(repos, userId, shopId, entity.OrderStatusPending, expirationMargin)))
+
+		after, err := repos.transaction.CountExpiredOrders(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, before+1, after)
+	})
+	t.Run("InsertOrderItems_items are inserted_then get the items of the order", func(t *testing.T) {
+		repos := newRepos()
+		userId, shopId := insertOrderOwner(t, repos)
+		order := newOrder(repos, userId, shopId, entity.OrderStatusPending, expirationMargin)
+		product := &entity.Product{Id: newContractId("P"), Name: newContractId("P-"), Price: 1000}
+		warehouse := &entity.Warehouse{Id: newContractId("W"), Name: newContractId("W-"), Enabled: true}
+		require.NoError(t, repos.transaction.InsertOrder(ctx, order))
+		require.NoError(t, repos.inventory.InsertProduct(ctx, product))
+		require.NoError(t, repos.inventory.InsertWarehouse(ctx, warehouse))
+		item := &entity.OrderItem{OrderId: order.Id, ProductId: product.Id, ShopId: shopId, WarehouseId: warehouse.Id, Quantity: 3, UnitPrice: 1000, PriceSource: entity.PriceSourceBase}
+
+		err := repos.transaction.InsertOrderItems(ctx, []*entity.OrderItem{item})
+
+		assert.NoError(t, err)
+		items, err := repos.transaction.GetOrderItemsByOrderId(ctx, order.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.OrderItem{item}, items)
+	})
+	t.Run("InsertPayment_payment id is used_then return error", func(t *testing.T) {
+		repos := newRepos()
+		userId, shopId := insertOrderOwner(t, repos)
+		order := newOrder(repos, userId, shopId, entity.OrderStatusPending, expirationMargin)
+		require.NoError(t, repos.transaction.InsertOrder(ctx, order))
+		payment := &entity.Payment{Id: newContractId("PAY"), OrderId: order.Id, Amount: order.Amount, Status: entity.PaymentStatusPaid}
+
+		assert.NoError(t, repos.transaction.InsertPayment(ctx, payment))
+		assert.Error(t, repos.transaction.InsertPayment(ctx, payment))
+	})
+}
+
+func testUserRepositoryContract(t *testing.T, newRepos func() *contractRepositories) {
+	ctx := context.Background()
+
+	insertUser := func(t *testing.T, repos *contractRepositories) *entity.User {
+		id := newContractId("U")
+		user := &entity.User{Id: id, Name: "name", Email: id + "@mail.com", PhoneNumber: id, CreatedAt: repos.now(), UpdatedAt: repos.now()}
+		require.NoError(t, repos.user.InsertUser(ctx, user))
+		return user
+	}
+
+	t.Run("GetUser_by id, email or phone number_then return the user", func(t *testing.T) {
+		repos := newRepos()
+		user := insertUser(t, repos)
+
+		for _, req := range []*entity.GetUserRequest{{Id: user.Id}, {Email: user.Email}, {PhoneNumber: user.PhoneNumber}} {
+			got, err := repos.user.GetUser(ctx, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, user.Id, got.Id)
+			assert.Equal(t, user.Email, got.Email)
+			assert.Equal(t, user.PhoneNumber, got.PhoneNumber)
+		}
+	})
+	t.Run("InsertUser_email is registered_then return unique violation", func(t *testing.T) {
+		repos := newRepos()
+		user := insertUser(t, repos)
+
+		err := repos.user.InsertUser(ctx, &entity.User{Id: newContractId("U"), Email: user.Email, CreatedAt: repos.now(), UpdatedAt: repos.now()})
+
+		assert.True(t, errors.Is(err, errorutil.ErrUniqueViolation))
+	})
+	t.Run("UpdateUserIdentifier_email is used by other user_then return unique violation", func(t *testing.T) {
+		repos := newRepos()
+		user := insertUser(t, repos)
+		other := insertUser(t, repos)
+
+		err := repos.user.UpdateUserIdentifier(ctx, user.Id, entity.IdentifierTypeEmail, other.Email, repos.now())
+
+		assert.True(t, errors.Is(err, errorutil.ErrUniqueViolation))
+	})
+	t.Run("UpdateUserIdentifier_email is not used_then set it as verified", func(t *testing.T) {
+		repos := newRepos()
+		user := insertUser(t, repos)
+		email := newContractId("E") + "@mail.com"
+
+		err := repos.user.UpdateUserIdentifier(ctx, user.Id, entity.IdentifierTypeEmail, email, repos.now())
+
+		assert.NoError(t, err)
+		got, err := repos.user.GetUser(ctx, &entity.GetUserRequest{Id: user.Id})
+		assert.NoError(t, err)
+		assert.Equal(t, email, got.Email)
+		assert.True(t, got.EmailVerified)
+	})
+	t.Run("GetAddresses_addresses are inserted and deleted_then return the default first without the deleted", func(t *testing.T) {
+		repos := newRepos()
+		user := insertUser(t, repos)
+		newAddress := func(isDefault bool, createdAt time.Time) *entity.Address {
+			return &entity.Address{Id: newContractId("A"), UserId: user.Id, Label: "home", RecipientName: "name", PhoneNumber: "0812",
+				Street: "street", City: "city", Province: "province", PostalCode: "12345", Country: "ID", IsDefault: isDefault,
+				CreatedAt: createdAt, UpdatedAt: createdAt}
+		}
+		oldest := newAddress(false, repos.now().Add(-2*time.Hour))
+		deleted := newAddress(false, repos.now().Add(-time.Hour))
+		defaultAddress := newAddress(true, repos.now())
+		for _, address := range []*entity.Address{oldest, deleted, defaultAddress} {
+			require.NoError(t, repos.user.InsertAddress(ctx, address))
+		}
+		require.NoError(t, repos.user.DeleteAddress(ctx, user.Id, deleted.Id, repos.now()))
+
+		addresses, err := repos.user.GetAddresses(ctx, user.Id)
+
+		assert.NoError(t, err)
+		if assert.Len(t, addresses, 2) {
+			assert.Equal(t, defaultAddress.Id, addresses[0].Id)
+			assert.Equal(t, oldest.Id, addresses[1].Id)
+		}
+		_, err = repos.user.GetAddress(ctx, user.Id, deleted.Id)
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+	})
+	t.Run("GetAddress_address of other user_then return not found error", func(t *testing.T) {
+		repos := newRepos()
+		user := insertUser(t, repos)
+		other := insertUser(t, repos)
+		address := &entity.Address{Id: newContractId("A"), UserId: user.Id, RecipientName: "name", PhoneNumber: "0812", Street: "street",
+			City: "city", Province: "province", PostalCode: "12345", Country: "ID", CreatedAt: repos.now(), UpdatedAt: repos.now()}
+		require.NoError(t, repos.user.InsertAddress(ctx, address))
+
+		_, err := repos.user.GetAddress(ctx, other.Id, address.Id)
+
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+	})
+	t.Run("GetLatestOtp_otps are requested_then return the latest one", func(t *testing.T) {
+		repos := newRepos()
+		identifier := newContractId("E") + "@mail.com"
+		older := &entity.Otp{Id: newContractId("OTP"), Identifier: identifier, Purpose: entity.OtpPurposeLogin, CodeHash: "hash",
+			ExpiredAt: repos.now().Add(time.Hour), CreatedAt: repos.now().Add(-time.Minute)}
+		latest := &entity.Otp{Id: newContractId("OTP"), Identifier: identifier, Purpose: entity.OtpPurposeLogin, CodeHash: "hash",
+			ExpiredAt: repos.now().Add(time.Hour), CreatedAt: repos.now()}
+		require.NoError(t, repos.user.InsertOtp(ctx, older))
+		require.NoError(t, repos.user.InsertOtp(ctx, latest))
+
+		otp, err := repos.user.GetLatestOtp(ctx, identifier, entity.OtpPurposeLogin)
+
+		assert.NoError(t, err)
+		assert.Equal(t, latest.Id, otp.Id)
+		count, err := repos.user.CountOtpsSince(ctx, identifier, repos.now().Add(-30*time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+	t.Run("IncreaseOtpAttempts_max attempts is reached_then return too many requests error", func(t *testing.T) {
+		repos := newRepos()
+		otp := &entity.Otp{Id: newContractId("OTP"), Identifier: newContractId("E"), Purpose: entity.OtpPurposeLogin, CodeHash: "hash",
+			ExpiredAt: repos.now().Add(time.Hour), CreatedAt: repos.now()}
+		require.NoError(t, repos.user.InsertOtp(ctx, otp))
+
+		assert.NoError(t, repos.user.IncreaseOtpAttempts(ctx, otp.Id, 2))
+		assert.NoError(t, repos.user.IncreaseOtpAttempts(ctx, otp.Id, 2))
+		err := repos.user.IncreaseOtpAttempts(ctx, otp.Id, 2)
+
+		assert.Equal(t, errorutil.ErrTooManyRequests, errorutil.GetErrorType(err))
+	})
+	t.Run("ConsumeOtp_otp is already used_then return unauthorized error", func(t *testing.T) {
+		repos := newRepos()
+		otp := &entity.Otp{Id: newContractId("OTP"), Identifier: newContractId("E"), Purpose: entity.OtpPurposeLogin, CodeHash: "hash",
+			ExpiredAt: repos.now().Add(time.Hour), CreatedAt: repos.now()}
+		require.NoError(t, repos.user.InsertOtp(ctx, otp))
+
+		assert.NoError(t, repos.user.ConsumeOtp(ctx, otp.Id, repos.now()))
+		err := repos.user.ConsumeOtp(ctx, otp.Id, repos.now())
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+	})
+}
+
+func testRedisRepositoryContract(t *testing.T, newRepos func() *contractRepositories) {
+	ctx := context.Background()
+
+	t.Run("GetReservedProductQuantity_products are locked by users_then sum the quantity until it is invalidated", func(t *testing.T) {
+		repos := newRepos()
+		productId, warehouseId := newContractId("P"), newContractId("W")
+		first := &entity.LockOrderProductRequest{ProductId: productId, WarehouseId: warehouseId, UserId: newContractId("U"), Quantity: 2}
+		second := &entity.LockOrderProductRequest{ProductId: productId, WarehouseId: warehouseId, UserId: newContractId("U"), Quantity: 3}
+		require.NoError(t, repos.redis.LockOrderProduct(ctx, first))
+		require.NoError(t, repos.redis.LockOrderProduct(ctx, second))
+
+		quantity, err := repos.redis.GetReservedProductQuantity(ctx, productId, warehouseId)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, quantity)
+		quantities, err := repos.redis.GetReservedQuantityPerWarehouse(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, quantities[warehouseId])
+
+		require.NoError(t, repos.redis.InvalidateLockOrderProduct(ctx, first))
+
+		quantity, err = repos.redis.GetReservedProductQuantity(ctx, productId, warehouseId)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, quantity)
+	})
+	t.Run("GetReservedProductQuantity_order expire time is passed_then the lock is released", func(t *testing.T) {
+		repos := newRepos()
+		req := &entity.LockOrderProductRequest{ProductId: newContractId("P"), WarehouseId: newContractId("W"), UserId: newContractId("U"), Quantity: 2}
+		require.NoError(t, repos.redis.LockOrderProduct(ctx, req))
+
+		repos.advance(contractOrderExpireTime + 100*time.Millisecond)
+
+		quantity, err := repos.redis.GetReservedProductQuantity(ctx, req.ProductId, req.WarehouseId)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, quantity)
+		quantities, err := repos.redis.GetReservedQuantityPerWarehouse(ctx)
+		assert.NoError(t, err)
+		assert.NotContains(t, quantities, req.WarehouseId)
+	})
+	t.Run("IsSessionRevoked_session is revoked_then it is revoked until the expiration", func(t *testing.T) {
+		repos := newRepos()
+		sessionId := newContractId("SES")
+
+		revoked, err := repos.redis.IsSessionRevoked(ctx, sessionId)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+
+		require.NoError(t, repos.redis.RevokeSession(ctx, sessionId, time.Second))
+
+		revoked, err = repos.redis.IsSessionRevoked(ctx, sessionId)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		repos.advance(time.Second + 100*time.Millisecond)
+
+		revoked, err = repos.redis.IsSessionRevoked(ctx, sessionId)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
+
+func testPriceRepositoryContract(t *testing.T, newRepos func() *contractRepositories) {
+	ctx := context.Background()
+
+	insertProductWithHistory := func(t *testing.T, repos *contractRepositories, price int, createdAt time.Time) (*entity.Product, *entity.PriceHistory) {
+		product := &entity.Product{Id: newContractId("P"), Name: newContractId("P-"), Price: price}
+		history := &entity.PriceHistory{Id: newContractId("PH"), ProductId: product.Id, Source: entity.PriceSourceBase, Price: price, CreatedAt: createdAt}
+		require.NoError(t, repos.inventory.InsertProduct(ctx, product))
+		require.NoError(t, repos.price.InsertPriceHistory(ctx, history))
+		return product, history
+	}
+
+	t.Run("GetProductPrices_time is before the first price history_then do not return the product", func(t *testing.T) {
+		repos := newRepos()
+		now := repos.now()
+		product, _ := insertProductWithHistory(t, repos, 1000, now)
+
+		prices, err := repos.price.GetProductPrices(ctx, &entity.GetProductPricesRequest{ProductIds: []string{product.Id}, At: now.Add(-time.Hour)})
+
+		assert.NoError(t, err)
+		assert.Empty(t, prices)
+	})
+	t.Run("GetProductPrices_base and shop price are changed_then return the prices of the history at the time", func(t *testing.T) {
+		repos := newRepos()
+		now := repos.now()
+		shop := &entity.Shop{Id: newContractId("S"), Name: newContractId("S-")}
+		require.NoError(t, repos.inventory.InsertShop(ctx, shop))
+		product, first := insertProductWithHistory(t, repos, 1000, now.Add(-2*time.Hour))
+		shopHistory := &entity.PriceHistory{Id: newContractId("PH"), ProductId: product.Id, ShopId: shop.Id, Source: entity.PriceSourceShop, Price: 900, CreatedAt: now.Add(-time.Hour)}
+		require.NoError(t, repos.price.UpsertShopProductPrice(ctx, &entity.ShopProductPrice{ShopId: shop.Id, ProductId: product.Id, Price: 900}))
+		require.NoError(t, repos.price.InsertPriceHistory(ctx, shopHistory))
+		require.NoError(t, repos.price.UpdateProductPrice(ctx, product.Id, 1200))
+		require.NoError(t, repos.price.InsertPriceHistory(ctx, &entity.PriceHistory{Id: newContractId("PH"), ProductId: product.Id, Source: entity.PriceSourceBase, Price: 1200, CreatedAt: now}))
+
+		prices, err := repos.price.GetProductPrices(ctx, &entity.GetProductPricesRequest{ShopId: shop.Id, ProductIds: []string{product.Id}, At: now.Add(-90 * time.Minute)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.ProductPrice{{ProductId: product.Id, BasePrice: 1000, BasePriceHistoryId: first.Id}}, prices)
+
+		prices, err = repos.price.GetProductPrices(ctx, &entity.GetProductPricesRequest{ShopId: shop.Id, ProductIds: []string{product.Id}, At: now.Add(-30 * time.Minute)})
+
+		assert.NoError(t, err)
+		shopPrice := 900
+		assert.Equal(t, []*entity.ProductPrice{{ProductId: product.Id, BasePrice: 1000, BasePriceHistoryId: first.Id, ShopPrice: &shopPrice, ShopPriceHistoryId: shopHistory.Id}}, prices)
+	})
+}
+
+func testPrivacyRepositoryContract(t *testing.T, newRepos func() *contractRepositories) {
+	ctx := context.Background()
+
+	t.Run("DeleteUserRecords_user created api keys_then revoke the keys of the user only", func(t *testing.T) {
+		repos := newRepos()
+		insertUser := func() *entity.User {
+			id := newContractId("U")
+			user := &entity.User{Id: id, Name: "name", Email: id + "@mail.com", PhoneNumber: id, CreatedAt: repos.now(), UpdatedAt: repos.now()}
+			require.NoError(t, repos.user.InsertUser(ctx, user))
+			return user
+		}
+		insertAPIKey := func(createdBy string) *entity.APIKey {
+			id := newContractId("AK")
+			apiKey := &entity.APIKey{Id: id, Name: id, Prefix: id, KeyHash: id, Scopes: []string{entity.PermissionProductRead}, CreatedBy: createdBy, CreatedAt: repos.now()}
+			require.NoError(t, repos.apiKey.InsertAPIKey(ctx, apiKey))
+			return apiKey
+		}
+		user, otherUser := insertUser(), insertUser()
+		userKey, otherUserKey := insertAPIKey(user.Id), insertAPIKey(otherUser.Id)
+		deletedAt := repos.now()
+
+		_, err := repos.privacy.DeleteUserRecords(ctx, user, deletedAt)
+
+		assert.NoError(t, err)
+		got, err := repos.apiKey.GetAPIKeyByHash(ctx, userKey.KeyHash)
+		require.NoError(t, err)
+		require.NotNil(t, got.RevokedAt)
+		assert.True(t, got.RevokedAt.Equal(deletedAt))
+		got, err = repos.apiKey.GetAPIKeyByHash(ctx, otherUserKey.KeyHash)
+		require.NoError(t, err)
+		assert.Nil(t, got.RevokedAt)
+	})
+	t.Run("DeleteUserRecords_user has sessions_then revoke the active sessions of the user only", func(t *testing.T) {
+		repos := newRepos()
+		insertUser := func() *entity.User {
+			id := newContractId("U")
+			user := &entity.User{Id: id, Name: "name", Email: id + "@mail.com", PhoneNumber: id, CreatedAt: repos.now(), UpdatedAt: repos.now()}
+			require.NoError(t, repos.user.InsertUser(ctx, user))
+			return user
+		}
+		insertSession := func(userId string) *entity.Session {
+			session := &entity.Session{Id: newContractId("SES"), UserId: userId, CreatedAt: repos.now()}
+			require.NoError(t, repos.auth.InsertSession(ctx, session))
+			return session
+		}
+		user, otherUser := insertUser(), insertUser()
+		activeSession, revokedSession, otherUserSession := insertSession(user.Id), insertSession(user.Id), insertSession(otherUser.Id)
+		revokedAt := repos.now().Add(-time.Hour)
+		require.NoError(t, repos.auth.RevokeSession(ctx, revokedSession.Id, revokedAt))
+		deletedAt := repos.now()
+
+		sessionIds, err := repos.privacy.DeleteUserRecords(ctx, user, deletedAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{activeSession.Id}, sessionIds)
+		got, err := repos.auth.GetSessionById(ctx, activeSession.Id)
+		require.NoError(t, err)
+		require.NotNil(t, got.RevokedAt)
+		assert.True(t, got.RevokedAt.Equal(deletedAt))
+		got, err = repos.auth.GetSessionById(ctx, revokedSession.Id)
+		require.NoError(t, err)
+		require.NotNil(t, got.RevokedAt)
+		assert.True(t, got.RevokedAt.Equal(revokedAt))
+		got, err = repos.auth.GetSessionById(ctx, otherUserSession.Id)
+		require.NoError(t, err)
+		assert.Nil(t, got.RevokedAt)
+	})
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"mfawzanid/warehouse-commerce/core/entity"
+)
+
+// memoryHealthRepository is the health of the memory storage, there is no database or redis to be down
+// and the schema is always the one the binary expects
+type memoryHealthRepository struct {
+	migrationVersion uint
+}
+
+func NewMemoryHealthRepository(migrationVersion uint) HealthRepositoryInterface {
+	return &memoryHealthRepository{
+		migrationVersion: migrationVersion,
+	}
+}
+
+func (r *memoryHealthRepository) PingDatabase(ctx context.Context) error {
+	return nil
+}
+
+func (r *memoryHealthRepository) PingRedis(ctx context.Context) error {
+	return nil
+}
+
+func (r *memoryHealthRepository) GetMigrationVersion(ctx context.Context) (*entity.MigrationVersion, error) {
+	return &entity.MigrationVersion{Version: r.migrationVersion}, nil
+}
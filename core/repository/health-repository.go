@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type HealthRepositoryInterface interface {
+	PingDatabase(ctx context.Context) error
+	PingRedis(ctx context.Context) error
+	// GetMigrationVersion returns the schema version that is recorded by the migrations
+	GetMigrationVersion(ctx context.Context) (*entity.MigrationVersion, error)
+}
+
+type healthRepository struct {
+	db          *sql.DB
+	redisClient *redis.Client
+}
+
+func NewHealthRepository(db *sql.DB, redisClient *redis.Client) HealthRepositoryInterface {
+	return &healthRepository{
+		db:          db,
+		redisClient: redisClient,
+	}
+}
+
+func (r *healthRepository) PingDatabase(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("error repo ping database: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *healthRepository) PingRedis(ctx context.Context) error {
+	if err := r.redisClient.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("error cache repo ping redis: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *healthRepository) GetMigrationVersion(ctx context.Context) (*entity.MigrationVersion, error) {
+	// the table of golang-migrate, it has one row of the current version
+	query := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+	var version int64
+	var dirty bool
+	if err := r.db.QueryRowContext(ctx, query).Scan(&version, &dirty); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &entity.MigrationVersion{}, nil
+		}
+		return nil, fmt.Errorf("error repo get migration version: %v", err.Error())
+	}
+
+	return &entity.MigrationVersion{Version: uint(version), Dirty: dirty}, nil
+}
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"strings"
+)
+
+type memoryInventoryRepository struct {
+	db memoryDB
+}
+
+func NewMemoryInventoryRepository(store *MemoryStore) InventoryRepositoryInterface {
+	return &memoryInventoryRepository{
+		db: store,
+	}
+}
+
+func (r *memoryInventoryRepository) InsertWarehouse(ctx context.Context, warehouse *entity.Warehouse) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.warehouses[warehouse.Id]; ok {
+			return errorutil.ErrUniqueViolation
+		}
+		for _, existing := range t.warehouses {
+			if existing.Name == warehouse.Name {
+				return errorutil.ErrUniqueViolation
+			}
+		}
+
+		t.warehouses[warehouse.Id] = *warehouse
+		return nil
+	})
+}
+
+func (r *memoryInventoryRepository) UpdateWarehouseStatus(ctx context.Context, req *entity.UpdateWarehouseStatusRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.run(func(t *memoryTables) error {
+		if warehouse, ok := t.warehouses[req.Id]; ok {
+			warehouse.Enabled = req.Enabled
+			t.warehouses[req.Id] = warehouse
+		}
+		return nil
+	})
+}
+
+func (r *memoryInventoryRepository) GetWarehouses(ctx context.Context, req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
+	var warehouses []*entity.Warehouse
+	err := r.db.run(func(t *memoryTables) error {
+		var rows []*entity.Warehouse
+		for _, warehouse := range t.warehouses {
+			if req.Enabled != nil && warehouse.Enabled != *req.Enabled {
+				continue
+			}
+			if len(req.Ids) > 0 && !slices.Contains(req.Ids, warehouse.Id) {
+				continue
+			}
+			if req.Name != "" && warehouse.Name != req.Name {
+				continue
+			}
+			rows = append(rows, &warehouse)
+		}
+
+		var err error
+		warehouses, err = listMemoryRows(warehouseQuerySpec, rows, func(w *entity.Warehouse) map[string]interface{} {
+			return map[string]interface{}{"id": w.Id, "name": w.Name, "enabled": w.Enabled}
+		}, req.Filters, req.Sorts, req.Pagination)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.GetWarehousesResponse{
+		Warehouses: warehouses,
+		Pagination: req.Pagination,
+	}, nil
+}
+
+// InsertShop inserts the shop, the owner is inserted as the owner member of the shop
+func (r *memoryInventoryRepository) InsertShop(ctx context.Context, shop *entity.Shop) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.shops[shop.Id]; ok {
+			return errorutil.ErrUniqueViolation
+		}
+		for _, existing := range t.shops {
+			if existing.Name == shop.Name {
+				return errorutil.ErrUniqueViolation
+			}
+		}
+		if _, ok := t.users[shop.OwnerId]; shop.OwnerId != "" && !ok {
+			return fmt.Errorf("error repo insert shop: owner '%s' is not found", shop.OwnerId)
+		}
+
+		t.shops[shop.Id] = *shop
+		if shop.OwnerId != "" {
+			t.shopMembers[shopMemberKey{shop.Id, shop.OwnerId}] = entity.ShopMember{
+				ShopId:    shop.Id,
+				UserId:    shop.OwnerId,
+				Role:      entity.ShopMemberRoleOwner,
+				CreatedAt: r.db.now(),
+			}
+		}
+		return nil
+	})
+}
+
+func (r *memoryInventoryRepository) GetShops(ctx context.Context, req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
+	var shops []*entity.Shop
+	err := r.db.run(func(t *memoryTables) error {
+		var rows []*entity.Shop
+		for _, shop := range t.shops {
+			if len(req.Ids) > 0 && !slices.Contains(req.Ids, shop.Id) {
+				continue
+			}
+			if req.Name != "" && shop.Name != req.Name {
+				continue
+			}
+			// the owner is not selected by the query
+			rows = append(rows, &entity.Shop{Id: shop.Id, Name: shop.Name})
+		}
+
+		var err error
+		shops, err = listMemoryRows(shopQuerySpec, rows, func(s *entity.Shop) map[string]interface{} {
+			return map[string]interface{}{"id": s.Id, "name": s.Name}
+		}, req.Filters, req.Sorts, req.Pagination)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.GetShopsResponse{
+		Shops:      shops,
+		Pagination: req.Pagination,
+	}, nil
+}
+
+func (r *memoryInventoryRepository) InsertShopWarehouses(ctx context.Context, req *entity.UpsertShopToWarehousesRequest) error {
+	return r.db.run(func(t *memoryTables) error {
+		if len(req.WarehouseIds) == 0 {
+			return fmt.Errorf("error repo insert shop warehouses: warehouse ids are empty")
+		}
+		if _, ok := t.shops[req.ShopId]; !ok {
+			return fmt.Errorf("error repo insert shop warehouses: shop '%s' is not found", req.ShopId)
+		}
+		for _, warehouseId := range req.WarehouseIds {
+			if _, ok := t.warehouses[warehouseId]; !ok {
+				return fmt.Errorf("error repo insert shop warehouses: warehouse '%s' is not found", warehouseId)
+			}
+		}
+
+		for _, warehouseId := range req.WarehouseIds {
+			t.shopWarehouses[shopWarehouseKey{req.ShopId, warehouseId}] = req.Enabled
+		}
+		return nil
+	})
+}
+
+func (r *memoryInventoryRepository) InsertProduct(ctx context.Context, product *entity.Product) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.products[product.Id]; ok {
+			return errorutil.ErrUniqueViolation
+		}
+		for _, existing := range t.products {
+			if product.Sku != "" && existing.Sku == product.Sku {
+				return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo insert product: %w", entity.ErrProductSkuIsUsed))
+			}
+			if existing.Name == product.Name {
+				return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo insert product: %w", entity.ErrProductNameIsUsed))
+			}
+		}
+
+		t.products[product.Id] = *product
+		return nil
+	})
+}
+
+// InsertProductWarehouse adds the total stock of the product in the warehouse, a negative total stock reduces it
+func (r *memoryInventoryRepository) InsertProductWarehouse(ctx context.Context, pw *entity.ProductWarehouse) error {
+	if err := pw.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.run(func(t *memoryTables) error {
+		if err := checkMemoryProductWarehouse(t, pw); err != nil {
+			return fmt.Errorf("error repo insert product warehouses: %v", err.Error())
+		}
+
+		t.productWarehouses[productWarehouseKey{pw.ProductId, pw.WarehouseId}] += pw.TotalStock
+		return nil
+	})
+}
+
+// checkMemoryProductWarehouse returns error if the product or the warehouse does not exist, like the foreign keys
+func checkMemoryProductWarehouse(t *memoryTables, pw *entity.ProductWarehouse) error {
+	if _, ok := t.products[pw.ProductId]; !ok {
+		return fmt.Errorf("product '%s' is not found", pw.ProductId)
+	}
+	if _, ok := t.warehouses[pw.WarehouseId]; !ok {
+		return fmt.Errorf("warehouse '%s' is not found", pw.WarehouseId)
+	}
+	return nil
+}
+
+func (r *memoryInventoryRepository) GetProductByName(ctx context.Context, name string) (*entity.Product, error) {
+	var product *entity.Product
+	err := r.db.run(func(t *memoryTables) error {
+		for _, existing := range t.products {
+			if existing.Name == name {
+				// the sku is not selected by the query
+				product = &entity.Product{Id: existing.Id, Name: existing.Name, Price: existing.Price}
+				return nil
+			}
+		}
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error get product by name '%s' is not found", name))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (r *memoryInventoryRepository) GetProductDetailsByShopId(ctx context.Context, req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
+	var pds []*entity.ProductDetail
+	err := r.db.run(func(t *memoryTables) error {
+		var rows []*entity.ProductDetail
+		for key, totalStock := range t.productWarehouses {
+			if enabled, ok := t.shopWarehouses[shopWarehouseKey{req.ShopId, key.warehouseId}]; !ok || !enabled {
+				continue
+			}
+			if len(req.ProductIds) > 0 && !slices.Contains(req.ProductIds, key.productId) {
+				continue
+			}
+			product := t.products[key.productId]
+
+			// active price list, shop override then base price
+			price := product.Price
+			if _, priceListPrice, ok := activeMemoryPriceListPrice(t, req.ShopId, product.Id, req.At); ok {
+				price = priceListPrice
+			} else if shopPrice, ok := t.shopProductPrices[shopProductKey{req.ShopId, product.Id}]; ok {
+				price = shopPrice
+			}
+
+			rows = append(rows, &entity.ProductDetail{
+				ProductId:   product.Id,
+				Name:        product.Name,
+				Price:       price,
+				TotalStock:  totalStock,
+				WarehouseId: key.warehouseId,
+			})
+		}
+
+		var err error
+		pds, err = listMemoryRows(productDetailQuerySpec, rows, func(pd *entity.ProductDetail) map[string]interface{} {
+			return map[string]interface{}{"productId": pd.ProductId, "name": pd.Name, "price": pd.Price, "totalStock": pd.TotalStock, "warehouseId": pd.WarehouseId}
+		}, req.Filters, req.Sorts, req.Pagination)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.GetProductDetailsByShopIdResponse{
+		ProductDetails: pds,
+		Pagination:     req.Pagination,
+	}, nil
+}
+
+func (r *memoryInventoryRepository) GetProductWarehousesByQuery(ctx context.Context, req *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var pws []*entity.ProductWarehouse
+	err := r.db.run(func(t *memoryTables) error {
+		for key, totalStock := range t.productWarehouses {
+			if !slices.Contains(req.ProductIds, key.productId) {
+				continue
+			}
+			if len(req.WarehouseIds) > 0 && !slices.Contains(req.WarehouseIds, key.warehouseId) {
+				continue
+			}
+			pws = append(pws, &entity.ProductWarehouse{ProductId: key.productId, WarehouseId: key.warehouseId, TotalStock: totalStock})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the query has no ordering, the map is sorted so the result does not change between the calls
+	slices.SortFunc(pws, func(a, b *entity.ProductWarehouse) int {
+		if result := strings.Compare(a.ProductId, b.ProductId); result != 0 {
+			return result
+		}
+		return strings.Compare(a.WarehouseId, b.WarehouseId)
+	})
+
+	return pws, nil
+}
+
+func (r *memoryInventoryRepository) UpdateProductWarehouseTotalStock(ctx context.Context, req *entity.UpdateProductWarehouseTotalStockRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.run(func(t *memoryTables) error {
+		key := productWarehouseKey{req.ProductId, req.WarehouseId}
+		if _, ok := t.productWarehouses[key]; ok {
+			t.productWarehouses[key] = req.TotalStock
+		}
+		return nil
+	})
+}
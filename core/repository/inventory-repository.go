@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"mfawzanid/warehouse-commerce/core/entity"
@@ -11,32 +12,29 @@ import (
 )
 
 type InventoryRepositoryInterface interface {
-	GetDb() *sql.DB
-
 	// warehouse
-	InsertWarehouse(warehouse *entity.Warehouse) error
-	UpdateWarehouseStatus(req *entity.UpdateWarehouseStatusRequest) error
-	GetWarehouses(req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error)
+	InsertWarehouse(ctx context.Context, warehouse *entity.Warehouse) error
+	UpdateWarehouseStatus(ctx context.Context, req *entity.UpdateWarehouseStatusRequest) error
+	GetWarehouses(ctx context.Context, req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error)
 
 	// shop
-	InsertShop(shop *entity.Shop) error
-	GetShops(req *entity.GetShopsRequest) (*entity.GetShopsResponse, error)
+	InsertShop(ctx context.Context, shop *entity.Shop) error
+	GetShops(ctx context.Context, req *entity.GetShopsRequest) (*entity.GetShopsResponse, error)
 
 	// shop_warehouse
-	InsertShopWarehouses(req *entity.UpsertShopToWarehousesRequest) error
+	InsertShopWarehouses(ctx context.Context, req *entity.UpsertShopToWarehousesRequest) error
 
 	// product
-	InsertProduct(tx *sql.Tx, product *entity.Product) error
-	InsertProductWarehouse(pw *entity.ProductWarehouse) error
-	InsertProductWarehouseTx(tx *sql.Tx, pw *entity.ProductWarehouse) error
-	GetProductByName(name string) (*entity.Product, error)
-	GetProductDetailsByShopId(req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error)
-	GetProductWarehousesByQuery(req *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error)
-	UpdateProductWarehouseTotalStock(req *entity.UpdateProductWarehouseTotalStockRequest) error
+	InsertProduct(ctx context.Context, product *entity.Product) error
+	InsertProductWarehouse(ctx context.Context, pw *entity.ProductWarehouse) error
+	GetProductByName(ctx context.Context, name string) (*entity.Product, error)
+	GetProductDetailsByShopId(ctx context.Context, req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error)
+	GetProductWarehousesByQuery(ctx context.Context, req *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error)
+	UpdateProductWarehouseTotalStock(ctx context.Context, req *entity.UpdateProductWarehouseTotalStockRequest) error
 }
 
 type inventoryRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewInventoryRepository(db *sql.DB) InventoryRepositoryInterface {
@@ -45,14 +43,10 @@ func NewInventoryRepository(db *sql.DB) InventoryRepositoryInterface {
 	}
 }
 
-func (r *inventoryRepository) GetDb() *sql.DB {
-	return r.db
-}
-
-func (r *inventoryRepository) InsertWarehouse(warehouse *entity.Warehouse) error {
+func (r *inventoryRepository) InsertWarehouse(ctx context.Context, warehouse *entity.Warehouse) error {
 	query := `INSERT INTO warehouses (id, name, enabled) VALUES ($1, $2, $3)`
 
-	_, err := r.db.Exec(query, warehouse.Id, warehouse.Name, warehouse.Enabled)
+	_, err := r.db.ExecContext(ctx, query, warehouse.Id, warehouse.Name, warehouse.Enabled)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationErrorCode {
 			return errorutil.ErrUniqueViolation
@@ -64,14 +58,14 @@ func (r *inventoryRepository) InsertWarehouse(warehouse *entity.Warehouse) error
 	return nil
 }
 
-func (r *inventoryRepository) UpdateWarehouseStatus(req *entity.UpdateWarehouseStatusRequest) error {
+func (r *inventoryRepository) UpdateWarehouseStatus(ctx context.Context, req *entity.UpdateWarehouseStatusRequest) error {
 	if err := req.Validate(); err != nil {
 		return err
 	}
 
 	query := `UPDATE warehouses SET enabled = $1 WHERE id = $2`
 
-	_, err := r.db.Exec(query, req.Enabled, req.Id)
+	_, err := r.db.ExecContext(ctx, query, req.Enabled, req.Id)
 	if err != nil {
 		return fmt.Errorf("error repo update warehouse status: %v", err.Error())
 	}
@@ -79,55 +73,51 @@ func (r *inventoryRepository) UpdateWarehouseStatus(req *entity.UpdateWarehouseS
 	return nil
 }
 
-func (r *inventoryRepository) GetWarehouses(req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
-	query := `SELECT id, name, enabled FROM warehouses`
+var warehouseQuerySpec = &querySpec{
+	fields: map[string]queryField{
+		"id":      {column: "id", fieldType: queryFieldTypeString},
+		"name":    {column: "name", fieldType: queryFieldTypeString},
+		"enabled": {column: "enabled", fieldType: queryFieldTypeBool},
+	},
+	defaultSort: []*entity.Sort{{Field: "name", Direction: entity.SortDirectionAsc}},
+	tieBreakers: []string{"id"},
+}
 
-	var conditions []string
-	var values []interface{}
-	valueIdx := 1
+func (r *inventoryRepository) GetWarehouses(ctx context.Context, req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
+	qb := newQueryBuilder(`SELECT id, name, enabled FROM warehouses`)
 
 	if req.Enabled != nil {
-		conditions = append(conditions, fmt.Sprintf("enabled = $%d", valueIdx))
-		values = append(values, *req.Enabled)
-		valueIdx++
+		qb.whereEq("enabled", *req.Enabled)
 	}
-
-	if len(req.Ids) > 0 {
-		placeholders := make([]string, len(req.Ids))
-		for i, id := range req.Ids {
-			placeholders[i] = fmt.Sprintf("$%d", valueIdx)
-			values = append(values, id)
-			valueIdx++
-		}
-		conditions = append(conditions, fmt.Sprintf("id IN (%s)", strings.Join(placeholders, ",")))
-	}
-
+	qb.whereIn("id", req.Ids)
 	if req.Name != "" {
-		conditions = append(conditions, fmt.Sprintf("name = $%d", valueIdx))
-		values = append(values, req.Name)
-		valueIdx++
+		qb.whereEq("name", req.Name)
 	}
 
-	if len(conditions) > 0 {
-		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conditions, " AND "))
+	if err := qb.applyFilters(warehouseQuerySpec, req.Filters); err != nil {
+		return nil, err
+	}
+	if err := qb.applySorts(warehouseQuerySpec, req.Sorts); err != nil {
+		return nil, err
 	}
 
 	if req.Pagination != nil {
-		queryCount := fmt.Sprintf(`SELECT COUNT(1) FROM (%s) AS derived`, query)
+		if !req.Pagination.SkipTotal {
+			queryCount, values := qb.countQuery()
 
-		err := r.db.QueryRow(queryCount, values...).Scan(&req.Pagination.Total)
-		if err != nil {
-			return nil, fmt.Errorf("error repo get warehouses: %v", err)
+			err := r.db.QueryRowContext(ctx, queryCount, values...).Scan(&req.Pagination.Total)
+			if err != nil {
+				return nil, fmt.Errorf("error repo get warehouses: %v", err)
+			}
 		}
 
-		req.Pagination.SetPagination()
-
-		offset := req.Pagination.GetOffset()
-		query = fmt.Sprintf("%s LIMIT $%d OFFSET $%d", query, valueIdx, valueIdx+1)
-		values = append(values, req.Pagination.PageSize, offset)
+		if err := qb.paginate(req.Pagination); err != nil {
+			return nil, err
+		}
 	}
 
-	rows, err := r.db.Query(query, values...)
+	query, values := qb.build()
+	rows, err := r.db.QueryContext(ctx, query, values...)
 	if err != nil {
 		return nil, fmt.Errorf("error repo get warehouses: %v", err.Error())
 	}
@@ -143,16 +133,31 @@ func (r *inventoryRepository) GetWarehouses(req *entity.GetWarehousesRequest) (*
 		warehouses = append(warehouses, warehouse)
 	}
 
+	if req.Pagination != nil {
+		warehouses, err = pageRows(qb, req.Pagination, warehouses, func(w *entity.Warehouse) map[string]interface{} {
+			return map[string]interface{}{"id": w.Id, "name": w.Name, "enabled": w.Enabled}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &entity.GetWarehousesResponse{
 		Warehouses: warehouses,
 		Pagination: req.Pagination,
 	}, nil
 }
 
-func (r *inventoryRepository) InsertShop(shop *entity.Shop) error {
-	query := `INSERT INTO shops (id, name) VALUES ($1, $2)`
+// InsertShop inserts the shop, the owner is inserted as the owner member of the shop in the same statement
+func (r *inventoryRepository) InsertShop(ctx context.Context, shop *entity.Shop) error {
+	query := `WITH new_shop AS (
+					INSERT INTO shops (id, name, owner_id) VALUES ($1, $2, $3) RETURNING id, owner_id
+				)
+				INSERT INTO shop_members (shop_id, user_id, role) SELECT id, owner_id, $4 FROM new_shop WHERE owner_id IS NOT NULL`
 
-	_, err := r.db.Exec(query, shop.Id, shop.Name)
+	ownerId := sql.NullString{String: shop.OwnerId, Valid: shop.OwnerId != ""}
+
+	_, err := r.db.ExecContext(ctx, query, shop.Id, shop.Name, ownerId, entity.ShopMemberRoleOwner)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationErrorCode {
 			return errorutil.ErrUniqueViolation
@@ -164,48 +169,47 @@ func (r *inventoryRepository) InsertShop(shop *entity.Shop) error {
 	return nil
 }
 
-func (r *inventoryRepository) GetShops(req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
-	query := `SELECT id, name FROM shops`
+var shopQuerySpec = &querySpec{
+	fields: map[string]queryField{
+		"id":   {column: "id", fieldType: queryFieldTypeString},
+		"name": {column: "name", fieldType: queryFieldTypeString},
+	},
+	defaultSort: []*entity.Sort{{Field: "name", Direction: entity.SortDirectionAsc}},
+	tieBreakers: []string{"id"},
+}
 
-	var conditions []string
-	var values []interface{}
-	valueIdx := 1
+func (r *inventoryRepository) GetShops(ctx context.Context, req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
+	qb := newQueryBuilder(`SELECT id, name FROM shops`)
 
-	if len(req.Ids) > 0 {
-		placeholders := make([]string, len(req.Ids))
-		for i, id := range req.Ids {
-			placeholders[i] = fmt.Sprintf("$%d", valueIdx)
-			values = append(values, id)
-			valueIdx++
-		}
-		conditions = append(conditions, fmt.Sprintf("id IN (%s)", strings.Join(placeholders, ",")))
-	}
+	qb.whereIn("id", req.Ids)
 	if req.Name != "" {
-		conditions = append(conditions, fmt.Sprintf("name = $%d", valueIdx))
-		values = append(values, req.Name)
-		valueIdx++
+		qb.whereEq("name", req.Name)
 	}
 
-	if len(conditions) > 0 {
-		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conditions, " AND "))
+	if err := qb.applyFilters(shopQuerySpec, req.Filters); err != nil {
+		return nil, err
+	}
+	if err := qb.applySorts(shopQuerySpec, req.Sorts); err != nil {
+		return nil, err
 	}
 
 	if req.Pagination != nil {
-		queryCount := fmt.Sprintf(`SELECT COUNT(1) FROM (%s) AS derived`, query)
+		if !req.Pagination.SkipTotal {
+			queryCount, values := qb.countQuery()
 
-		err := r.db.QueryRow(queryCount, values...).Scan(&req.Pagination.Total)
-		if err != nil {
-			return nil, fmt.Errorf("error repo get shops: %v", err)
+			err := r.db.QueryRowContext(ctx, queryCount, values...).Scan(&req.Pagination.Total)
+			if err != nil {
+				return nil, fmt.Errorf("error repo get shops: %v", err)
+			}
 		}
 
-		req.Pagination.SetPagination()
-
-		offset := req.Pagination.GetOffset()
-		query = fmt.Sprintf("%s LIMIT $%d OFFSET $%d", query, valueIdx, valueIdx+1)
-		values = append(values, req.Pagination.PageSize, offset)
+		if err := qb.paginate(req.Pagination); err != nil {
+			return nil, err
+		}
 	}
 
-	rows, err := r.db.Query(query, values...)
+	query, values := qb.build()
+	rows, err := r.db.QueryContext(ctx, query, values...)
 	if err != nil {
 		return nil, fmt.Errorf("error repo get shops: %v", err.Error())
 	}
@@ -221,13 +225,22 @@ func (r *inventoryRepository) GetShops(req *entity.GetShopsRequest) (*entity.Get
 		shops = append(shops, shop)
 	}
 
+	if req.Pagination != nil {
+		shops, err = pageRows(qb, req.Pagination, shops, func(s *entity.Shop) map[string]interface{} {
+			return map[string]interface{}{"id": s.Id, "name": s.Name}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &entity.GetShopsResponse{
 		Shops:      shops,
 		Pagination: req.Pagination,
 	}, nil
 }
 
-func (r *inventoryRepository) InsertShopWarehouses(req *entity.UpsertShopToWarehousesRequest) error {
+func (r *inventoryRepository) InsertShopWarehouses(ctx context.Context, req *entity.UpsertShopToWarehousesRequest) error {
 	query := `INSERT INTO shop_warehouses (shop_id, warehouse_id, enabled) VALUES %s 
 				ON CONFLICT (shop_id, warehouse_id)
 				DO UPDATE SET enabled = EXCLUDED.enabled`
@@ -245,7 +258,7 @@ func (r *inventoryRepository) InsertShopWarehouses(req *entity.UpsertShopToWareh
 
 	query = fmt.Sprintf(query, queryValues)
 
-	_, err := r.db.Exec(query, values...)
+	_, err := r.db.ExecContext(ctx, query, values...)
 	if err != nil {
 		return fmt.Errorf("error repo insert shop warehouses: %v", err.Error())
 	}
@@ -253,12 +266,20 @@ func (r *inventoryRepository) InsertShopWarehouses(req *entity.UpsertShopToWareh
 	return nil
 }
 
-func (r *inventoryRepository) InsertProduct(tx *sql.Tx, product *entity.Product) error {
-	query := `INSERT INTO products (id, name, price) VALUES ($1, $2, $3)`
+func (r *inventoryRepository) InsertProduct(ctx context.Context, product *entity.Product) error {
+	query := `INSERT INTO products (id, sku, name, price) VALUES ($1, $2, $3, $4)`
+
+	sku := sql.NullString{String: product.Sku, Valid: product.Sku != ""}
 
-	_, err := tx.Exec(query, product.Id, product.Name, product.Price)
+	_, err := r.db.ExecContext(ctx, query, product.Id, sku, product.Name, product.Price)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationErrorCode {
+			switch pqErr.Constraint {
+			case "products_sku_key":
+				return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo insert product: %w", entity.ErrProductSkuIsUsed))
+			case "products_name_key":
+				return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo insert product: %w", entity.ErrProductNameIsUsed))
+			}
 			return errorutil.ErrUniqueViolation
 		} else {
 			return fmt.Errorf("error repo insert product: %v", err.Error())
@@ -268,11 +289,8 @@ func (r *inventoryRepository) InsertProduct(tx *sql.Tx, product *entity.Product)
 	return nil
 }
 
-type Execer interface {
-	Exec(query string, args ...interface{}) (sql.Result, error)
-}
-
-func (r *inventoryRepository) insertProductWarehouse(exec Execer, pw *entity.ProductWarehouse) error {
+// InsertProductWarehouse adds the total stock of the product in the warehouse, a negative total stock reduces it
+func (r *inventoryRepository) InsertProductWarehouse(ctx context.Context, pw *entity.ProductWarehouse) error {
 	if err := pw.Validate(); err != nil {
 		return err
 	}
@@ -282,7 +300,7 @@ func (r *inventoryRepository) insertProductWarehouse(exec Execer, pw *entity.Pro
 				ON CONFLICT (product_id, warehouse_id)
 				DO UPDATE SET total_stock = product_warehouses.total_stock + EXCLUDED.total_stock`
 
-	_, err := exec.Exec(query, pw.ProductId, pw.WarehouseId, pw.TotalStock)
+	_, err := r.db.ExecContext(ctx, query, pw.ProductId, pw.WarehouseId, pw.TotalStock)
 	if err != nil {
 		return fmt.Errorf("error repo insert product warehouses: %v", err.Error())
 	}
@@ -290,21 +308,12 @@ func (r *inventoryRepository) insertProductWarehouse(exec Execer, pw *entity.Pro
 	return nil
 }
 
-func (r *inventoryRepository) InsertProductWarehouse(pw *entity.ProductWarehouse) error {
-	return r.insertProductWarehouse(r.db, pw)
-}
-
-// InsertProductWarehouseTx uses tx for support multiple insert in transfer product between warehouse
-func (r *inventoryRepository) InsertProductWarehouseTx(tx *sql.Tx, pw *entity.ProductWarehouse) error {
-	return r.insertProductWarehouse(tx, pw)
-}
-
-func (r *inventoryRepository) GetProductByName(name string) (*entity.Product, error) {
+func (r *inventoryRepository) GetProductByName(ctx context.Context, name string) (*entity.Product, error) {
 	query := `SELECT id, name, price FROM products WHERE name = $1`
 
 	product := &entity.Product{}
 
-	err := r.db.QueryRow(query, name).Scan(&product.Id, &product.Name, &product.Price)
+	err := r.db.QueryRowContext(ctx, query, name).Scan(&product.Id, &product.Name, &product.Price)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error get product by name '%s' is not found", name))
@@ -316,55 +325,70 @@ func (r *inventoryRepository) GetProductByName(name string) (*entity.Product, er
 	return product, nil
 }
 
-func (r *inventoryRepository) GetProductDetailsByShopId(req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
-	query := `SELECT p.id, p.name, p.price, pw.total_stock, pw.warehouse_id 
+var productDetailQuerySpec = &querySpec{
+	fields: map[string]queryField{
+		"productId":   {column: "p.id", fieldType: queryFieldTypeString},
+		"name":        {column: "p.name", fieldType: queryFieldTypeString},
+		"price":       {column: "COALESCE(apl.price, spp.price, p.price)", fieldType: queryFieldTypeInt}, // active price list, shop override then base price
+		"totalStock":  {column: "pw.total_stock", fieldType: queryFieldTypeInt},
+		"warehouseId": {column: "pw.warehouse_id", fieldType: queryFieldTypeString},
+	},
+	defaultSort: []*entity.Sort{{Field: "name", Direction: entity.SortDirectionAsc}},
+	tieBreakers: []string{"productId", "warehouseId"},
+}
+
+func (r *inventoryRepository) GetProductDetailsByShopId(ctx context.Context, req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
+	qb := newQueryBuilder(`SELECT p.id, p.name, COALESCE(apl.price, spp.price, p.price), pw.total_stock, pw.warehouse_id 
 				FROM products p
 				INNER JOIN product_warehouses pw
 				ON p.id = pw.product_id  
 				INNER JOIN shop_warehouses sw 
-				ON pw.warehouse_id = sw.warehouse_id`
-
-	var conditions []string
-	var values []interface{}
-	valueIdx := 1
+				ON pw.warehouse_id = sw.warehouse_id
+				LEFT JOIN shop_product_prices spp
+				ON spp.product_id = p.id AND spp.shop_id = sw.shop_id
+				LEFT JOIN LATERAL (
+					SELECT pli.price
+					FROM price_list_items pli
+					INNER JOIN price_lists pl
+					ON pl.id = pli.price_list_id
+					WHERE pl.shop_id = sw.shop_id AND pli.product_id = p.id
+					AND pl.start_at <= $1 AND (pl.end_at IS NULL OR pl.end_at > $1)
+					ORDER BY pl.start_at DESC, pl.id DESC
+					LIMIT 1
+				) apl ON true`)
+
+	qb.placeholder(req.At)
 
 	// mandatory condition
-	conditions = append(conditions, "sw.enabled = true")
-
-	conditions = append(conditions, fmt.Sprintf("sw.shop_id = $%d", valueIdx))
-	valueIdx++
-	values = append(values, req.ShopId)
-
-	if len(req.ProductIds) > 0 {
-		placeholders := make([]string, len(req.ProductIds))
-		for i, id := range req.ProductIds {
-			placeholders[i] = fmt.Sprintf("$%d", valueIdx)
-			values = append(values, id)
-			valueIdx++
-		}
-		conditions = append(conditions, fmt.Sprintf("product_id IN (%s)", strings.Join(placeholders, ",")))
-	}
+	qb.where("sw.enabled = true")
+	qb.whereEq("sw.shop_id", req.ShopId)
 
-	if len(conditions) > 0 {
-		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conditions, " AND "))
+	qb.whereIn("pw.product_id", req.ProductIds)
+
+	if err := qb.applyFilters(productDetailQuerySpec, req.Filters); err != nil {
+		return nil, err
+	}
+	if err := qb.applySorts(productDetailQuerySpec, req.Sorts); err != nil {
+		return nil, err
 	}
 
 	if req.Pagination != nil {
-		queryCount := fmt.Sprintf(`SELECT COUNT(1) FROM (%s) AS derived`, query)
+		if !req.Pagination.SkipTotal {
+			queryCount, values := qb.countQuery()
 
-		err := r.db.QueryRow(queryCount, values...).Scan(&req.Pagination.Total)
-		if err != nil {
-			return nil, fmt.Errorf("error repo get products by shop id: %v", err)
+			err := r.db.QueryRowContext(ctx, queryCount, values...).Scan(&req.Pagination.Total)
+			if err != nil {
+				return nil, fmt.Errorf("error repo get products by shop id: %v", err)
+			}
 		}
 
-		req.Pagination.SetPagination()
-
-		offset := req.Pagination.GetOffset()
-		query = fmt.Sprintf("%s LIMIT $%d OFFSET $%d", query, valueIdx, valueIdx+1)
-		values = append(values, req.Pagination.PageSize, offset)
+		if err := qb.paginate(req.Pagination); err != nil {
+			return nil, err
+		}
 	}
 
-	rows, err := r.db.Query(query, values...)
+	query, values := qb.build()
+	rows, err := r.db.QueryContext(ctx, query, values...)
 	if err != nil {
 		return nil, fmt.Errorf("error repo get products by shop id: %v", err.Error())
 	}
@@ -380,48 +404,34 @@ func (r *inventoryRepository) GetProductDetailsByShopId(req *entity.GetProductDe
 	}
 	defer rows.Close()
 
+	if req.Pagination != nil {
+		pds, err = pageRows(qb, req.Pagination, pds, func(pd *entity.ProductDetail) map[string]interface{} {
+			return map[string]interface{}{"productId": pd.ProductId, "name": pd.Name, "price": pd.Price, "totalStock": pd.TotalStock, "warehouseId": pd.WarehouseId}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &entity.GetProductDetailsByShopIdResponse{
 		ProductDetails: pds,
 		Pagination:     req.Pagination,
 	}, nil
 }
 
-func (r *inventoryRepository) GetProductWarehousesByQuery(req *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error) {
+func (r *inventoryRepository) GetProductWarehousesByQuery(ctx context.Context, req *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	query := `SELECT product_id, warehouse_id, total_stock FROM product_warehouses`
-
-	conditions := []string{}
-	values := []interface{}{}
-	valueIdx := 1
-
-	if len(req.ProductIds) > 0 {
-		placeholders := make([]string, len(req.ProductIds))
-		for i, id := range req.ProductIds {
-			placeholders[i] = fmt.Sprintf("$%d", valueIdx)
-			values = append(values, id)
-			valueIdx++
-		}
-		conditions = append(conditions, fmt.Sprintf("product_id IN (%s)", strings.Join(placeholders, ",")))
-	}
+	qb := newQueryBuilder(`SELECT product_id, warehouse_id, total_stock FROM product_warehouses`)
 
-	if len(req.WarehouseIds) > 0 {
-		placeholders := make([]string, len(req.WarehouseIds))
-		for i, id := range req.WarehouseIds {
-			placeholders[i] = fmt.Sprintf("$%d", valueIdx)
-			values = append(values, id)
-			valueIdx++
-		}
-		conditions = append(conditions, fmt.Sprintf("warehouse_id IN (%s)", strings.Join(placeholders, ",")))
-	}
+	qb.whereIn("product_id", req.ProductIds)
+	qb.whereIn("warehouse_id", req.WarehouseIds)
 
-	if len(conditions) > 0 {
-		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conditions, " AND "))
-	}
+	query, values := qb.build()
 
-	rows, err := r.db.Query(query, values...)
+	rows, err := r.db.QueryContext(ctx, query, values...)
 	if err != nil {
 		return nil, fmt.Errorf("error repo get product warehouse by query: %v", err.Error())
 	}
@@ -440,14 +450,14 @@ func (r *inventoryRepository) GetProductWarehousesByQuery(req *entity.GetProduct
 	return pws, nil
 }
 
-func (r *inventoryRepository) UpdateProductWarehouseTotalStock(req *entity.UpdateProductWarehouseTotalStockRequest) (err error) {
+func (r *inventoryRepository) UpdateProductWarehouseTotalStock(ctx context.Context, req *entity.UpdateProductWarehouseTotalStockRequest) (err error) {
 	if err := req.Validate(); err != nil {
 		return err
 	}
 
 	query := `UPDATE product_warehouses SET total_stock = $1 WHERE product_id = $2 AND warehouse_id = $3`
 
-	_, err = r.db.Exec(query, req.TotalStock, req.ProductId, req.WarehouseId)
+	_, err = r.db.ExecContext(ctx, query, req.TotalStock, req.ProductId, req.WarehouseId)
 	if err != nil {
 		return fmt.Errorf("error repo update product total stock: %v", err.Error())
 	}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryMembershipRepository struct {
+	db memoryDB
+}
+
+func NewMemoryMembershipRepository(store *MemoryStore) MembershipRepositoryInterface {
+	return &memoryMembershipRepository{
+		db: store,
+	}
+}
+
+func (r *memoryMembershipRepository) GetShopMember(ctx context.Context, shopId, userId string) (*entity.ShopMember, error) {
+	var member entity.ShopMember
+	err := r.db.run(func(t *memoryTables) error {
+		var ok bool
+		if member, ok = t.shopMembers[shopMemberKey{shopId, userId}]; !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get shop member: user '%s' is not a member of shop '%s'", userId, shopId))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *memoryMembershipRepository) GetShopMembers(ctx context.Context, shopId string) ([]*entity.ShopMember, error) {
+	members := []*entity.ShopMember{}
+	err := r.db.run(func(t *memoryTables) error {
+		for key, member := range t.shopMembers {
+			if key.shopId == shopId {
+				members = append(members, &member)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(members, func(a, b *entity.ShopMember) int {
+		if result := a.CreatedAt.Compare(b.CreatedAt); result != 0 {
+			return result
+		}
+		return strings.Compare(a.UserId, b.UserId)
+	})
+
+	return members, nil
+}
+
+// InsertShopMember inserts the member, it returns bad request error if the user is already a member of the shop
+func (r *memoryMembershipRepository) InsertShopMember(ctx context.Context, member *entity.ShopMember) error {
+	return r.db.run(func(t *memoryTables) error {
+		key := shopMemberKey{member.ShopId, member.UserId}
+		if _, ok := t.shopMembers[key]; ok {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo insert shop member: user '%s' is already a member of shop '%s'", member.UserId, member.ShopId))
+		}
+		if _, ok := t.shops[member.ShopId]; !ok {
+			return fmt.Errorf("error repo insert shop member: shop '%s' is not found", member.ShopId)
+		}
+		if _, ok := t.users[member.UserId]; !ok {
+			return fmt.Errorf("error repo insert shop member: user '%s' is not found", member.UserId)
+		}
+
+		t.shopMembers[key] = *member
+		return nil
+	})
+}
+
+func (r *memoryMembershipRepository) DeleteShopMember(ctx context.Context, shopId, userId string) error {
+	return r.db.run(func(t *memoryTables) error {
+		key := shopMemberKey{shopId, userId}
+		if _, ok := t.shopMembers[key]; !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo delete shop member: user '%s' is not a member of shop '%s'", userId, shopId))
+		}
+
+		delete(t.shopMembers, key)
+		return nil
+	})
+}
+
+func (r *memoryMembershipRepository) InsertShopInvitation(ctx context.Context, invitation *entity.ShopInvitation) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.shopInvitations[invitation.Id]; ok {
+			return fmt.Errorf("error repo insert shop invitation: invitation id '%s' already exists", invitation.Id)
+		}
+		if _, ok := t.shops[invitation.ShopId]; !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo insert shop invitation: shop '%s' is not found", invitation.ShopId))
+		}
+
+		stored := *invitation
+		stored.AcceptedAt = nil
+		t.shopInvitations[invitation.Id] = stored
+		return nil
+	})
+}
+
+func (r *memoryMembershipRepository) GetShopInvitationById(ctx context.Context, id string) (*entity.ShopInvitation, error) {
+	var invitation entity.ShopInvitation
+	err := r.db.run(func(t *memoryTables) error {
+		existing, ok := t.shopInvitations[id]
+		if !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get shop invitation: invitation id '%s' is not found", id))
+		}
+		invitation = existing
+		invitation.AcceptedAt = timePtr(existing.AcceptedAt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// AcceptShopInvitation marks the invitation as accepted, it returns bad request error if the invitation is already accepted
+func (r *memoryMembershipRepository) AcceptShopInvitation(ctx context.Context, id string, acceptedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		invitation, ok := t.shopInvitations[id]
+		if !ok || invitation.AcceptedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo accept shop invitation: invitation '%s' is already accepted", id))
+		}
+
+		invitation.AcceptedAt = &acceptedAt
+		t.shopInvitations[id] = invitation
+		return nil
+	})
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type MembershipRepositoryInterface interface {
+	// shop_member
+	GetShopMember(ctx context.Context, shopId, userId string) (*entity.ShopMember, error)
+	GetShopMembers(ctx context.Context, shopId string) ([]*entity.ShopMember, error)
+	InsertShopMember(ctx context.Context, member *entity.ShopMember) error
+	DeleteShopMember(ctx context.Context, shopId, userId string) error
+
+	// shop_invitation
+	InsertShopInvitation(ctx context.Context, invitation *entity.ShopInvitation) error
+	GetShopInvitationById(ctx context.Context, id string) (*entity.ShopInvitation, error)
+	AcceptShopInvitation(ctx context.Context, id string, acceptedAt time.Time) error
+}
+
+type membershipRepository struct {
+	db DBTX
+}
+
+func NewMembershipRepository(db *sql.DB) MembershipRepositoryInterface {
+	return &membershipRepository{
+		db: db,
+	}
+}
+
+func (r *membershipRepository) GetShopMember(ctx context.Context, shopId, userId string) (*entity.ShopMember, error) {
+	query := `SELECT shop_id, user_id, role, created_at FROM shop_members WHERE shop_id = $1 AND user_id = $2`
+
+	member := &entity.ShopMember{}
+
+	err := r.db.QueryRowContext(ctx, query, shopId, userId).Scan(&member.ShopId, &member.UserId, &member.Role, &member.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get shop member: user '%s' is not a member of shop '%s'", userId, shopId))
+		}
+		return nil, fmt.Errorf("error repo get shop member: %v", err.Error())
+	}
+
+	return member, nil
+}
+
+func (r *membershipRepository) GetShopMembers(ctx context.Context, shopId string) ([]*entity.ShopMember, error) {
+	query := `SELECT shop_id, user_id, role, created_at FROM shop_members WHERE shop_id = $1 ORDER BY created_at, user_id`
+
+	rows, err := r.db.QueryContext(ctx, query, shopId)
+	if err != nil {
+		return nil, fmt.Errorf("error repo get shop members: %v", err.Error())
+	}
+	defer rows.Close()
+
+	members := []*entity.ShopMember{}
+	for rows.Next() {
+		member := &entity.ShopMember{}
+		if err := rows.Scan(&member.ShopId, &member.UserId, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error repo get shop members: %v", err.Error())
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo get shop members: %v", err.Error())
+	}
+
+	return members, nil
+}
+
+// InsertShopMember inserts the member, it returns bad request error if the user is already a member of the shop
+func (r *membershipRepository) InsertShopMember(ctx context.Context, member *entity.ShopMember) error {
+	query := `INSERT INTO shop_members (shop_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)`
+
+	_, err := r.db.ExecContext(ctx, query, member.ShopId, member.UserId, member.Role, member.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationErrorCode {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo insert shop member: user '%s' is already a member of shop '%s'", member.UserId, member.ShopId))
+		}
+		return fmt.Errorf("error repo insert shop member: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *membershipRepository) DeleteShopMember(ctx context.Context, shopId, userId string) error {
+	query := `DELETE FROM shop_members WHERE shop_id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, shopId, userId)
+	if err != nil {
+		return fmt.Errorf("error repo delete shop member: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo delete shop member: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo delete shop member: user '%s' is not a member of shop '%s'", userId, shopId))
+	}
+
+	return nil
+}
+
+func (r *membershipRepository) InsertShopInvitation(ctx context.Context, invitation *entity.ShopInvitation) error {
+	query := `INSERT INTO shop_invitations (id, shop_id, identifier_type, identifier, role, invited_by, expired_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query, invitation.Id, invitation.ShopId, invitation.IdentifierType, invitation.Identifier,
+		invitation.Role, invitation.InvitedBy, invitation.ExpiredAt, invitation.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolationErrorCode {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo insert shop invitation: shop '%s' is not found", invitation.ShopId))
+		}
+		return fmt.Errorf("error repo insert shop invitation: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *membershipRepository) GetShopInvitationById(ctx context.Context, id string) (*entity.ShopInvitation, error) {
+	query := `SELECT id, shop_id, identifier_type, identifier, role, invited_by, expired_at, accepted_at, created_at
+				FROM shop_invitations WHERE id = $1`
+
+	invitation := &entity.ShopInvitation{}
+	var acceptedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&invitation.Id, &invitation.ShopId, &invitation.IdentifierType, &invitation.Identifier,
+		&invitation.Role, &invitation.InvitedBy, &invitation.ExpiredAt, &acceptedAt, &invitation.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get shop invitation: invitation id '%s' is not found", id))
+		}
+		return nil, fmt.Errorf("error repo get shop invitation: %v", err.Error())
+	}
+
+	if acceptedAt.Valid {
+		invitation.AcceptedAt = &acceptedAt.Time
+	}
+
+	return invitation, nil
+}
+
+// AcceptShopInvitation marks the invitation as accepted, it returns bad request error if the invitation is already accepted
+func (r *membershipRepository) AcceptShopInvitation(ctx context.Context, id string, acceptedAt time.Time) error {
+	query := `UPDATE shop_invitations SET accepted_at = $1 WHERE id = $2 AND accepted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, acceptedAt, id)
+	if err != nil {
+		return fmt.Errorf("error repo accept shop invitation: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo accept shop invitation: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo accept shop invitation: invitation '%s' is already accepted", id))
+	}
+
+	return nil
+}
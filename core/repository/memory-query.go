@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"cmp"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"strings"
+)
+
+// listMemoryRows filters, sorts and paginates the rows in memory like the queryBuilder does in the database,
+// the filters and the sorts are validated by the same spec and the cursors are the same. values returns the value
+// of each field of the spec in a row.
+func listMemoryRows[T any](spec *querySpec, rows []T, values func(row T) map[string]interface{}, filters []*entity.Filter,
+	sorts []*entity.Sort, pagination *entity.Pagination) ([]T, error) {
+	matches, err := memoryFilters(spec, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	qb := newQueryBuilder("")
+	if err := qb.applySorts(spec, sorts); err != nil {
+		return nil, err
+	}
+
+	var result []T
+	for _, row := range rows {
+		if matches(values(row)) {
+			result = append(result, row)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b T) int {
+		return compareMemoryKeys(qb.keys, values(a), values(b))
+	})
+
+	if pagination == nil {
+		return result, nil
+	}
+
+	if !pagination.SkipTotal {
+		pagination.Total = len(result)
+	}
+	pagination.Validate()
+	pagination.SetPagination()
+	qb.pageSize = pagination.PageSize
+
+	var page []T
+	if !pagination.IsCursor() {
+		offset := min(pagination.GetOffset(), len(result))
+		page = result[offset:min(offset+qb.pageSize+1, len(result))]
+	} else {
+		cursorValues, err := memoryCursorValues(qb, pagination.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		// the page before the cursor is taken in the reversed ordering, then pageRows reverses it back
+		if qb.backward {
+			for i := len(result) - 1; i >= 0 && len(page) <= qb.pageSize; i-- {
+				if compareMemoryKeys(qb.keys, values(result[i]), cursorValues) < 0 {
+					page = append(page, result[i])
+				}
+			}
+		} else {
+			for i := 0; i < len(result) && len(page) <= qb.pageSize; i++ {
+				if compareMemoryKeys(qb.keys, values(result[i]), cursorValues) > 0 {
+					page = append(page, result[i])
+				}
+			}
+		}
+	}
+
+	return pageRows(qb, pagination, slices.Clone(page), values)
+}
+
+// memoryFilters returns the function that matches the values of a row with all filters,
+// it returns bad request error if the field or operator is not allowed
+func memoryFilters(spec *querySpec, filters []*entity.Filter) (func(values map[string]interface{}) bool, error) {
+	var conditions []func(values map[string]interface{}) bool
+	for _, filter := range filters {
+		field, ok := spec.fields[filter.Field]
+		if !ok {
+			return nil, errorutil.NewFieldValidationError("error filter", &errorutil.FieldError{Field: "filter", Message: fmt.Sprintf("field '%s' is not filterable", filter.Field)})
+		}
+		if !isAllowedOperator(field.fieldType, filter.Operator) {
+			return nil, errorutil.NewFieldValidationError("error filter", &errorutil.FieldError{Field: "filter", Message: fmt.Sprintf("operator '%s' is not allowed for field '%s'", filter.Operator, filter.Field)})
+		}
+
+		filterValues := make([]interface{}, len(filter.Values))
+		for i, rawValue := range filter.Values {
+			value, err := parseQueryFieldValue(field.fieldType, rawValue)
+			if err != nil {
+				return nil, errorutil.NewFieldValidationError("error filter", &errorutil.FieldError{Field: "filter", Message: fmt.Sprintf("value '%s' of field '%s' is invalid", rawValue, filter.Field)})
+			}
+			filterValues[i] = value
+		}
+
+		name, operator := filter.Field, filter.Operator
+		conditions = append(conditions, func(values map[string]interface{}) bool {
+			value := values[name]
+			switch operator {
+			case entity.FilterOperatorIn:
+				return slices.ContainsFunc(filterValues, func(filterValue interface{}) bool {
+					return compareMemoryValues(value, filterValue) == 0
+				})
+			case entity.FilterOperatorPrefix:
+				return strings.HasPrefix(fmt.Sprint(value), filterValues[0].(string))
+			case entity.FilterOperatorContains:
+				return strings.Contains(fmt.Sprint(value), filterValues[0].(string))
+			}
+
+			result := compareMemoryValues(value, filterValues[0])
+			switch operator {
+			case entity.FilterOperatorEq:
+				return result == 0
+			case entity.FilterOperatorNe:
+				return result != 0
+			case entity.FilterOperatorGt:
+				return result > 0
+			case entity.FilterOperatorGte:
+				return result >= 0
+			case entity.FilterOperatorLt:
+				return result < 0
+			default:
+				return result <= 0
+			}
+		})
+	}
+
+	return func(values map[string]interface{}) bool {
+		for _, condition := range conditions {
+			if !condition(values) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// memoryCursorValues decodes the cursor into the values of the keys, like paginate it returns bad request error
+// if the cursor is not created for the ordering
+func memoryCursorValues(qb *queryBuilder, encoded string) (map[string]interface{}, error) {
+	cursor, err := decodeCursor(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.Signature != qb.signature() || len(cursor.Values) != len(qb.keys) {
+		return nil, errorutil.NewFieldValidationError("error cursor", &errorutil.FieldError{Field: "cursor", Message: "cursor does not match the requested sort"})
+	}
+
+	values := make(map[string]interface{}, len(qb.keys))
+	for i, key := range qb.keys {
+		value, err := parseQueryFieldValue(key.fieldType, cursor.Values[i])
+		if err != nil {
+			return nil, errorutil.NewFieldValidationError("error cursor", &errorutil.FieldError{Field: "cursor", Message: "cursor is invalid"})
+		}
+		values[key.field] = value
+	}
+
+	qb.backward = cursor.Backward
+	return values, nil
+}
+
+// compareMemoryKeys compares two rows by the keys of the ordering, the first key that differs decides
+func compareMemoryKeys(keys []queryKey, a, b map[string]interface{}) int {
+	for _, key := range keys {
+		result := compareMemoryValues(a[key.field], b[key.field])
+		if key.desc {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+func compareMemoryValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case int:
+		return cmp.Compare(a, b.(int))
+	case bool:
+		b := b.(bool)
+		switch {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		default:
+			return 1
+		}
+	default:
+		return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"maps"
+	"mfawzanid/warehouse-commerce/core/entity"
+	clockutil "mfawzanid/warehouse-commerce/utils/clock"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the tables of the memory storage, it is to the memory repositories what *sql.DB is to the postgres ones.
+// It is for the tests and the local development without containers, the data is gone when the process stops.
+type MemoryStore struct {
+	mu     sync.Mutex
+	clock  clockutil.Clock // NOW() of the queries, e.g. whether an order is expired
+	tables *memoryTables
+}
+
+func NewMemoryStore(clock clockutil.Clock) *MemoryStore {
+	return &MemoryStore{
+		clock:  clock,
+		tables: newMemoryTables(),
+	}
+}
+
+// memoryDB is implemented by the store and by the transaction of a memory unit of work, like DBTX
+type memoryDB interface {
+	// run runs fn on the tables while no one else does
+	run(fn func(t *memoryTables) error) error
+	now() time.Time
+}
+
+func (s *MemoryStore) run(fn func(t *memoryTables) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.tables)
+}
+
+func (s *MemoryStore) now() time.Time {
+	return s.clock.Now()
+}
+
+type (
+	userRoleKey         struct{ userId, roleId string }
+	shopWarehouseKey    struct{ shopId, warehouseId string }
+	productWarehouseKey struct{ productId, warehouseId string }
+	shopMemberKey       struct{ shopId, userId string }
+	shopProductKey      struct{ shopId, productId string }
+	priceListItemKey    struct{ priceListId, productId string }
+)
+
+// memoryUser is the users row, the deleted user is kept anonymized for the orders
+type memoryUser struct {
+	entity.User
+	DeletedAt *time.Time
+}
+
+type memoryAddress struct {
+	entity.Address
+	DeletedAt *time.Time
+}
+
+// memoryTables holds the rows by value, a row is replaced as a whole and never changed in place,
+// so a shallow copy of the tables is a snapshot that a unit of work can roll back to
+type memoryTables struct {
+	users             map[string]memoryUser
+	otps              map[string]entity.Otp
+	addresses         map[string]memoryAddress
+	rolePermissions   map[string][]string // seeded like the init migration, it is only read
+	userRoles         map[userRoleKey]bool
+	sessions          map[string]entity.Session
+	refreshTokens     map[string]entity.RefreshToken
+	apiKeys           map[string]entity.APIKey
+	warehouses        map[string]entity.Warehouse
+	shops             map[string]entity.Shop
+	shopWarehouses    map[shopWarehouseKey]bool // enabled
+	products          map[string]entity.Product
+	productWarehouses map[productWarehouseKey]int // total stock
+	shopMembers       map[shopMemberKey]entity.ShopMember
+	shopInvitations   map[string]entity.ShopInvitation
+	orders            map[string]entity.Order
+	orderItems        []entity.OrderItem // in the order of insertion, like the rows without ordering
+	payments          map[string]entity.Payment
+	shopProductPrices map[shopProductKey]int
+	priceHistory      map[string]entity.PriceHistory
+	priceLists        map[string]entity.PriceList // without the items
+	priceListItems    map[priceListItemKey]int
+	importJobs        map[string]entity.CatalogImportJob
+	privacyRequests   map[string]entity.PrivacyRequest
+}
+
+func newMemoryTables() *memoryTables {
+	return &memoryTables{
+		users:             map[string]memoryUser{},
+		otps:              map[string]entity.Otp{},
+		addresses:         map[string]memoryAddress{},
+		rolePermissions:   seedRolePermissions(),
+		userRoles:         map[userRoleKey]bool{},
+		sessions:          map[string]entity.Session{},
+		refreshTokens:     map[string]entity.RefreshToken{},
+		apiKeys:           map[string]entity.APIKey{},
+		warehouses:        map[string]entity.Warehouse{},
+		shops:             map[string]entity.Shop{},
+		shopWarehouses:    map[shopWarehouseKey]bool{},
+		products:          map[string]entity.Product{},
+		productWarehouses: map[productWarehouseKey]int{},
+		shopMembers:       map[shopMemberKey]entity.ShopMember{},
+		shopInvitations:   map[string]entity.ShopInvitation{},
+		orders:            map[string]entity.Order{},
+		payments:          map[string]entity.Payment{},
+		shopProductPrices: map[shopProductKey]int{},
+		priceHistory:      map[string]entity.PriceHistory{},
+		priceLists:        map[string]entity.PriceList{},
+		priceListItems:    map[priceListItemKey]int{},
+		importJobs:        map[string]entity.CatalogImportJob{},
+		privacyRequests:   map[string]entity.PrivacyRequest{},
+	}
+}
+
+func (t *memoryTables) clone() *memoryTables {
+	return &memoryTables{
+		users:             maps.Clone(t.users),
+		otps:              maps.Clone(t.otps),
+		addresses:         maps.Clone(t.addresses),
+		rolePermissions:   t.rolePermissions,
+		userRoles:         maps.Clone(t.userRoles),
+		sessions:          maps.Clone(t.sessions),
+		refreshTokens:     maps.Clone(t.refreshTokens),
+		apiKeys:           maps.Clone(t.apiKeys),
+		warehouses:        maps.Clone(t.warehouses),
+		shops:             maps.Clone(t.shops),
+		shopWarehouses:    maps.Clone(t.shopWarehouses),
+		products:          maps.Clone(t.products),
+		productWarehouses: maps.Clone(t.productWarehouses),
+		shopMembers:       maps.Clone(t.shopMembers),
+		shopInvitations:   maps.Clone(t.shopInvitations),
+		orders:            maps.Clone(t.orders),
+		orderItems:        slices.Clone(t.orderItems),
+		payments:          maps.Clone(t.payments),
+		shopProductPrices: maps.Clone(t.shopProductPrices),
+		priceHistory:      maps.Clone(t.priceHistory),
+		priceLists:        maps.Clone(t.priceLists),
+		priceListItems:    maps.Clone(t.priceListItems),
+		importJobs:        maps.Clone(t.importJobs),
+		privacyRequests:   maps.Clone(t.privacyRequests),
+	}
+}
+
+// seedRolePermissions returns the roles and their permissions that are inserted by the init migration
+func seedRolePermissions() map[string][]string {
+	return map[string][]string{
+		entity.RoleAdmin: {
+			entity.PermissionWarehouseRead, entity.PermissionWarehouseWrite, entity.PermissionShopRead, entity.PermissionShopWrite,
+			entity.PermissionShopManageAny, entity.PermissionProductRead, entity.PermissionProductWrite, entity.PermissionStockWrite,
+			entity.PermissionPriceWrite, entity.PermissionCatalogImport, entity.PermissionCatalogExport, entity.PermissionOrderCreate,
+			entity.PermissionOrderPay, entity.PermissionRoleAssign, entity.PermissionAPIKeyManage,
+		},
+		entity.RoleMerchant: {
+			entity.PermissionWarehouseRead, entity.PermissionShopRead, entity.PermissionShopWrite, entity.PermissionProductRead,
+			entity.PermissionPriceWrite, entity.PermissionOrderCreate, entity.PermissionOrderPay,
+		},
+		entity.RoleWarehouseOperator: {
+			entity.PermissionWarehouseRead, entity.PermissionWarehouseWrite, entity.PermissionShopRead, entity.PermissionProductRead,
+			entity.PermissionProductWrite, entity.PermissionStockWrite, entity.PermissionCatalogImport, entity.PermissionCatalogExport,
+		},
+		entity.RoleCustomer: {
+			entity.PermissionShopRead, entity.PermissionProductRead, entity.PermissionOrderCreate, entity.PermissionOrderPay,
+		},
+	}
+}
+
+// timePtr returns a pointer to a copy of the time, so the stored row does not share it with the caller
+func timePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := *t
+	return &value
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryPriceRepository struct {
+	db memoryDB
+}
+
+func NewMemoryPriceRepository(store *MemoryStore) PriceRepositoryInterface {
+	return &memoryPriceRepository{
+		db: store,
+	}
+}
+
+func (r *memoryPriceRepository) UpdateProductPrice(ctx context.Context, productId string, price int) error {
+	return r.db.run(func(t *memoryTables) error {
+		product, ok := t.products[productId]
+		if !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo update product price: product id '%s' is not found", productId))
+		}
+
+		product.Price = price
+		t.products[productId] = product
+		return nil
+	})
+}
+
+func (r *memoryPriceRepository) UpsertShopProductPrice(ctx context.Context, price *entity.ShopProductPrice) error {
+	return r.db.run(func(t *memoryTables) error {
+		_, shopOk := t.shops[price.ShopId]
+		_, productOk := t.products[price.ProductId]
+		if !shopOk || !productOk {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo upsert shop product price: shop '%s' or product '%s' is not found", price.ShopId, price.ProductId))
+		}
+
+		t.shopProductPrices[shopProductKey{price.ShopId, price.ProductId}] = price.Price
+		return nil
+	})
+}
+
+func (r *memoryPriceRepository) InsertPriceHistory(ctx context.Context, history *entity.PriceHistory) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.priceHistory[history.Id]; ok {
+			return fmt.Errorf("error repo insert price history: price history id '%s' already exists", history.Id)
+		}
+		if _, ok := t.products[history.ProductId]; !ok {
+			return fmt.Errorf("error repo insert price history: product '%s' is not found", history.ProductId)
+		}
+		if _, ok := t.shops[history.ShopId]; history.ShopId != "" && !ok {
+			return fmt.Errorf("error repo insert price history: shop '%s' is not found", history.ShopId)
+		}
+		if _, ok := t.users[history.ActorId]; history.ActorId != "" && !ok {
+			return fmt.Errorf("error repo insert price history: actor '%s' is not found", history.ActorId)
+		}
+
+		t.priceHistory[history.Id] = *history
+		return nil
+	})
+}
+
+func (r *memoryPriceRepository) GetPriceHistoryById(ctx context.Context, id string) (*entity.PriceHistory, error) {
+	var history entity.PriceHistory
+	err := r.db.run(func(t *memoryTables) error {
+		var ok bool
+		if history, ok = t.priceHistory[id]; !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get price history: price history id '%s' is not found", id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+func (r *memoryPriceRepository) InsertPriceList(ctx context.Context, priceList *entity.PriceList) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.priceLists[priceList.Id]; ok {
+			return fmt.Errorf("error repo insert price list: price list id '%s' already exists", priceList.Id)
+		}
+		if _, ok := t.shops[priceList.ShopId]; !ok {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo insert price list: shop '%s' is not found", priceList.ShopId))
+		}
+
+		t.priceLists[priceList.Id] = entity.PriceList{
+			Id:      priceList.Id,
+			ShopId:  priceList.ShopId,
+			Name:    priceList.Name,
+			StartAt: priceList.StartAt,
+			EndAt:   timePtr(priceList.EndAt),
+		}
+		return nil
+	})
+}
+
+func (r *memoryPriceRepository) InsertPriceListItems(ctx context.Context, items []*entity.PriceListItem) error {
+	return r.db.run(func(t *memoryTables) error {
+		if len(items) == 0 {
+			return fmt.Errorf("error repo insert price list items: items are empty")
+		}
+
+		keys := make(map[priceListItemKey]bool)
+		for _, item := range items {
+			_, priceListOk := t.priceLists[item.PriceListId]
+			_, productOk := t.products[item.ProductId]
+			if !priceListOk || !productOk {
+				return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo insert price list items: some products are not found"))
+			}
+
+			key := priceListItemKey{item.PriceListId, item.ProductId}
+			if _, ok := t.priceListItems[key]; ok || keys[key] {
+				return fmt.Errorf("error repo insert price list items: product '%s' is already in price list '%s'", item.ProductId, item.PriceListId)
+			}
+			keys[key] = true
+		}
+
+		for _, item := range items {
+			t.priceListItems[priceListItemKey{item.PriceListId, item.ProductId}] = item.Price
+		}
+		return nil
+	})
+}
+
+func (r *memoryPriceRepository) GetPriceLists(ctx context.Context, req *entity.GetPriceListsRequest) ([]*entity.PriceList, error) {
+	var priceLists []*entity.PriceList
+	err := r.db.run(func(t *memoryTables) error {
+		for _, priceList := range t.priceLists {
+			if priceList.ShopId != req.ShopId || (priceList.EndAt != nil && !priceList.EndAt.After(req.ActiveFrom)) {
+				continue
+			}
+
+			pl := priceList
+			pl.EndAt = timePtr(priceList.EndAt)
+			for key, price := range t.priceListItems {
+				if key.priceListId == pl.Id {
+					pl.Items = append(pl.Items, &entity.PriceListItem{PriceListId: pl.Id, ProductId: key.productId, Price: price})
+				}
+			}
+			// the items are inner joined, so a price list without items is not returned
+			if len(pl.Items) == 0 {
+				continue
+			}
+			slices.SortFunc(pl.Items, func(a, b *entity.PriceListItem) int {
+				return strings.Compare(a.ProductId, b.ProductId)
+			})
+
+			priceLists = append(priceLists, &pl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(priceLists, func(a, b *entity.PriceList) int {
+		if result := a.StartAt.Compare(b.StartAt); result != 0 {
+			return result
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return priceLists, nil
+}
+
+// GetProductPrices returns base price, shop override price and active price list price (the latest started one)
+// of the products at the time, see the postgres repository for the rules
+func (r *memoryPriceRepository) GetProductPrices(ctx context.Context, req *entity.GetProductPricesRequest) ([]*entity.ProductPrice, error) {
+	var prices []*entity.ProductPrice
+	err := r.db.run(func(t *memoryTables) error {
+		for _, product := range t.products {
+			if len(req.ProductIds) > 0 && !slices.Contains(req.ProductIds, product.Id) {
+				continue
+			}
+
+			baseHistory := latestMemoryPriceHistory(t, product.Id, "", req.At)
+			if baseHistory == nil {
+				continue
+			}
+			price := &entity.ProductPrice{ProductId: product.Id, BasePrice: baseHistory.Price, BasePriceHistoryId: baseHistory.Id}
+
+			if req.ShopId != "" {
+				if history := latestMemoryPriceHistory(t, product.Id, req.ShopId, req.At); history != nil {
+					price.ShopPrice, price.ShopPriceHistoryId = &history.Price, history.Id
+				}
+			}
+
+			if priceListId, itemPrice, ok := activeMemoryPriceListPrice(t, req.ShopId, product.Id, req.At); ok {
+				price.PriceListId, price.PriceListPrice = priceListId, &itemPrice
+			}
+
+			prices = append(prices, price)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(prices, func(a, b *entity.ProductPrice) int {
+		return strings.Compare(a.ProductId, b.ProductId)
+	})
+
+	return prices, nil
+}
+
+// activeMemoryPriceListPrice returns the price of the product in the active price list of the shop at the time (the latest started one)
+func activeMemoryPriceListPrice(t *memoryTables, shopId, productId string, at time.Time) (string, int, bool) {
+	var activePriceList *entity.PriceList
+	var price int
+	for key, itemPrice := range t.priceListItems {
+		priceList := t.priceLists[key.priceListId]
+		if key.productId != productId || priceList.ShopId != shopId || priceList.StartAt.After(at) ||
+			(priceList.EndAt != nil && !priceList.EndAt.After(at)) {
+			continue
+		}
+		if activePriceList == nil || priceList.StartAt.After(activePriceList.StartAt) ||
+			(priceList.StartAt.Equal(activePriceList.StartAt) && priceList.Id > activePriceList.Id) {
+			activePriceList = &priceList
+			price = itemPrice
+		}
+	}
+	if activePriceList == nil {
+		return "", 0, false
+	}
+	return activePriceList.Id, price, true
+}
+
+// latestMemoryPriceHistory returns the latest price history of the product at the time, empty shop id is the base price
+func latestMemoryPriceHistory(t *memoryTables, productId, shopId string, at time.Time) *entity.PriceHistory {
+	var latest *entity.PriceHistory
+	for _, history := range t.priceHistory {
+		if history.ProductId != productId || history.ShopId != shopId || history.CreatedAt.After(at) {
+			continue
+		}
+		if latest == nil || history.CreatedAt.After(latest.CreatedAt) ||
+			(history.CreatedAt.Equal(latest.CreatedAt) && history.Id > latest.Id) {
+			latest = &history
+		}
+	}
+	return latest
+}
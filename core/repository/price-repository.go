@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type PriceRepositoryInterface interface {
+	// product
+	UpdateProductPrice(ctx context.Context, productId string, price int) error
+
+	// shop_product_price
+	UpsertShopProductPrice(ctx context.Context, price *entity.ShopProductPrice) error
+
+	// price_history
+	InsertPriceHistory(ctx context.Context, history *entity.PriceHistory) error
+	GetPriceHistoryById(ctx context.Context, id string) (*entity.PriceHistory, error)
+
+	// price_list
+	InsertPriceList(ctx context.Context, priceList *entity.PriceList) error
+	InsertPriceListItems(ctx context.Context, items []*entity.PriceListItem) error
+	GetPriceLists(ctx context.Context, req *entity.GetPriceListsRequest) ([]*entity.PriceList, error)
+
+	// price resolution
+	GetProductPrices(ctx context.Context, req *entity.GetProductPricesRequest) ([]*entity.ProductPrice, error)
+}
+
+type priceRepository struct {
+	db DBTX
+}
+
+func NewPriceRepository(db *sql.DB) PriceRepositoryInterface {
+	return &priceRepository{
+		db: db,
+	}
+}
+
+const (
+	foreignKeyViolationErrorCode = "23503"
+)
+
+func (r *priceRepository) UpdateProductPrice(ctx context.Context, productId string, price int) error {
+	query := `UPDATE products SET price = $1 WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, price, productId)
+	if err != nil {
+		return fmt.Errorf("error repo update product price: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo update product price: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo update product price: product id '%s' is not found", productId))
+	}
+
+	return nil
+}
+
+func (r *priceRepository) UpsertShopProductPrice(ctx context.Context, price *entity.ShopProductPrice) error {
+	query := `INSERT INTO shop_product_prices (shop_id, product_id, price)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (shop_id, product_id)
+				DO UPDATE SET price = EXCLUDED.price`
+
+	_, err := r.db.ExecContext(ctx, query, price.ShopId, price.ProductId, price.Price)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolationErrorCode {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo upsert shop product price: shop '%s' or product '%s' is not found", price.ShopId, price.ProductId))
+		}
+		return fmt.Errorf("error repo upsert shop product price: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *priceRepository) InsertPriceHistory(ctx context.Context, history *entity.PriceHistory) error {
+	query := `INSERT INTO price_history (id, product_id, shop_id, source, price, actor_id, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	shopId := sql.NullString{String: history.ShopId, Valid: history.ShopId != ""}
+	actorId := sql.NullString{String: history.ActorId, Valid: history.ActorId != ""}
+
+	_, err := r.db.ExecContext(ctx, query, history.Id, history.ProductId, shopId, history.Source, history.Price, actorId, history.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error repo insert price history: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *priceRepository) GetPriceHistoryById(ctx context.Context, id string) (*entity.PriceHistory, error) {
+	query := `SELECT id, product_id, shop_id, source, price, actor_id, created_at
+				FROM price_history
+				WHERE id = $1`
+
+	history := &entity.PriceHistory{}
+	var shopId, actorId sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&history.Id, &history.ProductId, &shopId, &history.Source, &history.Price, &actorId, &history.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get price history: price history id '%s' is not found", id))
+		}
+		return nil, fmt.Errorf("error repo get price history: %v", err.Error())
+	}
+
+	history.ShopId = shopId.String
+	history.ActorId = actorId.String
+
+	return history, nil
+}
+
+func (r *priceRepository) InsertPriceList(ctx context.Context, priceList *entity.PriceList) error {
+	query := `INSERT INTO price_lists (id, shop_id, name, start_at, end_at) VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.db.ExecContext(ctx, query, priceList.Id, priceList.ShopId, priceList.Name, priceList.StartAt, priceList.EndAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolationErrorCode {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo insert price list: shop '%s' is not found", priceList.ShopId))
+		}
+		return fmt.Errorf("error repo insert price list: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *priceRepository) InsertPriceListItems(ctx context.Context, items []*entity.PriceListItem) error {
+	query := `INSERT INTO price_list_items (price_list_id, product_id, price) VALUES %s`
+
+	values := []interface{}{}
+	placeholders := []string{}
+
+	for i, item := range items {
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d)", i*3+1, i*3+2, i*3+3))
+		values = append(values, item.PriceListId, item.ProductId, item.Price)
+	}
+
+	query = fmt.Sprintf(query, strings.Join(placeholders, ", "))
+
+	_, err := r.db.ExecContext(ctx, query, values...)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolationErrorCode {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo insert price list items: some products are not found"))
+		}
+		return fmt.Errorf("error repo insert price list items: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *priceRepository) GetPriceLists(ctx context.Context, req *entity.GetPriceListsRequest) ([]*entity.PriceList, error) {
+	query := `SELECT pl.id, pl.shop_id, pl.name, pl.start_at, pl.end_at, pli.product_id, pli.price
+				FROM price_lists pl
+				INNER JOIN price_list_items pli
+				ON pl.id = pli.price_list_id
+				WHERE pl.shop_id = $1 AND (pl.end_at IS NULL OR pl.end_at > $2)
+				ORDER BY pl.start_at, pl.id, pli.product_id`
+
+	rows, err := r.db.QueryContext(ctx, query, req.ShopId, req.ActiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("error repo get price lists: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var priceLists []*entity.PriceList
+	priceListMap := make(map[string]*entity.PriceList)
+	for rows.Next() {
+		pl := &entity.PriceList{}
+		item := &entity.PriceListItem{}
+		var endAt sql.NullTime
+		err := rows.Scan(&pl.Id, &pl.ShopId, &pl.Name, &pl.StartAt, &endAt, &item.ProductId, &item.Price)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing, ok := priceListMap[pl.Id]; ok {
+			pl = existing
+		} else {
+			if endAt.Valid {
+				pl.EndAt = &endAt.Time
+			}
+			priceListMap[pl.Id] = pl
+			priceLists = append(priceLists, pl)
+		}
+
+		item.PriceListId = pl.Id
+		pl.Items = append(pl.Items, item)
+	}
+
+	return priceLists, nil
+}
+
+// GetProductPrices returns base price, shop override price and active price list price (the latest started one)
+// of the products at the time. Base & shop price are taken from the latest price history at the time,
+// a product that has no base price history at the time (not created yet) is not returned.
+func (r *priceRepository) GetProductPrices(ctx context.Context, req *entity.GetProductPricesRequest) ([]*entity.ProductPrice, error) {
+	qb := newQueryBuilder(`SELECT p.id, bph.price, bph.id, sph.price, sph.id, apl.price_list_id, apl.price
+				FROM products p
+				INNER JOIN LATERAL (
+					SELECT ph.id, ph.price
+					FROM price_history ph
+					WHERE ph.product_id = p.id AND ph.shop_id IS NULL AND ph.created_at <= $2
+					ORDER BY ph.created_at DESC, ph.id DESC
+					LIMIT 1
+				) bph ON true
+				LEFT JOIN LATERAL (
+					SELECT ph.id, ph.price
+					FROM price_history ph
+					WHERE ph.product_id = p.id AND ph.shop_id = $1 AND ph.created_at <= $2
+					ORDER BY ph.created_at DESC, ph.id DESC
+					LIMIT 1
+				) sph ON true
+				LEFT JOIN LATERAL (
+					SELECT pli.price_list_id, pli.price
+					FROM price_list_items pli
+					INNER JOIN price_lists pl
+					ON pl.id = pli.price_list_id
+					WHERE pl.shop_id = $1 AND pli.product_id = p.id
+					AND pl.start_at <= $2 AND (pl.end_at IS NULL OR pl.end_at > $2)
+					ORDER BY pl.start_at DESC, pl.id DESC
+					LIMIT 1
+				) apl ON true`)
+
+	qb.placeholder(req.ShopId)
+	qb.placeholder(req.At)
+	qb.whereIn("p.id", req.ProductIds)
+
+	query, values := qb.build()
+
+	rows, err := r.db.QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, fmt.Errorf("error repo get product prices: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var prices []*entity.ProductPrice
+	for rows.Next() {
+		price := &entity.ProductPrice{}
+		var shopPrice, priceListPrice sql.NullInt64
+		var basePriceHistoryId, shopPriceHistoryId, priceListId sql.NullString
+		err := rows.Scan(&price.ProductId, &price.BasePrice, &basePriceHistoryId, &shopPrice, &shopPriceHistoryId, &priceListId, &priceListPrice)
+		if err != nil {
+			return nil, err
+		}
+
+		price.BasePriceHistoryId = basePriceHistoryId.String
+		if shopPrice.Valid {
+			value := int(shopPrice.Int64)
+			price.ShopPrice = &value
+			price.ShopPriceHistoryId = shopPriceHistoryId.String
+		}
+		if priceListPrice.Valid {
+			value := int(priceListPrice.Int64)
+			price.PriceListId = priceListId.String
+			price.PriceListPrice = &value
+		}
+
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+}
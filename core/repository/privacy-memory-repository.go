@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryPrivacyRepository struct {
+	db memoryDB
+}
+
+func NewMemoryPrivacyRepository(store *MemoryStore) PrivacyRepositoryInterface {
+	return &memoryPrivacyRepository{
+		db: store,
+	}
+}
+
+func (r *memoryPrivacyRepository) GetAllAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	addresses := []*entity.Address{}
+	err := r.db.run(func(t *memoryTables) error {
+		for _, address := range t.addresses {
+			if address.UserId == userId {
+				addresses = append(addresses, &address.Address)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(addresses, func(a, b *entity.Address) int {
+		if result := a.CreatedAt.Compare(b.CreatedAt); result != 0 {
+			return result
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return addresses, nil
+}
+
+func (r *memoryPrivacyRepository) GetOrdersByUserId(ctx context.Context, userId string) ([]*entity.Order, error) {
+	orders := []*entity.Order{}
+	err := r.db.run(func(t *memoryTables) error {
+		for _, order := range t.orders {
+			if order.UserId == userId {
+				orders = append(orders, &order)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(orders, func(a, b *entity.Order) int {
+		if result := a.CreatedAt.Compare(b.CreatedAt); result != 0 {
+			return result
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return orders, nil
+}
+
+func (r *memoryPrivacyRepository) GetOrderItemsByOrderIds(ctx context.Context, orderIds []string) ([]*entity.OrderItem, error) {
+	items := []*entity.OrderItem{}
+	err := r.db.run(func(t *memoryTables) error {
+		for _, item := range t.orderItems {
+			if slices.Contains(orderIds, item.OrderId) {
+				items = append(items, &item)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(items, func(a, b *entity.OrderItem) int {
+		if result := strings.Compare(a.OrderId, b.OrderId); result != 0 {
+			return result
+		}
+		return strings.Compare(a.ProductId, b.ProductId)
+	})
+
+	return items, nil
+}
+
+func (r *memoryPrivacyRepository) GetPaymentsByOrderIds(ctx context.Context, orderIds []string) ([]*entity.Payment, error) {
+	payments := []*entity.Payment{}
+	err := r.db.run(func(t *memoryTables) error {
+		for _, payment := range t.payments {
+			if slices.Contains(orderIds, payment.OrderId) {
+				payments = append(payments, &payment)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(payments, func(a, b *entity.Payment) int {
+		if result := a.CreatedAt.Compare(b.CreatedAt); result != 0 {
+			return result
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return payments, nil
+}
+
+func (r *memoryPrivacyRepository) CountOwnedShops(ctx context.Context, userId string) (int, error) {
+	var count int
+	err := r.db.run(func(t *memoryTables) error {
+		for key, member := range t.shopMembers {
+			if key.userId == userId && member.Role == entity.ShopMemberRoleOwner {
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+func (r *memoryPrivacyRepository) AnonymizeUser(ctx context.Context, userId string, deletedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		user, ok := t.users[userId]
+		if !ok || user.DeletedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo anonymize user: user id '%s' is not found or already deleted", userId))
+		}
+
+		t.users[userId] = memoryUser{
+			User:      entity.User{Id: user.Id, CreatedAt: user.CreatedAt, UpdatedAt: deletedAt},
+			DeletedAt: &deletedAt,
+		}
+		return nil
+	})
+}
+
+func (r *memoryPrivacyRepository) AnonymizeAddresses(ctx context.Context, userId string, deletedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		for id, address := range t.addresses {
+			if address.UserId != userId {
+				continue
+			}
+
+			address.Label, address.RecipientName, address.PhoneNumber, address.Street, address.PostalCode = "", "", "", "", ""
+			address.IsDefault, address.UpdatedAt = false, deletedAt
+			if address.DeletedAt == nil {
+				address.DeletedAt = &deletedAt
+			}
+			t.addresses[id] = address
+		}
+		return nil
+	})
+}
+
+func (r *memoryPrivacyRepository) DeleteUserRecords(ctx context.Context, user *entity.User, deletedAt time.Time) ([]string, error) {
+	isIdentifier := func(identifier string) bool {
+		return identifier != "" && (identifier == user.Email || identifier == user.PhoneNumber)
+	}
+
+	sessionIds := []string{}
+	err := r.db.run(func(t *memoryTables) error {
+		for key := range t.userRoles {
+			if key.userId == user.Id {
+				delete(t.userRoles, key)
+			}
+		}
+		for key := range t.shopMembers {
+			if key.userId == user.Id {
+				delete(t.shopMembers, key)
+			}
+		}
+		for id, invitation := range t.shopInvitations {
+			if isIdentifier(invitation.Identifier) && invitation.AcceptedAt == nil {
+				delete(t.shopInvitations, id)
+			}
+		}
+		for id, otp := range t.otps {
+			if isIdentifier(otp.Identifier) {
+				delete(t.otps, id)
+			}
+		}
+		for id, apiKey := range t.apiKeys {
+			if apiKey.CreatedBy == user.Id && apiKey.RevokedAt == nil {
+				apiKey.RevokedAt = &deletedAt
+				t.apiKeys[id] = apiKey
+			}
+		}
+		for id, session := range t.sessions {
+			if session.UserId == user.Id && session.RevokedAt == nil {
+				session.RevokedAt = &deletedAt
+				t.sessions[id] = session
+				sessionIds = append(sessionIds, id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(sessionIds)
+	return sessionIds, nil
+}
+
+func (r *memoryPrivacyRepository) InsertPrivacyRequest(ctx context.Context, privacyRequest *entity.PrivacyRequest) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.privacyRequests[privacyRequest.Id]; ok {
+			return fmt.Errorf("error repo insert privacy request: privacy request id '%s' already exists", privacyRequest.Id)
+		}
+		if _, ok := t.users[privacyRequest.UserId]; !ok {
+			return fmt.Errorf("error repo insert privacy request: user '%s' is not found", privacyRequest.UserId)
+		}
+
+		stored := *privacyRequest
+		stored.CompletedAt = timePtr(privacyRequest.CompletedAt)
+		t.privacyRequests[privacyRequest.Id] = stored
+		return nil
+	})
+}
+
+func (r *memoryPrivacyRepository) CompletePrivacyRequest(ctx context.Context, id string, completedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		if privacyRequest, ok := t.privacyRequests[id]; ok {
+			privacyRequest.CompletedAt = &completedAt
+			t.privacyRequests[id] = privacyRequest
+		}
+		return nil
+	})
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PrivacyRepositoryInterface interface {
+	// export
+	GetAllAddresses(ctx context.Context, userId string) ([]*entity.Address, error)
+	GetOrdersByUserId(ctx context.Context, userId string) ([]*entity.Order, error)
+	GetOrderItemsByOrderIds(ctx context.Context, orderIds []string) ([]*entity.OrderItem, error)
+	GetPaymentsByOrderIds(ctx context.Context, orderIds []string) ([]*entity.Payment, error)
+
+	// deletion
+	CountOwnedShops(ctx context.Context, userId string) (int, error)
+	// AnonymizeUser clears the personal fields of the user, it returns not found error if the user is already deleted
+	AnonymizeUser(ctx context.Context, userId string, deletedAt time.Time) error
+	// AnonymizeAddresses clears the personal fields of the addresses, city, province and country are kept for the orders
+	AnonymizeAddresses(ctx context.Context, userId string, deletedAt time.Time) error
+	// DeleteUserRecords deletes the records that are only needed while the user exists: roles, shop memberships,
+	// pending invitations and otps of the identifiers, revokes the api keys that the user created and the sessions
+	// of the user. It returns the ids of the revoked sessions so they can be added to the revocation list.
+	DeleteUserRecords(ctx context.Context, user *entity.User, deletedAt time.Time) ([]string, error)
+
+	// privacy_request
+	InsertPrivacyRequest(ctx context.Context, privacyRequest *entity.PrivacyRequest) error
+	CompletePrivacyRequest(ctx context.Context, id string, completedAt time.Time) error
+}
+
+type privacyRepository struct {
+	db DBTX
+}
+
+func NewPrivacyRepository(db *sql.DB) PrivacyRepositoryInterface {
+	return &privacyRepository{
+		db: db,
+	}
+}
+
+func (r *privacyRepository) GetAllAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	query := `SELECT ` + addressColumns + ` FROM user_addresses WHERE user_id = $1 ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error repo get all addresses: %v", err.Error())
+	}
+	defer rows.Close()
+
+	addresses := []*entity.Address{}
+	for rows.Next() {
+		address, err := scanAddress(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("error repo get all addresses: %v", err.Error())
+		}
+		addresses = append(addresses, address)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo get all addresses: %v", err.Error())
+	}
+
+	return addresses, nil
+}
+
+func (r *privacyRepository) GetOrdersByUserId(ctx context.Context, userId string) ([]*entity.Order, error) {
+	query := `SELECT id, user_id, shop_id, status, amount, created_at, expired_at, COALESCE(shipping_address_id, '')
+				FROM orders
+				WHERE user_id = $1
+				ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error repo get orders by user id: %v", err.Error())
+	}
+	defer rows.Close()
+
+	orders := []*entity.Order{}
+	for rows.Next() {
+		order := &entity.Order{}
+		if err := rows.Scan(&order.Id, &order.UserId, &order.ShopId, &order.Status, &order.Amount, &order.CreatedAt,
+			&order.ExpiredAt, &order.ShippingAddressId); err != nil {
+			return nil, fmt.Errorf("error repo get orders by user id: %v", err.Error())
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo get orders by user id: %v", err.Error())
+	}
+
+	return orders, nil
+}
+
+func (r *privacyRepository) GetOrderItemsByOrderIds(ctx context.Context, orderIds []string) ([]*entity.OrderItem, error) {
+	query := `SELECT order_id, product_id, shop_id, warehouse_id, quantity, unit_price, COALESCE(price_source, ''),
+				COALESCE(price_history_id, ''), COALESCE(price_list_id, '')
+				FROM order_items
+				WHERE order_id = ANY($1)
+				ORDER BY order_id, product_id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(orderIds))
+	if err != nil {
+		return nil, fmt.Errorf("error repo get order items: %v", err.Error())
+	}
+	defer rows.Close()
+
+	items := []*entity.OrderItem{}
+	for rows.Next() {
+		item := &entity.OrderItem{}
+		if err := rows.Scan(&item.OrderId, &item.ProductId, &item.ShopId, &item.WarehouseId, &item.Quantity, &item.UnitPrice,
+			&item.PriceSource, &item.PriceHistoryId, &item.PriceListId); err != nil {
+			return nil, fmt.Errorf("error repo get order items: %v", err.Error())
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo get order items: %v", err.Error())
+	}
+
+	return items, nil
+}
+
+func (r *privacyRepository) GetPaymentsByOrderIds(ctx context.Context, orderIds []string) ([]*entity.Payment, error) {
+	query := `SELECT id, order_id, amount, status, created_at FROM payments WHERE order_id = ANY($1) ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(orderIds))
+	if err != nil {
+		return nil, fmt.Errorf("error repo get payments: %v", err.Error())
+	}
+	defer rows.Close()
+
+	payments := []*entity.Payment{}
+	for rows.Next() {
+		payment := &entity.Payment{}
+		if err := rows.Scan(&payment.Id, &payment.OrderId, &payment.Amount, &payment.Status, &payment.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error repo get payments: %v", err.Error())
+		}
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo get payments: %v", err.Error())
+	}
+
+	return payments, nil
+}
+
+func (r *privacyRepository) CountOwnedShops(ctx context.Context, userId string) (int, error) {
+	query := `SELECT COUNT(1) FROM shop_members WHERE user_id = $1 AND role = $2`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userId, entity.ShopMemberRoleOwner).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error repo count owned shops: %v", err.Error())
+	}
+
+	return count, nil
+}
+
+func (r *privacyRepository) AnonymizeUser(ctx context.Context, userId string, deletedAt time.Time) error {
+	query := `UPDATE users
+				SET name = '', email = NULL, email_verified = FALSE, phone_number = NULL, phone_number_verified = FALSE,
+					password_hash = NULL, updated_at = $1, deleted_at = $1
+				WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, deletedAt, userId)
+	if err != nil {
+		return fmt.Errorf("error repo anonymize user: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo anonymize user: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo anonymize user: user id '%s' is not found or already deleted", userId))
+	}
+
+	return nil
+}
+
+func (r *privacyRepository) AnonymizeAddresses(ctx context.Context, userId string, deletedAt time.Time) error {
+	query := `UPDATE user_addresses
+				SET label = '', recipient_name = '', phone_number = '', street = '', postal_code = '', is_default = FALSE,
+					updated_at = $1, deleted_at = COALESCE(deleted_at, $1)
+				WHERE user_id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, deletedAt, userId)
+	if err != nil {
+		return fmt.Errorf("error repo anonymize addresses: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *privacyRepository) DeleteUserRecords(ctx context.Context, user *entity.User, deletedAt time.Time) ([]string, error) {
+	identifiers := []string{}
+	for _, identifier := range []string{user.Email, user.PhoneNumber} {
+		if identifier != "" {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+
+	queries := []struct {
+		query  string
+		values []any
+	}{
+		{`DELETE FROM user_roles WHERE user_id = $1`, []any{user.Id}},
+		{`DELETE FROM shop_members WHERE user_id = $1`, []any{user.Id}},
+		{`DELETE FROM shop_invitations WHERE identifier = ANY($1) AND accepted_at IS NULL`, []any{pq.Array(identifiers)}},
+		{`DELETE FROM user_otps WHERE identifier = ANY($1)`, []any{pq.Array(identifiers)}},
+		{`UPDATE api_keys SET revoked_at = $1 WHERE created_by = $2 AND revoked_at IS NULL`, []any{deletedAt, user.Id}},
+	}
+
+	for _, q := range queries {
+		if _, err := r.db.ExecContext(ctx, q.query, q.values...); err != nil {
+			return nil, fmt.Errorf("error repo delete user records: %v", err.Error())
+		}
+	}
+
+	query := `UPDATE user_sessions SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL RETURNING id`
+
+	rows, err := r.db.QueryContext(ctx, query, deletedAt, user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error repo delete user records: %v", err.Error())
+	}
+	defer rows.Close()
+
+	sessionIds := []string{}
+	for rows.Next() {
+		var sessionId string
+		if err := rows.Scan(&sessionId); err != nil {
+			return nil, fmt.Errorf("error repo delete user records: %v", err.Error())
+		}
+		sessionIds = append(sessionIds, sessionId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo delete user records: %v", err.Error())
+	}
+
+	return sessionIds, nil
+}
+
+func (r *privacyRepository) InsertPrivacyRequest(ctx context.Context, privacyRequest *entity.PrivacyRequest) error {
+	query := `INSERT INTO privacy_requests (id, user_id, type, channel, requested_by, created_at, completed_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	requestedBy := sql.NullString{String: privacyRequest.RequestedBy, Valid: privacyRequest.RequestedBy != ""}
+
+	var completedAt sql.NullTime
+	if privacyRequest.CompletedAt != nil {
+		completedAt = sql.NullTime{Time: *privacyRequest.CompletedAt, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, query, privacyRequest.Id, privacyRequest.UserId, privacyRequest.Type, privacyRequest.Channel,
+		requestedBy, privacyRequest.CreatedAt, completedAt)
+	if err != nil {
+		return fmt.Errorf("error repo insert privacy request: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *privacyRepository) CompletePrivacyRequest(ctx context.Context, id string, completedAt time.Time) error {
+	query := `UPDATE privacy_requests SET completed_at = $1 WHERE id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, completedAt, id)
+	if err != nil {
+		return fmt.Errorf("error repo complete privacy request: %v", err.Error())
+	}
+
+	return nil
+}
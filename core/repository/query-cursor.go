@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+)
+
+// queryCursor is the decoded form of the opaque cursor that is given to the client
+type queryCursor struct {
+	Signature string   `json:"s"` // ordering that the cursor is created for
+	Values    []string `json:"v"` // key values of the last seen row
+	Backward  bool     `json:"b,omitempty"`
+}
+
+func (b *queryBuilder) encodeCursor(keyValues map[string]interface{}, backward bool) (string, error) {
+	cursor := queryCursor{
+		Signature: b.signature(),
+		Values:    make([]string, len(b.keys)),
+		Backward:  backward,
+	}
+	for i, key := range b.keys {
+		cursor.Values[i] = fmt.Sprint(keyValues[key.field])
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("error encode cursor: %v", err.Error())
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(encoded string) (*queryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errorutil.NewFieldValidationError("error cursor", &errorutil.FieldError{Field: "cursor", Message: "cursor is invalid"})
+	}
+
+	cursor := &queryCursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, errorutil.NewFieldValidationError("error cursor", &errorutil.FieldError{Field: "cursor", Message: "cursor is invalid"})
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"strconv"
+	"strings"
+)
+
+type queryFieldType int
+
+const (
+	queryFieldTypeString queryFieldType = iota
+	queryFieldTypeInt
+	queryFieldTypeBool
+)
+
+// queryFieldOperators whitelists the filter operators allowed per field type
+var queryFieldOperators = map[queryFieldType][]string{
+	queryFieldTypeString: {entity.FilterOperatorEq, entity.FilterOperatorNe, entity.FilterOperatorPrefix, entity.FilterOperatorContains, entity.FilterOperatorIn},
+	queryFieldTypeInt:    {entity.FilterOperatorEq, entity.FilterOperatorNe, entity.FilterOperatorGt, entity.FilterOperatorGte, entity.FilterOperatorLt, entity.FilterOperatorLte, entity.FilterOperatorIn},
+	queryFieldTypeBool:   {entity.FilterOperatorEq, entity.FilterOperatorNe},
+}
+
+var queryComparisonOperators = map[string]string{
+	entity.FilterOperatorEq:  "=",
+	entity.FilterOperatorNe:  "<>",
+	entity.FilterOperatorGt:  ">",
+	entity.FilterOperatorGte: ">=",
+	entity.FilterOperatorLt:  "<",
+	entity.FilterOperatorLte: "<=",
+}
+
+// queryField maps a field name exposed to the client into its column
+type queryField struct {
+	column    string
+	fieldType queryFieldType
+}
+
+// querySpec whitelists the fields that a list query can be sorted and filtered by
+type querySpec struct {
+	fields      map[string]queryField
+	defaultSort []*entity.Sort
+	tieBreakers []string // unique fields appended to the ordering so paging is deterministic
+}
+
+// queryKey is a field of the ordering, all keys together are unique so they are used as cursor
+type queryKey struct {
+	field string
+	queryField
+	desc bool
+}
+
+// queryBuilder builds parameterized select query, client values are never put into the query string
+type queryBuilder struct {
+	query      string
+	conditions []string
+	keys       []queryKey
+	limit      string
+	values     []interface{}
+
+	pageSize int
+	backward bool // fetch page before the cursor, the ordering is reversed then the rows are reversed back
+}
+
+func newQueryBuilder(query string) *queryBuilder {
+	return &queryBuilder{
+		query: query,
+	}
+}
+
+// placeholder registers the value and returns its placeholder
+func (b *queryBuilder) placeholder(value interface{}) string {
+	b.values = append(b.values, value)
+	return fmt.Sprintf("$%d", len(b.values))
+}
+
+// where adds a condition that has no value (e.g. `sw.enabled = true`)
+func (b *queryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+func (b *queryBuilder) whereEq(column string, value interface{}) {
+	b.conditions = append(b.conditions, fmt.Sprintf("%s = %s", column, b.placeholder(value)))
+}
+
+func (b *queryBuilder) whereIn(column string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = b.placeholder(value)
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ",")))
+}
+
+// applyFilters adds the filter conditions, it returns bad request error if the field or operator is not allowed
+func (b *queryBuilder) applyFilters(spec *querySpec, filters []*entity.Filter) error {
+	for _, filter := range filters {
+		field, ok := spec.fields[filter.Field]
+		if !ok {
+			return errorutil.NewFieldValidationError("error filter", &errorutil.FieldError{Field: "filter", Message: fmt.Sprintf("field '%s' is not filterable", filter.Field)})
+		}
+		if !isAllowedOperator(field.fieldType, filter.Operator) {
+			return errorutil.NewFieldValidationError("error filter", &errorutil.FieldError{Field: "filter", Message: fmt.Sprintf("operator '%s' is not allowed for field '%s'", filter.Operator, filter.Field)})
+		}
+
+		values := make([]interface{}, len(filter.Values))
+		for i, rawValue := range filter.Values {
+			value, err := parseQueryFieldValue(field.fieldType, rawValue)
+			if err != nil {
+				return errorutil.NewFieldValidationError("error filter", &errorutil.FieldError{Field: "filter", Message: fmt.Sprintf("value '%s' of field '%s' is invalid", rawValue, filter.Field)})
+			}
+			values[i] = value
+		}
+
+		switch filter.Operator {
+		case entity.FilterOperatorIn:
+			placeholders := make([]string, len(values))
+			for i, value := range values {
+				placeholders[i] = b.placeholder(value)
+			}
+			b.conditions = append(b.conditions, fmt.Sprintf("%s IN (%s)", field.column, strings.Join(placeholders, ",")))
+		case entity.FilterOperatorPrefix:
+			b.conditions = append(b.conditions, fmt.Sprintf("%s LIKE %s", field.column, b.placeholder(escapeLike(filter.Values[0])+"%")))
+		case entity.FilterOperatorContains:
+			b.conditions = append(b.conditions, fmt.Sprintf("%s LIKE %s", field.column, b.placeholder("%"+escapeLike(filter.Values[0])+"%")))
+		default:
+			b.conditions = append(b.conditions, fmt.Sprintf("%s %s %s", field.column, queryComparisonOperators[filter.Operator], b.placeholder(values[0])))
+		}
+	}
+
+	return nil
+}
+
+// applySorts sets the ordering, default sort is used if there is no sort requested
+func (b *queryBuilder) applySorts(spec *querySpec, sorts []*entity.Sort) error {
+	if len(sorts) == 0 {
+		sorts = spec.defaultSort
+	}
+
+	ordered := make(map[string]bool)
+	for _, sort := range sorts {
+		field, ok := spec.fields[sort.Field]
+		if !ok {
+			return errorutil.NewFieldValidationError("error sort", &errorutil.FieldError{Field: "sort", Message: fmt.Sprintf("field '%s' is not sortable", sort.Field)})
+		}
+		if sort.Direction != entity.SortDirectionAsc && sort.Direction != entity.SortDirectionDesc {
+			return errorutil.NewFieldValidationError("error sort", &errorutil.FieldError{Field: "sort", Message: fmt.Sprintf("direction '%s' is invalid", sort.Direction)})
+		}
+
+		if ordered[sort.Field] {
+			continue
+		}
+		ordered[sort.Field] = true
+		b.keys = append(b.keys, queryKey{field: sort.Field, queryField: field, desc: sort.Direction == entity.SortDirectionDesc})
+	}
+
+	for _, fieldName := range spec.tieBreakers {
+		if ordered[fieldName] {
+			continue
+		}
+		ordered[fieldName] = true
+		b.keys = append(b.keys, queryKey{field: fieldName, queryField: spec.fields[fieldName]})
+	}
+
+	return nil
+}
+
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// countQuery returns query to count all rows that match the conditions
+func (b *queryBuilder) countQuery() (string, []interface{}) {
+	return fmt.Sprintf(`SELECT COUNT(1) FROM (%s%s) AS derived`, b.query, b.whereClause()), b.values
+}
+
+// paginate sets limit & offset of the query based on pagination (that already has total),
+// if pagination has cursor then it uses keyset condition instead of offset.
+// One more row than page size is fetched to know whether there is a next page.
+func (b *queryBuilder) paginate(pagination *entity.Pagination) error {
+	pagination.Validate()
+	pagination.SetPagination()
+	b.pageSize = pagination.PageSize
+
+	if !pagination.IsCursor() {
+		offset := pagination.GetOffset()
+		b.limit = fmt.Sprintf(" LIMIT %s OFFSET %s", b.placeholder(pagination.PageSize+1), b.placeholder(offset))
+		return nil
+	}
+
+	cursor, err := decodeCursor(pagination.Cursor)
+	if err != nil {
+		return err
+	}
+	if cursor.Signature != b.signature() || len(cursor.Values) != len(b.keys) {
+		return errorutil.NewFieldValidationError("error cursor", &errorutil.FieldError{Field: "cursor", Message: "cursor does not match the requested sort"})
+	}
+
+	values := make([]interface{}, len(b.keys))
+	for i, key := range b.keys {
+		value, err := parseQueryFieldValue(key.fieldType, cursor.Values[i])
+		if err != nil {
+			return errorutil.NewFieldValidationError("error cursor", &errorutil.FieldError{Field: "cursor", Message: "cursor is invalid"})
+		}
+		values[i] = value
+	}
+
+	b.backward = cursor.Backward
+	b.whereAfterKeys(values)
+	b.limit = fmt.Sprintf(" LIMIT %s", b.placeholder(pagination.PageSize+1))
+
+	return nil
+}
+
+// whereAfterKeys adds keyset condition so only rows after (or before if backward) the key values are returned,
+// e.g. for keys (a ASC, b DESC): a > $1 OR (a = $1 AND b < $2)
+func (b *queryBuilder) whereAfterKeys(values []interface{}) {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = b.placeholder(value)
+	}
+
+	var orConditions []string
+	for i, key := range b.keys {
+		var andConditions []string
+		for j := 0; j < i; j++ {
+			andConditions = append(andConditions, fmt.Sprintf("%s = %s", b.keys[j].column, placeholders[j]))
+		}
+
+		operator := ">"
+		if key.desc != b.backward {
+			operator = "<"
+		}
+		andConditions = append(andConditions, fmt.Sprintf("%s %s %s", key.column, operator, placeholders[i]))
+
+		orConditions = append(orConditions, "("+strings.Join(andConditions, " AND ")+")")
+	}
+
+	b.conditions = append(b.conditions, "("+strings.Join(orConditions, " OR ")+")")
+}
+
+// signature identifies the ordering, so a cursor can not be used with another ordering
+func (b *queryBuilder) signature() string {
+	var parts []string
+	for _, key := range b.keys {
+		direction := entity.SortDirectionAsc
+		if key.desc {
+			direction = entity.SortDirectionDesc
+		}
+		parts = append(parts, key.field+":"+direction)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (b *queryBuilder) build() (string, []interface{}) {
+	query := b.query + b.whereClause()
+
+	if len(b.keys) > 0 {
+		orders := make([]string, len(b.keys))
+		for i, key := range b.keys {
+			direction := "ASC"
+			if key.desc != b.backward {
+				direction = "DESC"
+			}
+			orders[i] = fmt.Sprintf("%s %s", key.column, direction)
+		}
+		query += " ORDER BY " + strings.Join(orders, ", ")
+	}
+
+	return query + b.limit, b.values
+}
+
+// pageRows trims the extra row that is fetched by paginate, restores the order of backward page
+// and sets next & prev cursor of the pagination. keyValues returns the value of each key field of a row.
+func pageRows[T any](b *queryBuilder, pagination *entity.Pagination, rows []T, keyValues func(row T) map[string]interface{}) ([]T, error) {
+	hasMore := len(rows) > b.pageSize
+	if hasMore {
+		rows = rows[:b.pageSize]
+	}
+
+	if b.backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	pagination.NextCursor, pagination.PrevCursor = "", ""
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	// there is a next page if more rows found after this page, or this page is fetched backward
+	if (!b.backward && hasMore) || b.backward {
+		cursor, err := b.encodeCursor(keyValues(rows[len(rows)-1]), false)
+		if err != nil {
+			return nil, err
+		}
+		pagination.NextCursor = cursor
+	}
+
+	// there is a previous page if this page is after a cursor or an offset, or more rows found before this page
+	if (b.backward && hasMore) || (!b.backward && (pagination.IsCursor() || pagination.GetOffset() > 0)) {
+		cursor, err := b.encodeCursor(keyValues(rows[0]), true)
+		if err != nil {
+			return nil, err
+		}
+		pagination.PrevCursor = cursor
+	}
+
+	return rows, nil
+}
+
+func isAllowedOperator(fieldType queryFieldType, operator string) bool {
+	for _, allowed := range queryFieldOperators[fieldType] {
+		if allowed == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func parseQueryFieldValue(fieldType queryFieldType, value string) (interface{}, error) {
+	switch fieldType {
+	case queryFieldTypeInt:
+		return strconv.Atoi(value)
+	case queryFieldTypeBool:
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// escapeLike escapes LIKE wildcards so client value is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	t.Run("QueryBuilder_no sort requested_then order by default sort and tie breaker", func(t *testing.T) {
+		qb := newQueryBuilder(`SELECT id, name FROM shops`)
+
+		err := qb.applySorts(shopQuerySpec, nil)
+		query, values := qb.build()
+
+		assert.Nil(t, err)
+		assert.Equal(t, `SELECT id, name FROM shops ORDER BY name ASC, id ASC`, query)
+		assert.Empty(t, values)
+	})
+	t.Run("QueryBuilder_filters and sorts requested_then return parameterized query", func(t *testing.T) {
+		qb := newQueryBuilder(`SELECT p.id FROM products p`)
+		qb.whereEq("sw.shop_id", "SHP-1")
+
+		err := qb.applyFilters(productDetailQuerySpec, []*entity.Filter{
+			{Field: "price", Operator: entity.FilterOperatorGte, Values: []string{"1000"}},
+			{Field: "name", Operator: entity.FilterOperatorPrefix, Values: []string{"50%_off"}},
+			{Field: "warehouseId", Operator: entity.FilterOperatorIn, Values: []string{"WRH-1", "WRH-2"}},
+		})
+		assert.Nil(t, err)
+
+		err = qb.applySorts(productDetailQuerySpec, []*entity.Sort{{Field: "price", Direction: entity.SortDirectionDesc}})
+		assert.Nil(t, err)
+
+		err = qb.paginate(&entity.Pagination{Page: 2, PageSize: 10, Total: 30})
+		assert.Nil(t, err)
+		query, values := qb.build()
+
+		assert.Equal(t, `SELECT p.id FROM products p WHERE sw.shop_id = $1 AND COALESCE(apl.price, spp.price, p.price) >= $2 AND p.name LIKE $3 AND pw.warehouse_id IN ($4,$5)`+
+			` ORDER BY COALESCE(apl.price, spp.price, p.price) DESC, p.id ASC, pw.warehouse_id ASC LIMIT $6 OFFSET $7`, query)
+		assert.Equal(t, []interface{}{"SHP-1", 1000, `50\%\_off%`, "WRH-1", "WRH-2", 11, 10}, values)
+	})
+	t.Run("QueryBuilder_field is not whitelisted_then return bad request error", func(t *testing.T) {
+		qb := newQueryBuilder(`SELECT id, name FROM shops`)
+
+		err := qb.applySorts(shopQuerySpec, []*entity.Sort{{Field: "name; DROP TABLE shops", Direction: entity.SortDirectionAsc}})
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Equal(t, []*errorutil.FieldError{{Field: "sort", Message: "field 'name; DROP TABLE shops' is not sortable"}}, errorutil.GetFields(err))
+
+		err = qb.applyFilters(shopQuerySpec, []*entity.Filter{{Field: "password", Operator: entity.FilterOperatorEq, Values: []string{"x"}}})
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Equal(t, []*errorutil.FieldError{{Field: "filter", Message: "field 'password' is not filterable"}}, errorutil.GetFields(err))
+	})
+	t.Run("QueryBuilder_operator or value is invalid for the field_then return bad request error", func(t *testing.T) {
+		qb := newQueryBuilder(`SELECT id, name, enabled FROM warehouses`)
+
+		err := qb.applyFilters(warehouseQuerySpec, []*entity.Filter{{Field: "enabled", Operator: entity.FilterOperatorGt, Values: []string{"true"}}})
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+
+		err = qb.applyFilters(productDetailQuerySpec, []*entity.Filter{{Field: "price", Operator: entity.FilterOperatorEq, Values: []string{"cheap"}}})
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("QueryBuilder_next cursor is used_then return keyset query after the last row", func(t *testing.T) {
+		sorts := []*entity.Sort{{Field: "name", Direction: entity.SortDirectionDesc}}
+		keyValues := func(s *entity.Shop) map[string]interface{} {
+			return map[string]interface{}{"id": s.Id, "name": s.Name}
+		}
+
+		// first page, one more row is fetched so there is next page
+		firstPage := &entity.Pagination{Page: 1, PageSize: 2, Total: 5}
+		qb := newQueryBuilder(`SELECT id, name FROM shops`)
+		assert.Nil(t, qb.applySorts(shopQuerySpec, sorts))
+		assert.Nil(t, qb.paginate(firstPage))
+
+		shops, err := pageRows(qb, firstPage, []*entity.Shop{{Id: "SHP-3", Name: "c"}, {Id: "SHP-2", Name: "b"}, {Id: "SHP-1", Name: "a"}}, keyValues)
+		assert.Nil(t, err)
+		assert.Len(t, shops, 2)
+		assert.NotEmpty(t, firstPage.NextCursor)
+		assert.Empty(t, firstPage.PrevCursor)
+
+		// second page using next cursor
+		secondPage := &entity.Pagination{PageSize: 2, Cursor: firstPage.NextCursor, SkipTotal: true}
+		qb = newQueryBuilder(`SELECT id, name FROM shops`)
+		assert.Nil(t, qb.applySorts(shopQuerySpec, sorts))
+		assert.Nil(t, qb.paginate(secondPage))
+		query, values := qb.build()
+
+		assert.Equal(t, `SELECT id, name FROM shops WHERE ((name < $1) OR (name = $1 AND id > $2)) ORDER BY name DESC, id ASC LIMIT $3`, query)
+		assert.Equal(t, []interface{}{"b", "SHP-2", 3}, values)
+		assert.Equal(t, entity.TotalUnknown, secondPage.Total)
+
+		shops, err = pageRows(qb, secondPage, []*entity.Shop{{Id: "SHP-1", Name: "a"}}, keyValues)
+		assert.Nil(t, err)
+		assert.Len(t, shops, 1)
+		assert.Empty(t, secondPage.NextCursor)
+		assert.NotEmpty(t, secondPage.PrevCursor)
+
+		// go back using prev cursor, ordering is reversed then the rows are reversed back
+		prevPage := &entity.Pagination{PageSize: 2, Cursor: secondPage.PrevCursor}
+		qb = newQueryBuilder(`SELECT id, name FROM shops`)
+		assert.Nil(t, qb.applySorts(shopQuerySpec, sorts))
+		assert.Nil(t, qb.paginate(prevPage))
+		query, _ = qb.build()
+
+		assert.Equal(t, `SELECT id, name FROM shops WHERE ((name > $1) OR (name = $1 AND id < $2)) ORDER BY name ASC, id DESC LIMIT $3`, query)
+
+		shops, err = pageRows(qb, prevPage, []*entity.Shop{{Id: "SHP-2", Name: "b"}, {Id: "SHP-3", Name: "c"}}, keyValues)
+		assert.Nil(t, err)
+		assert.Equal(t, "SHP-3", shops[0].Id)
+		assert.NotEmpty(t, prevPage.NextCursor)
+		assert.Empty(t, prevPage.PrevCursor)
+	})
+	t.Run("QueryBuilder_cursor does not match the sort_then return bad request error", func(t *testing.T) {
+		pagination := &entity.Pagination{Page: 1, PageSize: 1}
+		qb := newQueryBuilder(`SELECT id, name FROM shops`)
+		assert.Nil(t, qb.applySorts(shopQuerySpec, nil))
+		assert.Nil(t, qb.paginate(pagination))
+		_, err := pageRows(qb, pagination, []*entity.Shop{{Id: "SHP-1", Name: "a"}, {Id: "SHP-2", Name: "b"}}, func(s *entity.Shop) map[string]interface{} {
+			return map[string]interface{}{"id": s.Id, "name": s.Name}
+		})
+		assert.Nil(t, err)
+
+		qb = newQueryBuilder(`SELECT id, name FROM shops`)
+		assert.Nil(t, qb.applySorts(shopQuerySpec, []*entity.Sort{{Field: "id", Direction: entity.SortDirectionAsc}}))
+		err = qb.paginate(&entity.Pagination{PageSize: 1, Cursor: pagination.NextCursor})
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+
+		err = qb.paginate(&entity.Pagination{PageSize: 1, Cursor: "not-a-cursor"})
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"sync"
+	"time"
+)
+
+// memoryRateLimitSweepInterval is how often the keys without a request in their window are removed,
+// like the redis keys that expire, so the keys of the callers that are gone do not grow the memory
+const memoryRateLimitSweepInterval = time.Minute
+
+// memoryRateLimitRepository is the in-memory sliding window, it is for tests and a single instance without redis
+type memoryRateLimitRepository struct {
+	mu        sync.Mutex
+	windows   map[string]*memoryRateLimitWindow
+	lastSweep time.Time
+}
+
+type memoryRateLimitWindow struct {
+	requests []time.Time // time of the requests in the window, the oldest is the first
+	window   time.Duration
+}
+
+func NewMemoryRateLimitRepository() RateLimitRepositoryInterface {
+	return &memoryRateLimitRepository{
+		windows: map[string]*memoryRateLimitWindow{},
+	}
+}
+
+func (r *memoryRateLimitRepository) AllowRequest(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*entity.RateLimitResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep(now)
+
+	keyWindow, ok := r.windows[key]
+	if !ok {
+		keyWindow = &memoryRateLimitWindow{}
+		r.windows[key] = keyWindow
+	}
+	keyWindow.window = window
+	keyWindow.evict(now)
+
+	requests := keyWindow.requests
+	if len(requests) >= limit {
+		return &entity.RateLimitResult{
+			Allowed:    false,
+			Limit:      limit,
+			Remaining:  0,
+			RetryAfter: requests[0].Add(window).Sub(now),
+		}, nil
+	}
+
+	keyWindow.requests = append(requests, now)
+	return &entity.RateLimitResult{
+		Allowed:   true,
+		Limit:     limit,
+		Remaining: limit - len(requests) - 1,
+	}, nil
+}
+
+// sweep removes the keys that have no request in their window, it runs at most once in the sweep interval
+func (r *memoryRateLimitRepository) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < memoryRateLimitSweepInterval {
+		return
+	}
+	r.lastSweep = now
+
+	for key, keyWindow := range r.windows {
+		keyWindow.evict(now)
+		if len(keyWindow.requests) == 0 {
+			delete(r.windows, key)
+		}
+	}
+}
+
+// evict removes the requests that are out of the window
+func (w *memoryRateLimitWindow) evict(now time.Time) {
+	windowStart := now.Add(-w.window)
+	i := 0
+	for i < len(w.requests) && !w.requests[i].After(windowStart) {
+		i++
+	}
+	w.requests = w.requests[i:]
+}
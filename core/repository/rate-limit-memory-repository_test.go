@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("MemoryRateLimit_requests under the limit_then allow with remaining", func(t *testing.T) {
+		repo := NewMemoryRateLimitRepository()
+
+		first, err := repo.AllowRequest(context.Background(), "key", 2, time.Minute, now)
+		assert.Nil(t, err)
+		assert.True(t, first.Allowed)
+		assert.Equal(t, 1, first.Remaining)
+
+		second, err := repo.AllowRequest(context.Background(), "key", 2, time.Minute, now.Add(time.Second))
+		assert.Nil(t, err)
+		assert.True(t, second.Allowed)
+		assert.Equal(t, 0, second.Remaining)
+	})
+	t.Run("MemoryRateLimit_limit is reached_then deny until the oldest request leaves the window", func(t *testing.T) {
+		repo := NewMemoryRateLimitRepository()
+		repo.AllowRequest(context.Background(), "key", 2, time.Minute, now)
+		repo.AllowRequest(context.Background(), "key", 2, time.Minute, now.Add(10*time.Second))
+
+		denied, err := repo.AllowRequest(context.Background(), "key", 2, time.Minute, now.Add(20*time.Second))
+		assert.Nil(t, err)
+		assert.False(t, denied.Allowed)
+		assert.Equal(t, 40*time.Second, denied.RetryAfter)
+
+		allowed, err := repo.AllowRequest(context.Background(), "key", 2, time.Minute, now.Add(time.Minute+time.Second))
+		assert.Nil(t, err)
+		assert.True(t, allowed.Allowed)
+		assert.Equal(t, 0, allowed.Remaining)
+	})
+	t.Run("MemoryRateLimit_different keys_then count separately", func(t *testing.T) {
+		repo := NewMemoryRateLimitRepository()
+		repo.AllowRequest(context.Background(), "key-1", 1, time.Minute, now)
+
+		result, err := repo.AllowRequest(context.Background(), "key-2", 1, time.Minute, now)
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+	})
+	t.Run("MemoryRateLimit_key has no request in its window_then remove the key on the next sweep", func(t *testing.T) {
+		repo := NewMemoryRateLimitRepository().(*memoryRateLimitRepository)
+		repo.AllowRequest(context.Background(), "key-1", 1, time.Minute, now)
+		repo.AllowRequest(context.Background(), "key-2", 1, time.Hour, now)
+
+		repo.AllowRequest(context.Background(), "key-3", 1, time.Minute, now.Add(time.Minute+memoryRateLimitSweepInterval))
+
+		assert.NotContains(t, repo.windows, "key-1")
+		assert.Contains(t, repo.windows, "key-2") // still in its window
+		assert.Contains(t, repo.windows, "key-3")
+	})
+}
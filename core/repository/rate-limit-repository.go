@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitRepositoryInterface counts requests of a key in a sliding window, the request is only counted if it is allowed
+type RateLimitRepositoryInterface interface {
+	AllowRequest(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*entity.RateLimitResult, error)
+}
+
+type redisRateLimitRepository struct {
+	redisClient *redis.Client
+}
+
+func NewRedisRateLimitRepository(redisClient *redis.Client) RateLimitRepositoryInterface {
+	return &redisRateLimitRepository{
+		redisClient: redisClient,
+	}
+}
+
+// slidingWindowScript keeps the time of each request in a sorted set, the requests out of the window are removed first.
+// It returns whether the request is allowed, the remaining requests and the milliseconds to retry.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return {1, limit - count - 1, 0}
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+return {0, 0, tonumber(oldest[2]) + window - now}
+`)
+
+func (r *redisRateLimitRepository) AllowRequest(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*entity.RateLimitResult, error) {
+	// the member must be unique, so requests in the same millisecond are counted separately
+	member, err := gonanoid.New()
+	if err != nil {
+		return nil, fmt.Errorf("error cache repo allow request in generating member: %v", err.Error())
+	}
+
+	result, err := slidingWindowScript.Run(ctx, r.redisClient, []string{generateRateLimitKey(key)},
+		now.UnixMilli(), window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("error cache repo allow request: %v", err.Error())
+	}
+
+	return &entity.RateLimitResult{
+		Allowed:    result[0] == 1,
+		Limit:      limit,
+		Remaining:  int(result[1]),
+		RetryAfter: time.Duration(result[2]) * time.Millisecond,
+	}, nil
+}
+
+func generateRateLimitKey(key string) string {
+	return fmt.Sprintf("rate-limit:%s", key)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+)
+
+type memoryRbacRepository struct {
+	db memoryDB
+}
+
+func NewMemoryRbacRepository(store *MemoryStore) RbacRepositoryInterface {
+	return &memoryRbacRepository{
+		db: store,
+	}
+}
+
+// GetUserRoles gets the roles of the user with the permissions of all the roles
+func (r *memoryRbacRepository) GetUserRoles(ctx context.Context, userId string) (*entity.UserRoles, error) {
+	userRoles := &entity.UserRoles{}
+	err := r.db.run(func(t *memoryTables) error {
+		for key := range t.userRoles {
+			if key.userId == userId {
+				userRoles.Roles = append(userRoles.Roles, key.roleId)
+			}
+		}
+		slices.Sort(userRoles.Roles)
+
+		// the permissions are in the order of the roles, then of their ids
+		seen := make(map[string]bool)
+		for _, role := range userRoles.Roles {
+			permissions := slices.Sorted(slices.Values(t.rolePermissions[role]))
+			for _, permission := range permissions {
+				if !seen[permission] {
+					seen[permission] = true
+					userRoles.Permissions = append(userRoles.Permissions, permission)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return userRoles, nil
+}
+
+// SetUserRoles replaces the roles of the user, unknown role or user returns bad request error
+func (r *memoryRbacRepository) SetUserRoles(ctx context.Context, userId string, roles []string) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.users[userId]; !ok {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo set user roles: user or role is not found"))
+		}
+		for _, role := range roles {
+			if _, ok := t.rolePermissions[role]; !ok {
+				return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo set user roles: user or role is not found"))
+			}
+		}
+
+		for key := range t.userRoles {
+			if key.userId == userId {
+				delete(t.userRoles, key)
+			}
+		}
+		for _, role := range roles {
+			t.userRoles[userRoleKey{userId, role}] = true
+		}
+		return nil
+	})
+}
+
+func (r *memoryRbacRepository) CountUsersByRole(ctx context.Context, role string) (int, error) {
+	var count int
+	err := r.db.run(func(t *memoryTables) error {
+		for key := range t.userRoles {
+			if key.roleId == role {
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, err
+}
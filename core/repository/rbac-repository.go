@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+
+	"github.com/lib/pq"
+)
+
+type RbacRepositoryInterface interface {
+	// user_role
+	GetUserRoles(ctx context.Context, userId string) (*entity.UserRoles, error)
+	SetUserRoles(ctx context.Context, userId string, roles []string) error
+	CountUsersByRole(ctx context.Context, role string) (int, error)
+}
+
+type rbacRepository struct {
+	db DBTX
+}
+
+func NewRbacRepository(db *sql.DB) RbacRepositoryInterface {
+	return &rbacRepository{
+		db: db,
+	}
+}
+
+// GetUserRoles gets the roles of the user with the permissions of all the roles
+func (r *rbacRepository) GetUserRoles(ctx context.Context, userId string) (*entity.UserRoles, error) {
+	query := `SELECT ur.role_id, COALESCE(rp.permission_id, '')
+				FROM user_roles ur
+				LEFT JOIN role_permissions rp
+				ON rp.role_id = ur.role_id
+				WHERE ur.user_id = $1
+				ORDER BY ur.role_id, rp.permission_id`
+
+	rows, err := r.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error repo get user roles: %v", err.Error())
+	}
+	defer rows.Close()
+
+	userRoles := &entity.UserRoles{}
+	roles := make(map[string]bool)
+	permissions := make(map[string]bool)
+	for rows.Next() {
+		var role, permission string
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, fmt.Errorf("error repo get user roles: %v", err.Error())
+		}
+		if !roles[role] {
+			roles[role] = true
+			userRoles.Roles = append(userRoles.Roles, role)
+		}
+		if permission != "" && !permissions[permission] {
+			permissions[permission] = true
+			userRoles.Permissions = append(userRoles.Permissions, permission)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo get user roles: %v", err.Error())
+	}
+
+	return userRoles, nil
+}
+
+// SetUserRoles replaces the roles of the user, unknown role or user returns bad request error
+func (r *rbacRepository) SetUserRoles(ctx context.Context, userId string, roles []string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = $1`, userId); err != nil {
+		return fmt.Errorf("error repo set user roles: %v", err.Error())
+	}
+
+	query := `INSERT INTO user_roles (user_id, role_id) SELECT $1, UNNEST($2::VARCHAR[])`
+
+	_, err := r.db.ExecContext(ctx, query, userId, pq.Array(roles))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolationErrorCode {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error repo set user roles: user or role is not found"))
+		}
+		return fmt.Errorf("error repo set user roles: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *rbacRepository) CountUsersByRole(ctx context.Context, role string) (int, error) {
+	query := `SELECT COUNT(1) FROM user_roles WHERE role_id = $1`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, role).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error repo count users by role: %v", err.Error())
+	}
+
+	return count, nil
+}
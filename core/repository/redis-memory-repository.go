@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"mfawzanid/warehouse-commerce/core/entity"
+	clockutil "mfawzanid/warehouse-commerce/utils/clock"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryCacheEntry is a key of redis with its expiration
+type memoryCacheEntry struct {
+	value     int
+	expiredAt time.Time
+}
+
+// memoryRedisRepository keeps the same keys as redis in memory, a key is gone once the clock reaches its expiration
+type memoryRedisRepository struct {
+	mu              sync.Mutex
+	clock           clockutil.Clock
+	orderExpireTime time.Duration // reserved stock is released when the order expires
+	entries         map[string]memoryCacheEntry
+}
+
+func NewMemoryRedisRepository(clock clockutil.Clock, orderExpireTime time.Duration) RedisRepositoryInterface {
+	return &memoryRedisRepository{
+		clock:           clock,
+		orderExpireTime: orderExpireTime,
+		entries:         map[string]memoryCacheEntry{},
+	}
+}
+
+func (r *memoryRedisRepository) set(key string, value int, expiration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[key] = memoryCacheEntry{value: value, expiredAt: r.clock.Now().Add(expiration)}
+}
+
+func (r *memoryRedisRepository) get(key string) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[key]
+	if ok && !r.clock.Now().Before(entry.expiredAt) {
+		delete(r.entries, key)
+		return 0, false
+	}
+	return entry.value, ok
+}
+
+// scan calls fn for each key that is not expired yet and matches the prefix, the expired keys are removed on the way
+func (r *memoryRedisRepository) scan(prefix string, fn func(key string, value int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	for key, entry := range r.entries {
+		if !now.Before(entry.expiredAt) {
+			delete(r.entries, key)
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			fn(key, entry.value)
+		}
+	}
+}
+
+func (r *memoryRedisRepository) LockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
+	r.set(generateProductReservedKey(req), req.Quantity, r.orderExpireTime)
+	return nil
+}
+
+func (r *memoryRedisRepository) InvalidateLockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, generateProductReservedKey(req))
+	return nil
+}
+
+func (r *memoryRedisRepository) GetReservedProductQuantity(ctx context.Context, productId, warehouseId string) (int, error) {
+	sum := 0
+	r.scan(strings.TrimSuffix(generateAllProductReservedKey(productId, warehouseId), "*"), func(key string, value int) {
+		sum += value
+	})
+
+	return sum, nil
+}
+
+func (r *memoryRedisRepository) GetReservedQuantityPerWarehouse(ctx context.Context) (map[string]int, error) {
+	quantities := make(map[string]int)
+	r.scan(strings.TrimSuffix(reservedKeyPattern, "*"), func(key string, value int) {
+		if warehouseId, ok := parseReservedKeyWarehouseId(key); ok {
+			quantities[warehouseId] += value
+		}
+	})
+
+	return quantities, nil
+}
+
+// RevokeSession adds the session to the revocation list, the expiration only needs to cover the lifetime of access token
+func (r *memoryRedisRepository) RevokeSession(ctx context.Context, sessionId string, expiration time.Duration) error {
+	r.set(generateRevokedSessionKey(sessionId), 1, expiration)
+	return nil
+}
+
+func (r *memoryRedisRepository) IsSessionRevoked(ctx context.Context, sessionId string) (bool, error) {
+	_, revoked := r.get(generateRevokedSessionKey(sessionId))
+	return revoked, nil
+}
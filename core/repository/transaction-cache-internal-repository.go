@@ -3,6 +3,12 @@ package repository
 import (
 	"fmt"
 	"mfawzanid/warehouse-commerce/core/entity"
+	"strings"
+)
+
+const (
+	reservedKeyPattern = "reserved:*"
+	reservedScanCount  = 1000
 )
 
 func generateProductReservedKey(req *entity.LockOrderProductRequest) string {
@@ -12,3 +18,12 @@ func generateProductReservedKey(req *entity.LockOrderProductRequest) string {
 func generateAllProductReservedKey(productId, warehouseId string) string {
 	return fmt.Sprintf("reserved:%s:%s:*", productId, warehouseId)
 }
+
+// parseReservedKeyWarehouseId returns the warehouse id of `reserved:{productId}:{warehouseId}:{userId}` key
+func parseReservedKeyWarehouseId(key string) (string, bool) {
+	parts := strings.Split(key, ":")
+	if len(parts) != 4 {
+		return "", false
+	}
+	return parts[2], true
+}
@@ -13,23 +13,29 @@ type RedisRepositoryInterface interface {
 	LockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error
 	InvalidateLockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error
 	GetReservedProductQuantity(ctx context.Context, productId, warehouseId string) (int, error)
+	// GetReservedQuantityPerWarehouse sums the reserved quantity of all products by the warehouse id
+	GetReservedQuantityPerWarehouse(ctx context.Context) (map[string]int, error)
+
+	// session
+	RevokeSession(ctx context.Context, sessionId string, expiration time.Duration) error
+	IsSessionRevoked(ctx context.Context, sessionId string) (bool, error)
 }
 
 type redisRepository struct {
-	redisClient *redis.Client
+	redisClient     *redis.Client
+	orderExpireTime time.Duration // reserved stock is released when the order expires
 }
 
-func NewRedisRepository(redisClient *redis.Client) RedisRepositoryInterface {
+func NewRedisRepository(redisClient *redis.Client, orderExpireTime time.Duration) RedisRepositoryInterface {
 	return &redisRepository{
-		redisClient: redisClient,
+		redisClient:     redisClient,
+		orderExpireTime: orderExpireTime,
 	}
 }
 
 func (r *redisRepository) LockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
 	key := generateProductReservedKey(req)
-	expiration := entity.OrderExpireTimeInMinute * time.Minute
-
-	_, err := r.redisClient.Set(ctx, key, req.Quantity, expiration).Result()
+	_, err := r.redisClient.Set(ctx, key, req.Quantity, r.orderExpireTime).Result()
 	if err != nil {
 		return fmt.Errorf("error cache repo lock order product: %v", err.Error())
 	}
@@ -63,3 +69,26 @@ func (r *redisRepository) GetReservedProductQuantity(ctx context.Context, produc
 
 	return sum, nil
 }
+
+func (r *redisRepository) GetReservedQuantityPerWarehouse(ctx context.Context) (map[string]int, error) {
+	quantities := make(map[string]int)
+
+	// scan does not block redis like keys, the reservations are many while the orders are pending
+	iter := r.redisClient.Scan(ctx, 0, reservedKeyPattern, reservedScanCount).Iterator()
+	for iter.Next(ctx) {
+		warehouseId, ok := parseReservedKeyWarehouseId(iter.Val())
+		if !ok {
+			continue
+		}
+		val, err := r.redisClient.Get(ctx, iter.Val()).Int()
+		if err != nil {
+			continue // the reservation is expired after it is scanned
+		}
+		quantities[warehouseId] += val
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("error cache repo getting reserved quantity per warehouse: %v", err.Error())
+	}
+
+	return quantities, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+)
+
+type memoryTransactionRepository struct {
+	db memoryDB
+}
+
+func NewMemoryTransactionRepository(store *MemoryStore) TransactionRepositoryInterface {
+	return &memoryTransactionRepository{
+		db: store,
+	}
+}
+
+func (r *memoryTransactionRepository) InsertOrder(ctx context.Context, order *entity.Order) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.orders[order.Id]; ok {
+			return fmt.Errorf("error repo insert order: order id '%s' already exists", order.Id)
+		}
+		if _, ok := t.users[order.UserId]; !ok {
+			return fmt.Errorf("error repo insert order: user '%s' is not found", order.UserId)
+		}
+		if _, ok := t.shops[order.ShopId]; !ok {
+			return fmt.Errorf("error repo insert order: shop '%s' is not found", order.ShopId)
+		}
+		if _, ok := t.addresses[order.ShippingAddressId]; order.ShippingAddressId != "" && !ok {
+			return fmt.Errorf("error repo insert order: address '%s' is not found", order.ShippingAddressId)
+		}
+
+		t.orders[order.Id] = *order
+		return nil
+	})
+}
+
+func (r *memoryTransactionRepository) UpdateOrder(ctx context.Context, req *entity.UpdateOrderRequest) error {
+	return r.db.run(func(t *memoryTables) error {
+		if order, ok := t.orders[req.OrderId]; ok {
+			order.Status = req.Status
+			t.orders[req.OrderId] = order
+		}
+		return nil
+	})
+}
+
+func (r *memoryTransactionRepository) GetOrderById(ctx context.Context, id string, isActive *bool) (*entity.Order, error) {
+	var order entity.Order
+	err := r.db.run(func(t *memoryTables) error {
+		var ok bool
+		order, ok = t.orders[id]
+		if ok && isActive != nil {
+			ok = *isActive == !order.ExpiredAt.Before(r.db.now())
+		}
+		if !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get order: order id '%v' is not found", id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// CountPendingOrders counts the orders of the user that are not paid and not expired yet
+func (r *memoryTransactionRepository) CountPendingOrders(ctx context.Context, userId string) (int, error) {
+	var count int
+	err := r.db.run(func(t *memoryTables) error {
+		now := r.db.now()
+		for _, order := range t.orders {
+			if order.UserId == userId && order.Status == entity.OrderStatusPending && !order.ExpiredAt.Before(now) {
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// LockPendingOrders does nothing, the memory unit of work already runs one transaction at a time
+func (r *memoryTransactionRepository) LockPendingOrders(ctx context.Context, userId string) error {
+	return nil
+}
+
+func (r *memoryTransactionRepository) CountExpiredOrders(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.run(func(t *memoryTables) error {
+		now := r.db.now()
+		for _, order := range t.orders {
+			if order.Status == entity.OrderStatusPending && order.ExpiredAt.Before(now) {
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+func (r *memoryTransactionRepository) InsertOrderItems(ctx context.Context, items []*entity.OrderItem) error {
+	return r.db.run(func(t *memoryTables) error {
+		if len(items) == 0 {
+			return fmt.Errorf("error repo insert order items: items are empty")
+		}
+		for _, item := range items {
+			if _, ok := t.orders[item.OrderId]; !ok {
+				return fmt.Errorf("error repo insert order items: order '%s' is not found", item.OrderId)
+			}
+			if err := checkMemoryProductWarehouse(t, &entity.ProductWarehouse{ProductId: item.ProductId, WarehouseId: item.WarehouseId}); err != nil {
+				return fmt.Errorf("error repo insert order items: %v", err.Error())
+			}
+		}
+
+		for _, item := range items {
+			t.orderItems = append(t.orderItems, *item)
+		}
+		return nil
+	})
+}
+
+func (r *memoryTransactionRepository) GetOrderItemsByOrderId(ctx context.Context, orderId string) ([]*entity.OrderItem, error) {
+	var items []*entity.OrderItem
+	err := r.db.run(func(t *memoryTables) error {
+		for _, item := range t.orderItems {
+			if item.OrderId == orderId {
+				items = append(items, &item)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *memoryTransactionRepository) InsertPayment(ctx context.Context, req *entity.Payment) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.payments[req.Id]; ok {
+			return fmt.Errorf("error repo insert payment: payment id '%s' already exists", req.Id)
+		}
+		if _, ok := t.orders[req.OrderId]; !ok {
+			return fmt.Errorf("error repo insert payment: order '%s' is not found", req.OrderId)
+		}
+
+		// the created time is the default of the column
+		t.payments[req.Id] = entity.Payment{
+			Id:        req.Id,
+			OrderId:   req.OrderId,
+			Amount:    req.Amount,
+			Status:    req.Status,
+			CreatedAt: r.db.now(),
+		}
+		return nil
+	})
+}
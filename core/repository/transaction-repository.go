@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"mfawzanid/warehouse-commerce/core/entity"
@@ -9,23 +10,27 @@ import (
 )
 
 type TransactionRepositoryInterface interface {
-	GetDb() *sql.DB
-
 	// order
-	InsertOrder(order *entity.Order) error
-	UpdateOrder(tx *sql.Tx, req *entity.UpdateOrderRequest) error
-	GetOrderById(id string, isActive *bool) (*entity.Order, error)
+	InsertOrder(ctx context.Context, order *entity.Order) error
+	UpdateOrder(ctx context.Context, req *entity.UpdateOrderRequest) error
+	GetOrderById(ctx context.Context, id string, isActive *bool) (*entity.Order, error)
+	CountPendingOrders(ctx context.Context, userId string) (int, error)
+	// LockPendingOrders locks the orders of the user until the transaction ends, so the pending orders
+	// are counted and a new one is inserted without a concurrent order of the same user in between
+	LockPendingOrders(ctx context.Context, userId string) error
+	// CountExpiredOrders counts the orders that are expired without being paid
+	CountExpiredOrders(ctx context.Context) (int, error)
 
 	// order_item
-	InsertOrderItems(items []*entity.OrderItem) error
-	GetOrderItemsByOrderId(orderId string) ([]*entity.OrderItem, error)
+	InsertOrderItems(ctx context.Context, items []*entity.OrderItem) error
+	GetOrderItemsByOrderId(ctx context.Context, orderId string) ([]*entity.OrderItem, error)
 
 	// payment
-	InsertPayment(tx *sql.Tx, req *entity.Payment) error
+	InsertPayment(ctx context.Context, req *entity.Payment) error
 }
 
 type transactionRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewTransactionRepository(db *sql.DB) TransactionRepositoryInterface {
@@ -34,15 +39,13 @@ func NewTransactionRepository(db *sql.DB) TransactionRepositoryInterface {
 	}
 }
 
-func (r *transactionRepository) GetDb() *sql.DB {
-	return r.db
-}
+func (r *transactionRepository) InsertOrder(ctx context.Context, order *entity.Order) error {
+	query := `INSERT INTO orders (id, user_id, shop_id, amount, status, created_at, expired_at, shipping_address_id) 
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
-func (r *transactionRepository) InsertOrder(order *entity.Order) error {
-	query := `INSERT INTO orders (id, user_id, shop_id, amount, status, created_at, expired_at) 
-				VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	shippingAddressId := sql.NullString{String: order.ShippingAddressId, Valid: order.ShippingAddressId != ""}
 
-	_, err := r.db.Exec(query, order.Id, order.UserId, order.ShopId, order.Amount, order.Status, order.CreatedAt, order.ExpiredAt)
+	_, err := r.db.ExecContext(ctx, query, order.Id, order.UserId, order.ShopId, order.Amount, order.Status, order.CreatedAt, order.ExpiredAt, shippingAddressId)
 	if err != nil {
 		return fmt.Errorf("error repo insert order: %v", err.Error())
 	}
@@ -50,12 +53,12 @@ func (r *transactionRepository) InsertOrder(order *entity.Order) error {
 	return nil
 }
 
-func (r *transactionRepository) UpdateOrder(tx *sql.Tx, req *entity.UpdateOrderRequest) error {
+func (r *transactionRepository) UpdateOrder(ctx context.Context, req *entity.UpdateOrderRequest) error {
 	query := `UPDATE orders 
 				SET status = $1 
 				WHERE id = $2`
 
-	_, err := tx.Exec(query, req.Status, req.OrderId)
+	_, err := r.db.ExecContext(ctx, query, req.Status, req.OrderId)
 	if err != nil {
 		return fmt.Errorf("error repo update order: %v", err.Error())
 	}
@@ -63,8 +66,8 @@ func (r *transactionRepository) UpdateOrder(tx *sql.Tx, req *entity.UpdateOrderR
 	return nil
 }
 
-func (r *transactionRepository) GetOrderById(id string, isActive *bool) (*entity.Order, error) {
-	query := `SELECT id, user_id, shop_id, status, amount, created_at, expired_at 
+func (r *transactionRepository) GetOrderById(ctx context.Context, id string, isActive *bool) (*entity.Order, error) {
+	query := `SELECT id, user_id, shop_id, status, amount, created_at, expired_at, COALESCE(shipping_address_id, '') 
 				FROM orders 
 				WHERE id = $1`
 
@@ -78,7 +81,7 @@ func (r *transactionRepository) GetOrderById(id string, isActive *bool) (*entity
 
 	order := &entity.Order{}
 
-	err := r.db.QueryRow(query, id).Scan(&order.Id, &order.UserId, &order.ShopId, &order.Status, &order.Amount, &order.CreatedAt, &order.ExpiredAt)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&order.Id, &order.UserId, &order.ShopId, &order.Status, &order.Amount, &order.CreatedAt, &order.ExpiredAt, &order.ShippingAddressId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get order: order id '%v' is not found", id))
@@ -90,15 +93,54 @@ func (r *transactionRepository) GetOrderById(id string, isActive *bool) (*entity
 	return order, nil
 }
 
-func (r *transactionRepository) InsertOrderItems(items []*entity.OrderItem) error {
-	query := `INSERT INTO order_items (order_id, product_id, shop_id, warehouse_id, quantity, unit_price) VALUES %s`
+// CountPendingOrders counts the orders of the user that are not paid and not expired yet
+func (r *transactionRepository) CountPendingOrders(ctx context.Context, userId string) (int, error) {
+	query := `SELECT COUNT(1) FROM orders WHERE user_id = $1 AND status = $2 AND expired_at >= NOW()`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userId, entity.OrderStatusPending).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error repo count pending orders: %v", err.Error())
+	}
+
+	return count, nil
+}
+
+// LockPendingOrders locks the row of the user, the orders of other users are not blocked
+func (r *transactionRepository) LockPendingOrders(ctx context.Context, userId string) error {
+	query := `SELECT id FROM users WHERE id = $1 FOR UPDATE`
+
+	var id string
+	if err := r.db.QueryRowContext(ctx, query, userId).Scan(&id); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error repo lock pending orders: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *transactionRepository) CountExpiredOrders(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(1) FROM orders WHERE status = $1 AND expired_at < NOW()`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, entity.OrderStatusPending).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error repo count expired orders: %v", err.Error())
+	}
+
+	return count, nil
+}
+
+func (r *transactionRepository) InsertOrderItems(ctx context.Context, items []*entity.OrderItem) error {
+	query := `INSERT INTO order_items (order_id, product_id, shop_id, warehouse_id, quantity, unit_price, price_source, price_history_id, price_list_id) VALUES %s`
 
 	values := []interface{}{}
 	placeholders := []string{}
 
 	for i, item := range items {
-		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", i*3+1, i*3+2, i*3+3, i*3+4, i*3+5, i*3+6))
-		values = append(values, item.OrderId, item.ProductId, item.ShopId, item.WarehouseId, item.Quantity, item.UnitPrice)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", i*9+1, i*9+2, i*9+3, i*9+4, i*9+5, i*9+6, i*9+7, i*9+8, i*9+9))
+		values = append(values, item.OrderId, item.ProductId, item.ShopId, item.WarehouseId, item.Quantity, item.UnitPrice,
+			item.PriceSource,
+			sql.NullString{String: item.PriceHistoryId, Valid: item.PriceHistoryId != ""},
+			sql.NullString{String: item.PriceListId, Valid: item.PriceListId != ""},
+		)
 	}
 
 	var queryValues string
@@ -106,7 +148,7 @@ func (r *transactionRepository) InsertOrderItems(items []*entity.OrderItem) erro
 
 	query = fmt.Sprintf(query, queryValues)
 
-	_, err := r.db.Exec(query, values...)
+	_, err := r.db.ExecContext(ctx, query, values...)
 	if err != nil {
 		return fmt.Errorf("error repo insert order items: %v", err.Error())
 	}
@@ -114,12 +156,12 @@ func (r *transactionRepository) InsertOrderItems(items []*entity.OrderItem) erro
 	return nil
 }
 
-func (r *transactionRepository) GetOrderItemsByOrderId(orderId string) ([]*entity.OrderItem, error) {
-	query := `SELECT order_id, product_id, shop_id, warehouse_id, quantity, unit_price 
+func (r *transactionRepository) GetOrderItemsByOrderId(ctx context.Context, orderId string) ([]*entity.OrderItem, error) {
+	query := `SELECT order_id, product_id, shop_id, warehouse_id, quantity, unit_price, price_source, price_history_id, price_list_id 
 				FROM order_items 
 				WHERE order_id = $1`
 
-	rows, err := r.db.Query(query, orderId)
+	rows, err := r.db.QueryContext(ctx, query, orderId)
 	if err != nil {
 		return nil, fmt.Errorf("error repo get order items: %v", err.Error())
 	}
@@ -127,21 +169,25 @@ func (r *transactionRepository) GetOrderItemsByOrderId(orderId string) ([]*entit
 	var items []*entity.OrderItem
 	for rows.Next() {
 		item := &entity.OrderItem{}
-		err := rows.Scan(&item.OrderId, &item.ProductId, &item.ShopId, &item.WarehouseId, &item.Quantity, &item.UnitPrice)
+		var priceSource, priceHistoryId, priceListId sql.NullString
+		err := rows.Scan(&item.OrderId, &item.ProductId, &item.ShopId, &item.WarehouseId, &item.Quantity, &item.UnitPrice, &priceSource, &priceHistoryId, &priceListId)
 		if err != nil {
 			return nil, err
 		}
+		item.PriceSource = priceSource.String
+		item.PriceHistoryId = priceHistoryId.String
+		item.PriceListId = priceListId.String
 		items = append(items, item)
 	}
 
 	return items, nil
 }
 
-func (r *transactionRepository) InsertPayment(tx *sql.Tx, req *entity.Payment) error {
+func (r *transactionRepository) InsertPayment(ctx context.Context, req *entity.Payment) error {
 	query := `INSERT INTO payments (id, order_id, amount, status) 
 				VALUES ($1, $2, $3, $4)`
 
-	_, err := tx.Exec(query, req.Id, req.OrderId, req.Amount, req.Status)
+	_, err := r.db.ExecContext(ctx, query, req.Id, req.OrderId, req.Amount, req.Status)
 	if err != nil {
 		return fmt.Errorf("error repo insert payment: %v", err.Error())
 	}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// memoryTx is the transaction of a memory unit of work, the store is already locked by Do
+type memoryTx struct {
+	store *MemoryStore
+}
+
+func (tx *memoryTx) run(fn func(t *memoryTables) error) error {
+	return fn(tx.store.tables)
+}
+
+func (tx *memoryTx) now() time.Time {
+	return tx.store.now()
+}
+
+func newMemoryRepositories(db memoryDB) *Repositories {
+	return &Repositories{
+		User:        &memoryUserRepository{db: db},
+		Auth:        &memoryAuthRepository{db: db},
+		Rbac:        &memoryRbacRepository{db: db},
+		APIKey:      &memoryAPIKeyRepository{db: db},
+		Inventory:   &memoryInventoryRepository{db: db},
+		Membership:  &memoryMembershipRepository{db: db},
+		Transaction: &memoryTransactionRepository{db: db},
+		Price:       &memoryPriceRepository{db: db},
+		Catalog:     &memoryCatalogRepository{db: db},
+		Privacy:     &memoryPrivacyRepository{db: db},
+	}
+}
+
+type memoryUnitOfWork struct {
+	store *MemoryStore
+}
+
+// NewMemoryUnitOfWork returns the unit of work of the memory storage, the work holds the store until it is done,
+// so fn must only use the given repositories, the ones of the store would wait for it
+func NewMemoryUnitOfWork(store *MemoryStore) UnitOfWorkInterface {
+	return &memoryUnitOfWork{
+		store: store,
+	}
+}
+
+func (u *memoryUnitOfWork) Do(ctx context.Context, fn func(repos *Repositories) error) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	snapshot := u.store.tables.clone()
+
+	// a panic in fn must not leave the half done writes
+	defer func() {
+		if p := recover(); p != nil {
+			u.store.tables = snapshot
+			panic(p)
+		}
+	}()
+
+	if err := fn(newMemoryRepositories(&memoryTx{store: u.store})); err != nil {
+		u.store.tables = snapshot
+		return err
+	}
+
+	return nil
+}
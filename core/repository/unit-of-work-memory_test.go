@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"mfawzanid/warehouse-commerce/core/entity"
+	clockutil "mfawzanid/warehouse-commerce/utils/clock"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	product := &entity.Product{Id: "PRODUCT-1", Name: "product", Price: 1000}
+	insertProduct := func(repos *Repositories) error {
+		return repos.Inventory.InsertProduct(ctx, product)
+	}
+
+	t.Run("Do_work succeeds_then keep the writes", func(t *testing.T) {
+		store := NewMemoryStore(clockutil.NewFake(time.Now()))
+
+		err := NewMemoryUnitOfWork(store).Do(ctx, insertProduct)
+
+		assert.Nil(t, err)
+		got, err := NewMemoryInventoryRepository(store).GetProductByName(ctx, product.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, product, got)
+	})
+	t.Run("Do_work returns error_then roll back the writes and return the error as is", func(t *testing.T) {
+		store := NewMemoryStore(clockutil.NewFake(time.Now()))
+		errWork := errors.New("work is failed")
+
+		err := NewMemoryUnitOfWork(store).Do(ctx, func(repos *Repositories) error {
+			if err := insertProduct(repos); err != nil {
+				return err
+			}
+			return errWork
+		})
+
+		assert.Equal(t, errWork, err)
+		_, err = NewMemoryInventoryRepository(store).GetProductByName(ctx, product.Name)
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+	})
+	t.Run("Do_work panics_then roll back the writes and panic", func(t *testing.T) {
+		store := NewMemoryStore(clockutil.NewFake(time.Now()))
+
+		assert.Panics(t, func() {
+			_ = NewMemoryUnitOfWork(store).Do(ctx, func(repos *Repositories) error {
+				_ = insertProduct(repos)
+				panic("unexpected")
+			})
+		})
+
+		// the store is not left locked
+		_, err := NewMemoryInventoryRepository(store).GetProductByName(ctx, product.Name)
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+	})
+}
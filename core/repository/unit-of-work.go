@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+)
+
+// DBTX is implemented by *sql.DB and *sql.Tx, so a repository runs its queries in the transaction it is bound to
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Repositories are bound to the transaction of a unit of work, a write must use them to be part of the transaction
+type Repositories struct {
+	User        UserRepositoryInterface
+	Auth        AuthRepositoryInterface
+	Rbac        RbacRepositoryInterface
+	APIKey      APIKeyRepositoryInterface
+	Inventory   InventoryRepositoryInterface
+	Membership  MembershipRepositoryInterface
+	Transaction TransactionRepositoryInterface
+	Price       PriceRepositoryInterface
+	Catalog     CatalogRepositoryInterface
+	Privacy     PrivacyRepositoryInterface
+}
+
+func newRepositories(db DBTX) *Repositories {
+	return &Repositories{
+		User:        &userRepository{db: db},
+		Auth:        &authRepository{db: db},
+		Rbac:        &rbacRepository{db: db},
+		APIKey:      &apiKeyRepository{db: db},
+		Inventory:   &inventoryRepository{db: db},
+		Membership:  &membershipRepository{db: db},
+		Transaction: &transactionRepository{db: db},
+		Price:       &priceRepository{db: db},
+		Catalog:     &catalogRepository{db: db},
+		Privacy:     &privacyRepository{db: db},
+	}
+}
+
+type UnitOfWorkInterface interface {
+	// Do runs fn in a transaction, it is committed if fn returns nil, otherwise it is rolled back and the error of fn is returned
+	Do(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+type unitOfWork struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewUnitOfWork(db *sql.DB, logger *slog.Logger) UnitOfWorkInterface {
+	return &unitOfWork{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos *Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		// the pool can not give a connection, e.g. the database is down
+		return errorutil.NewErrorCode(errorutil.ErrUnavailable, fmt.Errorf("error db in beginning transaction: %v", err.Error()))
+	}
+
+	// a panic in fn must not leave the transaction (and its connection) open
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(newRepositories(tx)); err != nil {
+		// the error of fn is the one to return (e.g. not found), the rollback error is only logged
+		if errRollback := tx.Rollback(); errRollback != nil {
+			u.logger.ErrorContext(ctx, "error db in rollback transaction", "error", errRollback)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error db in committing transaction: %v", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	revokeAPIKey := func(repos *Repositories) error {
+		return repos.APIKey.RevokeAPIKey(context.Background(), "KEY-1", time.Now())
+	}
+
+	t.Run("Do_work succeeds_then run the queries in the transaction and commit", func(t *testing.T) {
+		db, mockDB, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec("UPDATE api_keys").WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectCommit()
+
+		err = NewUnitOfWork(db, logutil.Discard()).Do(context.Background(), revokeAPIKey)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mockDB.ExpectationsWereMet())
+	})
+	t.Run("Do_work returns error_then rollback and return the error as is", func(t *testing.T) {
+		db, mockDB, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec("UPDATE api_keys").WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDB.ExpectRollback()
+
+		err = NewUnitOfWork(db, logutil.Discard()).Do(context.Background(), revokeAPIKey)
+
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+		assert.Nil(t, mockDB.ExpectationsWereMet())
+	})
+	t.Run("Do_commit is error_then return error", func(t *testing.T) {
+		db, mockDB, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec("UPDATE api_keys").WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectCommit().WillReturnError(errors.New("connection reset"))
+
+		err = NewUnitOfWork(db, logutil.Discard()).Do(context.Background(), revokeAPIKey)
+
+		assert.ErrorContains(t, err, "connection reset")
+		assert.Nil(t, mockDB.ExpectationsWereMet())
+	})
+	t.Run("Do_work panics_then rollback and panic", func(t *testing.T) {
+		db, mockDB, err := sqlmock.New()
+		assert.Nil(t, err)
+		defer db.Close()
+
+		mockDB.ExpectBegin()
+		mockDB.ExpectRollback()
+
+		assert.Panics(t, func() {
+			_ = NewUnitOfWork(db, logutil.Discard()).Do(context.Background(), func(repos *Repositories) error {
+				panic("unexpected")
+			})
+		})
+		assert.Nil(t, mockDB.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryUserRepository struct {
+	db memoryDB
+}
+
+func NewMemoryUserRepository(store *MemoryStore) UserRepositoryInterface {
+	return &memoryUserRepository{
+		db: store,
+	}
+}
+
+func (r *memoryUserRepository) GetUser(ctx context.Context, req *entity.GetUserRequest) (*entity.User, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var user entity.User
+	err := r.db.run(func(t *memoryTables) error {
+		for _, existing := range t.users {
+			if (req.Id != "" && existing.Id == req.Id) ||
+				(req.Id == "" && req.Email != "" && existing.Email == req.Email) ||
+				(req.Id == "" && req.Email == "" && req.PhoneNumber != "" && existing.PhoneNumber == req.PhoneNumber) {
+				user = existing.User
+				return nil
+			}
+		}
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get user by id '%s' or identifier '%s' or '%s'", req.Id, req.Email, req.PhoneNumber))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// InsertUser inserts the user with the default role, so a user always has a role
+func (r *memoryUserRepository) InsertUser(ctx context.Context, user *entity.User) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.users[user.Id]; ok {
+			return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo create user: identifier is already registered"))
+		}
+		if isMemoryIdentifierUsed(t, "", user.Email, user.PhoneNumber) {
+			return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo create user: identifier is already registered"))
+		}
+
+		t.users[user.Id] = memoryUser{User: *user}
+		t.userRoles[userRoleKey{user.Id, entity.DefaultRole}] = true
+		return nil
+	})
+}
+
+// isMemoryIdentifierUsed returns true if the email or the phone number is used by a user other than userId,
+// empty identifier is null in the database so it is never used
+func isMemoryIdentifierUsed(t *memoryTables, userId, email, phoneNumber string) bool {
+	for _, existing := range t.users {
+		if existing.Id == userId {
+			continue
+		}
+		if (email != "" && existing.Email == email) || (phoneNumber != "" && existing.PhoneNumber == phoneNumber) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryUserRepository) UpdateUserName(ctx context.Context, userId, name string, updatedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		user, ok := t.users[userId]
+		if !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo update user name: user id '%s' is not found", userId))
+		}
+
+		user.Name, user.UpdatedAt = name, updatedAt
+		t.users[userId] = user
+		return nil
+	})
+}
+
+func (r *memoryUserRepository) UpdateUserIdentifier(ctx context.Context, userId, identifierType, identifier string, updatedAt time.Time) error {
+	if identifierType != entity.IdentifierTypeEmail && identifierType != entity.IdentifierTypePhoneNumber {
+		return fmt.Errorf("error repo update user identifier: identifier type '%s' is not valid", identifierType)
+	}
+
+	return r.db.run(func(t *memoryTables) error {
+		user, ok := t.users[userId]
+		if !ok {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo update user identifier: user id '%s' is not found", userId))
+		}
+
+		if identifierType == entity.IdentifierTypeEmail {
+			if isMemoryIdentifierUsed(t, userId, identifier, "") {
+				return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo update user identifier: %s is used by other user", identifierType))
+			}
+			user.Email, user.EmailVerified = identifier, true
+		} else {
+			if isMemoryIdentifierUsed(t, userId, "", identifier) {
+				return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo update user identifier: %s is used by other user", identifierType))
+			}
+			user.PhoneNumber, user.PhoneNumberVerified = identifier, true
+		}
+		user.UpdatedAt = updatedAt
+
+		t.users[userId] = user
+		return nil
+	})
+}
+
+func (r *memoryUserRepository) GetAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	addresses := []*entity.Address{}
+	err := r.db.run(func(t *memoryTables) error {
+		for _, address := range t.addresses {
+			if address.UserId == userId && address.DeletedAt == nil {
+				addresses = append(addresses, &address.Address)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// default address first, then the oldest
+	slices.SortFunc(addresses, func(a, b *entity.Address) int {
+		if a.IsDefault != b.IsDefault {
+			return compareMemoryValues(b.IsDefault, a.IsDefault)
+		}
+		if result := a.CreatedAt.Compare(b.CreatedAt); result != 0 {
+			return result
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return addresses, nil
+}
+
+// GetAddress gets the address of the user, address of other user is not found
+func (r *memoryUserRepository) GetAddress(ctx context.Context, userId, id string) (*entity.Address, error) {
+	var address entity.Address
+	err := r.db.run(func(t *memoryTables) error {
+		existing, ok := t.addresses[id]
+		if !ok || existing.UserId != userId || existing.DeletedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get address: address id '%s' is not found", id))
+		}
+		address = existing.Address
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+func (r *memoryUserRepository) InsertAddress(ctx context.Context, address *entity.Address) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.addresses[address.Id]; ok {
+			return fmt.Errorf("error repo insert address: address id '%s' already exists", address.Id)
+		}
+		if _, ok := t.users[address.UserId]; !ok {
+			return fmt.Errorf("error repo insert address: user '%s' is not found", address.UserId)
+		}
+
+		t.addresses[address.Id] = memoryAddress{Address: *address}
+		return nil
+	})
+}
+
+func (r *memoryUserRepository) UpdateAddress(ctx context.Context, address *entity.Address) error {
+	return r.db.run(func(t *memoryTables) error {
+		existing, ok := t.addresses[address.Id]
+		if !ok || existing.UserId != address.UserId || existing.DeletedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo update address: address id '%s' is not found", address.Id))
+		}
+
+		// the owner and the created time are not updated
+		updated := *address
+		updated.CreatedAt = existing.CreatedAt
+		t.addresses[address.Id] = memoryAddress{Address: updated}
+		return nil
+	})
+}
+
+func (r *memoryUserRepository) UnsetDefaultAddress(ctx context.Context, userId string) error {
+	return r.db.run(func(t *memoryTables) error {
+		for id, address := range t.addresses {
+			if address.UserId == userId && address.IsDefault {
+				address.IsDefault = false
+				t.addresses[id] = address
+			}
+		}
+		return nil
+	})
+}
+
+func (r *memoryUserRepository) DeleteAddress(ctx context.Context, userId, id string, deletedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		address, ok := t.addresses[id]
+		if !ok || address.UserId != userId || address.DeletedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo delete address: address id '%s' is not found", id))
+		}
+
+		address.DeletedAt, address.IsDefault = &deletedAt, false
+		t.addresses[id] = address
+		return nil
+	})
+}
+
+func (r *memoryUserRepository) InsertOtp(ctx context.Context, otp *entity.Otp) error {
+	return r.db.run(func(t *memoryTables) error {
+		if _, ok := t.otps[otp.Id]; ok {
+			return fmt.Errorf("error repo insert otp: otp id '%s' already exists", otp.Id)
+		}
+
+		stored := *otp
+		stored.ConsumedAt = nil
+		t.otps[otp.Id] = stored
+		return nil
+	})
+}
+
+// GetLatestOtp gets the last requested otp, the older ones are not usable anymore
+func (r *memoryUserRepository) GetLatestOtp(ctx context.Context, identifier, purpose string) (*entity.Otp, error) {
+	var latest *entity.Otp
+	err := r.db.run(func(t *memoryTables) error {
+		for _, otp := range t.otps {
+			if otp.Identifier == identifier && otp.Purpose == purpose && (latest == nil || otp.CreatedAt.After(latest.CreatedAt)) {
+				latest = &otp
+			}
+		}
+		if latest == nil {
+			return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get latest otp: otp of identifier '%s' is not found", identifier))
+		}
+		latest.ConsumedAt = timePtr(latest.ConsumedAt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
+
+func (r *memoryUserRepository) CountOtpsSince(ctx context.Context, identifier string, since time.Time) (int, error) {
+	var count int
+	err := r.db.run(func(t *memoryTables) error {
+		for _, otp := range t.otps {
+			if otp.Identifier == identifier && !otp.CreatedAt.Before(since) {
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// IncreaseOtpAttempts reserves an attempt to verify the otp before the code is compared,
+// so concurrent attempts can not exceed the max attempts
+func (r *memoryUserRepository) IncreaseOtpAttempts(ctx context.Context, id string, maxAttempts int) error {
+	return r.db.run(func(t *memoryTables) error {
+		otp, ok := t.otps[id]
+		if !ok || otp.Attempts >= maxAttempts || otp.ConsumedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrTooManyRequests, fmt.Errorf("error repo increase otp attempts: otp '%s' is used or has reached max attempts", id))
+		}
+
+		otp.Attempts++
+		t.otps[id] = otp
+		return nil
+	})
+}
+
+// ConsumeOtp marks the otp as used, it returns unauthorized error if the otp is already used
+func (r *memoryUserRepository) ConsumeOtp(ctx context.Context, id string, consumedAt time.Time) error {
+	return r.db.run(func(t *memoryTables) error {
+		otp, ok := t.otps[id]
+		if !ok || otp.ConsumedAt != nil {
+			return errorutil.NewErrorCode(errorutil.ErrUnauthorized, fmt.Errorf("error repo consume otp: otp '%s' is already used", id))
+		}
+
+		otp.ConsumedAt = &consumedAt
+		t.otps[id] = otp
+		return nil
+	})
+}
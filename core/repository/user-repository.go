@@ -1,21 +1,42 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"mfawzanid/warehouse-commerce/core/entity"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
 
 	"github.com/lib/pq"
 )
 
 type UserRepositoryInterface interface {
-	GetUser(req *entity.GetUserRequest) (*entity.User, error)
-	InsertUser(user *entity.User) error
+	GetUser(ctx context.Context, req *entity.GetUserRequest) (*entity.User, error)
+	InsertUser(ctx context.Context, user *entity.User) error
+	UpdateUserName(ctx context.Context, userId, name string, updatedAt time.Time) error
+	// UpdateUserIdentifier sets the email or phone number as verified, it returns unique violation error if the identifier is used by other user
+	UpdateUserIdentifier(ctx context.Context, userId, identifierType, identifier string, updatedAt time.Time) error
+
+	// address
+	GetAddresses(ctx context.Context, userId string) ([]*entity.Address, error)
+	GetAddress(ctx context.Context, userId, id string) (*entity.Address, error)
+	InsertAddress(ctx context.Context, address *entity.Address) error
+	UpdateAddress(ctx context.Context, address *entity.Address) error
+	// UnsetDefaultAddress makes all addresses of the user not default, so a new default address can be set
+	UnsetDefaultAddress(ctx context.Context, userId string) error
+	DeleteAddress(ctx context.Context, userId, id string, deletedAt time.Time) error
+
+	// otp
+	InsertOtp(ctx context.Context, otp *entity.Otp) error
+	GetLatestOtp(ctx context.Context, identifier, purpose string) (*entity.Otp, error)
+	CountOtpsSince(ctx context.Context, identifier string, since time.Time) (int, error)
+	IncreaseOtpAttempts(ctx context.Context, id string, maxAttempts int) error
+	ConsumeOtp(ctx context.Context, id string, consumedAt time.Time) error
 }
 
 type userRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewUserRepository(db *sql.DB) UserRepositoryInterface {
@@ -28,15 +49,19 @@ const (
 	uniqueViolationErrorCode = "23505"
 )
 
-func (r *userRepository) GetUser(req *entity.GetUserRequest) (*entity.User, error) {
+func (r *userRepository) GetUser(ctx context.Context, req *entity.GetUserRequest) (*entity.User, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	query := `SELECT id, email, phone_number FROM users`
+	query := `SELECT id, name, COALESCE(email, ''), email_verified, COALESCE(phone_number, ''), phone_number_verified,
+				COALESCE(password_hash, ''), created_at, updated_at FROM users`
 
 	values := []interface{}{}
-	if req.Email != "" {
+	if req.Id != "" {
+		query += " WHERE id = $1"
+		values = append(values, req.Id)
+	} else if req.Email != "" {
 		query += " WHERE email = $1"
 		values = append(values, req.Email)
 	} else if req.PhoneNumber != "" {
@@ -46,10 +71,11 @@ func (r *userRepository) GetUser(req *entity.GetUserRequest) (*entity.User, erro
 
 	user := &entity.User{}
 
-	err := r.db.QueryRow(query, values...).Scan(&user.Id, &user.Email, &user.PhoneNumber)
+	err := r.db.QueryRowContext(ctx, query, values...).Scan(&user.Id, &user.Name, &user.Email, &user.EmailVerified, &user.PhoneNumber,
+		&user.PhoneNumberVerified, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get user by identifier '%s' or '%s'", req.Email, req.PhoneNumber))
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get user by id '%s' or identifier '%s' or '%s'", req.Id, req.Email, req.PhoneNumber))
 		} else {
 			return nil, fmt.Errorf("error repo get user: %v", err.Error())
 		}
@@ -58,16 +84,287 @@ func (r *userRepository) GetUser(req *entity.GetUserRequest) (*entity.User, erro
 	return user, nil
 }
 
-func (r *userRepository) InsertUser(user *entity.User) error {
-	query := "INSERT INTO users (id, email, phone_number) VALUES ($1, $2, $3)"
+func (r *userRepository) InsertUser(ctx context.Context, user *entity.User) error {
+	// the user is inserted with the default role in one statement, so a user always has a role
+	query := `WITH new_user AS (
+					INSERT INTO users (id, name, email, email_verified, phone_number, phone_number_verified, password_hash, created_at, updated_at)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id
+				)
+				INSERT INTO user_roles (user_id, role_id) SELECT id, $10 FROM new_user`
+
+	// empty identifier is stored as null, so it does not violate the unique constraint
+	email := sql.NullString{String: user.Email, Valid: user.Email != ""}
+	phoneNumber := sql.NullString{String: user.PhoneNumber, Valid: user.PhoneNumber != ""}
+	passwordHash := sql.NullString{String: user.PasswordHash, Valid: user.PasswordHash != ""}
 
-	_, err := r.db.Exec(query, user.Id, user.Email, user.PhoneNumber)
+	_, err := r.db.ExecContext(ctx, query, user.Id, user.Name, email, user.EmailVerified, phoneNumber, user.PhoneNumberVerified,
+		passwordHash, user.CreatedAt, user.UpdatedAt, entity.DefaultRole)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationErrorCode {
-			return nil
-		} else {
-			return fmt.Errorf("error repo create user: %v", err.Error())
+			return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo create user: identifier is already registered"))
 		}
+		return fmt.Errorf("error repo create user: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateUserName(ctx context.Context, userId, name string, updatedAt time.Time) error {
+	query := `UPDATE users SET name = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, name, updatedAt, userId)
+	if err != nil {
+		return fmt.Errorf("error repo update user name: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo update user name: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo update user name: user id '%s' is not found", userId))
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateUserIdentifier(ctx context.Context, userId, identifierType, identifier string, updatedAt time.Time) error {
+	var query string
+	if identifierType == entity.IdentifierTypeEmail {
+		query = `UPDATE users SET email = $1, email_verified = TRUE, updated_at = $2 WHERE id = $3`
+	} else if identifierType == entity.IdentifierTypePhoneNumber {
+		query = `UPDATE users SET phone_number = $1, phone_number_verified = TRUE, updated_at = $2 WHERE id = $3`
+	} else {
+		return fmt.Errorf("error repo update user identifier: identifier type '%s' is not valid", identifierType)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, identifier, updatedAt, userId)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationErrorCode {
+			return errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo update user identifier: %s is used by other user", identifierType))
+		}
+		return fmt.Errorf("error repo update user identifier: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo update user identifier: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo update user identifier: user id '%s' is not found", userId))
+	}
+
+	return nil
+}
+
+const addressColumns = `id, user_id, label, recipient_name, phone_number, street, city, province, postal_code, country, is_default, created_at, updated_at`
+
+func (r *userRepository) GetAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	query := `SELECT ` + addressColumns + ` FROM user_addresses
+				WHERE user_id = $1 AND deleted_at IS NULL
+				ORDER BY is_default DESC, created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error repo get addresses: %v", err.Error())
+	}
+	defer rows.Close()
+
+	addresses := []*entity.Address{}
+	for rows.Next() {
+		address, err := scanAddress(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("error repo get addresses: %v", err.Error())
+		}
+		addresses = append(addresses, address)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error repo get addresses: %v", err.Error())
+	}
+
+	return addresses, nil
+}
+
+// GetAddress gets the address of the user, address of other user is not found
+func (r *userRepository) GetAddress(ctx context.Context, userId, id string) (*entity.Address, error) {
+	query := `SELECT ` + addressColumns + ` FROM user_addresses WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+
+	address, err := scanAddress(r.db.QueryRowContext(ctx, query, id, userId).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get address: address id '%s' is not found", id))
+		}
+		return nil, fmt.Errorf("error repo get address: %v", err.Error())
+	}
+
+	return address, nil
+}
+
+func (r *userRepository) InsertAddress(ctx context.Context, address *entity.Address) error {
+	query := `INSERT INTO user_addresses (id, user_id, label, recipient_name, phone_number, street, city, province, postal_code, country, is_default, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err := r.db.ExecContext(ctx, query, address.Id, address.UserId, address.Label, address.RecipientName, address.PhoneNumber, address.Street,
+		address.City, address.Province, address.PostalCode, address.Country, address.IsDefault, address.CreatedAt, address.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error repo insert address: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateAddress(ctx context.Context, address *entity.Address) error {
+	query := `UPDATE user_addresses
+				SET label = $1, recipient_name = $2, phone_number = $3, street = $4, city = $5, province = $6, postal_code = $7,
+					country = $8, is_default = $9, updated_at = $10
+				WHERE id = $11 AND user_id = $12 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, address.Label, address.RecipientName, address.PhoneNumber, address.Street, address.City, address.Province,
+		address.PostalCode, address.Country, address.IsDefault, address.UpdatedAt, address.Id, address.UserId)
+	if err != nil {
+		return fmt.Errorf("error repo update address: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo update address: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo update address: address id '%s' is not found", address.Id))
+	}
+
+	return nil
+}
+
+func (r *userRepository) UnsetDefaultAddress(ctx context.Context, userId string) error {
+	query := `UPDATE user_addresses SET is_default = FALSE WHERE user_id = $1 AND is_default`
+
+	_, err := r.db.ExecContext(ctx, query, userId)
+	if err != nil {
+		return fmt.Errorf("error repo unset default address: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (r *userRepository) DeleteAddress(ctx context.Context, userId, id string, deletedAt time.Time) error {
+	query := `UPDATE user_addresses SET deleted_at = $1, is_default = FALSE WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, deletedAt, id, userId)
+	if err != nil {
+		return fmt.Errorf("error repo delete address: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo delete address: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo delete address: address id '%s' is not found", id))
+	}
+
+	return nil
+}
+
+// scanAddress scans the address columns with the scan function of a row or rows
+func scanAddress(scan func(dest ...any) error) (*entity.Address, error) {
+	address := &entity.Address{}
+
+	err := scan(&address.Id, &address.UserId, &address.Label, &address.RecipientName, &address.PhoneNumber, &address.Street,
+		&address.City, &address.Province, &address.PostalCode, &address.Country, &address.IsDefault, &address.CreatedAt, &address.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (r *userRepository) InsertOtp(ctx context.Context, otp *entity.Otp) error {
+	query := `INSERT INTO user_otps (id, identifier, purpose, code_hash, attempts, expired_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.ExecContext(ctx, query, otp.Id, otp.Identifier, otp.Purpose, otp.CodeHash, otp.Attempts, otp.ExpiredAt, otp.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error repo insert otp: %v", err.Error())
+	}
+
+	return nil
+}
+
+// GetLatestOtp gets the last requested otp, the older ones are not usable anymore
+func (r *userRepository) GetLatestOtp(ctx context.Context, identifier, purpose string) (*entity.Otp, error) {
+	query := `SELECT id, identifier, purpose, code_hash, attempts, expired_at, consumed_at, created_at
+				FROM user_otps
+				WHERE identifier = $1 AND purpose = $2
+				ORDER BY created_at DESC
+				LIMIT 1`
+
+	otp := &entity.Otp{}
+	var consumedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, identifier, purpose).Scan(&otp.Id, &otp.Identifier, &otp.Purpose, &otp.CodeHash, &otp.Attempts, &otp.ExpiredAt, &consumedAt, &otp.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error repo get latest otp: otp of identifier '%s' is not found", identifier))
+		}
+		return nil, fmt.Errorf("error repo get latest otp: %v", err.Error())
+	}
+
+	if consumedAt.Valid {
+		otp.ConsumedAt = &consumedAt.Time
+	}
+
+	return otp, nil
+}
+
+func (r *userRepository) CountOtpsSince(ctx context.Context, identifier string, since time.Time) (int, error) {
+	query := `SELECT COUNT(1) FROM user_otps WHERE identifier = $1 AND created_at >= $2`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, identifier, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error repo count otps: %v", err.Error())
+	}
+
+	return count, nil
+}
+
+// IncreaseOtpAttempts reserves an attempt to verify the otp before the code is compared,
+// so concurrent attempts can not exceed the max attempts
+func (r *userRepository) IncreaseOtpAttempts(ctx context.Context, id string, maxAttempts int) error {
+	query := `UPDATE user_otps SET attempts = attempts + 1 WHERE id = $1 AND attempts < $2 AND consumed_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, maxAttempts)
+	if err != nil {
+		return fmt.Errorf("error repo increase otp attempts: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo increase otp attempts: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrTooManyRequests, fmt.Errorf("error repo increase otp attempts: otp '%s' is used or has reached max attempts", id))
+	}
+
+	return nil
+}
+
+// ConsumeOtp marks the otp as used, it returns unauthorized error if the otp is already used
+func (r *userRepository) ConsumeOtp(ctx context.Context, id string, consumedAt time.Time) error {
+	query := `UPDATE user_otps SET consumed_at = $1 WHERE id = $2 AND consumed_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, consumedAt, id)
+	if err != nil {
+		return fmt.Errorf("error repo consume otp: %v", err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error repo consume otp: %v", err.Error())
+	}
+	if rowsAffected == 0 {
+		return errorutil.NewErrorCode(errorutil.ErrUnauthorized, fmt.Errorf("error repo consume otp: otp '%s' is already used", id))
 	}
 
 	return nil
@@ -0,0 +1,339 @@
+package tracing
+
+import (
+	"context"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	traceutil "mfawzanid/warehouse-commerce/utils/trace"
+	"time"
+)
+
+// inventoryRepository starts a span for every query of the inventory
+type inventoryRepository struct {
+	repository.InventoryRepositoryInterface
+}
+
+func WrapInventoryRepository(repo repository.InventoryRepositoryInterface) repository.InventoryRepositoryInterface {
+	return &inventoryRepository{repo}
+}
+
+func (r *inventoryRepository) InsertWarehouse(ctx context.Context, warehouse *entity.Warehouse) error {
+	ctx, span := start(ctx, "InventoryRepository.InsertWarehouse", dbSystemPostgres)
+	err := r.InventoryRepositoryInterface.InsertWarehouse(ctx, warehouse)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *inventoryRepository) UpdateWarehouseStatus(ctx context.Context, req *entity.UpdateWarehouseStatusRequest) error {
+	ctx, span := start(ctx, "InventoryRepository.UpdateWarehouseStatus", dbSystemPostgres)
+	err := r.InventoryRepositoryInterface.UpdateWarehouseStatus(ctx, req)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *inventoryRepository) GetWarehouses(ctx context.Context, req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
+	ctx, span := start(ctx, "InventoryRepository.GetWarehouses", dbSystemPostgres)
+	result, err := r.InventoryRepositoryInterface.GetWarehouses(ctx, req)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *inventoryRepository) InsertShop(ctx context.Context, shop *entity.Shop) error {
+	ctx, span := start(ctx, "InventoryRepository.InsertShop", dbSystemPostgres)
+	err := r.InventoryRepositoryInterface.InsertShop(ctx, shop)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *inventoryRepository) GetShops(ctx context.Context, req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
+	ctx, span := start(ctx, "InventoryRepository.GetShops", dbSystemPostgres)
+	result, err := r.InventoryRepositoryInterface.GetShops(ctx, req)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *inventoryRepository) InsertShopWarehouses(ctx context.Context, req *entity.UpsertShopToWarehousesRequest) error {
+	ctx, span := start(ctx, "InventoryRepository.InsertShopWarehouses", dbSystemPostgres)
+	err := r.InventoryRepositoryInterface.InsertShopWarehouses(ctx, req)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *inventoryRepository) InsertProduct(ctx context.Context, product *entity.Product) error {
+	ctx, span := start(ctx, "InventoryRepository.InsertProduct", dbSystemPostgres)
+	err := r.InventoryRepositoryInterface.InsertProduct(ctx, product)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *inventoryRepository) InsertProductWarehouse(ctx context.Context, pw *entity.ProductWarehouse) error {
+	ctx, span := start(ctx, "InventoryRepository.InsertProductWarehouse", dbSystemPostgres)
+	err := r.InventoryRepositoryInterface.InsertProductWarehouse(ctx, pw)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *inventoryRepository) GetProductByName(ctx context.Context, name string) (*entity.Product, error) {
+	ctx, span := start(ctx, "InventoryRepository.GetProductByName", dbSystemPostgres)
+	result, err := r.InventoryRepositoryInterface.GetProductByName(ctx, name)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *inventoryRepository) GetProductDetailsByShopId(ctx context.Context, req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
+	ctx, span := start(ctx, "InventoryRepository.GetProductDetailsByShopId", dbSystemPostgres)
+	result, err := r.InventoryRepositoryInterface.GetProductDetailsByShopId(ctx, req)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *inventoryRepository) GetProductWarehousesByQuery(ctx context.Context, req *entity.GetProductWarehousesByQueryRequest) ([]*entity.ProductWarehouse, error) {
+	ctx, span := start(ctx, "InventoryRepository.GetProductWarehousesByQuery", dbSystemPostgres)
+	result, err := r.InventoryRepositoryInterface.GetProductWarehousesByQuery(ctx, req)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *inventoryRepository) UpdateProductWarehouseTotalStock(ctx context.Context, req *entity.UpdateProductWarehouseTotalStockRequest) error {
+	ctx, span := start(ctx, "InventoryRepository.UpdateProductWarehouseTotalStock", dbSystemPostgres)
+	err := r.InventoryRepositoryInterface.UpdateProductWarehouseTotalStock(ctx, req)
+	traceutil.End(span, err)
+	return err
+}
+
+// transactionRepository starts a span for every query of the orders and the payments
+type transactionRepository struct {
+	repository.TransactionRepositoryInterface
+}
+
+func WrapTransactionRepository(repo repository.TransactionRepositoryInterface) repository.TransactionRepositoryInterface {
+	return &transactionRepository{repo}
+}
+
+func (r *transactionRepository) InsertOrder(ctx context.Context, order *entity.Order) error {
+	ctx, span := start(ctx, "TransactionRepository.InsertOrder", dbSystemPostgres)
+	err := r.TransactionRepositoryInterface.InsertOrder(ctx, order)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *transactionRepository) UpdateOrder(ctx context.Context, req *entity.UpdateOrderRequest) error {
+	ctx, span := start(ctx, "TransactionRepository.UpdateOrder", dbSystemPostgres)
+	err := r.TransactionRepositoryInterface.UpdateOrder(ctx, req)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *transactionRepository) GetOrderById(ctx context.Context, id string, isActive *bool) (*entity.Order, error) {
+	ctx, span := start(ctx, "TransactionRepository.GetOrderById", dbSystemPostgres)
+	result, err := r.TransactionRepositoryInterface.GetOrderById(ctx, id, isActive)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *transactionRepository) CountPendingOrders(ctx context.Context, userId string) (int, error) {
+	ctx, span := start(ctx, "TransactionRepository.CountPendingOrders", dbSystemPostgres)
+	result, err := r.TransactionRepositoryInterface.CountPendingOrders(ctx, userId)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *transactionRepository) LockPendingOrders(ctx context.Context, userId string) error {
+	ctx, span := start(ctx, "TransactionRepository.LockPendingOrders", dbSystemPostgres)
+	err := r.TransactionRepositoryInterface.LockPendingOrders(ctx, userId)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *transactionRepository) CountExpiredOrders(ctx context.Context) (int, error) {
+	ctx, span := start(ctx, "TransactionRepository.CountExpiredOrders", dbSystemPostgres)
+	result, err := r.TransactionRepositoryInterface.CountExpiredOrders(ctx)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *transactionRepository) InsertOrderItems(ctx context.Context, items []*entity.OrderItem) error {
+	ctx, span := start(ctx, "TransactionRepository.InsertOrderItems", dbSystemPostgres)
+	err := r.TransactionRepositoryInterface.InsertOrderItems(ctx, items)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *transactionRepository) GetOrderItemsByOrderId(ctx context.Context, orderId string) ([]*entity.OrderItem, error) {
+	ctx, span := start(ctx, "TransactionRepository.GetOrderItemsByOrderId", dbSystemPostgres)
+	result, err := r.TransactionRepositoryInterface.GetOrderItemsByOrderId(ctx, orderId)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *transactionRepository) InsertPayment(ctx context.Context, req *entity.Payment) error {
+	ctx, span := start(ctx, "TransactionRepository.InsertPayment", dbSystemPostgres)
+	err := r.TransactionRepositoryInterface.InsertPayment(ctx, req)
+	traceutil.End(span, err)
+	return err
+}
+
+// userRepository starts a span for every query of the users
+type userRepository struct {
+	repository.UserRepositoryInterface
+}
+
+func WrapUserRepository(repo repository.UserRepositoryInterface) repository.UserRepositoryInterface {
+	return &userRepository{repo}
+}
+
+func (r *userRepository) GetUser(ctx context.Context, req *entity.GetUserRequest) (*entity.User, error) {
+	ctx, span := start(ctx, "UserRepository.GetUser", dbSystemPostgres)
+	result, err := r.UserRepositoryInterface.GetUser(ctx, req)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *userRepository) InsertUser(ctx context.Context, user *entity.User) error {
+	ctx, span := start(ctx, "UserRepository.InsertUser", dbSystemPostgres)
+	err := r.UserRepositoryInterface.InsertUser(ctx, user)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) UpdateUserName(ctx context.Context, userId, name string, updatedAt time.Time) error {
+	ctx, span := start(ctx, "UserRepository.UpdateUserName", dbSystemPostgres)
+	err := r.UserRepositoryInterface.UpdateUserName(ctx, userId, name, updatedAt)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) UpdateUserIdentifier(ctx context.Context, userId, identifierType, identifier string, updatedAt time.Time) error {
+	ctx, span := start(ctx, "UserRepository.UpdateUserIdentifier", dbSystemPostgres)
+	err := r.UserRepositoryInterface.UpdateUserIdentifier(ctx, userId, identifierType, identifier, updatedAt)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) GetAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	ctx, span := start(ctx, "UserRepository.GetAddresses", dbSystemPostgres)
+	result, err := r.UserRepositoryInterface.GetAddresses(ctx, userId)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *userRepository) GetAddress(ctx context.Context, userId, id string) (*entity.Address, error) {
+	ctx, span := start(ctx, "UserRepository.GetAddress", dbSystemPostgres)
+	result, err := r.UserRepositoryInterface.GetAddress(ctx, userId, id)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *userRepository) InsertAddress(ctx context.Context, address *entity.Address) error {
+	ctx, span := start(ctx, "UserRepository.InsertAddress", dbSystemPostgres)
+	err := r.UserRepositoryInterface.InsertAddress(ctx, address)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) UpdateAddress(ctx context.Context, address *entity.Address) error {
+	ctx, span := start(ctx, "UserRepository.UpdateAddress", dbSystemPostgres)
+	err := r.UserRepositoryInterface.UpdateAddress(ctx, address)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) UnsetDefaultAddress(ctx context.Context, userId string) error {
+	ctx, span := start(ctx, "UserRepository.UnsetDefaultAddress", dbSystemPostgres)
+	err := r.UserRepositoryInterface.UnsetDefaultAddress(ctx, userId)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) DeleteAddress(ctx context.Context, userId, id string, deletedAt time.Time) error {
+	ctx, span := start(ctx, "UserRepository.DeleteAddress", dbSystemPostgres)
+	err := r.UserRepositoryInterface.DeleteAddress(ctx, userId, id, deletedAt)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) InsertOtp(ctx context.Context, otp *entity.Otp) error {
+	ctx, span := start(ctx, "UserRepository.InsertOtp", dbSystemPostgres)
+	err := r.UserRepositoryInterface.InsertOtp(ctx, otp)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) GetLatestOtp(ctx context.Context, identifier, purpose string) (*entity.Otp, error) {
+	ctx, span := start(ctx, "UserRepository.GetLatestOtp", dbSystemPostgres)
+	result, err := r.UserRepositoryInterface.GetLatestOtp(ctx, identifier, purpose)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *userRepository) CountOtpsSince(ctx context.Context, identifier string, since time.Time) (int, error) {
+	ctx, span := start(ctx, "UserRepository.CountOtpsSince", dbSystemPostgres)
+	result, err := r.UserRepositoryInterface.CountOtpsSince(ctx, identifier, since)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *userRepository) IncreaseOtpAttempts(ctx context.Context, id string, maxAttempts int) error {
+	ctx, span := start(ctx, "UserRepository.IncreaseOtpAttempts", dbSystemPostgres)
+	err := r.UserRepositoryInterface.IncreaseOtpAttempts(ctx, id, maxAttempts)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *userRepository) ConsumeOtp(ctx context.Context, id string, consumedAt time.Time) error {
+	ctx, span := start(ctx, "UserRepository.ConsumeOtp", dbSystemPostgres)
+	err := r.UserRepositoryInterface.ConsumeOtp(ctx, id, consumedAt)
+	traceutil.End(span, err)
+	return err
+}
+
+// redisRepository starts a span for every redis call of the reservations and the sessions
+type redisRepository struct {
+	repository.RedisRepositoryInterface
+}
+
+func WrapRedisRepository(repo repository.RedisRepositoryInterface) repository.RedisRepositoryInterface {
+	return &redisRepository{repo}
+}
+
+func (r *redisRepository) LockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
+	ctx, span := start(ctx, "RedisRepository.LockOrderProduct", dbSystemRedis)
+	err := r.RedisRepositoryInterface.LockOrderProduct(ctx, req)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *redisRepository) InvalidateLockOrderProduct(ctx context.Context, req *entity.LockOrderProductRequest) error {
+	ctx, span := start(ctx, "RedisRepository.InvalidateLockOrderProduct", dbSystemRedis)
+	err := r.RedisRepositoryInterface.InvalidateLockOrderProduct(ctx, req)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *redisRepository) GetReservedProductQuantity(ctx context.Context, productId, warehouseId string) (int, error) {
+	ctx, span := start(ctx, "RedisRepository.GetReservedProductQuantity", dbSystemRedis)
+	result, err := r.RedisRepositoryInterface.GetReservedProductQuantity(ctx, productId, warehouseId)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *redisRepository) GetReservedQuantityPerWarehouse(ctx context.Context) (map[string]int, error) {
+	ctx, span := start(ctx, "RedisRepository.GetReservedQuantityPerWarehouse", dbSystemRedis)
+	result, err := r.RedisRepositoryInterface.GetReservedQuantityPerWarehouse(ctx)
+	traceutil.End(span, err)
+	return result, err
+}
+
+func (r *redisRepository) RevokeSession(ctx context.Context, sessionId string, expiration time.Duration) error {
+	ctx, span := start(ctx, "RedisRepository.RevokeSession", dbSystemRedis)
+	err := r.RedisRepositoryInterface.RevokeSession(ctx, sessionId, expiration)
+	traceutil.End(span, err)
+	return err
+}
+
+func (r *redisRepository) IsSessionRevoked(ctx context.Context, sessionId string) (bool, error) {
+	ctx, span := start(ctx, "RedisRepository.IsSessionRevoked", dbSystemRedis)
+	result, err := r.RedisRepositoryInterface.IsSessionRevoked(ctx, sessionId)
+	traceutil.End(span, err)
+	return result, err
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"mfawzanid/warehouse-commerce/core/repository"
+	traceutil "mfawzanid/warehouse-commerce/utils/trace"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	dbSystemPostgres = semconv.DBSystemPostgreSQL
+	dbSystemRedis    = semconv.DBSystemRedis
+)
+
+// start starts the client span of a repository call, e.g. InventoryRepository.InsertProduct
+func start(ctx context.Context, name string, dbSystem attribute.KeyValue) (context.Context, trace.Span) {
+	return traceutil.Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystem))
+}
+
+// unitOfWork wraps the repositories of the transaction, so their queries are traced too
+type unitOfWork struct {
+	repository.UnitOfWorkInterface
+}
+
+func WrapUnitOfWork(uow repository.UnitOfWorkInterface) repository.UnitOfWorkInterface {
+	return &unitOfWork{uow}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	ctx, span := traceutil.Tracer().Start(ctx, "UnitOfWork.Do")
+	err := u.UnitOfWorkInterface.Do(ctx, func(repos *repository.Repositories) error {
+		wrapped := *repos
+		wrapped.Inventory = WrapInventoryRepository(repos.Inventory)
+		wrapped.Transaction = WrapTransactionRepository(repos.Transaction)
+		wrapped.User = WrapUserRepository(repos.User)
+		return fn(&wrapped)
+	})
+	traceutil.End(span, err)
+	return err
+}
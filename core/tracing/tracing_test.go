@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/mocks"
+	"mfawzanid/warehouse-commerce/core/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	t.Run("Do_repositories of the transaction_then trace their queries", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+		transactionRepo := &mocks.TransactionRepositoryInterface{}
+		transactionRepo.On("InsertOrder", mock.Anything, mock.Anything).Return(nil)
+		transactionRepo.On("InsertOrderItems", mock.Anything, mock.Anything).Return(errors.New("error repo insert order items"))
+
+		uow := &mocks.UnitOfWorkInterface{}
+		uow.On("Do", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+			return fn(&repository.Repositories{Transaction: transactionRepo})
+		})
+
+		err := WrapUnitOfWork(uow).Do(context.Background(), func(repos *repository.Repositories) error {
+			if err := repos.Transaction.InsertOrder(context.Background(), &entity.Order{Id: "ORD-1"}); err != nil {
+				return err
+			}
+			return repos.Transaction.InsertOrderItems(context.Background(), []*entity.OrderItem{{OrderId: "ORD-1"}})
+		})
+
+		assert.NotNil(t, err)
+		spans := recorder.Ended()
+		assert.Len(t, spans, 3)
+		assert.Equal(t, "TransactionRepository.InsertOrder", spans[0].Name())
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Equal(t, "TransactionRepository.InsertOrderItems", spans[1].Name())
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+		assert.Equal(t, "UnitOfWork.Do", spans[2].Name())
+	})
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"time"
+)
+
+const (
+	apiKeyByteLength   = 32
+	apiKeyPrefix       = "wck_" // makes the key recognizable, e.g. by secret scanners
+	apiKeyPrefixLength = 12
+)
+
+// newAPIKey returns api key record with the hash of the key and the key itself to be returned to the admin
+func newAPIKey(req *entity.CreateAPIKeyRequest, now time.Time) (*entity.APIKey, string, error) {
+	apiKeyId, err := serialutil.GenerateId(apiKeyPrefixSerial)
+	if err != nil {
+		return nil, "", fmt.Errorf("error create api key in generating uuid: %v", err.Error())
+	}
+
+	secret := make([]byte, apiKeyByteLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("error create api key in generating secret: %v", err.Error())
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &entity.APIKey{
+		Id:        apiKeyId,
+		Name:      req.Name,
+		Prefix:    key[:apiKeyPrefixLength],
+		KeyHash:   hashToken(key),
+		Scopes:    req.Scopes,
+		CreatedBy: req.ActorId,
+		ExpiredAt: req.ExpiredAt,
+		CreatedAt: now,
+	}, key, nil
+}
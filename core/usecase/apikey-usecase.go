@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+type APIKeyUsecaseInterface interface {
+	// CreateAPIKey returns the key only once, only its hash is stored
+	CreateAPIKey(ctx context.Context, req *entity.CreateAPIKeyRequest) (*entity.CreateAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context) ([]*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	// VerifyAPIKey returns unauthorized error if the key is unknown, expired or revoked
+	VerifyAPIKey(ctx context.Context, key string) (*entity.APIKey, error)
+}
+
+type apiKeyUsecase struct {
+	apiKeyRepo repository.APIKeyRepositoryInterface
+	logger     *slog.Logger
+}
+
+func NewAPIKeyUsecase(apiKeyRepo repository.APIKeyRepositoryInterface, logger *slog.Logger) APIKeyUsecaseInterface {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		logger:     logger,
+	}
+}
+
+const (
+	apiKeyPrefixSerial     = "APK"
+	apiKeyLastUsedInterval = time.Minute // last used time is precise enough to find unused keys
+)
+
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, req *entity.CreateAPIKeyRequest) (*entity.CreateAPIKeyResponse, error) {
+	timeNow := time.Now()
+
+	if err := req.Validate(timeNow); err != nil {
+		return nil, err
+	}
+
+	apiKey, key, err := newAPIKey(req, timeNow)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.apiKeyRepo.InsertAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return &entity.CreateAPIKeyResponse{APIKey: apiKey, Key: key}, nil
+}
+
+func (u *apiKeyUsecase) GetAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	return u.apiKeyRepo.GetAPIKeys(ctx)
+}
+
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, id string) error {
+	if id == "" {
+		return errorutil.NewFieldValidationError("error revoke api key", &errorutil.FieldError{Field: "apiKeyId", Message: "api key id is mandatory"})
+	}
+
+	return u.apiKeyRepo.RevokeAPIKey(ctx, id, time.Now())
+}
+
+func (u *apiKeyUsecase) VerifyAPIKey(ctx context.Context, key string) (*entity.APIKey, error) {
+	timeNow := time.Now()
+
+	apiKey, err := u.apiKeyRepo.GetAPIKeyByHash(ctx, hashToken(key))
+	if err != nil {
+		if errorutil.GetErrorType(err) == errorutil.ErrNotFound {
+			return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify api key: api key is invalid"))
+		}
+		return nil, err
+	}
+	if !apiKey.IsActive(timeNow) {
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify api key: api key is expired or revoked"))
+	}
+
+	// the request is not failed because of the tracking
+	if apiKey.LastUsedAt == nil || timeNow.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedInterval {
+		if err := u.apiKeyRepo.UpdateAPIKeyLastUsedAt(ctx, apiKey.Id, timeNow, timeNow.Add(-apiKeyLastUsedInterval)); err != nil {
+			u.logger.WarnContext(ctx, "error verify api key in tracking last used", "apiKeyId", apiKey.Id, "error", err)
+		}
+	}
+
+	return apiKey, nil
+}
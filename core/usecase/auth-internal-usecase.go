@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	refreshTokenByteLength = 32
+)
+
+func newSession(userId string) (*entity.Session, error) {
+	sessionId, err := serialutil.GenerateId(sessionPrefixSerial)
+	if err != nil {
+		return nil, fmt.Errorf("error create session in generating uuid: %v", err.Error())
+	}
+
+	return &entity.Session{
+		Id:        sessionId,
+		UserId:    userId,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// newRefreshToken returns refresh token record with the hash of the token and the token itself to be returned to the user
+func newRefreshToken(sessionId string, expiredDuration time.Duration) (*entity.RefreshToken, string, error) {
+	tokenId, err := serialutil.GenerateId(refreshTokenPrefixSerial)
+	if err != nil {
+		return nil, "", fmt.Errorf("error create refresh token in generating uuid: %v", err.Error())
+	}
+
+	secret := make([]byte, refreshTokenByteLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("error create refresh token in generating secret: %v", err.Error())
+	}
+	tokenString := base64.RawURLEncoding.EncodeToString(secret)
+
+	timeNow := time.Now()
+	return &entity.RefreshToken{
+		Id:        tokenId,
+		SessionId: sessionId,
+		TokenHash: hashToken(tokenString),
+		ExpiredAt: timeNow.Add(expiredDuration),
+		CreatedAt: timeNow,
+	}, tokenString, nil
+}
+
+// hashToken hashes random token, unlike password it has enough entropy so it does not need slow hash
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func (u *authUsecase) createAuthToken(ctx context.Context, session *entity.Session, refreshToken string) (*entity.AuthToken, error) {
+	// roles are loaded on every token, so the changes of the roles are applied on the next refresh
+	userRoles, err := u.rbacRepo.GetUserRoles(ctx, session.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := u.createAccessToken(session, userRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiredIn:    int(u.tokenConfig.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (u *authUsecase) createAccessToken(session *entity.Session, userRoles *entity.UserRoles) (tokenString string, err error) {
+	tokenId, err := gonanoid.New()
+	if err != nil {
+		return "", fmt.Errorf("error create token in generating jti: %v", err.Error())
+	}
+
+	key := u.tokenConfig.KeySet.ActiveKey()
+	timeNow := time.Now()
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), jwt.MapClaims{
+		entity.ContextUserId: session.UserId,
+		claimSessionId:       session.Id,
+		claimRoles:           userRoles.Roles,
+		claimPermissions:     userRoles.Permissions,
+		"iss":                u.tokenConfig.Issuer,
+		"aud":                u.tokenConfig.Audience,
+		"iat":                timeNow.Unix(),
+		"exp":                timeNow.Add(u.tokenConfig.AccessTokenTTL).Unix(),
+		"jti":                tokenId,
+	})
+	token.Header[headerKeyId] = key.Id
+
+	tokenString, err = token.SignedString(key.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("error create token: %v", err.Error())
+	}
+
+	return tokenString, nil
+}
+
+// rotateRefreshToken marks the refresh token as used and issues the next one in the same session
+func (u *authUsecase) rotateRefreshToken(ctx context.Context, session *entity.Session, refreshToken *entity.RefreshToken) (*entity.AuthToken, error) {
+	nextRefreshToken, tokenString, err := newRefreshToken(session.Id, u.tokenConfig.RefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Auth.UseRefreshToken(ctx, refreshToken.Id, time.Now()); err != nil {
+			return err
+		}
+		return repos.Auth.InsertRefreshToken(ctx, nextRefreshToken)
+	}); err != nil {
+		return nil, err
+	}
+
+	return u.createAuthToken(ctx, session, tokenString)
+}
+
+// revokeReusedSession revokes the session whose refresh token is reused and returns the unauthorized error
+func (u *authUsecase) revokeReusedSession(ctx context.Context, sessionId string) error {
+	if err := u.RevokeSession(ctx, sessionId); err != nil {
+		return err
+	}
+	return errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error refresh session: refresh token is reused, the session is revoked"))
+}
+
+// addToRevocationList adds the session to the revocation list, a failed write is retried since the list may be down shortly
+func (u *authUsecase) addToRevocationList(ctx context.Context, sessionId string) error {
+	var err error
+	for attempt := 1; attempt <= revocationListWriteAttempts; attempt++ {
+		if err = u.redisRepo.RevokeSession(ctx, sessionId, u.tokenConfig.AccessTokenTTL); err == nil {
+			return nil
+		}
+		if attempt < revocationListWriteAttempts {
+			time.Sleep(revocationListRetryInterval)
+		}
+	}
+	return err
+}
+
+// isSessionRevoked checks the revocation list, db is checked if the revocation list can not be reached
+func (u *authUsecase) isSessionRevoked(ctx context.Context, sessionId string) (bool, error) {
+	revoked, err := u.redisRepo.IsSessionRevoked(ctx, sessionId)
+	if err == nil {
+		return revoked, nil
+	}
+	u.logger.WarnContext(ctx, "error verify token in checking revocation list, fallback to db", "error", err)
+
+	session, err := u.authRepo.GetSessionById(ctx, sessionId)
+	if err != nil {
+		if errorutil.GetErrorType(err) == errorutil.ErrNotFound {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return session.IsRevoked(), nil
+}
+
+// getStringsClaim gets string array claim, json array is decoded as []interface{}
+func getStringsClaim(claims jwt.MapClaims, name string) []string {
+	values, _ := claims[name].([]interface{})
+
+	var result []string
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -1,61 +1,223 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 
 	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	keyutil "mfawzanid/warehouse-commerce/utils/key"
 )
 
 type AuthUsecaseInterface interface {
-	CreateToken(username string) (tokenString string, err error)
-	VerifyToken(tokenString string) (userId string, err error)
+	// CreateSession starts a session of the user and returns access token with its refresh token
+	CreateSession(ctx context.Context, userId string) (*entity.AuthToken, error)
+	// RefreshSession rotates the refresh token, reusing a used refresh token revokes the whole session
+	RefreshSession(ctx context.Context, req *entity.RefreshSessionRequest) (*entity.AuthToken, error)
+	RevokeSession(ctx context.Context, sessionId string) error
+	// AddToRevocationList adds sessions that are already revoked in db to the revocation list, e.g. when the account is deleted
+	AddToRevocationList(ctx context.Context, sessionIds []string) error
+	VerifyToken(ctx context.Context, tokenString string) (*entity.TokenClaims, error)
+	// GetJSONWebKeys returns the public keys so other services can verify the tokens
+	GetJSONWebKeys() []keyutil.JSONWebKey
 }
 
 type authUsecase struct {
+	authRepo    repository.AuthRepositoryInterface
+	rbacRepo    repository.RbacRepositoryInterface
+	redisRepo   repository.RedisRepositoryInterface
+	unitOfWork  repository.UnitOfWorkInterface
+	tokenConfig *entity.TokenConfig
+	logger      *slog.Logger
 }
 
-func NewAuthUsecase() AuthUsecaseInterface {
-	return &authUsecase{}
+func NewAuthUsecase(authRepo repository.AuthRepositoryInterface, rbacRepo repository.RbacRepositoryInterface, redisRepo repository.RedisRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, tokenConfig *entity.TokenConfig, logger *slog.Logger) AuthUsecaseInterface {
+	return &authUsecase{
+		authRepo:    authRepo,
+		rbacRepo:    rbacRepo,
+		redisRepo:   redisRepo,
+		unitOfWork:  unitOfWork,
+		tokenConfig: tokenConfig,
+		logger:      logger,
+	}
 }
 
 const (
-	tokenSecret = "token_secret" // TODO improve by set as config
+	sessionPrefixSerial      = "SES"
+	refreshTokenPrefixSerial = "RFT"
+
+	claimSessionId   = "sid"
+	claimRoles       = "roles"
+	claimPermissions = "permissions"
+	headerKeyId      = "kid"
+
+	// a revoked session must reach the revocation list, otherwise its access tokens are accepted until they expire
+	revocationListWriteAttempts = 3
+	revocationListRetryInterval = 50 * time.Millisecond
 )
 
-func (u *authUsecase) CreateToken(userId string) (tokenString string, err error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		entity.ContextUserId: userId,
-		"exp":                time.Now().Add(24 * time.Hour).Unix(),
-	})
+func (u *authUsecase) CreateSession(ctx context.Context, userId string) (*entity.AuthToken, error) {
+	session, err := newSession(userId)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, tokenString, err := newRefreshToken(session.Id, u.tokenConfig.RefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Auth.InsertSession(ctx, session); err != nil {
+			return err
+		}
+		return repos.Auth.InsertRefreshToken(ctx, refreshToken)
+	}); err != nil {
+		return nil, err
+	}
 
-	tokenString, err = token.SignedString([]byte(tokenSecret))
+	return u.createAuthToken(ctx, session, tokenString)
+}
+
+func (u *authUsecase) RefreshSession(ctx context.Context, req *entity.RefreshSessionRequest) (*entity.AuthToken, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := u.authRepo.GetRefreshTokenByHash(ctx, hashToken(req.RefreshToken))
+	if err != nil {
+		if errorutil.GetErrorType(err) == errorutil.ErrNotFound {
+			return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error refresh session: refresh token is invalid"))
+		}
+		return nil, err
+	}
+
+	session, err := u.authRepo.GetSessionById(ctx, refreshToken.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	if session.IsRevoked() {
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error refresh session: session is revoked"))
+	}
+
+	// a used refresh token may be stolen, so the session of both the thief and the user is revoked
+	if refreshToken.UsedAt != nil {
+		return nil, u.revokeReusedSession(ctx, session.Id)
+	}
+	if !time.Now().Before(refreshToken.ExpiredAt) {
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error refresh session: refresh token is expired"))
+	}
+
+	authToken, err := u.rotateRefreshToken(ctx, session, refreshToken)
 	if err != nil {
-		return "", fmt.Errorf("error create token: %v", err.Error())
+		if errorutil.GetErrorType(err) == errorutil.ErrUnauthorized {
+			// the refresh token is used concurrently
+			return nil, u.revokeReusedSession(ctx, session.Id)
+		}
+		return nil, err
 	}
 
-	return tokenString, nil
+	return authToken, nil
 }
 
-func (u *authUsecase) VerifyToken(tokenString string) (userId string, err error) {
-	token, err := jwt.Parse(tokenString, func(tokenString *jwt.Token) (interface{}, error) {
-		return []byte(tokenSecret), nil
+// RevokeSession revokes the session in db then adds it to the revocation list that is checked on every request
+func (u *authUsecase) RevokeSession(ctx context.Context, sessionId string) error {
+	if sessionId == "" {
+		return errorutil.NewFieldValidationError("error revoke session", &errorutil.FieldError{Field: "sessionId", Message: "session id is mandatory"})
+	}
+
+	if err := u.authRepo.RevokeSession(ctx, sessionId, time.Now()); err != nil {
+		return err
+	}
+
+	// db is the source of truth, but the token check trusts the revocation list while it is reachable,
+	// so the revocation fails if the list is not updated. Revoking again is safe, the revoked time in db is kept.
+	// The session is revoked in db, so the list is updated even if the client is gone.
+	if err := u.addToRevocationList(context.WithoutCancel(ctx), sessionId); err != nil {
+		u.logger.ErrorContext(ctx, "error revoke session in revocation list", "sessionId", sessionId, "error", err)
+		return errorutil.NewErrorCode(errorutil.ErrUnavailable, fmt.Errorf("error revoke session: %v", err.Error()))
+	}
+
+	return nil
+}
+
+// AddToRevocationList tries every session even if one fails, the failed sessions are still revoked in db
+func (u *authUsecase) AddToRevocationList(ctx context.Context, sessionIds []string) error {
+	var errs []error
+	for _, sessionId := range sessionIds {
+		if err := u.addToRevocationList(ctx, sessionId); err != nil {
+			errs = append(errs, fmt.Errorf("session '%s': %v", sessionId, err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errorutil.NewErrorCode(errorutil.ErrUnavailable, fmt.Errorf("error add to revocation list: %v", errors.Join(errs...).Error()))
+	}
+
+	return nil
+}
+
+func (u *authUsecase) VerifyToken(ctx context.Context, tokenString string) (*entity.TokenClaims, error) {
+	keySet := u.tokenConfig.KeySet
+
+	// the algorithm is pinned by the key, so a token can not choose how it is verified
+	parser := &jwt.Parser{ValidMethods: keySet.Algorithms()}
+	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		keyId, ok := token.Header[headerKeyId].(string)
+		if !ok {
+			return nil, errors.New("key id is not found in token header")
+		}
+		key, ok := keySet.GetKey(keyId)
+		if !ok {
+			return nil, fmt.Errorf("key '%s' is unknown", keyId)
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("algorithm '%s' is not allowed for key '%s'", token.Method.Alg(), keyId)
+		}
+		return key.PublicKey, nil
 	})
 	if err != nil {
-		return "", errorutil.NewErrorCode(errorutil.ErrUnauthorized, fmt.Errorf("error verify token: %v", err.Error()))
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, fmt.Errorf("error verify token: %v", err.Error()))
 	}
 	if !token.Valid {
-		return "", errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify token: token is invalid"))
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify token: token is invalid"))
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	if !claims.VerifyIssuer(u.tokenConfig.Issuer, true) || !claims.VerifyAudience(u.tokenConfig.Audience, true) {
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify token: token is issued by other issuer or for other audience"))
 	}
 
-	userId, ok := token.Claims.(jwt.MapClaims)["userId"].(string)
+	userId, ok := claims[entity.ContextUserId].(string)
+	if !ok {
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify token: user id is not found in token"))
+	}
+	sessionId, ok := claims[claimSessionId].(string)
 	if !ok {
-		return "", errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify token: user id is not found in token"))
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify token: session id is not found in token"))
 	}
 
-	return userId, nil
+	revoked, err := u.isSessionRevoked(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify token: session is revoked"))
+	}
+
+	return &entity.TokenClaims{
+		UserId:      userId,
+		SessionId:   sessionId,
+		Roles:       getStringsClaim(claims, claimRoles),
+		Permissions: getStringsClaim(claims, claimPermissions),
+	}, nil
+}
+
+func (u *authUsecase) GetJSONWebKeys() []keyutil.JSONWebKey {
+	return u.tokenConfig.KeySet.JSONWebKeys()
 }
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"strconv"
+	"strings"
+)
+
+// parseCatalogRows parses csv (with header) or jsonl content, a line that can not be parsed is returned as row error.
+// It returns error if the content can not be read at all (e.g. csv header is invalid).
+func parseCatalogRows(format string, reader io.Reader) ([]*entity.CatalogRow, []*entity.CatalogRowError, error) {
+	if format == entity.CatalogFormatCSV {
+		return parseCatalogCSV(reader)
+	}
+	return parseCatalogJSONL(reader)
+}
+
+func parseCatalogCSV(reader io.Reader) ([]*entity.CatalogRow, []*entity.CatalogRowError, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1 // column count is validated per row, so a bad row does not stop the others
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, errorutil.NewErrorCode(errorutil.ErrBadRequest, errors.New("error import catalog: content is empty"))
+		}
+		return nil, nil, errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error import catalog: header is invalid: %v", err.Error()))
+	}
+
+	columnIndex := make(map[string]int)
+	for i, column := range header {
+		columnIndex[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range catalogColumns {
+		if _, ok := columnIndex[strings.ToLower(column)]; !ok {
+			return nil, nil, errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error import catalog: column '%s' is mandatory in header", column))
+		}
+	}
+
+	var rows []*entity.CatalogRow
+	var rowErrors []*entity.CatalogRowError
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("error import catalog in reading csv: %v", err.Error())
+			}
+			rowErrors = append(rowErrors, &entity.CatalogRowError{Line: parseErr.Line, Error: parseErr.Err.Error()})
+			continue
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		if len(record) != len(header) {
+			rowErrors = append(rowErrors, &entity.CatalogRowError{Line: line, Error: fmt.Sprintf("row has %d columns, header has %d", len(record), len(header))})
+			continue
+		}
+
+		value := func(column string) string {
+			return strings.TrimSpace(record[columnIndex[strings.ToLower(column)]])
+		}
+
+		row := &entity.CatalogRow{
+			Line:        line,
+			Sku:         value("sku"),
+			Name:        value("name"),
+			WarehouseId: value("warehouseId"),
+		}
+		if row.Price, err = strconv.Atoi(value("price")); err != nil {
+			rowErrors = append(rowErrors, &entity.CatalogRowError{Line: line, Sku: row.Sku, Error: "price must be a number"})
+			continue
+		}
+		if row.TotalStock, err = strconv.Atoi(value("totalStock")); err != nil {
+			rowErrors = append(rowErrors, &entity.CatalogRowError{Line: line, Sku: row.Sku, Error: "total stock must be a number"})
+			continue
+		}
+
+		rows = append(rows, row)
+		if len(rows)+len(rowErrors) > entity.MaxCatalogImportRows {
+			return nil, nil, errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error import catalog: rows must be at most %d", entity.MaxCatalogImportRows))
+		}
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseCatalogJSONL(reader io.Reader) ([]*entity.CatalogRow, []*entity.CatalogRowError, error) {
+	scanner := bufio.NewScanner(reader)
+
+	var rows []*entity.CatalogRow
+	var rowErrors []*entity.CatalogRowError
+	var line int
+	for scanner.Scan() {
+		line++
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		row := &entity.CatalogRow{}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(row); err != nil {
+			rowErrors = append(rowErrors, &entity.CatalogRowError{Line: line, Error: fmt.Sprintf("row is invalid json: %v", err.Error())})
+			continue
+		}
+		row.Line = line
+		row.Sku = strings.TrimSpace(row.Sku)
+		row.Name = strings.TrimSpace(row.Name)
+		row.WarehouseId = strings.TrimSpace(row.WarehouseId)
+
+		rows = append(rows, row)
+		if len(rows)+len(rowErrors) > entity.MaxCatalogImportRows {
+			return nil, nil, errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error import catalog: rows must be at most %d", entity.MaxCatalogImportRows))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error import catalog in reading jsonl: %v", err.Error()))
+	}
+
+	if len(rows)+len(rowErrors) == 0 {
+		return nil, nil, errorutil.NewErrorCode(errorutil.ErrBadRequest, errors.New("error import catalog: content is empty"))
+	}
+
+	return rows, rowErrors, nil
+}
+
+// validateCatalogRows validates each row, the rows of a sku must have the same name & price
+// and the warehouses must exist
+func (u *catalogUsecase) validateCatalogRows(ctx context.Context, rows []*entity.CatalogRow) ([]*entity.CatalogRowError, error) {
+	var rowErrors []*entity.CatalogRowError
+	var validRows []*entity.CatalogRow
+
+	firstSkuRows := make(map[string]*entity.CatalogRow)
+	skuWarehouses := make(map[string]bool)
+	for _, row := range rows {
+		if err := row.Validate(); err != nil {
+			rowErrors = append(rowErrors, &entity.CatalogRowError{Line: row.Line, Sku: row.Sku, Error: err.Error()})
+			continue
+		}
+
+		skuWarehouse := row.Sku + "/" + row.WarehouseId
+		if skuWarehouses[skuWarehouse] {
+			rowErrors = append(rowErrors, &entity.CatalogRowError{Line: row.Line, Sku: row.Sku, Error: fmt.Sprintf("warehouse '%s' is duplicated for the sku", row.WarehouseId)})
+			continue
+		}
+		skuWarehouses[skuWarehouse] = true
+
+		if firstRow, ok := firstSkuRows[row.Sku]; ok {
+			if firstRow.Name != row.Name || firstRow.Price != row.Price {
+				rowErrors = append(rowErrors, &entity.CatalogRowError{Line: row.Line, Sku: row.Sku, Error: fmt.Sprintf("name and price must be same with line %d of the sku", firstRow.Line)})
+				continue
+			}
+		} else {
+			firstSkuRows[row.Sku] = row
+		}
+
+		validRows = append(validRows, row)
+	}
+
+	if len(validRows) == 0 {
+		return rowErrors, nil
+	}
+
+	var warehouseIds []string
+	warehouseIdMap := make(map[string]bool)
+	for _, row := range validRows {
+		if !warehouseIdMap[row.WarehouseId] {
+			warehouseIdMap[row.WarehouseId] = true
+			warehouseIds = append(warehouseIds, row.WarehouseId)
+		}
+	}
+
+	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(ctx, &entity.GetWarehousesRequest{
+		Ids: warehouseIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	existingWarehouses := make(map[string]bool)
+	for _, warehouse := range getWarehousesResp.Warehouses {
+		existingWarehouses[warehouse.Id] = true
+	}
+	for _, row := range validRows {
+		if !existingWarehouses[row.WarehouseId] {
+			rowErrors = append(rowErrors, &entity.CatalogRowError{Line: row.Line, Sku: row.Sku, Error: fmt.Sprintf("warehouse '%s' is not found", row.WarehouseId)})
+		}
+	}
+
+	return rowErrors, nil
+}
+
+// importCatalogProduct inserts or updates the product of the sku and sets its stock in each warehouse in one transaction
+func (u *catalogUsecase) importCatalogProduct(ctx context.Context, rows []*entity.CatalogRow, actorId string) error {
+	first := rows[0]
+
+	return u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		product, getErr := repos.Catalog.GetProductBySku(ctx, first.Sku)
+		if getErr != nil && errorutil.GetErrorType(getErr) != errorutil.ErrNotFound {
+			return getErr
+		}
+
+		var priceChanged bool
+		if product == nil {
+			productId, err := serialutil.GenerateId(productPrefixSerial)
+			if err != nil {
+				return fmt.Errorf("error import catalog product in generating uuid: %v", err.Error())
+			}
+
+			product = &entity.Product{Id: productId, Sku: first.Sku, Name: first.Name, Price: first.Price}
+			if err := repos.Inventory.InsertProduct(ctx, product); err != nil {
+				switch {
+				case errors.Is(err, entity.ErrProductSkuIsUsed):
+					// the sku is inserted by another import after it is read
+					return errorutil.NewErrorCode(errorutil.ErrConflict, fmt.Errorf("sku '%s' is imported by another import at the same time, import it again", first.Sku))
+				case errors.Is(err, entity.ErrProductNameIsUsed):
+					return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("name '%s' is used by another product", first.Name))
+				}
+				return err
+			}
+			priceChanged = true
+		} else if product.Name != first.Name || product.Price != first.Price {
+			priceChanged = product.Price != first.Price
+			product.Name = first.Name
+			product.Price = first.Price
+			if err := repos.Catalog.UpdateProduct(ctx, product); err != nil {
+				return err
+			}
+		}
+
+		if priceChanged {
+			history, err := newPriceHistory(product.Id, "", product.Price, actorId)
+			if err != nil {
+				return err
+			}
+			if err := repos.Price.InsertPriceHistory(ctx, history); err != nil {
+				return err
+			}
+		}
+
+		for _, row := range rows {
+			if err := repos.Catalog.SetProductWarehouseStock(ctx, &entity.ProductWarehouse{
+				ProductId:   product.Id,
+				WarehouseId: row.WarehouseId,
+				TotalStock:  row.TotalStock,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// groupCatalogRowsBySku groups the rows of each sku, the order of the first row of each sku is kept
+func groupCatalogRowsBySku(rows []*entity.CatalogRow) [][]*entity.CatalogRow {
+	var groups [][]*entity.CatalogRow
+	groupIndex := make(map[string]int)
+	for _, row := range rows {
+		if i, ok := groupIndex[row.Sku]; ok {
+			groups[i] = append(groups[i], row)
+			continue
+		}
+		groupIndex[row.Sku] = len(groups)
+		groups = append(groups, []*entity.CatalogRow{row})
+	}
+	return groups
+}
+
+// countErrorLines counts the lines that have errors, a line can have more than one error
+func countErrorLines(rowErrors []*entity.CatalogRowError) int {
+	lines := make(map[int]bool)
+	for _, rowError := range rowErrors {
+		lines[rowError.Line] = true
+	}
+	return len(lines)
+}
+
+// flushExport flushes the buffered rows, then flushes the writer too if it supports it (e.g. http response)
+func flushExport(writer io.Writer, flush func() error) error {
+	if err := flush(); err != nil {
+		return fmt.Errorf("error export catalog: %v", err.Error())
+	}
+	if flusher, ok := writer.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+	return nil
+}
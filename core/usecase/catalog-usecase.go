@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	lifecycleutil "mfawzanid/warehouse-commerce/utils/lifecycle"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type CatalogUsecaseInterface interface {
+	// ImportCatalog validates all rows then upserts products by sku in background job,
+	// dry run or import with invalid rows only returns the report without running the job
+	ImportCatalog(ctx context.Context, req *entity.ImportCatalogRequest) (*entity.CatalogImportJob, error)
+	GetImportJob(ctx context.Context, id string) (*entity.CatalogImportJob, error)
+
+	// ExportCatalog writes product stock per warehouse to the writer row by row
+	ExportCatalog(ctx context.Context, req *entity.ExportCatalogRequest) error
+}
+
+type catalogUsecase struct {
+	inventoryRepo repository.InventoryRepositoryInterface
+	catalogRepo   repository.CatalogRepositoryInterface
+	priceRepo     repository.PriceRepositoryInterface
+	unitOfWork    repository.UnitOfWorkInterface
+	lifecycle     *lifecycleutil.Manager
+	logger        *slog.Logger
+}
+
+func NewCatalogUsecase(inventoryRepo repository.InventoryRepositoryInterface, catalogRepo repository.CatalogRepositoryInterface, priceRepo repository.PriceRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, lifecycle *lifecycleutil.Manager, logger *slog.Logger) CatalogUsecaseInterface {
+	return &catalogUsecase{
+		inventoryRepo: inventoryRepo,
+		catalogRepo:   catalogRepo,
+		priceRepo:     priceRepo,
+		unitOfWork:    unitOfWork,
+		lifecycle:     lifecycle,
+		logger:        logger,
+	}
+}
+
+const (
+	importJobPrefixSerial = "IMP"
+
+	importJobProgressInterval = 100 // progress of import job is saved every this number of rows
+	exportFlushInterval       = 100 // exported rows are flushed to the client every this number of rows
+
+	importJobInterruptedError = "import job is interrupted by server shutdown, import the row again"
+)
+
+var catalogColumns = []string{"sku", "name", "price", "warehouseId", "totalStock"}
+
+func (u *catalogUsecase) ImportCatalog(ctx context.Context, req *entity.ImportCatalogRequest) (*entity.CatalogImportJob, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// a line that can not be parsed is reported as row error, so total rows are the parsed rows and the errors
+	rows, rowErrors, err := parseCatalogRows(req.Format, req.Reader)
+	if err != nil {
+		return nil, err
+	}
+	totalRows := len(rows) + len(rowErrors)
+
+	validationErrors, err := u.validateCatalogRows(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	rowErrors = append(rowErrors, validationErrors...)
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Line < rowErrors[j].Line
+	})
+
+	timeNow := time.Now()
+	job := &entity.CatalogImportJob{
+		Format:    req.Format,
+		DryRun:    req.DryRun,
+		TotalRows: totalRows,
+		Errors:    rowErrors,
+		ActorId:   req.ActorId,
+		CreatedAt: timeNow,
+		UpdatedAt: timeNow,
+	}
+
+	// nothing is written if some rows are invalid, so the file can be fixed and imported again
+	if len(rowErrors) > 0 {
+		job.Status = entity.CatalogImportJobStatusFailed
+		job.ProcessedRows = job.TotalRows
+		job.FailedRows = countErrorLines(rowErrors)
+		job.SucceededRows = job.TotalRows - job.FailedRows
+		return job, nil
+	}
+
+	if req.DryRun {
+		job.Status = entity.CatalogImportJobStatusSucceeded
+		job.ProcessedRows = job.TotalRows
+		job.SucceededRows = job.TotalRows
+		return job, nil
+	}
+
+	job.Id, err = serialutil.GenerateId(importJobPrefixSerial)
+	if err != nil {
+		return nil, fmt.Errorf("error import catalog in generating uuid: %v", err.Error())
+	}
+	job.Status = entity.CatalogImportJobStatusRunning
+
+	if err := u.catalogRepo.InsertImportJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// the job is run in background, the progress can be tracked by the job id
+	jobCopy := *job
+	if err := u.lifecycle.Go(ctx, "catalog import job "+job.Id, func(ctx context.Context) {
+		u.runImportJob(ctx, jobCopy, rows)
+	}); err != nil {
+		// the server is stopping, nothing is imported so the file can be imported again
+		job.Status = entity.CatalogImportJobStatusFailed
+		job.UpdatedAt = time.Now()
+		if err := u.catalogRepo.UpdateImportJob(ctx, job); err != nil {
+			u.logger.ErrorContext(ctx, "error import catalog: error update the job that is not run", "jobId", job.Id, "error", err)
+		}
+		return nil, errorutil.NewErrorCode(errorutil.ErrUnavailable, fmt.Errorf("error import catalog: %w", err))
+	}
+
+	return job, nil
+}
+
+func (u *catalogUsecase) GetImportJob(ctx context.Context, id string) (*entity.CatalogImportJob, error) {
+	if id == "" {
+		return nil, errorutil.NewFieldValidationError("error get import job", &errorutil.FieldError{Field: "jobId", Message: "job id is mandatory"})
+	}
+
+	job, err := u.catalogRepo.GetImportJobById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Errors == nil {
+		job.Errors = []*entity.CatalogRowError{}
+	}
+
+	return job, nil
+}
+
+func (u *catalogUsecase) ExportCatalog(ctx context.Context, req *entity.ExportCatalogRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	var writeRow func(row *entity.CatalogExportRow) error
+	var flush func() error
+
+	switch req.Format {
+	case entity.CatalogFormatCSV:
+		csvWriter := csv.NewWriter(req.Writer)
+		if err := csvWriter.Write(append([]string{"productId"}, catalogColumns...)); err != nil {
+			return fmt.Errorf("error export catalog: %v", err.Error())
+		}
+		writeRow = func(row *entity.CatalogExportRow) error {
+			return csvWriter.Write([]string{row.ProductId, row.Sku, row.Name, strconv.Itoa(row.Price), row.WarehouseId, strconv.Itoa(row.TotalStock)})
+		}
+		flush = func() error {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	default:
+		encoder := json.NewEncoder(req.Writer)
+		writeRow = func(row *entity.CatalogExportRow) error {
+			return encoder.Encode(row)
+		}
+		flush = func() error {
+			return nil
+		}
+	}
+
+	var count int
+	err := u.catalogRepo.IterateCatalog(ctx, func(row *entity.CatalogExportRow) error {
+		if err := writeRow(row); err != nil {
+			return fmt.Errorf("error export catalog: %v", err.Error())
+		}
+
+		count++
+		if count%exportFlushInterval == 0 {
+			return flushExport(req.Writer, flush)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flushExport(req.Writer, flush)
+}
+
+// runImportJob upserts the products one sku at a time, a failed sku does not stop the others
+func (u *catalogUsecase) runImportJob(ctx context.Context, job entity.CatalogImportJob, rows []*entity.CatalogRow) {
+	nextProgressAt := importJobProgressInterval
+
+	for _, skuRows := range groupCatalogRowsBySku(rows) {
+		// a cancelled job stops between skus, the rows that are not imported are reported to be imported again
+		if ctx.Err() != nil {
+			job.FailedRows += len(skuRows)
+			job.ProcessedRows += len(skuRows)
+			for _, row := range skuRows {
+				job.Errors = append(job.Errors, &entity.CatalogRowError{Line: row.Line, Sku: row.Sku, Error: importJobInterruptedError})
+			}
+			continue
+		}
+
+		if err := u.importCatalogProduct(ctx, skuRows, job.ActorId); err != nil {
+			job.FailedRows += len(skuRows)
+			for _, row := range skuRows {
+				job.Errors = append(job.Errors, &entity.CatalogRowError{
+					Line:  row.Line,
+					Sku:   row.Sku,
+					Error: errorutil.GetOriginalError(err).Error(),
+				})
+			}
+		} else {
+			job.SucceededRows += len(skuRows)
+		}
+		job.ProcessedRows += len(skuRows)
+
+		if job.ProcessedRows >= nextProgressAt {
+			nextProgressAt = (job.ProcessedRows/importJobProgressInterval + 1) * importJobProgressInterval
+			job.UpdatedAt = time.Now()
+			if err := u.catalogRepo.UpdateImportJob(ctx, &job); err != nil {
+				u.logger.ErrorContext(ctx, "error import catalog job in saving progress", "jobId", job.Id, "error", err)
+			}
+		}
+	}
+
+	job.Status = entity.CatalogImportJobStatusSucceeded
+	if job.FailedRows > 0 {
+		job.Status = entity.CatalogImportJobStatusFailed
+	}
+	job.UpdatedAt = time.Now()
+
+	if err := u.catalogRepo.UpdateImportJob(ctx, &job); err != nil {
+		u.logger.ErrorContext(ctx, "error import catalog job in saving result", "jobId", job.Id, "error", err)
+		return
+	}
+
+	u.logger.InfoContext(ctx, "catalog import job is done", "jobId", job.Id, "status", job.Status, "processedRows", job.ProcessedRows, "failedRows", job.FailedRows)
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	"sync"
+	"time"
+)
+
+type HealthUsecaseInterface interface {
+	// CheckReadiness checks the dependencies concurrently, each of them within the check timeout.
+	// The server is down without the database or with a schema that is not migrated, and degraded without redis
+	// since the requests fall back to the database (revocation list) or are allowed (rate limit).
+	CheckReadiness(ctx context.Context) *entity.HealthReport
+}
+
+type healthUsecase struct {
+	healthRepo       repository.HealthRepositoryInterface
+	migrationVersion uint          // version of the last migration of the server, the database must be at it
+	checkTimeout     time.Duration // a dependency that does not respond in time is down
+
+	mu         sync.Mutex
+	lastStatus map[string]string // to log a dependency only when its status changes, the readiness is checked every few seconds
+
+	logger *slog.Logger
+}
+
+func NewHealthUsecase(healthRepo repository.HealthRepositoryInterface, migrationVersion uint, checkTimeout time.Duration, logger *slog.Logger) HealthUsecaseInterface {
+	return &healthUsecase{
+		healthRepo:       healthRepo,
+		migrationVersion: migrationVersion,
+		checkTimeout:     checkTimeout,
+		lastStatus:       map[string]string{},
+		logger:           logger,
+	}
+}
+
+func (u *healthUsecase) CheckReadiness(ctx context.Context) *entity.HealthReport {
+	checks := map[string]func(ctx context.Context) error{
+		entity.HealthDependencyDatabase:  u.healthRepo.PingDatabase,
+		entity.HealthDependencyMigration: u.checkMigrationVersion,
+		entity.HealthDependencyRedis:     u.healthRepo.PingRedis,
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	dependencies := map[string]*entity.DependencyHealth{}
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dependency := u.checkDependency(ctx, check)
+
+			mu.Lock()
+			dependencies[name] = dependency
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	report := &entity.HealthReport{
+		Status:       entity.HealthStatusOk,
+		Dependencies: dependencies,
+		CheckedAt:    time.Now(),
+	}
+	switch {
+	case dependencies[entity.HealthDependencyDatabase].Status == entity.HealthStatusDown,
+		dependencies[entity.HealthDependencyMigration].Status == entity.HealthStatusDown:
+		report.Status = entity.HealthStatusDown
+	case dependencies[entity.HealthDependencyRedis].Status == entity.HealthStatusDown:
+		report.Status = entity.HealthStatusDegraded
+	}
+
+	u.logStatusChanges(ctx, report)
+
+	return report
+}
+
+func (u *healthUsecase) checkDependency(ctx context.Context, check func(ctx context.Context) error) *entity.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, u.checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	dependency := &entity.DependencyHealth{
+		Status:    entity.HealthStatusOk,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		dependency.Status = entity.HealthStatusDown
+		dependency.Error = err.Error()
+	}
+
+	return dependency
+}
+
+// checkMigrationVersion returns error if the schema is behind the version of the server, e.g. the migration is not run yet or failed.
+// A schema that is ahead is ready, the migrations are backward compatible so the old instances keep serving in a rolling deploy
+func (u *healthUsecase) checkMigrationVersion(ctx context.Context) error {
+	version, err := u.healthRepo.GetMigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version.Dirty {
+		return fmt.Errorf("schema version %d is dirty, the migration failed", version.Version)
+	}
+	if version.Version < u.migrationVersion {
+		return fmt.Errorf("schema version %d is behind %d", version.Version, u.migrationVersion)
+	}
+
+	return nil
+}
+
+func (u *healthUsecase) logStatusChanges(ctx context.Context, report *entity.HealthReport) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for name, dependency := range report.Dependencies {
+		lastStatus, ok := u.lastStatus[name]
+		u.lastStatus[name] = dependency.Status
+		if (!ok && dependency.Status == entity.HealthStatusOk) || lastStatus == dependency.Status {
+			continue
+		}
+
+		if dependency.Status == entity.HealthStatusOk {
+			u.logger.InfoContext(ctx, "dependency is recovered", "dependency", name)
+		} else {
+			u.logger.WarnContext(ctx, "dependency is down", "dependency", name, "error", dependency.Error)
+		}
+	}
+}
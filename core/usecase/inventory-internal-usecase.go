@@ -1,13 +1,14 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 	"mfawzanid/warehouse-commerce/core/entity"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
 )
 
-func (u *inventoryUsecase) getWarehouseId(warehouseName string) (string, error) {
-	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(&entity.GetWarehousesRequest{
+func (u *inventoryUsecase) getWarehouseId(ctx context.Context, warehouseName string) (string, error) {
+	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(ctx, &entity.GetWarehousesRequest{
 		Name: warehouseName,
 	})
 	if err != nil {
@@ -21,8 +22,8 @@ func (u *inventoryUsecase) getWarehouseId(warehouseName string) (string, error)
 	return "", errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error get warehouse by name '%v'", warehouseName))
 }
 
-func (u *inventoryUsecase) getShopId(shopName string) (string, error) {
-	getShopsResp, err := u.inventoryRepo.GetShops(&entity.GetShopsRequest{
+func (u *inventoryUsecase) getShopId(ctx context.Context, shopName string) (string, error) {
+	getShopsResp, err := u.inventoryRepo.GetShops(ctx, &entity.GetShopsRequest{
 		Name: shopName,
 	})
 	if err != nil {
@@ -36,8 +37,8 @@ func (u *inventoryUsecase) getShopId(shopName string) (string, error) {
 	return "", errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error get shop by name '%v'", shopName))
 }
 
-func (u *inventoryUsecase) getProductId(productName string) (string, error) {
-	product, err := u.inventoryRepo.GetProductByName(productName)
+func (u *inventoryUsecase) getProductId(ctx context.Context, productName string) (string, error) {
+	product, err := u.inventoryRepo.GetProductByName(ctx, productName)
 	if err != nil {
 		return "", err
 	}
@@ -1,40 +1,50 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"mfawzanid/warehouse-commerce/core/entity"
 	"mfawzanid/warehouse-commerce/core/repository"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
 	serialutil "mfawzanid/warehouse-commerce/utils/serial"
-	transactionutil "mfawzanid/warehouse-commerce/utils/transaction"
 )
 
 type InventoryUsecaseInterface interface {
 	// warehouse
-	CreateWarehouse(req *entity.CreateWarehouseRequest) (string, error)
-	UpdateWarehouseStatus(req *entity.UpdateWarehouseStatusRequest) error
-	GetWarehouses(req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error)
+	CreateWarehouse(ctx context.Context, req *entity.CreateWarehouseRequest) (string, error)
+	UpdateWarehouseStatus(ctx context.Context, req *entity.UpdateWarehouseStatusRequest) error
+	GetWarehouses(ctx context.Context, req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error)
 
 	// shop
-	CreateShop(req *entity.CreateShopRequest) (string, error)
-	GetShops(req *entity.GetShopsRequest) (*entity.GetShopsResponse, error)
+	CreateShop(ctx context.Context, req *entity.CreateShopRequest) (string, error)
+	GetShops(ctx context.Context, req *entity.GetShopsRequest) (*entity.GetShopsResponse, error)
 
-	// shop-warehouse
-	UpsertShopToWarehouses(req *entity.UpsertShopToWarehousesRequest) error
+	// shop-warehouse, the actor must be a manager of the shop
+	UpsertShopToWarehouses(ctx context.Context, req *entity.UpsertShopToWarehousesRequest) error
 
 	// product
-	CreateProduct(req *entity.CreateProductRequest) (string, error)
-	UpdateProductStock(req *entity.UpdateProductWarehouseTotalStockRequest) error
-	TransferProduct(req *entity.TransferProductRequest) error
+	CreateProduct(ctx context.Context, req *entity.CreateProductRequest) (string, error)
+	UpdateProductStock(ctx context.Context, req *entity.UpdateProductWarehouseTotalStockRequest) error
+	TransferProduct(ctx context.Context, req *entity.TransferProductRequest) error
 }
 
 type inventoryUsecase struct {
-	inventoryRepo repository.InventoryRepositoryInterface
+	inventoryRepo  repository.InventoryRepositoryInterface
+	priceRepo      repository.PriceRepositoryInterface
+	membershipRepo repository.MembershipRepositoryInterface
+	unitOfWork     repository.UnitOfWorkInterface
+	logger         *slog.Logger
 }
 
-func NewInventoryUsecase(inventoryRepo repository.InventoryRepositoryInterface) InventoryUsecaseInterface {
+func NewInventoryUsecase(inventoryRepo repository.InventoryRepositoryInterface, priceRepo repository.PriceRepositoryInterface, membershipRepo repository.MembershipRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, logger *slog.Logger) InventoryUsecaseInterface {
 	return &inventoryUsecase{
-		inventoryRepo: inventoryRepo,
+		inventoryRepo:  inventoryRepo,
+		priceRepo:      priceRepo,
+		membershipRepo: membershipRepo,
+		unitOfWork:     unitOfWork,
+		logger:         logger,
 	}
 }
 
@@ -44,7 +54,7 @@ const (
 	productPrefixSerial   = "PRD"
 )
 
-func (u *inventoryUsecase) CreateWarehouse(req *entity.CreateWarehouseRequest) (string, error) {
+func (u *inventoryUsecase) CreateWarehouse(ctx context.Context, req *entity.CreateWarehouseRequest) (string, error) {
 	if err := req.Validate(); err != nil {
 		return "", err
 	}
@@ -59,10 +69,10 @@ func (u *inventoryUsecase) CreateWarehouse(req *entity.CreateWarehouseRequest) (
 		Name:    req.Name,
 		Enabled: false,
 	}
-	if err := u.inventoryRepo.InsertWarehouse(warehouse); err != nil {
+	if err := u.inventoryRepo.InsertWarehouse(ctx, warehouse); err != nil {
 		if err == errorutil.ErrUniqueViolation {
 			// warehouse name is unique so if warehouse name has exist, then just return existing warehouse id
-			warehouseId, err := u.getWarehouseId(req.Name)
+			warehouseId, err := u.getWarehouseId(ctx, req.Name)
 			if err != nil {
 				return "", err
 			}
@@ -76,11 +86,11 @@ func (u *inventoryUsecase) CreateWarehouse(req *entity.CreateWarehouseRequest) (
 	return warehouse.Id, nil
 }
 
-func (u *inventoryUsecase) UpdateWarehouseStatus(req *entity.UpdateWarehouseStatusRequest) error {
-	return u.inventoryRepo.UpdateWarehouseStatus(req)
+func (u *inventoryUsecase) UpdateWarehouseStatus(ctx context.Context, req *entity.UpdateWarehouseStatusRequest) error {
+	return u.inventoryRepo.UpdateWarehouseStatus(ctx, req)
 }
 
-func (u *inventoryUsecase) GetWarehouses(req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
+func (u *inventoryUsecase) GetWarehouses(ctx context.Context, req *entity.GetWarehousesRequest) (*entity.GetWarehousesResponse, error) {
 	isEnabled := true
 	req.Enabled = &isEnabled
 
@@ -88,10 +98,10 @@ func (u *inventoryUsecase) GetWarehouses(req *entity.GetWarehousesRequest) (*ent
 		return nil, err
 	}
 
-	return u.inventoryRepo.GetWarehouses(req)
+	return u.inventoryRepo.GetWarehouses(ctx, req)
 }
 
-func (u *inventoryUsecase) CreateShop(req *entity.CreateShopRequest) (string, error) {
+func (u *inventoryUsecase) CreateShop(ctx context.Context, req *entity.CreateShopRequest) (string, error) {
 	if err := req.Validate(); err != nil {
 		return "", err
 	}
@@ -102,14 +112,15 @@ func (u *inventoryUsecase) CreateShop(req *entity.CreateShopRequest) (string, er
 	}
 
 	shop := &entity.Shop{
-		Id:   shopId,
-		Name: req.Name,
+		Id:      shopId,
+		Name:    req.Name,
+		OwnerId: req.OwnerId,
 	}
 
-	if err = u.inventoryRepo.InsertShop(shop); err != nil {
+	if err = u.inventoryRepo.InsertShop(ctx, shop); err != nil {
 		if err == errorutil.ErrUniqueViolation {
 			// shop name is unique so if shop name has exist, then just return existing shop id
-			shopId, err := u.getShopId(req.Name)
+			shopId, err := u.getShopId(ctx, req.Name)
 			if err != nil {
 				return "", err
 			}
@@ -123,17 +134,25 @@ func (u *inventoryUsecase) CreateShop(req *entity.CreateShopRequest) (string, er
 	return shop.Id, nil
 }
 
-func (u *inventoryUsecase) GetShops(req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
+func (u *inventoryUsecase) GetShops(ctx context.Context, req *entity.GetShopsRequest) (*entity.GetShopsResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	return u.inventoryRepo.GetShops(req)
+	return u.inventoryRepo.GetShops(ctx, req)
 }
 
-func (u *inventoryUsecase) UpsertShopToWarehouses(req *entity.UpsertShopToWarehousesRequest) error {
+func (u *inventoryUsecase) UpsertShopToWarehouses(ctx context.Context, req *entity.UpsertShopToWarehousesRequest) error {
+	if _, err := authorizeShopMember(ctx, u.membershipRepo, &entity.AuthorizeShopMemberRequest{
+		ShopId:  req.ShopId,
+		Actor:   req.Actor,
+		MinRole: entity.ShopMemberRoleManager,
+	}); err != nil {
+		return err
+	}
+
 	// validate shopId whether exist or not
-	getShopsResp, err := u.inventoryRepo.GetShops(&entity.GetShopsRequest{
+	getShopsResp, err := u.inventoryRepo.GetShops(ctx, &entity.GetShopsRequest{
 		Ids: []string{req.ShopId},
 	})
 	if err != nil {
@@ -144,7 +163,7 @@ func (u *inventoryUsecase) UpsertShopToWarehouses(req *entity.UpsertShopToWareho
 	}
 
 	// validate warehouseIds whether exist or not
-	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(&entity.GetWarehousesRequest{
+	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(ctx, &entity.GetWarehousesRequest{
 		Ids: req.WarehouseIds,
 	})
 	if err != nil {
@@ -154,16 +173,16 @@ func (u *inventoryUsecase) UpsertShopToWarehouses(req *entity.UpsertShopToWareho
 		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error upsert shop to warehouses: some warehouses are not found"))
 	}
 
-	return u.inventoryRepo.InsertShopWarehouses(req)
+	return u.inventoryRepo.InsertShopWarehouses(ctx, req)
 }
 
-func (u *inventoryUsecase) CreateProduct(req *entity.CreateProductRequest) (string, error) {
+func (u *inventoryUsecase) CreateProduct(ctx context.Context, req *entity.CreateProductRequest) (string, error) {
 	if err := req.Validate(); err != nil {
 		return "", err
 	}
 
 	// validate warehouseId whether exist or not
-	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(&entity.GetWarehousesRequest{
+	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(ctx, &entity.GetWarehousesRequest{
 		Ids: []string{req.WarehouseId},
 	})
 	if err != nil {
@@ -178,55 +197,57 @@ func (u *inventoryUsecase) CreateProduct(req *entity.CreateProductRequest) (stri
 		return "", fmt.Errorf("error create product in generating uuid: %v", err.Error())
 	}
 
-	tx, err := u.inventoryRepo.GetDb().Begin()
+	// initial base price is recorded as the first price history
+	history, err := newPriceHistory(productId, "", req.Price, req.ActorId)
 	if err != nil {
-		return "", fmt.Errorf("error create product in initiating transaction: %v", err.Error())
+		return "", err
 	}
 
-	defer func() {
-		err = transactionutil.SettleTransaction(tx, err)
-	}()
-
-	if err := u.inventoryRepo.InsertProduct(tx, &entity.Product{
-		Id:    productId,
-		Name:  req.Name,
-		Price: req.Price,
-	}); err != nil {
-		if err == errorutil.ErrUniqueViolation {
-			// produt name is assumed set as unique so if product name has exist, then just return existing product id
-			productId, err = u.getProductId(req.Name)
-			if err != nil {
-				return "", err
-			}
+	var productExists bool
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Inventory.InsertProduct(ctx, &entity.Product{
+			Id:    productId,
+			Name:  req.Name,
+			Price: req.Price,
+		}); err != nil {
+			productExists = errors.Is(err, entity.ErrProductNameIsUsed)
+			return err
+		}
 
-			return productId, nil
-		} else {
-			return "", err
+		if err := repos.Price.InsertPriceHistory(ctx, history); err != nil {
+			return err
 		}
-	}
 
-	if err := u.inventoryRepo.InsertProductWarehouseTx(tx, &entity.ProductWarehouse{
-		ProductId:   productId,
-		WarehouseId: req.WarehouseId,
-		TotalStock:  req.TotalStock,
+		return repos.Inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{
+			ProductId:   productId,
+			WarehouseId: req.WarehouseId,
+			TotalStock:  req.TotalStock,
+		})
 	}); err != nil {
+		if productExists {
+			// produt name is assumed set as unique so if product name has exist, then just return existing product id,
+			// the transaction is aborted by the violation, so it is read after the rollback
+			return u.getProductId(ctx, req.Name)
+		}
 		return "", err
 	}
 
+	u.logger.InfoContext(ctx, "product is created", "productId", productId, "warehouseId", req.WarehouseId, "totalStock", req.TotalStock)
+
 	return productId, nil
 }
 
-func (u *inventoryUsecase) UpdateProductStock(req *entity.UpdateProductWarehouseTotalStockRequest) error {
-	return u.inventoryRepo.UpdateProductWarehouseTotalStock(req)
+func (u *inventoryUsecase) UpdateProductStock(ctx context.Context, req *entity.UpdateProductWarehouseTotalStockRequest) error {
+	return u.inventoryRepo.UpdateProductWarehouseTotalStock(ctx, req)
 }
 
-func (u *inventoryUsecase) TransferProduct(req *entity.TransferProductRequest) error {
+func (u *inventoryUsecase) TransferProduct(ctx context.Context, req *entity.TransferProductRequest) error {
 	if err := req.Validate(); err != nil {
 		return err
 	}
 
 	// validate both warehouses
-	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(&entity.GetWarehousesRequest{
+	getWarehousesResp, err := u.inventoryRepo.GetWarehouses(ctx, &entity.GetWarehousesRequest{
 		Ids: []string{req.SourceWarehouseId, req.DestinationWarehouseId},
 	})
 	if err != nil {
@@ -237,7 +258,7 @@ func (u *inventoryUsecase) TransferProduct(req *entity.TransferProductRequest) e
 	}
 
 	// validate product from source warehouse
-	sourceProductWarehouses, err := u.inventoryRepo.GetProductWarehousesByQuery(&entity.GetProductWarehousesByQueryRequest{
+	sourceProductWarehouses, err := u.inventoryRepo.GetProductWarehousesByQuery(ctx, &entity.GetProductWarehousesByQueryRequest{
 		ProductIds:   []string{req.ProductId},
 		WarehouseIds: []string{req.SourceWarehouseId},
 	})
@@ -254,30 +275,27 @@ func (u *inventoryUsecase) TransferProduct(req *entity.TransferProductRequest) e
 		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error transfer product: total stock that transfered is not sufficient"))
 	}
 
-	tx, err := u.inventoryRepo.GetDb().Begin()
-	if err != nil {
-		return fmt.Errorf("error transfer product in initiating transaction: %v", err.Error())
-	}
-
-	defer func() {
-		err = transactionutil.SettleTransaction(tx, err)
-	}()
+	// both warehouses are updated in one transaction
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{
+			ProductId:   req.ProductId,
+			WarehouseId: req.SourceWarehouseId,
+			TotalStock:  -req.TotalStock, // add existing value with -totalStock that will transfer
+		}); err != nil {
+			return err
+		}
 
-	if err := u.inventoryRepo.InsertProductWarehouseTx(tx, &entity.ProductWarehouse{
-		ProductId:   req.ProductId,
-		WarehouseId: req.SourceWarehouseId,
-		TotalStock:  -req.TotalStock, // add existing value with -totalStock that will transfer
+		return repos.Inventory.InsertProductWarehouse(ctx, &entity.ProductWarehouse{
+			ProductId:   req.ProductId,
+			WarehouseId: req.DestinationWarehouseId,
+			TotalStock:  req.TotalStock, // add existing value with totalStock that will transfer
+		})
 	}); err != nil {
 		return err
 	}
 
-	if err := u.inventoryRepo.InsertProductWarehouseTx(tx, &entity.ProductWarehouse{
-		ProductId:   req.ProductId,
-		WarehouseId: req.DestinationWarehouseId,
-		TotalStock:  req.TotalStock, // add existing value with totalStock that will transfer
-	}); err != nil {
-		return err
-	}
+	u.logger.InfoContext(ctx, "product is transferred", "productId", req.ProductId, "sourceWarehouseId", req.SourceWarehouseId,
+		"destinationWarehouseId", req.DestinationWarehouseId, "totalStock", req.TotalStock)
 
 	return nil
 }
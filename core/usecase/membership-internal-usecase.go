@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"time"
+)
+
+// authorizeShopMember is shared by the usecases that are scoped to a shop. It returns the member of the actor,
+// or nil if the actor is not checked since it can manage any shop.
+func authorizeShopMember(ctx context.Context, membershipRepo repository.MembershipRepositoryInterface, req *entity.AuthorizeShopMemberRequest) (*entity.ShopMember, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if entity.HasPermission(req.Actor.Permissions, entity.PermissionShopManageAny) {
+		return nil, nil
+	}
+
+	member, err := membershipRepo.GetShopMember(ctx, req.ShopId, req.Actor.UserId)
+	if err != nil {
+		if errorutil.GetErrorType(err) == errorutil.ErrNotFound {
+			return nil, errorutil.NewErrorCode(errorutil.ErrForbidden, fmt.Errorf("error authorize shop member: user is not a member of shop '%s'", req.ShopId))
+		}
+		return nil, err
+	}
+	if !entity.IsShopMemberRoleAtLeast(member.Role, req.MinRole) {
+		return nil, errorutil.NewErrorCode(errorutil.ErrForbidden, fmt.Errorf("error authorize shop member: role '%s' of shop '%s' is required", req.MinRole, req.ShopId))
+	}
+
+	return member, nil
+}
+
+func newShopInvitation(req *entity.InviteShopMemberRequest, now time.Time) (*entity.ShopInvitation, error) {
+	invitationId, err := serialutil.GenerateId(shopInvitationPrefixSerial)
+	if err != nil {
+		return nil, fmt.Errorf("error invite shop member in generating uuid: %v", err.Error())
+	}
+
+	return &entity.ShopInvitation{
+		Id:             invitationId,
+		ShopId:         req.ShopId,
+		IdentifierType: req.IdentifierType,
+		Identifier:     req.Identifier,
+		Role:           req.Role,
+		InvitedBy:      req.Actor.UserId,
+		ExpiredAt:      now.Add(shopInvitationExpiredDuration),
+		CreatedAt:      now,
+	}, nil
+}
+
+// isInvitedUser checks the identifier of the invitation, identifiers are verified by otp on registration
+func isInvitedUser(invitation *entity.ShopInvitation, user *entity.User) bool {
+	if invitation.IdentifierType == entity.IdentifierTypeEmail {
+		return user.Email == invitation.Identifier
+	}
+	return user.PhoneNumber == invitation.Identifier
+}
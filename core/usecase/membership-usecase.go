@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/notifier"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+type MembershipUsecaseInterface interface {
+	// AuthorizeShopMember returns forbidden error if the user is not a member of the shop with the minimum role and can not manage any shop
+	AuthorizeShopMember(ctx context.Context, req *entity.AuthorizeShopMemberRequest) error
+	// InviteShopMember sends an invitation to the email or phone number, the invited user accepts it after login
+	InviteShopMember(ctx context.Context, req *entity.InviteShopMemberRequest) (invitationId string, err error)
+	AcceptShopInvitation(ctx context.Context, req *entity.AcceptShopInvitationRequest) (shopId string, err error)
+	GetShopMembers(ctx context.Context, req *entity.GetShopMembersRequest) ([]*entity.ShopMember, error)
+	RemoveShopMember(ctx context.Context, req *entity.RemoveShopMemberRequest) error
+}
+
+type membershipUsecase struct {
+	membershipRepo repository.MembershipRepositoryInterface
+	userRepo       repository.UserRepositoryInterface
+	unitOfWork     repository.UnitOfWorkInterface
+	notifier       notifier.NotifierInterface
+	logger         *slog.Logger
+}
+
+func NewMembershipUsecase(membershipRepo repository.MembershipRepositoryInterface, userRepo repository.UserRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, notifier notifier.NotifierInterface, logger *slog.Logger) MembershipUsecaseInterface {
+	return &membershipUsecase{
+		membershipRepo: membershipRepo,
+		userRepo:       userRepo,
+		unitOfWork:     unitOfWork,
+		notifier:       notifier,
+		logger:         logger,
+	}
+}
+
+const (
+	shopInvitationPrefixSerial    = "INV"
+	shopInvitationExpiredDuration = 7 * 24 * time.Hour
+)
+
+func (u *membershipUsecase) AuthorizeShopMember(ctx context.Context, req *entity.AuthorizeShopMemberRequest) error {
+	_, err := authorizeShopMember(ctx, u.membershipRepo, req)
+	return err
+}
+
+func (u *membershipUsecase) InviteShopMember(ctx context.Context, req *entity.InviteShopMemberRequest) (string, error) {
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+
+	actor, err := authorizeShopMember(ctx, u.membershipRepo, &entity.AuthorizeShopMemberRequest{
+		ShopId:  req.ShopId,
+		Actor:   req.Actor,
+		MinRole: entity.ShopMemberRoleManager,
+	})
+	if err != nil {
+		return "", err
+	}
+	if actor != nil && !entity.IsShopMemberRoleHigher(actor.Role, req.Role) {
+		return "", errorutil.NewErrorCode(errorutil.ErrForbidden, fmt.Errorf("error invite shop member: '%s' can not invite '%s'", actor.Role, req.Role))
+	}
+
+	invitation, err := newShopInvitation(req, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	if err := u.membershipRepo.InsertShopInvitation(ctx, invitation); err != nil {
+		return "", err
+	}
+
+	u.logger.InfoContext(ctx, "shop member is invited", "invitationId", invitation.Id, "shopId", invitation.ShopId, "role", invitation.Role, "invitedBy", req.Actor.UserId)
+
+	message := fmt.Sprintf("you are invited as %s of shop '%s', login and accept invitation '%s' before it expires in %d days",
+		invitation.Role, invitation.ShopId, invitation.Id, int(shopInvitationExpiredDuration.Hours()/24))
+	if err := u.notifier.Notify(req.IdentifierType, req.Identifier, message); err != nil {
+		return "", err
+	}
+
+	return invitation.Id, nil
+}
+
+func (u *membershipUsecase) AcceptShopInvitation(ctx context.Context, req *entity.AcceptShopInvitationRequest) (string, error) {
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+
+	timeNow := time.Now()
+
+	invitation, err := u.membershipRepo.GetShopInvitationById(ctx, req.InvitationId)
+	if err != nil {
+		return "", err
+	}
+	if !invitation.IsPending(timeNow) {
+		return "", errorutil.NewErrorCode(errorutil.ErrConflict, errors.New("error accept shop invitation: invitation is expired or already accepted"))
+	}
+
+	user, err := u.userRepo.GetUser(ctx, &entity.GetUserRequest{Id: req.UserId})
+	if err != nil {
+		return "", err
+	}
+	if !isInvitedUser(invitation, user) {
+		return "", errorutil.NewErrorCode(errorutil.ErrForbidden, errors.New("error accept shop invitation: invitation is for other user"))
+	}
+
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Membership.AcceptShopInvitation(ctx, invitation.Id, timeNow); err != nil {
+			return err
+		}
+		return repos.Membership.InsertShopMember(ctx, &entity.ShopMember{
+			ShopId:    invitation.ShopId,
+			UserId:    user.Id,
+			Role:      invitation.Role,
+			CreatedAt: timeNow,
+		})
+	}); err != nil {
+		return "", err
+	}
+
+	u.logger.InfoContext(ctx, "shop invitation is accepted", "invitationId", invitation.Id, "shopId", invitation.ShopId, "userId", user.Id, "role", invitation.Role)
+
+	return invitation.ShopId, nil
+}
+
+func (u *membershipUsecase) GetShopMembers(ctx context.Context, req *entity.GetShopMembersRequest) ([]*entity.ShopMember, error) {
+	if _, err := authorizeShopMember(ctx, u.membershipRepo, &entity.AuthorizeShopMemberRequest{
+		ShopId:  req.ShopId,
+		Actor:   req.Actor,
+		MinRole: entity.ShopMemberRoleStaff,
+	}); err != nil {
+		return nil, err
+	}
+
+	return u.membershipRepo.GetShopMembers(ctx, req.ShopId)
+}
+
+func (u *membershipUsecase) RemoveShopMember(ctx context.Context, req *entity.RemoveShopMemberRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	actor, err := authorizeShopMember(ctx, u.membershipRepo, &entity.AuthorizeShopMemberRequest{
+		ShopId:  req.ShopId,
+		Actor:   req.Actor,
+		MinRole: entity.ShopMemberRoleManager,
+	})
+	if err != nil {
+		return err
+	}
+
+	member, err := u.membershipRepo.GetShopMember(ctx, req.ShopId, req.UserId)
+	if err != nil {
+		return err
+	}
+	if member.Role == entity.ShopMemberRoleOwner {
+		return errorutil.NewErrorCode(errorutil.ErrBadRequest, errors.New("error remove shop member: owner can not be removed"))
+	}
+	if actor != nil && !entity.IsShopMemberRoleHigher(actor.Role, member.Role) {
+		return errorutil.NewErrorCode(errorutil.ErrForbidden, fmt.Errorf("error remove shop member: '%s' can not remove '%s'", actor.Role, member.Role))
+	}
+
+	if err := u.membershipRepo.DeleteShopMember(ctx, req.ShopId, req.UserId); err != nil {
+		return err
+	}
+
+	u.logger.InfoContext(ctx, "shop member is removed", "shopId", req.ShopId, "userId", req.UserId, "role", member.Role, "removedBy", req.Actor.UserId)
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"time"
+)
+
+// newPriceHistory returns history record of a price change, shop id is empty for base price
+func newPriceHistory(productId, shopId string, price int, actorId string) (*entity.PriceHistory, error) {
+	historyId, err := serialutil.GenerateId(priceHistoryPrefixSerial)
+	if err != nil {
+		return nil, fmt.Errorf("error create price history in generating uuid: %v", err.Error())
+	}
+
+	source := entity.PriceSourceBase
+	if shopId != "" {
+		source = entity.PriceSourceShop
+	}
+
+	return &entity.PriceHistory{
+		Id:        historyId,
+		ProductId: productId,
+		ShopId:    shopId,
+		Source:    source,
+		Price:     price,
+		ActorId:   actorId,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"time"
+)
+
+type PriceUsecaseInterface interface {
+	UpdateProductPrice(ctx context.Context, req *entity.UpdateProductPriceRequest) error
+
+	// shop price & price list, the actor must be a staff of the shop
+	UpsertShopProductPrice(ctx context.Context, req *entity.UpsertShopProductPriceRequest) error
+	CreatePriceList(ctx context.Context, req *entity.CreatePriceListRequest) (string, error)
+	GetPriceLists(ctx context.Context, req *entity.GetPriceListsRequest) (*entity.GetPriceListsResponse, error)
+
+	// ResolvePrices returns the price of each product in the shop at the time, keyed by product id
+	ResolvePrices(ctx context.Context, shopId string, productIds []string, at time.Time) (map[string]*entity.ResolvedPrice, error)
+	GetProductPriceAt(ctx context.Context, req *entity.GetProductPriceAtRequest) (*entity.GetProductPriceAtResponse, error)
+}
+
+type priceUsecase struct {
+	priceRepo      repository.PriceRepositoryInterface
+	membershipRepo repository.MembershipRepositoryInterface
+	unitOfWork     repository.UnitOfWorkInterface
+	logger         *slog.Logger
+}
+
+func NewPriceUsecase(priceRepo repository.PriceRepositoryInterface, membershipRepo repository.MembershipRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, logger *slog.Logger) PriceUsecaseInterface {
+	return &priceUsecase{
+		priceRepo:      priceRepo,
+		membershipRepo: membershipRepo,
+		unitOfWork:     unitOfWork,
+		logger:         logger,
+	}
+}
+
+const (
+	priceListPrefixSerial    = "PRL"
+	priceHistoryPrefixSerial = "PRH"
+)
+
+func (u *priceUsecase) UpdateProductPrice(ctx context.Context, req *entity.UpdateProductPriceRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	history, err := newPriceHistory(req.ProductId, "", req.Price, req.ActorId)
+	if err != nil {
+		return err
+	}
+
+	// price & its history are written in one transaction
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Price.UpdateProductPrice(ctx, req.ProductId, req.Price); err != nil {
+			return err
+		}
+		return repos.Price.InsertPriceHistory(ctx, history)
+	}); err != nil {
+		return err
+	}
+
+	u.logger.InfoContext(ctx, "product price is updated", "productId", req.ProductId, "price", req.Price, "priceHistoryId", history.Id)
+
+	return nil
+}
+
+func (u *priceUsecase) UpsertShopProductPrice(ctx context.Context, req *entity.UpsertShopProductPriceRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := authorizeShopMember(ctx, u.membershipRepo, &entity.AuthorizeShopMemberRequest{
+		ShopId:  req.ShopId,
+		Actor:   req.Actor,
+		MinRole: entity.ShopMemberRoleStaff,
+	}); err != nil {
+		return err
+	}
+
+	history, err := newPriceHistory(req.ProductId, req.ShopId, req.Price, req.ActorId)
+	if err != nil {
+		return err
+	}
+
+	// price & its history are written in one transaction
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Price.UpsertShopProductPrice(ctx, &entity.ShopProductPrice{
+			ShopId:    req.ShopId,
+			ProductId: req.ProductId,
+			Price:     req.Price,
+		}); err != nil {
+			return err
+		}
+		return repos.Price.InsertPriceHistory(ctx, history)
+	}); err != nil {
+		return err
+	}
+
+	u.logger.InfoContext(ctx, "shop product price is updated", "shopId", req.ShopId, "productId", req.ProductId, "price", req.Price, "priceHistoryId", history.Id)
+
+	return nil
+}
+
+func (u *priceUsecase) CreatePriceList(ctx context.Context, req *entity.CreatePriceListRequest) (string, error) {
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+
+	if _, err := authorizeShopMember(ctx, u.membershipRepo, &entity.AuthorizeShopMemberRequest{
+		ShopId:  req.ShopId,
+		Actor:   req.Actor,
+		MinRole: entity.ShopMemberRoleStaff,
+	}); err != nil {
+		return "", err
+	}
+
+	priceListId, err := serialutil.GenerateId(priceListPrefixSerial)
+	if err != nil {
+		return "", fmt.Errorf("error create price list in generating uuid: %v", err.Error())
+	}
+
+	for _, item := range req.Items {
+		item.PriceListId = priceListId
+	}
+
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Price.InsertPriceList(ctx, &entity.PriceList{
+			Id:      priceListId,
+			ShopId:  req.ShopId,
+			Name:    req.Name,
+			StartAt: req.StartAt,
+			EndAt:   req.EndAt,
+		}); err != nil {
+			return err
+		}
+		return repos.Price.InsertPriceListItems(ctx, req.Items)
+	}); err != nil {
+		return "", err
+	}
+
+	u.logger.InfoContext(ctx, "price list is created", "priceListId", priceListId, "shopId", req.ShopId, "items", len(req.Items))
+
+	return priceListId, nil
+}
+
+func (u *priceUsecase) GetPriceLists(ctx context.Context, req *entity.GetPriceListsRequest) (*entity.GetPriceListsResponse, error) {
+	if req.ShopId == "" {
+		return nil, errorutil.NewFieldValidationError("error get price lists", &errorutil.FieldError{Field: "shopId", Message: "shop id is mandatory"})
+	}
+
+	if _, err := authorizeShopMember(ctx, u.membershipRepo, &entity.AuthorizeShopMemberRequest{
+		ShopId:  req.ShopId,
+		Actor:   req.Actor,
+		MinRole: entity.ShopMemberRoleStaff,
+	}); err != nil {
+		return nil, err
+	}
+
+	req.ActiveFrom = time.Now()
+
+	priceLists, err := u.priceRepo.GetPriceLists(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if priceLists == nil {
+		priceLists = []*entity.PriceList{}
+	}
+
+	return &entity.GetPriceListsResponse{
+		PriceLists: priceLists,
+	}, nil
+}
+
+// ResolvePrices resolves price with priority: active price list, shop override price, then base product price
+func (u *priceUsecase) ResolvePrices(ctx context.Context, shopId string, productIds []string, at time.Time) (map[string]*entity.ResolvedPrice, error) {
+	resolvedPrices := make(map[string]*entity.ResolvedPrice)
+	if len(productIds) == 0 {
+		return resolvedPrices, nil
+	}
+
+	productPrices, err := u.priceRepo.GetProductPrices(ctx, &entity.GetProductPricesRequest{
+		ShopId:     shopId,
+		ProductIds: productIds,
+		At:         at,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, productPrice := range productPrices {
+		resolvedPrice := &entity.ResolvedPrice{
+			ProductId:      productPrice.ProductId,
+			Price:          productPrice.BasePrice,
+			Source:         entity.PriceSourceBase,
+			PriceHistoryId: productPrice.BasePriceHistoryId,
+		}
+
+		if productPrice.PriceListPrice != nil {
+			resolvedPrice.Price = *productPrice.PriceListPrice
+			resolvedPrice.Source = entity.PriceSourcePriceList
+			resolvedPrice.PriceHistoryId = ""
+			resolvedPrice.PriceListId = productPrice.PriceListId
+		} else if productPrice.ShopPrice != nil {
+			resolvedPrice.Price = *productPrice.ShopPrice
+			resolvedPrice.Source = entity.PriceSourceShop
+			resolvedPrice.PriceHistoryId = productPrice.ShopPriceHistoryId
+		}
+
+		resolvedPrices[productPrice.ProductId] = resolvedPrice
+	}
+
+	return resolvedPrices, nil
+}
+
+// GetProductPriceAt returns the price of a product (in a shop if shop id is set) at a point in time, default is now
+func (u *priceUsecase) GetProductPriceAt(ctx context.Context, req *entity.GetProductPriceAtRequest) (*entity.GetProductPriceAtResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+
+	resolvedPrices, err := u.ResolvePrices(ctx, req.ShopId, []string{req.ProductId}, at)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedPrice, ok := resolvedPrices[req.ProductId]
+	if !ok {
+		return nil, errorutil.NewErrorCode(errorutil.ErrNotFound, fmt.Errorf("error get product price: product id '%s' is not found", req.ProductId))
+	}
+
+	resp := &entity.GetProductPriceAtResponse{
+		ProductId:      req.ProductId,
+		ShopId:         req.ShopId,
+		At:             at,
+		Price:          resolvedPrice.Price,
+		Source:         resolvedPrice.Source,
+		PriceHistoryId: resolvedPrice.PriceHistoryId,
+		PriceListId:    resolvedPrice.PriceListId,
+	}
+
+	// who & when the price is changed
+	if resolvedPrice.PriceHistoryId != "" {
+		history, err := u.priceRepo.GetPriceHistoryById(ctx, resolvedPrice.PriceHistoryId)
+		if err != nil {
+			return nil, err
+		}
+		resp.ChangedBy = history.ActorId
+		resp.ChangedAt = &history.CreatedAt
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"time"
+)
+
+func newPrivacyRequest(req *entity.UserDataRequest, requestType string, timeNow time.Time) (*entity.PrivacyRequest, error) {
+	privacyRequestId, err := serialutil.GenerateId(privacyRequestPrefixSerial)
+	if err != nil {
+		return nil, fmt.Errorf("error create privacy request in generating uuid: %v", err.Error())
+	}
+
+	return &entity.PrivacyRequest{
+		Id:          privacyRequestId,
+		UserId:      req.UserId,
+		Type:        requestType,
+		Channel:     req.Channel,
+		RequestedBy: req.RequestedBy,
+		CreatedAt:   timeNow,
+	}, nil
+}
+
+// getUserOrderExports gets the orders of the user and groups the items and payments by their order
+func (u *privacyUsecase) getUserOrderExports(ctx context.Context, userId string) ([]*entity.UserOrderExport, error) {
+	orders, err := u.privacyRepo.GetOrdersByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	orderExports := []*entity.UserOrderExport{}
+	if len(orders) == 0 {
+		return orderExports, nil
+	}
+
+	orderIds := make([]string, 0, len(orders))
+	orderExportMap := make(map[string]*entity.UserOrderExport, len(orders))
+	for _, order := range orders {
+		orderExport := &entity.UserOrderExport{
+			Order:    order,
+			Items:    []*entity.OrderItem{},
+			Payments: []*entity.Payment{},
+		}
+		orderIds = append(orderIds, order.Id)
+		orderExportMap[order.Id] = orderExport
+		orderExports = append(orderExports, orderExport)
+	}
+
+	items, err := u.privacyRepo.GetOrderItemsByOrderIds(ctx, orderIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if orderExport, ok := orderExportMap[item.OrderId]; ok {
+			orderExport.Items = append(orderExport.Items, item)
+		}
+	}
+
+	payments, err := u.privacyRepo.GetPaymentsByOrderIds(ctx, orderIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range payments {
+		if orderExport, ok := orderExportMap[payment.OrderId]; ok {
+			orderExport.Payments = append(orderExport.Payments, payment)
+		}
+	}
+
+	return orderExports, nil
+}
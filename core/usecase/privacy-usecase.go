@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
+)
+
+type PrivacyUsecaseInterface interface {
+	// ExportUserData returns everything tied to the user: profile, addresses, orders with the items and payments
+	ExportUserData(ctx context.Context, req *entity.UserDataRequest) (*entity.UserDataExport, error)
+	// DeleteUserData anonymizes the personal fields of the user, orders and payments are kept for accounting
+	DeleteUserData(ctx context.Context, req *entity.UserDataRequest) error
+}
+
+type privacyUsecase struct {
+	privacyRepo repository.PrivacyRepositoryInterface
+	userRepo    repository.UserRepositoryInterface
+	unitOfWork  repository.UnitOfWorkInterface
+	authUsecase AuthUsecaseInterface
+	logger      *slog.Logger
+}
+
+func NewPrivacyUsecase(privacyRepo repository.PrivacyRepositoryInterface, userRepo repository.UserRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, authUsecase AuthUsecaseInterface, logger *slog.Logger) PrivacyUsecaseInterface {
+	return &privacyUsecase{
+		privacyRepo: privacyRepo,
+		userRepo:    userRepo,
+		unitOfWork:  unitOfWork,
+		authUsecase: authUsecase,
+		logger:      logger,
+	}
+}
+
+const (
+	privacyRequestPrefixSerial = "PRQ"
+)
+
+func (u *privacyUsecase) ExportUserData(ctx context.Context, req *entity.UserDataRequest) (*entity.UserDataExport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	timeNow := time.Now()
+
+	user, err := u.userRepo.GetUser(ctx, &entity.GetUserRequest{Id: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := u.privacyRepo.GetAllAddresses(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := u.getUserOrderExports(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the export is completed once the archive is built
+	privacyRequest, err := newPrivacyRequest(req, entity.PrivacyRequestTypeExport, timeNow)
+	if err != nil {
+		return nil, err
+	}
+	privacyRequest.CompletedAt = &timeNow
+
+	if err := u.privacyRepo.InsertPrivacyRequest(ctx, privacyRequest); err != nil {
+		return nil, err
+	}
+
+	return &entity.UserDataExport{
+		ExportedAt: timeNow,
+		Profile:    user,
+		Addresses:  addresses,
+		Orders:     orders,
+	}, nil
+}
+
+func (u *privacyUsecase) DeleteUserData(ctx context.Context, req *entity.UserDataRequest) (err error) {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	timeNow := time.Now()
+
+	user, err := u.userRepo.GetUser(ctx, &entity.GetUserRequest{Id: req.UserId})
+	if err != nil {
+		return err
+	}
+
+	// a shop can not be left without owner, the shop must be handed over first
+	ownedShops, err := u.privacyRepo.CountOwnedShops(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+	if ownedShops > 0 {
+		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error delete user data: user owns %d shops", ownedShops))
+	}
+
+	// the request is recorded before the deletion, so a failed deletion is still audited
+	privacyRequest, err := newPrivacyRequest(req, entity.PrivacyRequestTypeDeletion, timeNow)
+	if err != nil {
+		return err
+	}
+
+	if err := u.privacyRepo.InsertPrivacyRequest(ctx, privacyRequest); err != nil {
+		return err
+	}
+
+	revokedSessionIds, err := u.anonymizeUser(ctx, user, privacyRequest.Id, timeNow)
+	if err != nil {
+		return err
+	}
+
+	// the sessions are already revoked in db with the deletion, so a failed write to the revocation list
+	// only leaves access tokens that expire soon. The list is updated even if the client is gone.
+	if err := u.authUsecase.AddToRevocationList(context.WithoutCancel(ctx), revokedSessionIds); err != nil {
+		u.logger.ErrorContext(ctx, "error add sessions of deleted user to revocation list", "userId", user.Id, "error", err)
+	}
+
+	return nil
+}
+
+// anonymizeUser returns the ids of the sessions that are revoked with the deletion
+func (u *privacyUsecase) anonymizeUser(ctx context.Context, user *entity.User, privacyRequestId string, timeNow time.Time) ([]string, error) {
+	var revokedSessionIds []string
+	err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Privacy.AnonymizeUser(ctx, user.Id, timeNow); err != nil {
+			if errorutil.GetErrorType(err) == errorutil.ErrNotFound {
+				return errorutil.NewErrorCode(errorutil.ErrConflict, errors.New("error delete user data: user is already deleted"))
+			}
+			return err
+		}
+
+		if err := repos.Privacy.AnonymizeAddresses(ctx, user.Id, timeNow); err != nil {
+			return err
+		}
+
+		sessionIds, err := repos.Privacy.DeleteUserRecords(ctx, user, timeNow)
+		if err != nil {
+			return err
+		}
+		revokedSessionIds = sessionIds
+
+		return repos.Privacy.CompletePrivacyRequest(ctx, privacyRequestId, timeNow)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revokedSessionIds, nil
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"strings"
+)
+
+// rateLimitSubject returns the caller that is counted by the rule, it falls back to the ip if the caller is anonymous
+func rateLimitSubject(req *entity.RateLimitRequest) string {
+	switch {
+	case req.Rule.KeyBy == entity.RateLimitKeyIdentifier && req.Identifier != "":
+		// the identifier is hashed, so the emails and phone numbers are not kept in the keys of the limiter
+		hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(req.Identifier))))
+		return entity.RateLimitKeyIdentifier + ":" + hex.EncodeToString(hash[:])
+	case req.Rule.KeyBy == entity.RateLimitKeyUser && req.UserId != "":
+		return entity.RateLimitKeyUser + ":" + req.UserId
+	case (req.Rule.KeyBy == entity.RateLimitKeyUser || req.Rule.KeyBy == entity.RateLimitKeyAPIKey) && req.APIKeyId != "":
+		return entity.RateLimitKeyAPIKey + ":" + req.APIKeyId
+	default:
+		return entity.RateLimitKeyIP + ":" + req.IP
+	}
+}
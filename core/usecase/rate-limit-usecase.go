@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	"time"
+)
+
+type RateLimitUsecaseInterface interface {
+	// AllowRequest counts the request by the key of the rule, the request is allowed if the limiter is unavailable
+	AllowRequest(ctx context.Context, req *entity.RateLimitRequest) (*entity.RateLimitResult, error)
+}
+
+type rateLimitUsecase struct {
+	rateLimitRepo repository.RateLimitRepositoryInterface
+	logger        *slog.Logger
+}
+
+func NewRateLimitUsecase(rateLimitRepo repository.RateLimitRepositoryInterface, logger *slog.Logger) RateLimitUsecaseInterface {
+	return &rateLimitUsecase{
+		rateLimitRepo: rateLimitRepo,
+		logger:        logger,
+	}
+}
+
+func (u *rateLimitUsecase) AllowRequest(ctx context.Context, req *entity.RateLimitRequest) (*entity.RateLimitResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s:%s", req.Route, rateLimitSubject(req))
+
+	result, err := u.rateLimitRepo.AllowRequest(ctx, key, req.Rule.Limit, req.Rule.Window, time.Now())
+	if err != nil {
+		// the limiter protects the service, so it must not take the service down when it is unavailable
+		u.logger.WarnContext(ctx, "error allow request, the request is allowed", "key", key, "error", err)
+		return &entity.RateLimitResult{Allowed: true, Limit: req.Rule.Limit, Remaining: req.Rule.Limit}, nil
+	}
+
+	return result, nil
+}
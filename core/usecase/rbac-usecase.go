@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"slices"
+	"time"
+)
+
+type RbacUsecaseInterface interface {
+	// AssignRoles replaces the roles of the user, the new permissions are applied on the next login or token refresh
+	AssignRoles(ctx context.Context, req *entity.AssignRolesRequest) error
+	// BootstrapAdmin creates the first admin, it returns error if an admin exists
+	BootstrapAdmin(ctx context.Context, req *entity.BootstrapAdminRequest) (userId string, err error)
+}
+
+type rbacUsecase struct {
+	rbacRepo   repository.RbacRepositoryInterface
+	userRepo   repository.UserRepositoryInterface
+	unitOfWork repository.UnitOfWorkInterface
+	logger     *slog.Logger
+}
+
+func NewRbacUsecase(rbacRepo repository.RbacRepositoryInterface, userRepo repository.UserRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, logger *slog.Logger) RbacUsecaseInterface {
+	return &rbacUsecase{
+		rbacRepo:   rbacRepo,
+		userRepo:   userRepo,
+		unitOfWork: unitOfWork,
+		logger:     logger,
+	}
+}
+
+func (u *rbacUsecase) AssignRoles(ctx context.Context, req *entity.AssignRolesRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// an admin can not remove its own admin role, so there is always an admin to assign roles
+	if req.UserId == req.ActorId && !slices.Contains(req.Roles, entity.RoleAdmin) {
+		actorRoles, err := u.rbacRepo.GetUserRoles(ctx, req.ActorId)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(actorRoles.Roles, entity.RoleAdmin) {
+			return errorutil.NewErrorCode(errorutil.ErrBadRequest, errors.New("error assign roles: can not remove own admin role"))
+		}
+	}
+
+	// the roles are replaced (delete then insert) in one transaction
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		return repos.Rbac.SetUserRoles(ctx, req.UserId, req.Roles)
+	}); err != nil {
+		return err
+	}
+
+	u.logger.InfoContext(ctx, "roles are assigned", "userId", req.UserId, "roles", req.Roles, "actorId", req.ActorId)
+
+	return nil
+}
+
+func (u *rbacUsecase) BootstrapAdmin(ctx context.Context, req *entity.BootstrapAdminRequest) (string, error) {
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+
+	count, err := u.rbacRepo.CountUsersByRole(ctx, entity.RoleAdmin)
+	if err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "", errorutil.NewErrorCode(errorutil.ErrBadRequest, errors.New("error bootstrap admin: admin exists, assign the role with the api"))
+	}
+
+	roles := []string{entity.RoleAdmin}
+
+	user, err := u.userRepo.GetUser(ctx, newGetUserRequest(req.IdentifierType, req.Identifier))
+	if err != nil && errorutil.GetErrorType(err) != errorutil.ErrNotFound {
+		return "", err
+	}
+
+	isNewUser := user == nil
+	if !isNewUser {
+		// registered user is promoted, its roles are kept
+		userRoles, err := u.rbacRepo.GetUserRoles(ctx, user.Id)
+		if err != nil {
+			return "", err
+		}
+		roles = append(roles, userRoles.Roles...)
+	} else {
+		newUserId, err := serialutil.GenerateId(userPrefixSerial)
+		if err != nil {
+			return "", fmt.Errorf("error bootstrap admin in generating uuid: %v", err.Error())
+		}
+
+		user, err = newUser(newUserId, req.IdentifierType, req.Identifier, req.Password, time.Now())
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// a new user is inserted with its roles, so there is no user without the admin role if it fails
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if isNewUser {
+			if err := repos.User.InsertUser(ctx, user); err != nil {
+				return err
+			}
+		}
+		return repos.Rbac.SetUserRoles(ctx, user.Id, roles)
+	}); err != nil {
+		return "", err
+	}
+
+	return user.Id, nil
+}
@@ -4,39 +4,78 @@ import (
 	"context"
 	"fmt"
 	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/repository"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"time"
 )
 
-func (u *transactionUsecase) validatePaymentAmount(orderId string, paymentAmount int) error {
+// validatePaymentAmount validates the order is active and owned by the user, and the amount is same with the order's
+func (u *transactionUsecase) validatePaymentAmount(ctx context.Context, orderId, userId string, paymentAmount int) error {
 	var isActiveOrder *bool
 	active := true
 	isActiveOrder = &active
 
 	if orderId == "" {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error pay order: order id is mandatory"))
+		return errorutil.NewFieldValidationError("error pay order", &errorutil.FieldError{Field: "orderId", Message: "order id is mandatory"})
 	}
 
-	order, err := u.transactionRepo.GetOrderById(orderId, isActiveOrder)
+	order, err := u.transactionRepo.GetOrderById(ctx, orderId, isActiveOrder)
 	if err != nil {
 		return err
 	}
 
+	if order.UserId != userId {
+		return errorutil.NewErrorCode(errorutil.ErrForbidden, fmt.Errorf("error pay order: order '%s' is owned by other user", orderId))
+	}
+
 	if paymentAmount != order.Amount {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error pay order: amount is not same with order's amount"))
+		return errorutil.NewFieldValidationError("error pay order", &errorutil.FieldError{Field: "amount", Message: "amount is not same with order's amount"})
+	}
+
+	return nil
+}
+
+// validateShippingAddress validates the address is in the address book of the user, the address is optional
+func (u *transactionUsecase) validateShippingAddress(ctx context.Context, userId, addressId string) error {
+	if addressId == "" {
+		return nil
+	}
+
+	if _, err := u.userRepo.GetAddress(ctx, userId, addressId); err != nil {
+		if errorutil.GetErrorType(err) == errorutil.ErrNotFound {
+			return errorutil.NewFieldValidationError("error order products", &errorutil.FieldError{Field: "shippingAddressId", Message: fmt.Sprintf("shipping address '%s' is not found", addressId)})
+		}
+		return err
+	}
+
+	return nil
+}
+
+// validatePendingOrders limits the pending orders of the user, since each of them reserves stock until it is paid or expired.
+// In the order transaction it must be called after the pending orders are locked, so concurrent orders can not pass it together.
+func validatePendingOrders(ctx context.Context, transactionRepo repository.TransactionRepositoryInterface, userId string) error {
+	count, err := transactionRepo.CountPendingOrders(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if count >= entity.MaxPendingOrdersPerUser {
+		return errorutil.NewErrorCode(errorutil.ErrTooManyRequests, fmt.Errorf("error order products: max %d pending orders, pay or wait until they expire", entity.MaxPendingOrdersPerUser))
 	}
 
 	return nil
 }
 
-func (u *transactionUsecase) getProductDetailMap(req *entity.OrderProductsRequest) (map[string]*entity.ProductDetail, error) {
+func (u *transactionUsecase) getProductDetailMap(ctx context.Context, req *entity.OrderProductsRequest) (map[string]*entity.ProductDetail, error) {
 	var productIds []string
 	for _, item := range req.Items {
 		productIds = append(productIds, item.ProductId)
 	}
 
-	getProductDetailsResp, err := u.inventoryRepo.GetProductDetailsByShopId(&entity.GetProductDetailsByShopIdRequest{
+	at := time.Now()
+	getProductDetailsResp, err := u.inventoryRepo.GetProductDetailsByShopId(ctx, &entity.GetProductDetailsByShopIdRequest{
 		ShopId:     req.ShopId,
 		ProductIds: productIds,
+		At:         at,
 	})
 	if err != nil {
 		return nil, err
@@ -45,6 +84,11 @@ func (u *transactionUsecase) getProductDetailMap(req *entity.OrderProductsReques
 		return nil, errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error order products: some products are not found"))
 	}
 
+	// order uses the price that is sold by the shop at this time
+	if err := u.setResolvedPrices(ctx, req.ShopId, getProductDetailsResp.ProductDetails, at); err != nil {
+		return nil, err
+	}
+
 	productDetailMap := make(map[string]*entity.ProductDetail)
 	for _, productDetail := range getProductDetailsResp.ProductDetails {
 		productDetailMap[productDetail.ProductId] = productDetail
@@ -53,15 +97,50 @@ func (u *transactionUsecase) getProductDetailMap(req *entity.OrderProductsReques
 	return productDetailMap, nil
 }
 
-func (u *transactionUsecase) validateTotalStock(productDetail *entity.ProductDetail, item *entity.OrderProductItem) error {
-	reservedQuantity, err := u.redisRepo.GetReservedProductQuantity(context.Background(), productDetail.ProductId, productDetail.WarehouseId)
+func (u *transactionUsecase) validateTotalStock(ctx context.Context, productDetail *entity.ProductDetail, item *entity.OrderProductItem) error {
+	reservedQuantity, err := u.redisRepo.GetReservedProductQuantity(ctx, productDetail.ProductId, productDetail.WarehouseId)
 	if err != nil {
 		return err
 	}
 
 	remainingStock := productDetail.TotalStock - reservedQuantity - item.Quantity
 	if remainingStock < 0 {
-		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error order products: product '%s' stock is not sufficient", item.ProductId))
+		return errorutil.NewErrorCode(errorutil.ErrBadRequest, fmt.Errorf("error order products: product '%s' %w", item.ProductId, entity.ErrInsufficientStock))
+	}
+
+	return nil
+}
+
+// invalidateLockOrderProducts releases the stock that is reserved by an order that is not created,
+// the locks are released even if the client is gone
+func (u *transactionUsecase) invalidateLockOrderProducts(ctx context.Context, lockedProducts []*entity.LockOrderProductRequest) {
+	for _, lockedProduct := range lockedProducts {
+		if err := u.redisRepo.InvalidateLockOrderProduct(context.WithoutCancel(ctx), lockedProduct); err != nil {
+			u.logger.ErrorContext(ctx, "error order products: error invalidate lock", "productId", lockedProduct.ProductId, "userId", lockedProduct.UserId, "error", err)
+		}
+	}
+}
+
+func (u *transactionUsecase) setResolvedPrices(ctx context.Context, shopId string, productDetails []*entity.ProductDetail, at time.Time) error {
+	productIdMap := make(map[string]bool)
+	var productIds []string
+	for _, productDetail := range productDetails {
+		if !productIdMap[productDetail.ProductId] {
+			productIdMap[productDetail.ProductId] = true
+			productIds = append(productIds, productDetail.ProductId)
+		}
+	}
+
+	resolvedPrices, err := u.priceUsecase.ResolvePrices(ctx, shopId, productIds, at)
+	if err != nil {
+		return err
+	}
+
+	for _, productDetail := range productDetails {
+		if resolvedPrice, ok := resolvedPrices[productDetail.ProductId]; ok {
+			productDetail.Price = resolvedPrice.Price
+			productDetail.ResolvedPrice = resolvedPrice
+		}
 	}
 
 	return nil
@@ -3,63 +3,98 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"mfawzanid/warehouse-commerce/core/entity"
 	"mfawzanid/warehouse-commerce/core/repository"
-	transactionutil "mfawzanid/warehouse-commerce/utils/transaction"
+	lifecycleutil "mfawzanid/warehouse-commerce/utils/lifecycle"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
 )
 
+// TransactionUsecaseInterface is used by the customers of a shop, so it does not check the shop membership
 type TransactionUsecaseInterface interface {
-	GetProductDetailsByShopId(req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error)
-	OrderProducts(req *entity.OrderProductsRequest) (string, error)
-	PayOrder(req *entity.PayOrderRequest) error
+	GetProductDetailsByShopId(ctx context.Context, req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error)
+	OrderProducts(ctx context.Context, req *entity.OrderProductsRequest) (string, error)
+	PayOrder(ctx context.Context, req *entity.PayOrderRequest) error
 }
 
+// TaskPayOrderPostAction is the background task that releases the reservation and updates the stock of a paid order
+const TaskPayOrderPostAction = "pay order post action"
+
 type transactionUsecase struct {
 	inventoryRepo   repository.InventoryRepositoryInterface
 	transactionRepo repository.TransactionRepositoryInterface
 	redisRepo       repository.RedisRepositoryInterface
+	priceUsecase    PriceUsecaseInterface
+	userRepo        repository.UserRepositoryInterface
+	unitOfWork      repository.UnitOfWorkInterface
+	orderExpireTime time.Duration // stock of a pending order is reserved until it expires
+	lifecycle       *lifecycleutil.Manager
+	logger          *slog.Logger
 }
 
-func NewTransactionUsecase(inventoryRepo repository.InventoryRepositoryInterface, transactionRepo repository.TransactionRepositoryInterface, redisRepo repository.RedisRepositoryInterface) TransactionUsecaseInterface {
+func NewTransactionUsecase(inventoryRepo repository.InventoryRepositoryInterface, transactionRepo repository.TransactionRepositoryInterface, redisRepo repository.RedisRepositoryInterface, priceUsecase PriceUsecaseInterface, userRepo repository.UserRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, orderExpireTime time.Duration, lifecycle *lifecycleutil.Manager, logger *slog.Logger) TransactionUsecaseInterface {
 	return &transactionUsecase{
 		inventoryRepo:   inventoryRepo,
 		transactionRepo: transactionRepo,
 		redisRepo:       redisRepo,
+		priceUsecase:    priceUsecase,
+		userRepo:        userRepo,
+		unitOfWork:      unitOfWork,
+		orderExpireTime: orderExpireTime,
+		lifecycle:       lifecycle,
+		logger:          logger,
 	}
 }
 
-func (u *transactionUsecase) GetProductDetailsByShopId(req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
+func (u *transactionUsecase) GetProductDetailsByShopId(ctx context.Context, req *entity.GetProductDetailsByShopIdRequest) (*entity.GetProductDetailsByShopIdResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	resp, err := u.inventoryRepo.GetProductDetailsByShopId(req)
+	// the price is sorted and filtered by the price that is sold at this time
+	if req.At.IsZero() {
+		req.At = time.Now()
+	}
+
+	resp, err := u.inventoryRepo.GetProductDetailsByShopId(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
 	// check reserved quantity, if any then should return the real remaining stock
 	for _, productDetail := range resp.ProductDetails {
-		reservedQuantity, err := u.redisRepo.GetReservedProductQuantity(context.Background(), productDetail.ProductId, productDetail.WarehouseId)
+		reservedQuantity, err := u.redisRepo.GetReservedProductQuantity(ctx, productDetail.ProductId, productDetail.WarehouseId)
 		if err != nil {
 			return nil, err
 		}
 		productDetail.TotalStock -= reservedQuantity
 	}
 
+	// set the price that is sold by the shop at this time
+	if err := u.setResolvedPrices(ctx, req.ShopId, resp.ProductDetails, req.At); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
-func (u *transactionUsecase) OrderProducts(req *entity.OrderProductsRequest) (string, error) {
+func (u *transactionUsecase) OrderProducts(ctx context.Context, req *entity.OrderProductsRequest) (orderId string, err error) {
 	if err := req.Validate(); err != nil {
 		return "", err
 	}
 
-	productDetailMap, err := u.getProductDetailMap(req)
+	if err := u.validateShippingAddress(ctx, req.UserId, req.ShippingAddressId); err != nil {
+		return "", err
+	}
+
+	// checked early so a user that reaches the limit does not reserve stock, it is checked again in the order transaction
+	if err := validatePendingOrders(ctx, u.transactionRepo, req.UserId); err != nil {
+		return "", err
+	}
+
+	productDetailMap, err := u.getProductDetailMap(ctx, req)
 	if err != nil {
 		return "", err
 	}
@@ -68,11 +103,19 @@ func (u *transactionUsecase) OrderProducts(req *entity.OrderProductsRequest) (st
 	if err != nil {
 		return "", fmt.Errorf("error order products in generating uuid: %v", err.Error())
 	}
-	orderId := newUUID.String()
+	orderId = newUUID.String()
 
 	var amount int
 	var orderItems []*entity.OrderItem
 
+	// the reserved stock is released if the order is not created, otherwise it is held until the reservation expires
+	var lockedProducts []*entity.LockOrderProductRequest
+	defer func() {
+		if err != nil {
+			u.invalidateLockOrderProducts(ctx, lockedProducts)
+		}
+	}()
+
 	for _, item := range req.Items {
 		// validate stock for each product
 		productDetail, ok := productDetailMap[item.ProductId]
@@ -80,117 +123,143 @@ func (u *transactionUsecase) OrderProducts(req *entity.OrderProductsRequest) (st
 			return "", fmt.Errorf("error order products: product '%s' is not found", item.ProductId)
 		}
 
-		if err := u.validateTotalStock(productDetail, item); err != nil {
+		if err := u.validateTotalStock(ctx, productDetail, item); err != nil {
 			return "", err
 		}
 
 		// lock the product and its quantity
-		if err = u.redisRepo.LockOrderProduct(context.Background(), &entity.LockOrderProductRequest{
+		lockedProduct := &entity.LockOrderProductRequest{
 			ProductId:   productDetail.ProductId,
 			WarehouseId: productDetail.WarehouseId,
 			UserId:      req.UserId,
 			Quantity:    item.Quantity,
-		}); err != nil {
+		}
+		if err := u.redisRepo.LockOrderProduct(ctx, lockedProduct); err != nil {
 			return "", fmt.Errorf("error order product '%s': %v", item.ProductId, err.Error())
 		}
+		lockedProducts = append(lockedProducts, lockedProduct)
 
 		amount += productDetail.Price * item.Quantity
 
-		orderItems = append(orderItems, &entity.OrderItem{
+		orderItem := &entity.OrderItem{
 			OrderId:     orderId,
 			ProductId:   item.ProductId,
 			ShopId:      req.ShopId,
 			WarehouseId: productDetail.WarehouseId,
 			Quantity:    item.Quantity,
 			UnitPrice:   productDetail.Price,
-		})
+			PriceSource: entity.PriceSourceBase,
+		}
+
+		// refer the price record that is used, so the unit price can be traced
+		if productDetail.ResolvedPrice != nil {
+			orderItem.PriceSource = productDetail.ResolvedPrice.Source
+			orderItem.PriceHistoryId = productDetail.ResolvedPrice.PriceHistoryId
+			orderItem.PriceListId = productDetail.ResolvedPrice.PriceListId
+		}
+
+		orderItems = append(orderItems, orderItem)
 	}
 
+	// order & its items are inserted in one transaction, so there is no order without items
 	timeNow := time.Now()
-	if err := u.transactionRepo.InsertOrder(&entity.Order{
-		Id:        orderId,
-		ShopId:    req.ShopId,
-		UserId:    req.UserId,
-		Amount:    amount,
-		Status:    entity.OrderStatusPending,
-		CreatedAt: timeNow,
-		ExpiredAt: timeNow.Add(time.Duration(entity.OrderExpireTimeInMinute * time.Minute)),
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Transaction.LockPendingOrders(ctx, req.UserId); err != nil {
+			return err
+		}
+		if err := validatePendingOrders(ctx, repos.Transaction, req.UserId); err != nil {
+			return err
+		}
+
+		if err := repos.Transaction.InsertOrder(ctx, &entity.Order{
+			Id:                orderId,
+			ShopId:            req.ShopId,
+			UserId:            req.UserId,
+			ShippingAddressId: req.ShippingAddressId,
+			Amount:            amount,
+			Status:            entity.OrderStatusPending,
+			CreatedAt:         timeNow,
+			ExpiredAt:         timeNow.Add(u.orderExpireTime),
+		}); err != nil {
+			return err
+		}
+		return repos.Transaction.InsertOrderItems(ctx, orderItems)
 	}); err != nil {
 		return "", err
 	}
 
-	if err := u.transactionRepo.InsertOrderItems(orderItems); err != nil {
-		return "", err
-	}
+	u.logger.InfoContext(ctx, "order is created", "orderId", orderId, "shopId", req.ShopId, "userId", req.UserId, "amount", amount)
 
 	return orderId, nil
 }
 
-func (u *transactionUsecase) PayOrder(req *entity.PayOrderRequest) error {
-	if err := u.validatePaymentAmount(req.OrderId, req.Amount); err != nil {
+func (u *transactionUsecase) PayOrder(ctx context.Context, req *entity.PayOrderRequest) error {
+	if err := u.validatePaymentAmount(ctx, req.OrderId, req.UserId, req.Amount); err != nil {
 		return err
 	}
 
-	// insert payment & update order are execute in one transaction
-	tx, err := u.inventoryRepo.GetDb().Begin()
-	if err != nil {
-		return fmt.Errorf("error pay order in initiating transaction: %v", err.Error())
-	}
-
-	defer func() {
-		err = transactionutil.SettleTransaction(tx, err)
-	}()
-
 	newUUID, err := uuid.NewV4()
 	if err != nil {
 		return fmt.Errorf("error pay order in generating uuid: %v", err.Error())
 	}
 
-	if err := u.transactionRepo.InsertPayment(tx, &entity.Payment{
-		Id:      newUUID.String(),
-		OrderId: req.OrderId,
-		UserId:  req.UserId,
-		Amount:  req.Amount,
-		Status:  entity.PaymentStatusPaid,
-	}); err != nil {
-		return err
-	}
+	// insert payment & update order are execute in one transaction
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Transaction.InsertPayment(ctx, &entity.Payment{
+			Id:      newUUID.String(),
+			OrderId: req.OrderId,
+			UserId:  req.UserId,
+			Amount:  req.Amount,
+			Status:  entity.PaymentStatusPaid,
+		}); err != nil {
+			return err
+		}
 
-	if err := u.transactionRepo.UpdateOrder(tx, &entity.UpdateOrderRequest{
-		OrderId: req.OrderId,
-		Status:  entity.OrderStatusSucceeded,
+		return repos.Transaction.UpdateOrder(ctx, &entity.UpdateOrderRequest{
+			OrderId: req.OrderId,
+			Status:  entity.OrderStatusSucceeded,
+		})
 	}); err != nil {
 		return err
 	}
 
-	// post actions execute async (invalidate locks & update stock)
-	go func(orderId, userId string) {
-		orderItems, err := u.transactionRepo.GetOrderItemsByOrderId(orderId)
+	u.logger.InfoContext(ctx, "order is paid", "orderId", req.OrderId, "userId", req.UserId, "amount", req.Amount)
+
+	// post actions execute async after the payment is committed (invalidate locks & update stock), the shutdown waits for them.
+	// The task keeps the request id of ctx, so its logs can be correlated with the payment.
+	orderId, userId := req.OrderId, req.UserId
+	if err := u.lifecycle.Go(ctx, TaskPayOrderPostAction, func(ctx context.Context) {
+		orderItems, err := u.transactionRepo.GetOrderItemsByOrderId(ctx, orderId)
 		if err != nil {
-			log.Printf("error pay order in post action async: error get order item: %v", err.Error())
+			u.logger.ErrorContext(ctx, "error pay order in post action async: error get order items", "orderId", orderId, "error", err)
 			return
 		}
 
 		for _, item := range orderItems {
-			if err := u.redisRepo.InvalidateLockOrderProduct(context.Background(), &entity.LockOrderProductRequest{
+			if err := u.redisRepo.InvalidateLockOrderProduct(ctx, &entity.LockOrderProductRequest{
 				ProductId:   item.ProductId,
 				WarehouseId: item.WarehouseId,
 				UserId:      userId,
 				Quantity:    item.Quantity,
 			}); err != nil {
-				log.Printf("error pay order in post action async: error invalidate lock product id '%s' for order id '%s': %v", item.ProductId, req.OrderId, err.Error())
+				u.logger.ErrorContext(ctx, "error pay order in post action async: error invalidate lock", "orderId", orderId, "productId", item.ProductId, "error", err)
 			}
 
 			// update product total stock
-			if err := u.inventoryRepo.InsertProductWarehouse(&entity.ProductWarehouse{
+			if err := u.inventoryRepo.InsertProductWarehouse(ctx, &entity.ProductWarehouse{
 				ProductId:   item.ProductId,
 				WarehouseId: item.WarehouseId,
 				TotalStock:  -item.Quantity,
 			}); err != nil {
-				log.Printf("error pay order in post action async: error update total stock product id '%s' for order id '%s': %v", item.ProductId, req.OrderId, err.Error())
+				u.logger.ErrorContext(ctx, "error pay order in post action async: error update total stock", "orderId", orderId, "productId", item.ProductId, "error", err)
 			}
 		}
-	}(req.OrderId, req.UserId)
+
+		u.logger.InfoContext(ctx, "post action of paid order is done", "orderId", orderId)
+	}); err != nil {
+		// the payment is committed, so it is not failed. The reservation expires by itself, but the stock of the order must be updated manually
+		u.logger.ErrorContext(ctx, "error pay order: post action is not run, the stock of the order is not updated", "orderId", orderId, "error", err)
+	}
 
 	return nil
 }
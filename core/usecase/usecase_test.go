@@ -1,15 +1,31 @@
 package usecase_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"mfawzanid/warehouse-commerce/core/entity"
 	"mfawzanid/warehouse-commerce/core/mocks"
+	"mfawzanid/warehouse-commerce/core/repository"
 	"mfawzanid/warehouse-commerce/core/usecase"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	keyutil "mfawzanid/warehouse-commerce/utils/key"
+	lifecycleutil "mfawzanid/warehouse-commerce/utils/lifecycle"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type usecaseTest struct {
@@ -17,11 +33,31 @@ type usecaseTest struct {
 	inventoryRepo   *mocks.InventoryRepositoryInterface
 	transactionRepo *mocks.TransactionRepositoryInterface
 	redisRepo       *mocks.RedisRepositoryInterface
+	priceRepo       *mocks.PriceRepositoryInterface
+	catalogRepo     *mocks.CatalogRepositoryInterface
+	notifier        *mocks.NotifierInterface
+	authRepo        *mocks.AuthRepositoryInterface
+	rbacRepo        *mocks.RbacRepositoryInterface
+	membershipRepo  *mocks.MembershipRepositoryInterface
+	apiKeyRepo      *mocks.APIKeyRepositoryInterface
+	privacyRepo     *mocks.PrivacyRepositoryInterface
+	rateLimitRepo   *mocks.RateLimitRepositoryInterface
+	healthRepo      *mocks.HealthRepositoryInterface
+	unitOfWork      *mocks.UnitOfWorkInterface
+	repos           *repository.Repositories // the repository mocks that are given to the work of the unit of work
 
 	authUsecase        usecase.AuthUsecaseInterface
 	userUsecase        usecase.UserUsecaseInterface
 	inventoryUsecase   usecase.InventoryUsecaseInterface
 	transactionUsecase usecase.TransactionUsecaseInterface
+	priceUsecase       usecase.PriceUsecaseInterface
+	catalogUsecase     usecase.CatalogUsecaseInterface
+	rbacUsecase        usecase.RbacUsecaseInterface
+	membershipUsecase  usecase.MembershipUsecaseInterface
+	apiKeyUsecase      usecase.APIKeyUsecaseInterface
+	privacyUsecase     usecase.PrivacyUsecaseInterface
+	rateLimitUsecase   usecase.RateLimitUsecaseInterface
+	healthUsecase      usecase.HealthUsecaseInterface
 }
 
 var ucTest usecaseTest
@@ -31,53 +67,324 @@ func init() {
 	mockInventoryRepo := mocks.InventoryRepositoryInterface{}
 	mockTransactionRepo := mocks.TransactionRepositoryInterface{}
 	mockRedisRepo := mocks.RedisRepositoryInterface{}
-
-	authUsecase := usecase.NewAuthUsecase()
-	userUsecase := usecase.NewUserUsecase(&mockUserRepo, authUsecase)
-	inventoryUsecase := usecase.NewInventoryUsecase(&mockInventoryRepo)
-	transactionUsecase := usecase.NewTransactionUsecase(&mockInventoryRepo, &mockTransactionRepo, &mockRedisRepo)
+	mockPriceRepo := mocks.PriceRepositoryInterface{}
+	mockCatalogRepo := mocks.CatalogRepositoryInterface{}
+	mockNotifier := mocks.NotifierInterface{}
+	mockAuthRepo := mocks.AuthRepositoryInterface{}
+	mockRbacRepo := mocks.RbacRepositoryInterface{}
+	mockMembershipRepo := mocks.MembershipRepositoryInterface{}
+	mockAPIKeyRepo := mocks.APIKeyRepositoryInterface{}
+	mockPrivacyRepo := mocks.PrivacyRepositoryInterface{}
+	mockRateLimitRepo := mocks.RateLimitRepositoryInterface{}
+	mockHealthRepo := mocks.HealthRepositoryInterface{}
+	mockUnitOfWork := mocks.UnitOfWorkInterface{}
+	lifecycle := lifecycleutil.NewManager(logutil.Discard())
+
+	authUsecase := usecase.NewAuthUsecase(&mockAuthRepo, &mockRbacRepo, &mockRedisRepo, &mockUnitOfWork, newTokenConfig(newEd25519Key("key-1"), "key-1"), logutil.Discard())
+	userUsecase := usecase.NewUserUsecase(&mockUserRepo, &mockUnitOfWork, authUsecase, &mockNotifier, logutil.Discard())
+	inventoryUsecase := usecase.NewInventoryUsecase(&mockInventoryRepo, &mockPriceRepo, &mockMembershipRepo, &mockUnitOfWork, logutil.Discard())
+	priceUsecase := usecase.NewPriceUsecase(&mockPriceRepo, &mockMembershipRepo, &mockUnitOfWork, logutil.Discard())
+	transactionUsecase := usecase.NewTransactionUsecase(&mockInventoryRepo, &mockTransactionRepo, &mockRedisRepo, priceUsecase, &mockUserRepo, &mockUnitOfWork, time.Minute, lifecycle, logutil.Discard())
+	catalogUsecase := usecase.NewCatalogUsecase(&mockInventoryRepo, &mockCatalogRepo, &mockPriceRepo, &mockUnitOfWork, lifecycle, logutil.Discard())
+	rbacUsecase := usecase.NewRbacUsecase(&mockRbacRepo, &mockUserRepo, &mockUnitOfWork, logutil.Discard())
+	membershipUsecase := usecase.NewMembershipUsecase(&mockMembershipRepo, &mockUserRepo, &mockUnitOfWork, &mockNotifier, logutil.Discard())
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(&mockAPIKeyRepo, logutil.Discard())
+	privacyUsecase := usecase.NewPrivacyUsecase(&mockPrivacyRepo, &mockUserRepo, &mockUnitOfWork, authUsecase, logutil.Discard())
+	rateLimitUsecase := usecase.NewRateLimitUsecase(&mockRateLimitRepo, logutil.Discard())
+	healthUsecase := usecase.NewHealthUsecase(&mockHealthRepo, 3, 50*time.Millisecond, logutil.Discard())
 
 	ucTest = usecaseTest{
 		userRepo:        &mockUserRepo,
 		inventoryRepo:   &mockInventoryRepo,
 		transactionRepo: &mockTransactionRepo,
 		redisRepo:       &mockRedisRepo,
+		priceRepo:       &mockPriceRepo,
+		catalogRepo:     &mockCatalogRepo,
+		notifier:        &mockNotifier,
+		authRepo:        &mockAuthRepo,
+		rbacRepo:        &mockRbacRepo,
+		membershipRepo:  &mockMembershipRepo,
+		apiKeyRepo:      &mockAPIKeyRepo,
+		privacyRepo:     &mockPrivacyRepo,
+		rateLimitRepo:   &mockRateLimitRepo,
+		healthRepo:      &mockHealthRepo,
+		unitOfWork:      &mockUnitOfWork,
+		repos: &repository.Repositories{
+			User:        &mockUserRepo,
+			Auth:        &mockAuthRepo,
+			Rbac:        &mockRbacRepo,
+			APIKey:      &mockAPIKeyRepo,
+			Inventory:   &mockInventoryRepo,
+			Membership:  &mockMembershipRepo,
+			Transaction: &mockTransactionRepo,
+			Price:       &mockPriceRepo,
+			Catalog:     &mockCatalogRepo,
+			Privacy:     &mockPrivacyRepo,
+		},
 
 		authUsecase:        authUsecase,
 		userUsecase:        userUsecase,
 		inventoryUsecase:   inventoryUsecase,
 		transactionUsecase: transactionUsecase,
+		priceUsecase:       priceUsecase,
+		catalogUsecase:     catalogUsecase,
+		rbacUsecase:        rbacUsecase,
+		membershipUsecase:  membershipUsecase,
+		apiKeyUsecase:      apiKeyUsecase,
+		privacyUsecase:     privacyUsecase,
+		rateLimitUsecase:   rateLimitUsecase,
+		healthUsecase:      healthUsecase,
+	}
+}
+
+// mockUnitOfWork runs the work of the next unit of work with the repository mocks,
+// commit and rollback are tested with the unit of work itself
+func mockUnitOfWork() {
+	ucTest.unitOfWork.On("Do", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+		return fn(ucTest.repos)
+	}).Once()
+}
+
+// requestOtp requests otp through the usecase and returns the stored otp with the code that is sent
+func requestOtp(t *testing.T, identifier, purpose string) (*entity.Otp, string) {
+	var otp *entity.Otp
+	var message string
+
+	ucTest.userRepo.On("CountOtpsSince", mock.Anything, identifier, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
+	if purpose == entity.OtpPurposeLogin {
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(&entity.User{Id: "USR-1", Email: identifier}, nil).Once()
+	} else {
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
 	}
+	ucTest.userRepo.On("InsertOtp", mock.Anything, mock.AnythingOfType("*entity.Otp")).Run(func(args mock.Arguments) {
+		otp = args.Get(1).(*entity.Otp)
+	}).Return(nil).Once()
+	ucTest.notifier.On("Notify", entity.IdentifierTypeEmail, identifier, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		message = args.Get(2).(string)
+	}).Return(nil).Once()
+
+	err := ucTest.userUsecase.RequestOtp(context.Background(), &entity.RequestOtpRequest{
+		IdentifierType: entity.IdentifierTypeEmail,
+		Identifier:     identifier,
+		Purpose:        purpose,
+	})
+	assert.Nil(t, err)
+
+	code := regexp.MustCompile(`code is (\d{6})`).FindStringSubmatch(message)
+	assert.Len(t, code, 2)
+
+	return otp, code[1]
+}
+
+func TestRequestOtp(t *testing.T) {
+	email := "email_1@mail.com"
+
+	t.Run("RequestOtp_purpose not valid_then return error", func(t *testing.T) {
+		err := ucTest.userUsecase.RequestOtp(context.Background(), &entity.RequestOtpRequest{
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Purpose:        "xxx",
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("RequestOtp_max requests is reached_then return too many requests error", func(t *testing.T) {
+		ucTest.userRepo.On("CountOtpsSince", mock.Anything, email, mock.AnythingOfType("time.Time")).Return(3, nil).Once()
+
+		err := ucTest.userUsecase.RequestOtp(context.Background(), &entity.RequestOtpRequest{
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Purpose:        entity.OtpPurposeRegister,
+		})
+
+		assert.Equal(t, errorutil.ErrTooManyRequests, errorutil.GetErrorType(err))
+	})
+	t.Run("RequestOtp_login with unregistered identifier_then return success without sending otp", func(t *testing.T) {
+		ucTest.userRepo.On("CountOtpsSince", mock.Anything, email, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		err := ucTest.userUsecase.RequestOtp(context.Background(), &entity.RequestOtpRequest{
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Purpose:        entity.OtpPurposeLogin,
+		})
+
+		assert.Nil(t, err)
+	})
+	t.Run("RequestOtp_register with new identifier_then store hashed code and send it", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeRegister)
+
+		assert.Equal(t, email, otp.Identifier)
+		assert.Equal(t, entity.OtpPurposeRegister, otp.Purpose)
+		assert.NotContains(t, otp.CodeHash, code)
+		assert.True(t, otp.IsUsable(time.Now()))
+	})
 }
 
 func TestRegisterUser(t *testing.T) {
-	t.Run("RegisterUser_identifier type not valid_then return error", func(t *testing.T) {
-		email := "email_1@mail.com"
+	email := "email_1@mail.com"
+	password := "password_1"
 
-		token, err := ucTest.userUsecase.RegisterUser(&entity.RegisterUserRequest{
+	t.Run("RegisterUser_identifier type not valid_then return error", func(t *testing.T) {
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
 			IdentifierType: "xxx",
 			Identifier:     email,
+			Password:       password,
+			OtpCode:        "123456",
 		})
 
 		assert.NotNil(t, err)
 		assert.Empty(t, token)
 	})
 	t.Run("RegisterUser_identifier value is empty_then return error", func(t *testing.T) {
-		token, err := ucTest.userUsecase.RegisterUser(&entity.RegisterUserRequest{
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
 			IdentifierType: "email",
 			Identifier:     "",
+			Password:       password,
+			OtpCode:        "123456",
 		})
 
 		assert.NotNil(t, err)
 		assert.Empty(t, token)
 	})
-	t.Run("RegisterUser_correct payload_then return success", func(t *testing.T) {
-		email := "email_1@mail.com"
-		ucTest.userRepo.On("InsertUser", mock.Anything).Return(nil).Once()
+	t.Run("RegisterUser_password is too short_then return error", func(t *testing.T) {
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       "short",
+			OtpCode:        "123456",
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("RegisterUser_otp is wrong and identifier is registered_then return unauthorized error without telling it is registered", func(t *testing.T) {
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeRegister).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
+			OtpCode:        "123456",
+		})
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("RegisterUser_identifier is registered_then return unique violation error", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeRegister)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeRegister).Return(otp, nil).Once()
+		ucTest.userRepo.On("IncreaseOtpAttempts", mock.Anything, otp.Id, 5).Return(nil).Once()
+		ucTest.userRepo.On("ConsumeOtp", mock.Anything, otp.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(&entity.User{Id: "USR-1", Email: email}, nil).Once()
+
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
+			OtpCode:        code,
+		})
+
+		assert.Equal(t, errorutil.ErrUniqueViolation, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("RegisterUser_otp is not requested_then return unauthorized error", func(t *testing.T) {
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeRegister).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
+			OtpCode:        "123456",
+		})
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("RegisterUser_otp is expired_then return unauthorized error", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeRegister)
+		otp.ExpiredAt = time.Now().Add(-time.Second)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeRegister).Return(otp, nil).Once()
+
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
+			OtpCode:        code,
+		})
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("RegisterUser_otp code is wrong_then return unauthorized error", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeRegister)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeRegister).Return(otp, nil).Once()
+		ucTest.userRepo.On("IncreaseOtpAttempts", mock.Anything, otp.Id, 5).Return(nil).Once()
+
+		wrongCode := "000000"
+		if code == wrongCode {
+			wrongCode = "111111"
+		}
+
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
+			OtpCode:        wrongCode,
+		})
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("RegisterUser_otp max attempts is reached_then return too many requests error", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeRegister)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeRegister).Return(otp, nil).Once()
+		ucTest.userRepo.On("IncreaseOtpAttempts", mock.Anything, otp.Id, 5).Return(errorutil.NewErrorCode(errorutil.ErrTooManyRequests, errors.New(""))).Once()
+
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
+			OtpCode:        code,
+		})
+
+		assert.Equal(t, errorutil.ErrTooManyRequests, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("RegisterUser_identifier is registered concurrently_then return unique violation error", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeRegister)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeRegister).Return(otp, nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+		ucTest.userRepo.On("IncreaseOtpAttempts", mock.Anything, otp.Id, 5).Return(nil).Once()
+		ucTest.userRepo.On("ConsumeOtp", mock.Anything, otp.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.userRepo.On("InsertUser", mock.Anything, mock.AnythingOfType("*entity.User")).Return(errorutil.NewErrorCode(errorutil.ErrUniqueViolation, errors.New(""))).Once()
+
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
+			OtpCode:        code,
+		})
 
-		token, err := ucTest.userUsecase.RegisterUser(&entity.RegisterUserRequest{
+		assert.Equal(t, errorutil.ErrUniqueViolation, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("RegisterUser_correct payload_then return success", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeRegister)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeRegister).Return(otp, nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+		ucTest.userRepo.On("IncreaseOtpAttempts", mock.Anything, otp.Id, 5).Return(nil).Once()
+		ucTest.userRepo.On("ConsumeOtp", mock.Anything, otp.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.userRepo.On("InsertUser", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+			return user.Email == email && user.EmailVerified && !user.PhoneNumberVerified && bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+		})).Return(nil).Once()
+		mockCreateSession(t)
+
+		token, err := ucTest.userUsecase.RegisterUser(context.Background(), &entity.RegisterUserRequest{
 			IdentifierType: "email",
 			Identifier:     email,
+			Password:       password,
+			OtpCode:        code,
 		})
 
 		assert.Nil(t, err)
@@ -86,296 +393,849 @@ func TestRegisterUser(t *testing.T) {
 }
 
 func TestLogin(t *testing.T) {
-	t.Run("Login_identifier type not valid_then return error", func(t *testing.T) {
-		email := "email_1@mail.com"
+	email := "email_1@mail.com"
+	password := "password_1"
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
 
-		token, err := ucTest.userUsecase.Login(&entity.LoginRequest{
+	t.Run("Login_identifier type not valid_then return error", func(t *testing.T) {
+		token, err := ucTest.userUsecase.Login(context.Background(), &entity.LoginRequest{
 			IdentifierType: "xxx",
 			Identifier:     email,
+			Password:       password,
 		})
 
 		assert.NotNil(t, err)
 		assert.Empty(t, token)
 	})
 	t.Run("Login_identifier value is empty_then return error", func(t *testing.T) {
-		token, err := ucTest.userUsecase.Login(&entity.LoginRequest{
+		token, err := ucTest.userUsecase.Login(context.Background(), &entity.LoginRequest{
 			IdentifierType: "email",
 			Identifier:     "",
+			Password:       password,
 		})
 
 		assert.NotNil(t, err)
 		assert.Empty(t, token)
 	})
-	t.Run("Login_correct payload_then return success", func(t *testing.T) {
-		email := "email_1@mail.com"
-		expectedUser := &entity.User{
-			Email: email,
-		}
+	t.Run("Login_password and otp code are empty_then return error", func(t *testing.T) {
+		token, err := ucTest.userUsecase.Login(context.Background(), &entity.LoginRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+		})
 
-		ucTest.userRepo.On("GetUser", mock.Anything).Return(expectedUser, nil).Once()
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("Login_user is not found_then return unauthorized error", func(t *testing.T) {
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
 
-		token, err := ucTest.userUsecase.Login(&entity.LoginRequest{
+		token, err := ucTest.userUsecase.Login(context.Background(), &entity.LoginRequest{
 			IdentifierType: "email",
 			Identifier:     email,
+			Password:       password,
 		})
 
-		assert.Nil(t, err)
-		assert.NotEmpty(t, token)
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
 	})
-}
+	t.Run("Login_password is wrong_then return unauthorized error", func(t *testing.T) {
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(&entity.User{Id: "USR-1", Email: email, PasswordHash: string(passwordHash)}, nil).Once()
 
-func TestCreateWarehouse(t *testing.T) {
-	t.Run("CreateWarehouse_empty warehouse name_then return success", func(t *testing.T) {
-		name := ""
+		token, err := ucTest.userUsecase.Login(context.Background(), &entity.LoginRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       "wrong_password",
+		})
 
-		id, err := ucTest.inventoryUsecase.CreateWarehouse(&entity.CreateWarehouseRequest{
-			Name: name,
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
+	})
+	t.Run("Login_password is not set_then return unauthorized error", func(t *testing.T) {
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(&entity.User{Id: "USR-1", Email: email}, nil).Once()
+
+		token, err := ucTest.userUsecase.Login(context.Background(), &entity.LoginRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
 		})
 
-		assert.NotNil(t, err)
-		assert.Empty(t, id)
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Empty(t, token)
 	})
-	t.Run("CreateWarehouse_correct payload_then return success", func(t *testing.T) {
-		name := "name"
+	t.Run("Login_correct password_then return success", func(t *testing.T) {
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(&entity.User{Id: "USR-1", Email: email, PasswordHash: string(passwordHash)}, nil).Once()
+		mockCreateSession(t)
 
-		ucTest.inventoryRepo.On("InsertWarehouse", mock.Anything).Return(nil).Once()
+		token, err := ucTest.userUsecase.Login(context.Background(), &entity.LoginRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			Password:       password,
+		})
 
-		id, err := ucTest.inventoryUsecase.CreateWarehouse(&entity.CreateWarehouseRequest{
-			Name: name,
+		assert.Nil(t, err)
+		assert.NotEmpty(t, token)
+	})
+	t.Run("Login_correct otp code_then return success", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeLogin)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeLogin).Return(otp, nil).Once()
+		ucTest.userRepo.On("IncreaseOtpAttempts", mock.Anything, otp.Id, 5).Return(nil).Once()
+		ucTest.userRepo.On("ConsumeOtp", mock.Anything, otp.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(&entity.User{Id: "USR-1", Email: email}, nil).Once()
+		mockCreateSession(t)
+
+		token, err := ucTest.userUsecase.Login(context.Background(), &entity.LoginRequest{
+			IdentifierType: "email",
+			Identifier:     email,
+			OtpCode:        code,
 		})
 
 		assert.Nil(t, err)
-		assert.NotEmpty(t, id)
+		assert.NotEmpty(t, token)
 	})
 }
 
-func TestUpdateWarehouseStatus(t *testing.T) {
-	t.Run("UpdateWarehouseStatus_correct payload_then return success", func(t *testing.T) {
-		id := "id"
+// mockCreateSession mocks a session creation, it returns the session and the refresh token that are inserted
+func mockCreateSession(t *testing.T) (*entity.Session, *entity.RefreshToken) {
+	session := &entity.Session{}
+	refreshToken := &entity.RefreshToken{}
+
+	mockUnitOfWork()
+	ucTest.authRepo.On("InsertSession", mock.Anything, mock.AnythingOfType("*entity.Session")).Run(func(args mock.Arguments) {
+		*session = *args.Get(1).(*entity.Session)
+	}).Return(nil).Once()
+	ucTest.authRepo.On("InsertRefreshToken", mock.Anything, mock.AnythingOfType("*entity.RefreshToken")).Run(func(args mock.Arguments) {
+		*refreshToken = *args.Get(1).(*entity.RefreshToken)
+	}).Return(nil).Once()
+	mockGetUserRoles()
+
+	return session, refreshToken
+}
+
+// mockGetUserRoles mocks the roles that are put in the access token
+func mockGetUserRoles() {
+	ucTest.rbacRepo.On("GetUserRoles", mock.Anything, mock.AnythingOfType("string")).Return(&entity.UserRoles{
+		Roles:       []string{entity.RoleCustomer},
+		Permissions: []string{entity.PermissionOrderCreate, entity.PermissionOrderPay},
+	}, nil).Once()
+}
 
-		ucTest.inventoryRepo.On("UpdateWarehouseStatus", mock.Anything).Return(nil).Once()
+func TestUpdateProfile(t *testing.T) {
+	t.Run("UpdateProfile_name is too long_then return bad request error", func(t *testing.T) {
+		user, err := ucTest.userUsecase.UpdateProfile(context.Background(), &entity.UpdateProfileRequest{UserId: "USR-1", Name: strings.Repeat("a", 101)})
 
-		err := ucTest.inventoryUsecase.UpdateWarehouseStatus(&entity.UpdateWarehouseStatusRequest{
-			Id: id,
-		})
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Nil(t, user)
+	})
+	t.Run("UpdateProfile_correct payload_then return updated profile", func(t *testing.T) {
+		ucTest.userRepo.On("UpdateUserName", mock.Anything, "USR-1", "John", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-1"}).Return(&entity.User{Id: "USR-1", Name: "John"}, nil).Once()
+
+		user, err := ucTest.userUsecase.UpdateProfile(context.Background(), &entity.UpdateProfileRequest{UserId: "USR-1", Name: "John"})
 
 		assert.Nil(t, err)
+		assert.Equal(t, "John", user.Name)
 	})
 }
 
-func TestGetWarehouses(t *testing.T) {
-	t.Run("GetWarehouses_correct payload_then return success", func(t *testing.T) {
-		ids := []string{"id_1"}
-		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything).Return(&entity.GetWarehousesResponse{
-			Warehouses: []*entity.Warehouse{},
-			Pagination: &entity.Pagination{},
-		}, nil).Once()
+func TestChangeIdentifier(t *testing.T) {
+	email := "new@mail.com"
 
-		resp, err := ucTest.inventoryUsecase.GetWarehouses(&entity.GetWarehousesRequest{
-			Ids: ids,
+	t.Run("ChangeIdentifier_otp code is empty_then return bad request error", func(t *testing.T) {
+		user, err := ucTest.userUsecase.ChangeIdentifier(context.Background(), &entity.ChangeIdentifierRequest{
+			UserId:         "USR-1",
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Nil(t, user)
+	})
+	t.Run("ChangeIdentifier_identifier is used by other user_then return unique violation error", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeVerify)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeVerify).Return(otp, nil).Once()
+		ucTest.userRepo.On("IncreaseOtpAttempts", mock.Anything, otp.Id, 5).Return(nil).Once()
+		ucTest.userRepo.On("ConsumeOtp", mock.Anything, otp.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.userRepo.On("UpdateUserIdentifier", mock.Anything, "USR-1", entity.IdentifierTypeEmail, email, mock.AnythingOfType("time.Time")).
+			Return(errorutil.NewErrorCode(errorutil.ErrUniqueViolation, errors.New(""))).Once()
+
+		user, err := ucTest.userUsecase.ChangeIdentifier(context.Background(), &entity.ChangeIdentifierRequest{
+			UserId:         "USR-1",
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			OtpCode:        code,
+		})
+
+		assert.Equal(t, errorutil.ErrUniqueViolation, errorutil.GetErrorType(err))
+		assert.Nil(t, user)
+	})
+	t.Run("ChangeIdentifier_otp is verified_then return profile with verified identifier", func(t *testing.T) {
+		otp, code := requestOtp(t, email, entity.OtpPurposeVerify)
+		ucTest.userRepo.On("GetLatestOtp", mock.Anything, email, entity.OtpPurposeVerify).Return(otp, nil).Once()
+		ucTest.userRepo.On("IncreaseOtpAttempts", mock.Anything, otp.Id, 5).Return(nil).Once()
+		ucTest.userRepo.On("ConsumeOtp", mock.Anything, otp.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.userRepo.On("UpdateUserIdentifier", mock.Anything, "USR-1", entity.IdentifierTypeEmail, email, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-1"}).Return(&entity.User{Id: "USR-1", Email: email, EmailVerified: true}, nil).Once()
+
+		user, err := ucTest.userUsecase.ChangeIdentifier(context.Background(), &entity.ChangeIdentifierRequest{
+			UserId:         "USR-1",
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			OtpCode:        code,
 		})
 
 		assert.Nil(t, err)
-		assert.NotEmpty(t, resp)
+		assert.True(t, user.EmailVerified)
 	})
 }
 
-func TestCreateShop(t *testing.T) {
-	t.Run("CreateShop_empty name_then return error", func(t *testing.T) {
-		name := ""
+func TestCreateAddress(t *testing.T) {
+	req := func() *entity.UpsertAddressRequest {
+		return &entity.UpsertAddressRequest{
+			UserId:        "USR-1",
+			RecipientName: "John",
+			PhoneNumber:   "08123456789",
+			Street:        "Jl. Sudirman 1",
+			City:          "Jakarta",
+			PostalCode:    "10220",
+			Country:       "ID",
+		}
+	}
 
-		shopId, err := ucTest.inventoryUsecase.CreateShop(&entity.CreateShopRequest{
-			Name: name,
-		})
+	t.Run("CreateAddress_mandatory fields are empty_then return bad request error with all the fields", func(t *testing.T) {
+		invalidReq := req()
+		invalidReq.Street = ""
+		invalidReq.City = ""
 
-		assert.NotNil(t, err)
-		assert.Empty(t, shopId)
+		address, err := ucTest.userUsecase.CreateAddress(context.Background(), invalidReq)
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Equal(t, []*errorutil.FieldError{
+			{Field: "street", Message: "street is mandatory"},
+			{Field: "city", Message: "city is mandatory"},
+		}, errorutil.GetFields(err))
+		assert.Nil(t, address)
 	})
-	t.Run("CreateShop_correct payload_then return success", func(t *testing.T) {
-		name := "shop_1"
+	t.Run("CreateAddress_first address_then set it as default", func(t *testing.T) {
+		ucTest.userRepo.On("GetAddresses", mock.Anything, "USR-1").Return([]*entity.Address{}, nil).Once()
+		mockUnitOfWork()
+		ucTest.userRepo.On("UnsetDefaultAddress", mock.Anything, "USR-1").Return(nil).Once()
+		ucTest.userRepo.On("InsertAddress", mock.Anything, mock.MatchedBy(func(address *entity.Address) bool {
+			return address.IsDefault && address.UserId == "USR-1" && strings.HasPrefix(address.Id, "ADR")
+		})).Return(nil).Once()
 
-		ucTest.inventoryRepo.On("InsertShop", mock.Anything).Return(nil).Once()
+		address, err := ucTest.userUsecase.CreateAddress(context.Background(), req())
 
-		shopId, err := ucTest.inventoryUsecase.CreateShop(&entity.CreateShopRequest{
-			Name: name,
-		})
+		assert.Nil(t, err)
+		assert.True(t, address.IsDefault)
+	})
+	t.Run("CreateAddress_user has default address_then keep it", func(t *testing.T) {
+		ucTest.userRepo.On("GetAddresses", mock.Anything, "USR-1").Return([]*entity.Address{{Id: "ADR-1", IsDefault: true}}, nil).Once()
+		mockUnitOfWork()
+		ucTest.userRepo.On("InsertAddress", mock.Anything, mock.MatchedBy(func(address *entity.Address) bool {
+			return !address.IsDefault
+		})).Return(nil).Once()
+
+		address, err := ucTest.userUsecase.CreateAddress(context.Background(), req())
 
 		assert.Nil(t, err)
-		assert.NotEmpty(t, shopId)
+		assert.False(t, address.IsDefault)
 	})
 }
 
-func TestGetShops(t *testing.T) {
-	t.Run("GetShops_correct payload_then return success", func(t *testing.T) {
-		ids := []string{"id_1"}
+func TestUpdateAddress(t *testing.T) {
+	t.Run("UpdateAddress_address of other user_then return not found error", func(t *testing.T) {
+		ucTest.userRepo.On("GetAddress", mock.Anything, "USR-1", "ADR-2").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		address, err := ucTest.userUsecase.UpdateAddress(context.Background(), &entity.UpsertAddressRequest{
+			Id:            "ADR-2",
+			UserId:        "USR-1",
+			RecipientName: "John",
+			PhoneNumber:   "08123456789",
+			Street:        "Jl. Sudirman 1",
+			City:          "Jakarta",
+			PostalCode:    "10220",
+			Country:       "ID",
+		})
 
-		ucTest.inventoryRepo.On("GetShops", mock.Anything).Return(&entity.GetShopsResponse{
-			Shops:      []*entity.Shop{},
-			Pagination: &entity.Pagination{},
-		}, nil).Once()
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+		assert.Nil(t, address)
+	})
+}
 
-		resp, err := ucTest.inventoryUsecase.GetShops(&entity.GetShopsRequest{
-			Ids: ids,
-		})
+func TestDeleteAddress(t *testing.T) {
+	t.Run("DeleteAddress_address id is empty_then return bad request error", func(t *testing.T) {
+		err := ucTest.userUsecase.DeleteAddress(context.Background(), "USR-1", "")
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("DeleteAddress_address exists_then delete it", func(t *testing.T) {
+		ucTest.userRepo.On("DeleteAddress", mock.Anything, "USR-1", "ADR-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		err := ucTest.userUsecase.DeleteAddress(context.Background(), "USR-1", "ADR-1")
 
 		assert.Nil(t, err)
-		assert.NotEmpty(t, resp)
 	})
 }
 
-func TestUpsertShopToWarehouses(t *testing.T) {
-	t.Run("UpsertShopToWarehouses_shop id is not found_then return error", func(t *testing.T) {
-		ucTest.inventoryRepo.On("GetShops", mock.Anything).Return(nil, errors.New("")).Once()
+func TestCreateSession(t *testing.T) {
+	t.Run("CreateSession_insert refresh token error_then return error", func(t *testing.T) {
+		mockUnitOfWork()
+		ucTest.authRepo.On("InsertSession", mock.Anything, mock.AnythingOfType("*entity.Session")).Return(nil).Once()
+		ucTest.authRepo.On("InsertRefreshToken", mock.Anything, mock.AnythingOfType("*entity.RefreshToken")).Return(errors.New("")).Once()
 
-		err := ucTest.inventoryUsecase.UpsertShopToWarehouses(&entity.UpsertShopToWarehousesRequest{})
+		authToken, err := ucTest.authUsecase.CreateSession(context.Background(), "USR-1")
 
 		assert.NotNil(t, err)
+		assert.Nil(t, authToken)
 	})
-	t.Run("UpsertShopToWarehouses_some warehouse id is not found_then return error", func(t *testing.T) {
-		shops := []*entity.Shop{{Id: "id"}}
-
-		ucTest.inventoryRepo.On("GetShops", mock.Anything).Return(&entity.GetShopsResponse{
-			Shops: shops,
-		}, nil).Once()
+	t.Run("CreateSession_success_then return access token and hashed refresh token is stored", func(t *testing.T) {
+		session, refreshToken := mockCreateSession(t)
 
-		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything).Return(nil, errors.New("")).Once()
+		authToken, err := ucTest.authUsecase.CreateSession(context.Background(), "USR-1")
 
-		err := ucTest.inventoryUsecase.UpsertShopToWarehouses(&entity.UpsertShopToWarehousesRequest{})
-
-		assert.NotNil(t, err)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, authToken.AccessToken)
+		assert.NotEmpty(t, authToken.RefreshToken)
+		assert.Equal(t, 900, authToken.ExpiredIn)
+		assert.Equal(t, "USR-1", session.UserId)
+		assert.Equal(t, session.Id, refreshToken.SessionId)
+		assert.NotEqual(t, authToken.RefreshToken, refreshToken.TokenHash)
 	})
+}
 
-	t.Run("UpsertShopToWarehouses_correct payload_then successfully updated", func(t *testing.T) {
-		shops := []*entity.Shop{{Id: "id"}}
-		ucTest.inventoryRepo.On("GetShops", mock.Anything).Return(&entity.GetShopsResponse{
-			Shops: shops,
-		}, nil).Once()
+func newEd25519Key(id string) *keyutil.SigningKey {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	return &keyutil.SigningKey{Id: id, Algorithm: keyutil.AlgorithmEdDSA, PrivateKey: privateKey, PublicKey: publicKey}
+}
 
-		warehouses := []*entity.Warehouse{{Id: "id"}}
-		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything).Return(&entity.GetWarehousesResponse{
-			Warehouses: warehouses,
-		}, nil).Once()
+func newTokenConfig(key *keyutil.SigningKey, activeKeyId string, otherKeys ...*keyutil.SigningKey) *entity.TokenConfig {
+	keySet, err := keyutil.NewKeySet(activeKeyId, append([]*keyutil.SigningKey{key}, otherKeys...))
+	if err != nil {
+		panic(err)
+	}
+	return &entity.TokenConfig{
+		Issuer:          "issuer",
+		Audience:        "audience",
+		KeySet:          keySet,
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 30 * 24 * time.Hour,
+	}
+}
 
-		ucTest.inventoryRepo.On("InsertShopWarehouses", mock.Anything).Return(nil).Once()
+func TestVerifyToken(t *testing.T) {
+	session, _ := mockCreateSession(t)
+	authToken, err := ucTest.authUsecase.CreateSession(context.Background(), "USR-1")
+	assert.Nil(t, err)
 
-		err := ucTest.inventoryUsecase.UpsertShopToWarehouses(&entity.UpsertShopToWarehousesRequest{})
+	t.Run("VerifyToken_token is invalid_then return unauthorized error", func(t *testing.T) {
+		claims, err := ucTest.authUsecase.VerifyToken(context.Background(), "xxx")
 
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, claims)
+	})
+	t.Run("VerifyToken_token is signed by unknown key_then return unauthorized error", func(t *testing.T) {
+		otherAuthUsecase := usecase.NewAuthUsecase(ucTest.authRepo, ucTest.rbacRepo, ucTest.redisRepo, ucTest.unitOfWork, newTokenConfig(newEd25519Key("key-2"), "key-2"), logutil.Discard())
+		mockCreateSession(t)
+		otherAuthToken, err := otherAuthUsecase.CreateSession(context.Background(), "USR-1")
 		assert.Nil(t, err)
+
+		claims, err := ucTest.authUsecase.VerifyToken(context.Background(), otherAuthToken.AccessToken)
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, claims)
 	})
-}
+	t.Run("VerifyToken_algorithm is not the algorithm of the key_then return unauthorized error", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"userId": "USR-1", "sid": session.Id, "iss": "issuer", "aud": "audience", "exp": time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = "key-1"
+		tokenString, err := token.SignedString([]byte("secret"))
+		assert.Nil(t, err)
 
-func TestCreateProduct(t *testing.T) {
-	t.Run("CreateProduct_bad request_then return error", func(t *testing.T) {
-		req := &entity.CreateProductRequest{}
+		claims, err := ucTest.authUsecase.VerifyToken(context.Background(), tokenString)
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, claims)
+	})
+	t.Run("VerifyToken_token is for other audience_then return unauthorized error", func(t *testing.T) {
+		tokenConfig := newTokenConfig(newEd25519Key("key-3"), "key-3")
+		tokenConfig.Audience = "other-audience"
+		otherAuthUsecase := usecase.NewAuthUsecase(ucTest.authRepo, ucTest.rbacRepo, ucTest.redisRepo, ucTest.unitOfWork, tokenConfig, logutil.Discard())
+		mockCreateSession(t)
+		otherAuthToken, err := otherAuthUsecase.CreateSession(context.Background(), "USR-1")
+		assert.Nil(t, err)
 
-		_, err := ucTest.inventoryUsecase.CreateProduct(req)
+		tokenConfig.Audience = "audience"
+		claims, err := otherAuthUsecase.VerifyToken(context.Background(), otherAuthToken.AccessToken)
 
-		assert.NotNil(t, err)
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, claims)
 	})
-	t.Run("CreateProduct_get warehouse is error_then return error", func(t *testing.T) {
-		warehouseId := "warehouse_id"
-		req := &entity.CreateProductRequest{
-			Name:        "name",
-			Price:       1000,
-			TotalStock:  10,
-			WarehouseId: warehouseId,
-		}
+	t.Run("VerifyToken_token is signed by rotated out key_then return claims", func(t *testing.T) {
+		oldKey := newEd25519Key("key-old")
+		oldAuthUsecase := usecase.NewAuthUsecase(ucTest.authRepo, ucTest.rbacRepo, ucTest.redisRepo, ucTest.unitOfWork, newTokenConfig(oldKey, "key-old"), logutil.Discard())
+		oldSession, _ := mockCreateSession(t)
+		oldAuthToken, err := oldAuthUsecase.CreateSession(context.Background(), "USR-1")
+		assert.Nil(t, err)
 
-		warehouses := []*entity.Warehouse{{Id: warehouseId}}
-		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything).Return(&entity.GetWarehousesResponse{
-			Warehouses: warehouses,
-		}, errors.New("")).Once()
+		// new key signs, old key only verifies the tokens that are signed before the rotation
+		verifyOnlyKey := &keyutil.SigningKey{Id: oldKey.Id, Algorithm: oldKey.Algorithm, PublicKey: oldKey.PublicKey}
+		rotatedAuthUsecase := usecase.NewAuthUsecase(ucTest.authRepo, ucTest.rbacRepo, ucTest.redisRepo, ucTest.unitOfWork, newTokenConfig(newEd25519Key("key-new"), "key-new", verifyOnlyKey), logutil.Discard())
+		ucTest.redisRepo.On("IsSessionRevoked", mock.Anything, oldSession.Id).Return(false, nil).Once()
 
-		productId, err := ucTest.inventoryUsecase.CreateProduct(req)
+		claims, err := rotatedAuthUsecase.VerifyToken(context.Background(), oldAuthToken.AccessToken)
 
-		assert.NotNil(t, err)
-		assert.Empty(t, productId)
+		assert.Nil(t, err)
+		assert.Equal(t, "USR-1", claims.UserId)
 	})
-	t.Run("CreateProduct_insert product is error_then return error", func(t *testing.T) {
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
+	t.Run("VerifyToken_session is revoked_then return unauthorized error", func(t *testing.T) {
+		ucTest.redisRepo.On("IsSessionRevoked", mock.Anything, session.Id).Return(true, nil).Once()
 
-		warehouseId := "warehouse_id"
-		req := &entity.CreateProductRequest{
-			Name:        "name",
-			Price:       1000,
-			TotalStock:  10,
-			WarehouseId: warehouseId,
-		}
+		claims, err := ucTest.authUsecase.VerifyToken(context.Background(), authToken.AccessToken)
 
-		warehouses := []*entity.Warehouse{{Id: warehouseId}}
-		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything).Return(&entity.GetWarehousesResponse{
-			Warehouses: warehouses,
-		}, nil).Once()
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, claims)
+	})
+	t.Run("VerifyToken_revocation list error and session is revoked in db_then return unauthorized error", func(t *testing.T) {
+		revokedAt := time.Now()
+		ucTest.redisRepo.On("IsSessionRevoked", mock.Anything, session.Id).Return(false, errors.New("")).Once()
+		ucTest.authRepo.On("GetSessionById", mock.Anything, session.Id).Return(&entity.Session{Id: session.Id, RevokedAt: &revokedAt}, nil).Once()
 
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		claims, err := ucTest.authUsecase.VerifyToken(context.Background(), authToken.AccessToken)
 
-		ucTest.inventoryRepo.On("InsertProduct", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(errors.New("")).Once()
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, claims)
+	})
+	t.Run("VerifyToken_session is active_then return claims", func(t *testing.T) {
+		ucTest.redisRepo.On("IsSessionRevoked", mock.Anything, session.Id).Return(false, nil).Once()
 
-		productId, err := ucTest.inventoryUsecase.CreateProduct(req)
+		claims, err := ucTest.authUsecase.VerifyToken(context.Background(), authToken.AccessToken)
 
-		assert.NotNil(t, err)
-		assert.Empty(t, productId)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.TokenClaims{
+			UserId:      "USR-1",
+			SessionId:   session.Id,
+			Roles:       []string{entity.RoleCustomer},
+			Permissions: []string{entity.PermissionOrderCreate, entity.PermissionOrderPay},
+		}, claims)
 	})
-	t.Run("CreateProduct_insert product warehouse is error_then return error", func(t *testing.T) {
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
+}
+
+func TestGetJSONWebKeys(t *testing.T) {
+	t.Run("GetJSONWebKeys_asymmetric and symmetric keys_then only return public keys", func(t *testing.T) {
+		key := newEd25519Key("key-1")
+		authUsecase := usecase.NewAuthUsecase(ucTest.authRepo, ucTest.rbacRepo, ucTest.redisRepo, ucTest.unitOfWork, newTokenConfig(key, "key-1", keyutil.NewHMACKey("secret", []byte("secret"))), logutil.Discard())
+
+		jwks := authUsecase.GetJSONWebKeys()
+
+		assert.Equal(t, []keyutil.JSONWebKey{{
+			Kty: "OKP",
+			Kid: "key-1",
+			Alg: keyutil.AlgorithmEdDSA,
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key.PublicKey.(ed25519.PublicKey)),
+		}}, jwks)
+	})
+}
+
+func TestRefreshSession(t *testing.T) {
+	session := &entity.Session{Id: "SES-1", UserId: "USR-1"}
+
+	t.Run("RefreshSession_refresh token is not found_then return unauthorized error", func(t *testing.T) {
+		ucTest.authRepo.On("GetRefreshTokenByHash", mock.Anything, mock.AnythingOfType("string")).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		authToken, err := ucTest.authUsecase.RefreshSession(context.Background(), &entity.RefreshSessionRequest{RefreshToken: "xxx"})
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, authToken)
+	})
+	t.Run("RefreshSession_refresh token is expired_then return unauthorized error", func(t *testing.T) {
+		ucTest.authRepo.On("GetRefreshTokenByHash", mock.Anything, mock.AnythingOfType("string")).Return(&entity.RefreshToken{
+			Id: "RFT-1", SessionId: session.Id, ExpiredAt: time.Now().Add(-time.Second),
+		}, nil).Once()
+		ucTest.authRepo.On("GetSessionById", mock.Anything, session.Id).Return(session, nil).Once()
+
+		authToken, err := ucTest.authUsecase.RefreshSession(context.Background(), &entity.RefreshSessionRequest{RefreshToken: "xxx"})
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, authToken)
+	})
+	t.Run("RefreshSession_refresh token is reused_then revoke the session", func(t *testing.T) {
+		usedAt := time.Now().Add(-time.Minute)
+		ucTest.authRepo.On("GetRefreshTokenByHash", mock.Anything, mock.AnythingOfType("string")).Return(&entity.RefreshToken{
+			Id: "RFT-1", SessionId: session.Id, ExpiredAt: time.Now().Add(time.Hour), UsedAt: &usedAt,
+		}, nil).Once()
+		ucTest.authRepo.On("GetSessionById", mock.Anything, session.Id).Return(session, nil).Once()
+		ucTest.authRepo.On("RevokeSession", mock.Anything, session.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, session.Id, 15*time.Minute).Return(nil).Once()
+
+		authToken, err := ucTest.authUsecase.RefreshSession(context.Background(), &entity.RefreshSessionRequest{RefreshToken: "xxx"})
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, authToken)
+	})
+	t.Run("RefreshSession_refresh token is used concurrently_then revoke the session", func(t *testing.T) {
+		ucTest.authRepo.On("GetRefreshTokenByHash", mock.Anything, mock.AnythingOfType("string")).Return(&entity.RefreshToken{
+			Id: "RFT-1", SessionId: session.Id, ExpiredAt: time.Now().Add(time.Hour),
+		}, nil).Once()
+		ucTest.authRepo.On("GetSessionById", mock.Anything, session.Id).Return(session, nil).Once()
+		mockUnitOfWork()
+		ucTest.authRepo.On("UseRefreshToken", mock.Anything, "RFT-1", mock.AnythingOfType("time.Time")).Return(errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New(""))).Once()
+		ucTest.authRepo.On("RevokeSession", mock.Anything, session.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		// the revocation list is down shortly, the write is retried
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, session.Id, 15*time.Minute).Return(errors.New("")).Once()
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, session.Id, 15*time.Minute).Return(nil).Once()
+
+		authToken, err := ucTest.authUsecase.RefreshSession(context.Background(), &entity.RefreshSessionRequest{RefreshToken: "xxx"})
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, authToken)
+	})
+	t.Run("RefreshSession_valid refresh token_then rotate the refresh token", func(t *testing.T) {
+		var nextRefreshToken *entity.RefreshToken
+		ucTest.authRepo.On("GetRefreshTokenByHash", mock.Anything, mock.AnythingOfType("string")).Return(&entity.RefreshToken{
+			Id: "RFT-1", SessionId: session.Id, ExpiredAt: time.Now().Add(time.Hour),
+		}, nil).Once()
+		ucTest.authRepo.On("GetSessionById", mock.Anything, session.Id).Return(session, nil).Once()
+		mockUnitOfWork()
+		ucTest.authRepo.On("UseRefreshToken", mock.Anything, "RFT-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.authRepo.On("InsertRefreshToken", mock.Anything, mock.AnythingOfType("*entity.RefreshToken")).Run(func(args mock.Arguments) {
+			nextRefreshToken = args.Get(1).(*entity.RefreshToken)
+		}).Return(nil).Once()
+		mockGetUserRoles()
+
+		authToken, err := ucTest.authUsecase.RefreshSession(context.Background(), &entity.RefreshSessionRequest{RefreshToken: "xxx"})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, authToken.AccessToken)
+		assert.NotEqual(t, "xxx", authToken.RefreshToken)
+		assert.Equal(t, session.Id, nextRefreshToken.SessionId)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	t.Run("RevokeSession_revocation list write fails_then return unavailable error and the token is rejected by db", func(t *testing.T) {
+		session, _ := mockCreateSession(t)
+		authToken, err := ucTest.authUsecase.CreateSession(context.Background(), "USR-1")
+		assert.Nil(t, err)
+
+		revokedAt := time.Now()
+		ucTest.authRepo.On("RevokeSession", mock.Anything, session.Id, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, session.Id, 15*time.Minute).Return(errors.New("connection refused")).Times(3)
+
+		err = ucTest.authUsecase.RevokeSession(context.Background(), session.Id)
+
+		assert.Equal(t, errorutil.ErrUnavailable, errorutil.GetErrorType(err))
+
+		// the list is still down, so the token check falls back to the revoked session in db
+		ucTest.redisRepo.On("IsSessionRevoked", mock.Anything, session.Id).Return(false, errors.New("connection refused")).Once()
+		ucTest.authRepo.On("GetSessionById", mock.Anything, session.Id).Return(&entity.Session{Id: session.Id, RevokedAt: &revokedAt}, nil).Once()
+
+		claims, err := ucTest.authUsecase.VerifyToken(context.Background(), authToken.AccessToken)
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, claims)
+	})
+	t.Run("RevokeSession_revocation list write fails once_then retry and revoke the session", func(t *testing.T) {
+		ucTest.authRepo.On("RevokeSession", mock.Anything, "SES-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, "SES-1", 15*time.Minute).Return(errors.New("")).Once()
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, "SES-1", 15*time.Minute).Return(nil).Once()
+
+		err := ucTest.authUsecase.RevokeSession(context.Background(), "SES-1")
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestCreateWarehouse(t *testing.T) {
+	t.Run("CreateWarehouse_empty warehouse name_then return success", func(t *testing.T) {
+		name := ""
+
+		id, err := ucTest.inventoryUsecase.CreateWarehouse(context.Background(), &entity.CreateWarehouseRequest{
+			Name: name,
+		})
+
+		assert.NotNil(t, err)
+		assert.Empty(t, id)
+	})
+	t.Run("CreateWarehouse_correct payload_then return success", func(t *testing.T) {
+		name := "name"
+
+		ucTest.inventoryRepo.On("InsertWarehouse", mock.Anything, mock.Anything).Return(nil).Once()
+
+		id, err := ucTest.inventoryUsecase.CreateWarehouse(context.Background(), &entity.CreateWarehouseRequest{
+			Name: name,
+		})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, id)
+	})
+}
+
+func TestUpdateWarehouseStatus(t *testing.T) {
+	t.Run("UpdateWarehouseStatus_correct payload_then return success", func(t *testing.T) {
+		id := "id"
+
+		ucTest.inventoryRepo.On("UpdateWarehouseStatus", mock.Anything, mock.Anything).Return(nil).Once()
+
+		err := ucTest.inventoryUsecase.UpdateWarehouseStatus(context.Background(), &entity.UpdateWarehouseStatusRequest{
+			Id: id,
+		})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestGetWarehouses(t *testing.T) {
+	t.Run("GetWarehouses_correct payload_then return success", func(t *testing.T) {
+		ids := []string{"id_1"}
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: []*entity.Warehouse{},
+			Pagination: &entity.Pagination{},
+		}, nil).Once()
+
+		resp, err := ucTest.inventoryUsecase.GetWarehouses(context.Background(), &entity.GetWarehousesRequest{
+			Ids: ids,
+		})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, resp)
+	})
+}
+
+func TestCreateShop(t *testing.T) {
+	t.Run("CreateShop_empty name_then return error", func(t *testing.T) {
+		name := ""
+
+		shopId, err := ucTest.inventoryUsecase.CreateShop(context.Background(), &entity.CreateShopRequest{
+			Name: name,
+		})
+
+		assert.NotNil(t, err)
+		assert.Empty(t, shopId)
+	})
+	t.Run("CreateShop_correct payload_then return success", func(t *testing.T) {
+		name := "shop_1"
+
+		ucTest.inventoryRepo.On("InsertShop", mock.Anything, mock.Anything).Return(nil).Once()
+
+		shopId, err := ucTest.inventoryUsecase.CreateShop(context.Background(), &entity.CreateShopRequest{
+			Name: name,
+		})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, shopId)
+	})
+}
+
+func TestGetShops(t *testing.T) {
+	t.Run("GetShops_correct payload_then return success", func(t *testing.T) {
+		ids := []string{"id_1"}
+
+		ucTest.inventoryRepo.On("GetShops", mock.Anything, mock.Anything).Return(&entity.GetShopsResponse{
+			Shops:      []*entity.Shop{},
+			Pagination: &entity.Pagination{},
+		}, nil).Once()
+
+		resp, err := ucTest.inventoryUsecase.GetShops(context.Background(), &entity.GetShopsRequest{
+			Ids: ids,
+		})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, resp)
+	})
+}
 
+func TestUpsertShopToWarehouses(t *testing.T) {
+	admin := entity.ShopActor{UserId: "USR-1", Permissions: []string{entity.PermissionShopManageAny}}
+	manager := entity.ShopActor{UserId: "USR-2"}
+
+	t.Run("UpsertShopToWarehouses_user is not a member_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "id", "USR-3").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		err := ucTest.inventoryUsecase.UpsertShopToWarehouses(context.Background(), &entity.UpsertShopToWarehousesRequest{ShopId: "id", Actor: entity.ShopActor{UserId: "USR-3"}})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+	})
+	t.Run("UpsertShopToWarehouses_user is a staff_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "id", "USR-3").Return(&entity.ShopMember{ShopId: "id", UserId: "USR-3", Role: entity.ShopMemberRoleStaff}, nil).Once()
+
+		err := ucTest.inventoryUsecase.UpsertShopToWarehouses(context.Background(), &entity.UpsertShopToWarehousesRequest{ShopId: "id", Actor: entity.ShopActor{UserId: "USR-3"}})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+	})
+	t.Run("UpsertShopToWarehouses_shop id is not found_then return error", func(t *testing.T) {
+		ucTest.inventoryRepo.On("GetShops", mock.Anything, mock.Anything).Return(nil, errors.New("")).Once()
+
+		err := ucTest.inventoryUsecase.UpsertShopToWarehouses(context.Background(), &entity.UpsertShopToWarehousesRequest{ShopId: "id", Actor: admin})
+
+		assert.NotNil(t, err)
+	})
+	t.Run("UpsertShopToWarehouses_some warehouse id is not found_then return error", func(t *testing.T) {
+		shops := []*entity.Shop{{Id: "id"}}
+
+		ucTest.inventoryRepo.On("GetShops", mock.Anything, mock.Anything).Return(&entity.GetShopsResponse{
+			Shops: shops,
+		}, nil).Once()
+
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(nil, errors.New("")).Once()
+
+		err := ucTest.inventoryUsecase.UpsertShopToWarehouses(context.Background(), &entity.UpsertShopToWarehousesRequest{ShopId: "id", Actor: admin})
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("UpsertShopToWarehouses_correct payload_then successfully updated", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "id", "USR-2").Return(&entity.ShopMember{ShopId: "id", UserId: "USR-2", Role: entity.ShopMemberRoleManager}, nil).Once()
+
+		shops := []*entity.Shop{{Id: "id"}}
+		ucTest.inventoryRepo.On("GetShops", mock.Anything, mock.Anything).Return(&entity.GetShopsResponse{
+			Shops: shops,
+		}, nil).Once()
+
+		warehouses := []*entity.Warehouse{{Id: "id"}}
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: warehouses,
+		}, nil).Once()
+
+		ucTest.inventoryRepo.On("InsertShopWarehouses", mock.Anything, mock.Anything).Return(nil).Once()
+
+		err := ucTest.inventoryUsecase.UpsertShopToWarehouses(context.Background(), &entity.UpsertShopToWarehousesRequest{ShopId: "id", Actor: manager})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestCreateProduct(t *testing.T) {
+	t.Run("CreateProduct_bad request_then return error", func(t *testing.T) {
+		req := &entity.CreateProductRequest{}
+
+		_, err := ucTest.inventoryUsecase.CreateProduct(context.Background(), req)
+
+		assert.NotNil(t, err)
+	})
+	t.Run("CreateProduct_get warehouse is error_then return error", func(t *testing.T) {
+		warehouseId := "warehouse_id"
+		req := &entity.CreateProductRequest{
+			Name:        "name",
+			Price:       1000,
+			TotalStock:  10,
+			WarehouseId: warehouseId,
+			ActorId:     "userId",
+		}
+
+		warehouses := []*entity.Warehouse{{Id: warehouseId}}
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: warehouses,
+		}, errors.New("")).Once()
+
+		productId, err := ucTest.inventoryUsecase.CreateProduct(context.Background(), req)
+
+		assert.NotNil(t, err)
+		assert.Empty(t, productId)
+	})
+	t.Run("CreateProduct_insert product is error_then return error", func(t *testing.T) {
+		warehouseId := "warehouse_id"
+		req := &entity.CreateProductRequest{
+			Name:        "name",
+			Price:       1000,
+			TotalStock:  10,
+			WarehouseId: warehouseId,
+			ActorId:     "userId",
+		}
+
+		warehouses := []*entity.Warehouse{{Id: warehouseId}}
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: warehouses,
+		}, nil).Once()
+
+		mockUnitOfWork()
+
+		ucTest.inventoryRepo.On("InsertProduct", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(errors.New("")).Once()
+
+		productId, err := ucTest.inventoryUsecase.CreateProduct(context.Background(), req)
+
+		assert.NotNil(t, err)
+		assert.Empty(t, productId)
+	})
+	t.Run("CreateProduct_insert price history is error_then return error", func(t *testing.T) {
 		warehouseId := "warehouse_id"
 		req := &entity.CreateProductRequest{
 			Name:        "name",
 			Price:       1000,
 			TotalStock:  10,
 			WarehouseId: warehouseId,
+			ActorId:     "userId",
 		}
 
 		warehouses := []*entity.Warehouse{{Id: warehouseId}}
-		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything).Return(&entity.GetWarehousesResponse{
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
 			Warehouses: warehouses,
 		}, nil).Once()
 
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		mockUnitOfWork()
 
 		ucTest.inventoryRepo.On("InsertProduct", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(nil).Once()
-		ucTest.inventoryRepo.On("InsertProductWarehouseTx", mock.Anything, mock.AnythingOfType("*entity.ProductWarehouse")).Return(errors.New("")).Once()
+		ucTest.priceRepo.On("InsertPriceHistory", mock.Anything, mock.AnythingOfType("*entity.PriceHistory")).Return(errors.New("")).Once()
 
-		productId, err := ucTest.inventoryUsecase.CreateProduct(req)
+		productId, err := ucTest.inventoryUsecase.CreateProduct(context.Background(), req)
 
 		assert.NotNil(t, err)
 		assert.Empty(t, productId)
 	})
-	t.Run("CreateProduct_correct payload_then return success", func(t *testing.T) {
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
+	t.Run("CreateProduct_insert product warehouse is error_then return error", func(t *testing.T) {
+		warehouseId := "warehouse_id"
+		req := &entity.CreateProductRequest{
+			Name:        "name",
+			Price:       1000,
+			TotalStock:  10,
+			WarehouseId: warehouseId,
+			ActorId:     "userId",
+		}
+
+		warehouses := []*entity.Warehouse{{Id: warehouseId}}
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: warehouses,
+		}, nil).Once()
+
+		mockUnitOfWork()
+
+		ucTest.inventoryRepo.On("InsertProduct", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(nil).Once()
+		ucTest.priceRepo.On("InsertPriceHistory", mock.Anything, mock.MatchedBy(func(history *entity.PriceHistory) bool {
+			return history.Source == entity.PriceSourceBase && history.Price == req.Price && history.ActorId == req.ActorId
+		})).Return(nil).Once()
+		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.AnythingOfType("*entity.ProductWarehouse")).Return(errors.New("")).Once()
+
+		productId, err := ucTest.inventoryUsecase.CreateProduct(context.Background(), req)
 
+		assert.NotNil(t, err)
+		assert.Empty(t, productId)
+	})
+	t.Run("CreateProduct_correct payload_then return success", func(t *testing.T) {
 		warehouseId := "warehouse_id"
 		req := &entity.CreateProductRequest{
 			Name:        "name",
 			Price:       1000,
 			TotalStock:  10,
 			WarehouseId: warehouseId,
+			ActorId:     "userId",
 		}
 
 		warehouses := []*entity.Warehouse{{Id: warehouseId}}
-		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything).Return(&entity.GetWarehousesResponse{
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
 			Warehouses: warehouses,
 		}, nil).Once()
 
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		mockUnitOfWork()
 
 		ucTest.inventoryRepo.On("InsertProduct", mock.Anything, mock.AnythingOfType("*entity.Product")).Return(nil).Once()
-		ucTest.inventoryRepo.On("InsertProductWarehouseTx", mock.Anything, mock.AnythingOfType("*entity.ProductWarehouse")).Return(nil).Once()
+		ucTest.priceRepo.On("InsertPriceHistory", mock.Anything, mock.MatchedBy(func(history *entity.PriceHistory) bool {
+			return history.Source == entity.PriceSourceBase && history.Price == req.Price && history.ActorId == req.ActorId
+		})).Return(nil).Once()
+		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.AnythingOfType("*entity.ProductWarehouse")).Return(nil).Once()
 
-		productId, err := ucTest.inventoryUsecase.CreateProduct(req)
+		productId, err := ucTest.inventoryUsecase.CreateProduct(context.Background(), req)
 
 		assert.NoError(t, err)
 		assert.NotEmpty(t, productId)
@@ -390,9 +1250,9 @@ func TestUpdateProductStock(t *testing.T) {
 			TotalStock:  10,
 		}
 
-		ucTest.inventoryRepo.On("UpdateProductWarehouseTotalStock", req).Return(nil).Once()
+		ucTest.inventoryRepo.On("UpdateProductWarehouseTotalStock", mock.Anything, req).Return(nil).Once()
 
-		err := ucTest.inventoryUsecase.UpdateProductStock(req)
+		err := ucTest.inventoryUsecase.UpdateProductStock(context.Background(), req)
 
 		assert.Nil(t, err)
 	})
@@ -402,7 +1262,7 @@ func TestTransferProduct(t *testing.T) {
 	t.Run("TransferProduct_bad request_then return error", func(t *testing.T) {
 		req := &entity.TransferProductRequest{}
 
-		err := ucTest.inventoryUsecase.TransferProduct(req)
+		err := ucTest.inventoryUsecase.TransferProduct(context.Background(), req)
 
 		assert.NotNil(t, err)
 	})
@@ -423,11 +1283,11 @@ func TestTransferProduct(t *testing.T) {
 			{Id: sourceWarehouseId},
 			{Id: destinationWarehouseId},
 		}
-		ucTest.inventoryRepo.On("GetWarehouses", &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
 			Warehouses: warehouses,
 		}, errors.New("")).Once()
 
-		err := ucTest.inventoryUsecase.TransferProduct(req)
+		err := ucTest.inventoryUsecase.TransferProduct(context.Background(), req)
 
 		assert.NotNil(t, err)
 	})
@@ -448,16 +1308,16 @@ func TestTransferProduct(t *testing.T) {
 			{Id: sourceWarehouseId},
 			{Id: destinationWarehouseId},
 		}
-		ucTest.inventoryRepo.On("GetWarehouses", &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
 			Warehouses: warehouses,
 		}, nil).Once()
 
-		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", &entity.GetProductWarehousesByQueryRequest{
+		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", mock.Anything, &entity.GetProductWarehousesByQueryRequest{
 			ProductIds:   []string{productId},
 			WarehouseIds: []string{sourceWarehouseId},
 		}).Return(nil, errors.New("")).Once()
 
-		err := ucTest.inventoryUsecase.TransferProduct(req)
+		err := ucTest.inventoryUsecase.TransferProduct(context.Background(), req)
 
 		assert.NotNil(t, err)
 	})
@@ -478,7 +1338,7 @@ func TestTransferProduct(t *testing.T) {
 			{Id: sourceWarehouseId},
 			{Id: destinationWarehouseId},
 		}
-		ucTest.inventoryRepo.On("GetWarehouses", &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
 			Warehouses: warehouses,
 		}, nil).Once()
 
@@ -488,12 +1348,12 @@ func TestTransferProduct(t *testing.T) {
 			TotalStock: 1,
 		}
 		productWarehouses := []*entity.ProductWarehouse{sourceProductWarehouse}
-		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", &entity.GetProductWarehousesByQueryRequest{
+		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", mock.Anything, &entity.GetProductWarehousesByQueryRequest{
 			ProductIds:   []string{productId},
 			WarehouseIds: []string{sourceWarehouseId},
 		}).Return(productWarehouses, nil).Once()
 
-		err := ucTest.inventoryUsecase.TransferProduct(req)
+		err := ucTest.inventoryUsecase.TransferProduct(context.Background(), req)
 
 		assert.NotNil(t, err)
 	})
@@ -514,7 +1374,7 @@ func TestTransferProduct(t *testing.T) {
 			{Id: sourceWarehouseId},
 			{Id: destinationWarehouseId},
 		}
-		ucTest.inventoryRepo.On("GetWarehouses", &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
 			Warehouses: warehouses,
 		}, nil).Once()
 
@@ -524,22 +1384,16 @@ func TestTransferProduct(t *testing.T) {
 			TotalStock: 100,
 		}
 		productWarehouses := []*entity.ProductWarehouse{sourceProductWarehouse}
-		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", &entity.GetProductWarehousesByQueryRequest{
+		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", mock.Anything, &entity.GetProductWarehousesByQueryRequest{
 			ProductIds:   []string{productId},
 			WarehouseIds: []string{sourceWarehouseId},
 		}).Return(productWarehouses, nil).Once()
 
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
-
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		mockUnitOfWork()
 
-		ucTest.inventoryRepo.On("InsertProductWarehouseTx", mock.Anything, mock.Anything).Return(errors.New("")).Once()
+		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.Anything).Return(errors.New("")).Once()
 
-		err = ucTest.inventoryUsecase.TransferProduct(req)
+		err := ucTest.inventoryUsecase.TransferProduct(context.Background(), req)
 
 		assert.NotNil(t, err)
 	})
@@ -560,7 +1414,7 @@ func TestTransferProduct(t *testing.T) {
 			{Id: sourceWarehouseId},
 			{Id: destinationWarehouseId},
 		}
-		ucTest.inventoryRepo.On("GetWarehouses", &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
 			Warehouses: warehouses,
 		}, nil).Once()
 
@@ -570,23 +1424,17 @@ func TestTransferProduct(t *testing.T) {
 			TotalStock: 100,
 		}
 		productWarehouses := []*entity.ProductWarehouse{sourceProductWarehouse}
-		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", &entity.GetProductWarehousesByQueryRequest{
+		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", mock.Anything, &entity.GetProductWarehousesByQueryRequest{
 			ProductIds:   []string{productId},
 			WarehouseIds: []string{sourceWarehouseId},
 		}).Return(productWarehouses, nil).Once()
 
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
-
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		mockUnitOfWork()
 
-		ucTest.inventoryRepo.On("InsertProductWarehouseTx", mock.Anything, mock.Anything).Return(nil).Once()
-		ucTest.inventoryRepo.On("InsertProductWarehouseTx", mock.Anything, mock.Anything).Return(errors.New("")).Once()
+		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.Anything).Return(nil).Once()
+		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.Anything).Return(errors.New("")).Once()
 
-		err = ucTest.inventoryUsecase.TransferProduct(req)
+		err := ucTest.inventoryUsecase.TransferProduct(context.Background(), req)
 
 		assert.NotNil(t, err)
 	})
@@ -608,7 +1456,7 @@ func TestTransferProduct(t *testing.T) {
 			{Id: sourceWarehouseId},
 			{Id: destinationWarehouseId},
 		}
-		ucTest.inventoryRepo.On("GetWarehouses", &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, &entity.GetWarehousesRequest{Ids: []string{sourceWarehouseId, destinationWarehouseId}}).Return(&entity.GetWarehousesResponse{
 			Warehouses: warehouses,
 		}, nil).Once()
 
@@ -618,31 +1466,26 @@ func TestTransferProduct(t *testing.T) {
 			TotalStock: 100,
 		}
 		productWarehouses := []*entity.ProductWarehouse{sourceProductWarehouse}
-		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", &entity.GetProductWarehousesByQueryRequest{
+		ucTest.inventoryRepo.On("GetProductWarehousesByQuery", mock.Anything, &entity.GetProductWarehousesByQueryRequest{
 			ProductIds:   []string{productId},
 			WarehouseIds: []string{sourceWarehouseId},
 		}).Return(productWarehouses, nil).Once()
 
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
-
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		mockUnitOfWork()
 
-		ucTest.inventoryRepo.On("InsertProductWarehouseTx", mock.Anything, mock.Anything).Return(nil).Once()
-		ucTest.inventoryRepo.On("InsertProductWarehouseTx", mock.Anything, mock.Anything).Return(nil).Once()
+		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.Anything).Return(nil).Once()
+		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.Anything).Return(nil).Once()
 
-		err = ucTest.inventoryUsecase.TransferProduct(req)
+		err := ucTest.inventoryUsecase.TransferProduct(context.Background(), req)
 
 		assert.NoError(t, err)
 	})
 }
 
 func TestGetProductDetailsByShopId(t *testing.T) {
+
 	t.Run("GetProductDetailsByShopId_bad request_then return error", func(t *testing.T) {
-		resp, err := ucTest.transactionUsecase.GetProductDetailsByShopId(&entity.GetProductDetailsByShopIdRequest{})
+		resp, err := ucTest.transactionUsecase.GetProductDetailsByShopId(context.Background(), &entity.GetProductDetailsByShopIdRequest{})
 
 		assert.NotNil(t, err)
 		assert.Empty(t, resp)
@@ -650,9 +1493,9 @@ func TestGetProductDetailsByShopId(t *testing.T) {
 	t.Run("GetProductDetailsByShopId_get product details error_then return error", func(t *testing.T) {
 		shopId := "shopId"
 
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything).Return(nil, errors.New("")).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, mock.Anything).Return(nil, errors.New("")).Once()
 
-		resp, err := ucTest.transactionUsecase.GetProductDetailsByShopId(&entity.GetProductDetailsByShopIdRequest{
+		resp, err := ucTest.transactionUsecase.GetProductDetailsByShopId(context.Background(), &entity.GetProductDetailsByShopIdRequest{
 			ShopId: shopId,
 		})
 
@@ -676,10 +1519,10 @@ func TestGetProductDetailsByShopId(t *testing.T) {
 			ProductDetails: productDetails,
 		}
 
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything).Return(getProductResp, nil).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, mock.Anything).Return(getProductResp, nil).Once()
 		ucTest.redisRepo.On("GetReservedProductQuantity", mock.Anything, productId, warehouseId).Return(0, errors.New("")).Once()
 
-		resp, err := ucTest.transactionUsecase.GetProductDetailsByShopId(&entity.GetProductDetailsByShopIdRequest{
+		resp, err := ucTest.transactionUsecase.GetProductDetailsByShopId(context.Background(), &entity.GetProductDetailsByShopIdRequest{
 			ShopId: shopId,
 		})
 
@@ -704,18 +1547,28 @@ func TestGetProductDetailsByShopId(t *testing.T) {
 			ProductDetails: productDetails,
 		}
 
+		shopPrice := 900
 		expectedProductDetails := []*entity.ProductDetail{
 			{
 				ProductId:   productId,
 				WarehouseId: warehouseId,
 				TotalStock:  totalStock - reservedStock,
+				Price:       shopPrice,
+				ResolvedPrice: &entity.ResolvedPrice{
+					ProductId: productId,
+					Price:     shopPrice,
+					Source:    entity.PriceSourceShop,
+				},
 			},
 		}
 
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything).Return(getProductResp, nil).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, mock.Anything).Return(getProductResp, nil).Once()
 		ucTest.redisRepo.On("GetReservedProductQuantity", mock.Anything, productId, warehouseId).Return(reservedStock, nil).Once()
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{
+			{ProductId: productId, BasePrice: 1000, ShopPrice: &shopPrice},
+		}, nil).Once()
 
-		resp, err := ucTest.transactionUsecase.GetProductDetailsByShopId(&entity.GetProductDetailsByShopIdRequest{
+		resp, err := ucTest.transactionUsecase.GetProductDetailsByShopId(context.Background(), &entity.GetProductDetailsByShopIdRequest{
 			ShopId: shopId,
 		})
 
@@ -726,26 +1579,99 @@ func TestGetProductDetailsByShopId(t *testing.T) {
 }
 
 func TestOrderProducts(t *testing.T) {
-	t.Run("OrderProducts_bad request_then return error", func(t *testing.T) {
-		req := &entity.OrderProductsRequest{}
-		id, err := ucTest.transactionUsecase.OrderProducts(req)
 
-		assert.NotNil(t, err)
-		assert.Empty(t, id)
-	})
-	t.Run("OrderProducts_get product detail by shop id error_then return error", func(t *testing.T) {
+	t.Run("OrderProducts_customer is not a member of the shop_then order without checking the membership", func(t *testing.T) {
 		productId := "productId"
 		shopId := "shopId"
+		warehouseId := "warehouseId"
 
-		// mock GetProductDetailsByShopId
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", &entity.GetProductDetailsByShopIdRequest{
-			ShopId:     shopId,
-			ProductIds: []string{productId},
-		}).Return(nil, errors.New("")).Once()
-
-		// usecase
-		orderProductItem := &entity.OrderProductItem{
-			ProductId: productId,
+		// a customer has order:create but is not a member, so GetShopMember must not be called
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "customerId").Return(0, nil).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, productId)).Return(&entity.GetProductDetailsByShopIdResponse{
+			ProductDetails: []*entity.ProductDetail{{ProductId: productId, WarehouseId: warehouseId, TotalStock: 100, Price: 1000}},
+		}, nil).Once()
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{
+			{ProductId: productId, BasePrice: 1000, BasePriceHistoryId: "PRH-1"},
+		}, nil).Once()
+		ucTest.redisRepo.On("GetReservedProductQuantity", mock.Anything, productId, warehouseId).Return(0, nil).Once()
+		ucTest.redisRepo.On("LockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
+		mockUnitOfWork()
+		ucTest.transactionRepo.On("LockPendingOrders", mock.Anything, "customerId").Return(nil).Once()
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "customerId").Return(0, nil).Once()
+		ucTest.transactionRepo.On("InsertOrder", mock.Anything, mock.MatchedBy(func(order *entity.Order) bool {
+			return order.UserId == "customerId" && order.ShopId == shopId
+		})).Return(nil).Once()
+		ucTest.transactionRepo.On("InsertOrderItems", mock.Anything, mock.Anything).Return(nil).Once()
+
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), &entity.OrderProductsRequest{
+			Items:  []*entity.OrderProductItem{{ProductId: productId, Quantity: 5}},
+			ShopId: shopId,
+			UserId: "customerId",
+		})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, id)
+	})
+	t.Run("OrderProducts_bad request_then return error", func(t *testing.T) {
+		req := &entity.OrderProductsRequest{}
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
+
+		assert.NotNil(t, err)
+		assert.Empty(t, id)
+	})
+	t.Run("OrderProducts_shipping address is not in the address book_then return bad request error", func(t *testing.T) {
+		ucTest.userRepo.On("GetAddress", mock.Anything, "userId", "ADR-2").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		req := &entity.OrderProductsRequest{
+			Items:             []*entity.OrderProductItem{{ProductId: "productId", Quantity: 5}},
+			ShopId:            "shopId",
+			UserId:            "userId",
+			ShippingAddressId: "ADR-2",
+		}
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Empty(t, id)
+	})
+	t.Run("OrderProducts_count pending orders error_then return error", func(t *testing.T) {
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, errors.New("")).Once()
+
+		req := &entity.OrderProductsRequest{
+			Items:  []*entity.OrderProductItem{{ProductId: "productId", Quantity: 5}},
+			ShopId: "shopId",
+			UserId: "userId",
+		}
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
+
+		assert.NotNil(t, err)
+		assert.Empty(t, id)
+	})
+	t.Run("OrderProducts_max pending orders is reached_then return too many requests error", func(t *testing.T) {
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(entity.MaxPendingOrdersPerUser, nil).Once()
+
+		req := &entity.OrderProductsRequest{
+			Items:  []*entity.OrderProductItem{{ProductId: "productId", Quantity: 5}},
+			ShopId: "shopId",
+			UserId: "userId",
+		}
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
+
+		assert.Equal(t, errorutil.ErrTooManyRequests, errorutil.GetErrorType(err))
+		assert.Empty(t, id)
+	})
+	t.Run("OrderProducts_get product detail by shop id error_then return error", func(t *testing.T) {
+		productId := "productId"
+		shopId := "shopId"
+
+		// mock CountPendingOrders
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
+		// mock GetProductDetailsByShopId
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, productId)).Return(nil, errors.New("")).Once()
+
+		// usecase
+		orderProductItem := &entity.OrderProductItem{
+			ProductId: productId,
 			Quantity:  5,
 		}
 		req := &entity.OrderProductsRequest{
@@ -753,7 +1679,7 @@ func TestOrderProducts(t *testing.T) {
 			ShopId: shopId,
 			UserId: "userId",
 		}
-		id, err := ucTest.transactionUsecase.OrderProducts(req)
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
 
 		assert.NotNil(t, err)
 		assert.Empty(t, id)
@@ -763,6 +1689,9 @@ func TestOrderProducts(t *testing.T) {
 		shopId := "shopId"
 		warehouseId := "warehouseId"
 
+		// mock CountPendingOrders
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
 		// mock GetProductDetailsByShopId
 		getProductDetailsByShopIdResp := &entity.GetProductDetailsByShopIdResponse{
 			ProductDetails: []*entity.ProductDetail{
@@ -774,10 +1703,10 @@ func TestOrderProducts(t *testing.T) {
 			},
 		}
 
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", &entity.GetProductDetailsByShopIdRequest{
-			ShopId:     shopId,
-			ProductIds: []string{productId},
-		}).Return(getProductDetailsByShopIdResp, nil).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, productId)).Return(getProductDetailsByShopIdResp, nil).Once()
+
+		// mock GetProductPrices
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{}, nil).Once()
 
 		// mock GetReservedProductQuantity
 		ucTest.redisRepo.On("GetReservedProductQuantity", mock.Anything, productId, warehouseId).Return(0, errors.New("")).Once()
@@ -792,7 +1721,7 @@ func TestOrderProducts(t *testing.T) {
 			ShopId: shopId,
 			UserId: "userId",
 		}
-		id, err := ucTest.transactionUsecase.OrderProducts(req)
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
 
 		assert.NotNil(t, err)
 		assert.Empty(t, id)
@@ -802,6 +1731,9 @@ func TestOrderProducts(t *testing.T) {
 		shopId := "shopId"
 		warehouseId := "warehouseId"
 
+		// mock CountPendingOrders
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
 		// mock GetProductDetailsByShopId
 		getProductDetailsByShopIdResp := &entity.GetProductDetailsByShopIdResponse{
 			ProductDetails: []*entity.ProductDetail{
@@ -813,10 +1745,10 @@ func TestOrderProducts(t *testing.T) {
 			},
 		}
 
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", &entity.GetProductDetailsByShopIdRequest{
-			ShopId:     shopId,
-			ProductIds: []string{productId},
-		}).Return(getProductDetailsByShopIdResp, nil).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, productId)).Return(getProductDetailsByShopIdResp, nil).Once()
+
+		// mock GetProductPrices
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{}, nil).Once()
 
 		// mock GetReservedProductQuantity
 		reservedQuantity := 5
@@ -835,16 +1767,126 @@ func TestOrderProducts(t *testing.T) {
 			ShopId: shopId,
 			UserId: "userId",
 		}
-		id, err := ucTest.transactionUsecase.OrderProducts(req)
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
+
+		assert.NotNil(t, err)
+		assert.Empty(t, id)
+	})
+	t.Run("OrderProducts_lock of a later product error_then invalidate the locks of the previous products", func(t *testing.T) {
+		shopId := "shopId"
+		warehouseId := "warehouseId"
+
+		// mock CountPendingOrders
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
+		// mock GetProductDetailsByShopId
+		getProductDetailsByShopIdResp := &entity.GetProductDetailsByShopIdResponse{
+			ProductDetails: []*entity.ProductDetail{
+				{ProductId: "productId1", WarehouseId: warehouseId, TotalStock: 100},
+				{ProductId: "productId2", WarehouseId: warehouseId, TotalStock: 100},
+			},
+		}
+
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, "productId1", "productId2")).Return(getProductDetailsByShopIdResp, nil).Once()
+
+		// mock GetProductPrices
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{}, nil).Once()
+
+		// mock GetReservedProductQuantity
+		ucTest.redisRepo.On("GetReservedProductQuantity", mock.Anything, "productId1", warehouseId).Return(0, nil).Once()
+		ucTest.redisRepo.On("GetReservedProductQuantity", mock.Anything, "productId2", warehouseId).Return(0, nil).Once()
+
+		// mock LockOrderProduct, the first product is locked and the second one fails
+		ucTest.redisRepo.On("LockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
+		ucTest.redisRepo.On("LockOrderProduct", mock.Anything, mock.Anything).Return(errors.New("")).Once()
+
+		// mock InvalidateLockOrderProduct
+		var invalidatedProducts []*entity.LockOrderProductRequest
+		ucTest.redisRepo.On("InvalidateLockOrderProduct", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			invalidatedProducts = append(invalidatedProducts, args.Get(1).(*entity.LockOrderProductRequest))
+		}).Return(nil).Once()
+
+		// usecase
+		req := &entity.OrderProductsRequest{
+			Items: []*entity.OrderProductItem{
+				{ProductId: "productId1", Quantity: 5},
+				{ProductId: "productId2", Quantity: 3},
+			},
+			ShopId: shopId,
+			UserId: "userId",
+		}
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
 
 		assert.NotNil(t, err)
 		assert.Empty(t, id)
+		assert.Equal(t, []*entity.LockOrderProductRequest{{ProductId: "productId1", WarehouseId: warehouseId, UserId: "userId", Quantity: 5}}, invalidatedProducts)
+	})
+	t.Run("OrderProducts_max pending orders is reached by a concurrent order_then return too many requests error and invalidate the locks", func(t *testing.T) {
+		productId := "productId"
+		shopId := "shopId"
+		warehouseId := "warehouseId"
+
+		// mock CountPendingOrders
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
+		// mock GetProductDetailsByShopId
+		getProductDetailsByShopIdResp := &entity.GetProductDetailsByShopIdResponse{
+			ProductDetails: []*entity.ProductDetail{
+				{
+					ProductId:   productId,
+					WarehouseId: warehouseId,
+					TotalStock:  100,
+				},
+			},
+		}
+
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, productId)).Return(getProductDetailsByShopIdResp, nil).Once()
+
+		// mock GetProductPrices
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{}, nil).Once()
+
+		// mock GetReservedProductQuantity
+		reservedQuantity := 5
+		ucTest.redisRepo.On("GetReservedProductQuantity", mock.Anything, productId, warehouseId).Return(reservedQuantity, nil).Once()
+
+		// mock LockOrderProduct
+		ucTest.redisRepo.On("LockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
+
+		// mock the pending orders that are counted again after they are locked in the transaction
+		mockUnitOfWork()
+		ucTest.transactionRepo.On("LockPendingOrders", mock.Anything, "userId").Return(nil).Once()
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(entity.MaxPendingOrdersPerUser, nil).Once()
+
+		// mock InvalidateLockOrderProduct, the reserved stock is released since the order is not created
+		var invalidatedProducts []*entity.LockOrderProductRequest
+		ucTest.redisRepo.On("InvalidateLockOrderProduct", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			invalidatedProducts = append(invalidatedProducts, args.Get(1).(*entity.LockOrderProductRequest))
+		}).Return(nil).Once()
+
+		// usecase
+		orderProductItem := &entity.OrderProductItem{
+			ProductId: productId,
+			Quantity:  5,
+		}
+		req := &entity.OrderProductsRequest{
+			Items:  []*entity.OrderProductItem{orderProductItem},
+			ShopId: shopId,
+			UserId: "userId",
+		}
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
+
+		assert.Equal(t, errorutil.ErrTooManyRequests, errorutil.GetErrorType(err))
+		assert.Empty(t, id)
+		assert.Equal(t, []*entity.LockOrderProductRequest{{ProductId: productId, WarehouseId: warehouseId, UserId: "userId", Quantity: 5}}, invalidatedProducts)
 	})
 	t.Run("OrderProducts_insert order error_then return error", func(t *testing.T) {
 		productId := "productId"
 		shopId := "shopId"
 		warehouseId := "warehouseId"
 
+		// mock CountPendingOrders
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
 		// mock GetProductDetailsByShopId
 		getProductDetailsByShopIdResp := &entity.GetProductDetailsByShopIdResponse{
 			ProductDetails: []*entity.ProductDetail{
@@ -856,10 +1898,10 @@ func TestOrderProducts(t *testing.T) {
 			},
 		}
 
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", &entity.GetProductDetailsByShopIdRequest{
-			ShopId:     shopId,
-			ProductIds: []string{productId},
-		}).Return(getProductDetailsByShopIdResp, nil).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, productId)).Return(getProductDetailsByShopIdResp, nil).Once()
+
+		// mock GetProductPrices
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{}, nil).Once()
 
 		// mock GetReservedProductQuantity
 		reservedQuantity := 5
@@ -868,8 +1910,16 @@ func TestOrderProducts(t *testing.T) {
 		// mock LockOrderProduct
 		ucTest.redisRepo.On("LockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
 
+		// mock the pending orders that are counted again after they are locked in the transaction
+		mockUnitOfWork()
+		ucTest.transactionRepo.On("LockPendingOrders", mock.Anything, "userId").Return(nil).Once()
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
 		// mock InsertOrder
-		ucTest.transactionRepo.On("InsertOrder", mock.Anything).Return(errors.New("")).Once()
+		ucTest.transactionRepo.On("InsertOrder", mock.Anything, mock.Anything).Return(errors.New("")).Once()
+
+		// mock InvalidateLockOrderProduct
+		ucTest.redisRepo.On("InvalidateLockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
 
 		// usecase
 		orderProductItem := &entity.OrderProductItem{
@@ -881,7 +1931,7 @@ func TestOrderProducts(t *testing.T) {
 			ShopId: shopId,
 			UserId: "userId",
 		}
-		id, err := ucTest.transactionUsecase.OrderProducts(req)
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
 
 		assert.NotNil(t, err)
 		assert.Empty(t, id)
@@ -891,6 +1941,9 @@ func TestOrderProducts(t *testing.T) {
 		shopId := "shopId"
 		warehouseId := "warehouseId"
 
+		// mock CountPendingOrders
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
 		// mock GetProductDetailsByShopId
 		getProductDetailsByShopIdResp := &entity.GetProductDetailsByShopIdResponse{
 			ProductDetails: []*entity.ProductDetail{
@@ -902,10 +1955,10 @@ func TestOrderProducts(t *testing.T) {
 			},
 		}
 
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", &entity.GetProductDetailsByShopIdRequest{
-			ShopId:     shopId,
-			ProductIds: []string{productId},
-		}).Return(getProductDetailsByShopIdResp, nil).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, productId)).Return(getProductDetailsByShopIdResp, nil).Once()
+
+		// mock GetProductPrices
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{}, nil).Once()
 
 		// mock GetReservedProductQuantity
 		reservedQuantity := 5
@@ -914,11 +1967,19 @@ func TestOrderProducts(t *testing.T) {
 		// mock LockOrderProduct
 		ucTest.redisRepo.On("LockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
 
+		// mock the pending orders that are counted again after they are locked in the transaction
+		mockUnitOfWork()
+		ucTest.transactionRepo.On("LockPendingOrders", mock.Anything, "userId").Return(nil).Once()
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
 		// mock InsertOrder
-		ucTest.transactionRepo.On("InsertOrder", mock.Anything).Return(nil).Once()
+		ucTest.transactionRepo.On("InsertOrder", mock.Anything, mock.Anything).Return(nil).Once()
 
 		// mock InsertOrderItems
-		ucTest.transactionRepo.On("InsertOrderItems", mock.Anything).Return(errors.New("")).Once()
+		ucTest.transactionRepo.On("InsertOrderItems", mock.Anything, mock.Anything).Return(errors.New("")).Once()
+
+		// mock InvalidateLockOrderProduct
+		ucTest.redisRepo.On("InvalidateLockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
 
 		// usecase
 		orderProductItem := &entity.OrderProductItem{
@@ -930,7 +1991,7 @@ func TestOrderProducts(t *testing.T) {
 			ShopId: shopId,
 			UserId: "userId",
 		}
-		id, err := ucTest.transactionUsecase.OrderProducts(req)
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
 
 		assert.NotNil(t, err)
 		assert.Empty(t, id)
@@ -940,6 +2001,9 @@ func TestOrderProducts(t *testing.T) {
 		shopId := "shopId"
 		warehouseId := "warehouseId"
 
+		// mock CountPendingOrders
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
 		// mock GetProductDetailsByShopId
 		getProductDetailsByShopIdResp := &entity.GetProductDetailsByShopIdResponse{
 			ProductDetails: []*entity.ProductDetail{
@@ -951,10 +2015,13 @@ func TestOrderProducts(t *testing.T) {
 			},
 		}
 
-		ucTest.inventoryRepo.On("GetProductDetailsByShopId", &entity.GetProductDetailsByShopIdRequest{
-			ShopId:     shopId,
-			ProductIds: []string{productId},
-		}).Return(getProductDetailsByShopIdResp, nil).Once()
+		ucTest.inventoryRepo.On("GetProductDetailsByShopId", mock.Anything, matchProductDetailsRequest(shopId, productId)).Return(getProductDetailsByShopIdResp, nil).Once()
+
+		// mock GetProductPrices
+		shopPrice := 900
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{
+			{ProductId: productId, BasePrice: 1000, BasePriceHistoryId: "PRH-1", ShopPrice: &shopPrice, ShopPriceHistoryId: "PRH-2"},
+		}, nil).Once()
 
 		// mock GetReservedProductQuantity
 		reservedQuantity := 5
@@ -963,11 +2030,20 @@ func TestOrderProducts(t *testing.T) {
 		// mock LockOrderProduct
 		ucTest.redisRepo.On("LockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
 
+		// mock the pending orders that are counted again after they are locked in the transaction
+		mockUnitOfWork()
+		ucTest.transactionRepo.On("LockPendingOrders", mock.Anything, "userId").Return(nil).Once()
+		ucTest.transactionRepo.On("CountPendingOrders", mock.Anything, "userId").Return(0, nil).Once()
+
 		// mock InsertOrder
-		ucTest.transactionRepo.On("InsertOrder", mock.Anything).Return(nil).Once()
+		ucTest.transactionRepo.On("InsertOrder", mock.Anything, mock.MatchedBy(func(order *entity.Order) bool {
+			return order.Amount == shopPrice*5
+		})).Return(nil).Once()
 
-		// mock InsertOrderItems
-		ucTest.transactionRepo.On("InsertOrderItems", mock.Anything).Return(nil).Once()
+		// mock InsertOrderItems, the item refers the shop price record that is used
+		ucTest.transactionRepo.On("InsertOrderItems", mock.Anything, mock.MatchedBy(func(items []*entity.OrderItem) bool {
+			return len(items) == 1 && items[0].UnitPrice == shopPrice && items[0].PriceSource == entity.PriceSourceShop && items[0].PriceHistoryId == "PRH-2"
+		})).Return(nil).Once()
 
 		// usecase
 		orderProductItem := &entity.OrderProductItem{
@@ -979,7 +2055,7 @@ func TestOrderProducts(t *testing.T) {
 			ShopId: shopId,
 			UserId: "userId",
 		}
-		id, err := ucTest.transactionUsecase.OrderProducts(req)
+		id, err := ucTest.transactionUsecase.OrderProducts(context.Background(), req)
 
 		assert.Nil(t, err)
 		assert.NotEmpty(t, id)
@@ -989,7 +2065,7 @@ func TestOrderProducts(t *testing.T) {
 func TestPayOrder(t *testing.T) {
 	t.Run("PayOrder_bad request_then return error", func(t *testing.T) {
 		// usecase
-		err := ucTest.transactionUsecase.PayOrder(&entity.PayOrderRequest{})
+		err := ucTest.transactionUsecase.PayOrder(context.Background(), &entity.PayOrderRequest{})
 
 		assert.NotNil(t, err)
 	})
@@ -1005,11 +2081,12 @@ func TestPayOrder(t *testing.T) {
 		amount := 1000
 		order := &entity.Order{
 			Amount: amount,
+			UserId: userId,
 		}
-		ucTest.transactionRepo.On("GetOrderById", orderId, isActiveOrder).Return(order, errors.New("")).Once()
+		ucTest.transactionRepo.On("GetOrderById", mock.Anything, orderId, isActiveOrder).Return(order, errors.New("")).Once()
 
 		// usecase
-		err := ucTest.transactionUsecase.PayOrder(&entity.PayOrderRequest{
+		err := ucTest.transactionUsecase.PayOrder(context.Background(), &entity.PayOrderRequest{
 			OrderId: "orderId",
 			Amount:  amount,
 			UserId:  userId,
@@ -1017,6 +2094,30 @@ func TestPayOrder(t *testing.T) {
 
 		assert.NotNil(t, err)
 	})
+	t.Run("PayOrder_order of other user_then return forbidden error", func(t *testing.T) {
+		orderId := "orderId"
+
+		var isActiveOrder *bool
+		active := true
+		isActiveOrder = &active
+
+		// mock GetOrderById
+		amount := 1000
+		order := &entity.Order{
+			Amount: amount,
+			UserId: "otherUserId",
+		}
+		ucTest.transactionRepo.On("GetOrderById", mock.Anything, orderId, isActiveOrder).Return(order, nil).Once()
+
+		// usecase
+		err := ucTest.transactionUsecase.PayOrder(context.Background(), &entity.PayOrderRequest{
+			OrderId: "orderId",
+			Amount:  amount,
+			UserId:  "userId",
+		})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+	})
 	t.Run("PayOrder_insert payment error_then return error", func(t *testing.T) {
 		orderId := "orderId"
 		userId := "userId"
@@ -1029,21 +2130,16 @@ func TestPayOrder(t *testing.T) {
 		amount := 1000
 		order := &entity.Order{
 			Amount: amount,
+			UserId: userId,
 		}
-		ucTest.transactionRepo.On("GetOrderById", orderId, isActiveOrder).Return(order, nil).Once()
-
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
+		ucTest.transactionRepo.On("GetOrderById", mock.Anything, orderId, isActiveOrder).Return(order, nil).Once()
 
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		mockUnitOfWork()
 
 		ucTest.transactionRepo.On("InsertPayment", mock.Anything, mock.Anything).Return(errors.New("")).Once()
 
 		// usecase
-		err = ucTest.transactionUsecase.PayOrder(&entity.PayOrderRequest{
+		err := ucTest.transactionUsecase.PayOrder(context.Background(), &entity.PayOrderRequest{
 			OrderId: "orderId",
 			Amount:  amount,
 			UserId:  userId,
@@ -1063,16 +2159,11 @@ func TestPayOrder(t *testing.T) {
 		amount := 1000
 		order := &entity.Order{
 			Amount: amount,
+			UserId: userId,
 		}
-		ucTest.transactionRepo.On("GetOrderById", orderId, isActiveOrder).Return(order, nil).Once()
-
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
+		ucTest.transactionRepo.On("GetOrderById", mock.Anything, orderId, isActiveOrder).Return(order, nil).Once()
 
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		mockUnitOfWork()
 
 		ucTest.transactionRepo.On("InsertPayment", mock.Anything, mock.Anything).Return(nil).Once()
 		ucTest.transactionRepo.On("UpdateOrder", mock.Anything, &entity.UpdateOrderRequest{
@@ -1081,7 +2172,7 @@ func TestPayOrder(t *testing.T) {
 		}).Return(errors.New("")).Once()
 
 		// usecase
-		err = ucTest.transactionUsecase.PayOrder(&entity.PayOrderRequest{
+		err := ucTest.transactionUsecase.PayOrder(context.Background(), &entity.PayOrderRequest{
 			OrderId: "orderId",
 			Amount:  amount,
 			UserId:  userId,
@@ -1104,16 +2195,11 @@ func TestPayOrder(t *testing.T) {
 		amount := 1000
 		order := &entity.Order{
 			Amount: amount,
+			UserId: userId,
 		}
-		ucTest.transactionRepo.On("GetOrderById", orderId, isActiveOrder).Return(order, nil).Once()
-
-		// setup sqlmock for transaction
-		db, mockDB, err := sqlmock.New()
-		assert.NoError(t, err)
-		defer db.Close()
+		ucTest.transactionRepo.On("GetOrderById", mock.Anything, orderId, isActiveOrder).Return(order, nil).Once()
 
-		ucTest.inventoryRepo.On("GetDb").Return(db).Once()
-		mockDB.ExpectBegin()
+		mockUnitOfWork()
 
 		ucTest.transactionRepo.On("InsertPayment", mock.Anything, mock.Anything).Return(nil).Once()
 		ucTest.transactionRepo.On("UpdateOrder", mock.Anything, &entity.UpdateOrderRequest{
@@ -1129,13 +2215,13 @@ func TestPayOrder(t *testing.T) {
 				Quantity:    quantity,
 			},
 		}
-		ucTest.transactionRepo.On("GetOrderItemsByOrderId", orderId).Return(orderItems, nil).Once()
+		ucTest.transactionRepo.On("GetOrderItemsByOrderId", mock.Anything, orderId).Return(orderItems, nil).Once()
 
 		ucTest.redisRepo.On("InvalidateLockOrderProduct", mock.Anything, mock.Anything).Return(nil).Once()
-		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything).Return(nil).Once()
+		ucTest.inventoryRepo.On("InsertProductWarehouse", mock.Anything, mock.Anything).Return(nil).Once()
 
 		// usecase
-		err = ucTest.transactionUsecase.PayOrder(&entity.PayOrderRequest{
+		err := ucTest.transactionUsecase.PayOrder(context.Background(), &entity.PayOrderRequest{
 			OrderId: "orderId",
 			Amount:  amount,
 			UserId:  userId,
@@ -1144,3 +2230,1087 @@ func TestPayOrder(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestResolvePrices(t *testing.T) {
+	t.Run("ResolvePrices_empty product ids_then return empty prices", func(t *testing.T) {
+		prices, err := ucTest.priceUsecase.ResolvePrices(context.Background(), "shopId", nil, time.Now())
+
+		assert.Nil(t, err)
+		assert.Empty(t, prices)
+	})
+	t.Run("ResolvePrices_get product prices error_then return error", func(t *testing.T) {
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return(nil, errors.New("")).Once()
+
+		prices, err := ucTest.priceUsecase.ResolvePrices(context.Background(), "shopId", []string{"productId"}, time.Now())
+
+		assert.NotNil(t, err)
+		assert.Nil(t, prices)
+	})
+	t.Run("ResolvePrices_correct payload_then price list over shop price over base price", func(t *testing.T) {
+		shopPrice := 900
+		priceListPrice := 800
+		at := time.Now()
+
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, &entity.GetProductPricesRequest{
+			ShopId:     "shopId",
+			ProductIds: []string{"base", "shop", "priceList"},
+			At:         at,
+		}).Return([]*entity.ProductPrice{
+			{ProductId: "base", BasePrice: 1000},
+			{ProductId: "shop", BasePrice: 1000, ShopPrice: &shopPrice},
+			{ProductId: "priceList", BasePrice: 1000, ShopPrice: &shopPrice, PriceListId: "PRL-1", PriceListPrice: &priceListPrice},
+		}, nil).Once()
+
+		prices, err := ucTest.priceUsecase.ResolvePrices(context.Background(), "shopId", []string{"base", "shop", "priceList"}, at)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.ResolvedPrice{ProductId: "base", Price: 1000, Source: entity.PriceSourceBase}, prices["base"])
+		assert.Equal(t, &entity.ResolvedPrice{ProductId: "shop", Price: shopPrice, Source: entity.PriceSourceShop}, prices["shop"])
+		assert.Equal(t, &entity.ResolvedPrice{ProductId: "priceList", Price: priceListPrice, Source: entity.PriceSourcePriceList, PriceListId: "PRL-1"}, prices["priceList"])
+	})
+}
+
+func TestCreatePriceList(t *testing.T) {
+	admin := entity.ShopActor{UserId: "USR-1", Permissions: []string{entity.PermissionShopManageAny}}
+
+	t.Run("CreatePriceList_user is not a member_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "shopId", "USR-3").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		id, err := ucTest.priceUsecase.CreatePriceList(context.Background(), &entity.CreatePriceListRequest{
+			ShopId:  "shopId",
+			Name:    "weekend sale",
+			StartAt: time.Now(),
+			Items:   []*entity.PriceListItem{{ProductId: "productId", Price: 800}},
+			Actor:   entity.ShopActor{UserId: "USR-3"},
+		})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+		assert.Empty(t, id)
+	})
+	t.Run("CreatePriceList_end at is before start at_then return error", func(t *testing.T) {
+		startAt := time.Now()
+		endAt := startAt.Add(-time.Hour)
+
+		id, err := ucTest.priceUsecase.CreatePriceList(context.Background(), &entity.CreatePriceListRequest{
+			ShopId:  "shopId",
+			Actor:   admin,
+			Name:    "weekend sale",
+			StartAt: startAt,
+			EndAt:   &endAt,
+			Items:   []*entity.PriceListItem{{ProductId: "productId", Price: 800}},
+		})
+
+		assert.Equal(t, []*errorutil.FieldError{{Field: "endAt", Message: "end at must be after start at"}}, errorutil.GetFields(err))
+		assert.Empty(t, id)
+	})
+	t.Run("CreatePriceList_price of an item is not positive_then return the field of the item", func(t *testing.T) {
+		id, err := ucTest.priceUsecase.CreatePriceList(context.Background(), &entity.CreatePriceListRequest{
+			ShopId:  "shopId",
+			Actor:   admin,
+			Name:    "weekend sale",
+			StartAt: time.Now(),
+			Items:   []*entity.PriceListItem{{ProductId: "productId", Price: 800}, {ProductId: "productId2", Price: 0}},
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Equal(t, []*errorutil.FieldError{{Field: "items[1].price", Message: "price of product 'productId2' must be more than zero"}}, errorutil.GetFields(err))
+		assert.Empty(t, id)
+	})
+	t.Run("CreatePriceList_correct payload_then return success", func(t *testing.T) {
+		mockUnitOfWork()
+
+		ucTest.priceRepo.On("InsertPriceList", mock.Anything, mock.AnythingOfType("*entity.PriceList")).Return(nil).Once()
+		ucTest.priceRepo.On("InsertPriceListItems", mock.Anything, mock.Anything).Return(nil).Once()
+
+		startAt := time.Now()
+		endAt := startAt.Add(48 * time.Hour)
+		id, err := ucTest.priceUsecase.CreatePriceList(context.Background(), &entity.CreatePriceListRequest{
+			ShopId:  "shopId",
+			Actor:   admin,
+			Name:    "weekend sale",
+			StartAt: startAt,
+			EndAt:   &endAt,
+			Items:   []*entity.PriceListItem{{ProductId: "productId", Price: 800}},
+		})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, id)
+	})
+}
+
+func TestGetPriceLists(t *testing.T) {
+	t.Run("GetPriceLists_user is not a member_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "shopId", "USR-3").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		resp, err := ucTest.priceUsecase.GetPriceLists(context.Background(), &entity.GetPriceListsRequest{ShopId: "shopId", Actor: entity.ShopActor{UserId: "USR-3"}})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+		assert.Nil(t, resp)
+	})
+	t.Run("GetPriceLists_user is a staff_then return the price lists that are not ended", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "shopId", "USR-3").Return(&entity.ShopMember{ShopId: "shopId", UserId: "USR-3", Role: entity.ShopMemberRoleStaff}, nil).Once()
+		ucTest.priceRepo.On("GetPriceLists", mock.Anything, mock.MatchedBy(func(req *entity.GetPriceListsRequest) bool {
+			return req.ShopId == "shopId" && !req.ActiveFrom.IsZero()
+		})).Return(nil, nil).Once()
+
+		resp, err := ucTest.priceUsecase.GetPriceLists(context.Background(), &entity.GetPriceListsRequest{ShopId: "shopId", Actor: entity.ShopActor{UserId: "USR-3"}})
+
+		assert.Nil(t, err)
+		assert.Empty(t, resp.PriceLists)
+	})
+}
+
+func TestUpdateProductPrice(t *testing.T) {
+	t.Run("UpdateProductPrice_bad request_then return error", func(t *testing.T) {
+		err := ucTest.priceUsecase.UpdateProductPrice(context.Background(), &entity.UpdateProductPriceRequest{ProductId: "productId"})
+
+		assert.NotNil(t, err)
+	})
+	t.Run("UpdateProductPrice_update product price is error_then return error", func(t *testing.T) {
+		mockUnitOfWork()
+
+		ucTest.priceRepo.On("UpdateProductPrice", mock.Anything, "productId", 1200).Return(errors.New("")).Once()
+
+		err := ucTest.priceUsecase.UpdateProductPrice(context.Background(), &entity.UpdateProductPriceRequest{ProductId: "productId", Price: 1200, ActorId: "userId"})
+
+		assert.NotNil(t, err)
+	})
+	t.Run("UpdateProductPrice_correct payload_then record price history", func(t *testing.T) {
+		mockUnitOfWork()
+
+		ucTest.priceRepo.On("UpdateProductPrice", mock.Anything, "productId", 1200).Return(nil).Once()
+		ucTest.priceRepo.On("InsertPriceHistory", mock.Anything, mock.MatchedBy(func(history *entity.PriceHistory) bool {
+			return history.ProductId == "productId" && history.ShopId == "" && history.Source == entity.PriceSourceBase &&
+				history.Price == 1200 && history.ActorId == "userId" && !history.CreatedAt.IsZero()
+		})).Return(nil).Once()
+
+		err := ucTest.priceUsecase.UpdateProductPrice(context.Background(), &entity.UpdateProductPriceRequest{ProductId: "productId", Price: 1200, ActorId: "userId"})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestUpsertShopProductPrice(t *testing.T) {
+	staff := entity.ShopActor{UserId: "userId"}
+
+	t.Run("UpsertShopProductPrice_user is not a member_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "shopId", "userId").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		err := ucTest.priceUsecase.UpsertShopProductPrice(context.Background(), &entity.UpsertShopProductPriceRequest{ShopId: "shopId", ProductId: "productId", Price: 900, ActorId: "userId", Actor: staff})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+	})
+	t.Run("UpsertShopProductPrice_correct payload_then record shop price history", func(t *testing.T) {
+		mockUnitOfWork()
+
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "shopId", "userId").Return(&entity.ShopMember{ShopId: "shopId", UserId: "userId", Role: entity.ShopMemberRoleStaff}, nil).Once()
+
+		ucTest.priceRepo.On("UpsertShopProductPrice", mock.Anything, &entity.ShopProductPrice{ShopId: "shopId", ProductId: "productId", Price: 900}).Return(nil).Once()
+		ucTest.priceRepo.On("InsertPriceHistory", mock.Anything, mock.MatchedBy(func(history *entity.PriceHistory) bool {
+			return history.ShopId == "shopId" && history.Source == entity.PriceSourceShop && history.Price == 900 && history.ActorId == "userId"
+		})).Return(nil).Once()
+
+		err := ucTest.priceUsecase.UpsertShopProductPrice(context.Background(), &entity.UpsertShopProductPriceRequest{ShopId: "shopId", ProductId: "productId", Price: 900, ActorId: "userId", Actor: staff})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestGetProductPriceAt(t *testing.T) {
+
+	t.Run("GetProductPriceAt_product is not found_then return not found error", func(t *testing.T) {
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, mock.Anything).Return([]*entity.ProductPrice{}, nil).Once()
+
+		resp, err := ucTest.priceUsecase.GetProductPriceAt(context.Background(), &entity.GetProductPriceAtRequest{ProductId: "productId"})
+
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+		assert.Nil(t, resp)
+	})
+	t.Run("GetProductPriceAt_correct payload_then return price at the time with its change record", func(t *testing.T) {
+		at := time.Now().Add(-24 * time.Hour)
+		changedAt := at.Add(-time.Hour)
+		shopPrice := 900
+
+		ucTest.priceRepo.On("GetProductPrices", mock.Anything, &entity.GetProductPricesRequest{
+			ShopId:     "shopId",
+			ProductIds: []string{"productId"},
+			At:         at,
+		}).Return([]*entity.ProductPrice{
+			{ProductId: "productId", BasePrice: 1000, BasePriceHistoryId: "PRH-1", ShopPrice: &shopPrice, ShopPriceHistoryId: "PRH-2"},
+		}, nil).Once()
+		ucTest.priceRepo.On("GetPriceHistoryById", mock.Anything, "PRH-2").Return(&entity.PriceHistory{
+			Id:        "PRH-2",
+			ActorId:   "userId",
+			CreatedAt: changedAt,
+		}, nil).Once()
+
+		resp, err := ucTest.priceUsecase.GetProductPriceAt(context.Background(), &entity.GetProductPriceAtRequest{ProductId: "productId", ShopId: "shopId", At: &at})
+
+		assert.Nil(t, err)
+		assert.Equal(t, shopPrice, resp.Price)
+		assert.Equal(t, entity.PriceSourceShop, resp.Source)
+		assert.Equal(t, "PRH-2", resp.PriceHistoryId)
+		assert.Equal(t, "userId", resp.ChangedBy)
+		assert.Equal(t, changedAt, *resp.ChangedAt)
+	})
+}
+
+func TestImportCatalog(t *testing.T) {
+	t.Run("ImportCatalog_format is invalid_then return bad request error", func(t *testing.T) {
+		job, err := ucTest.catalogUsecase.ImportCatalog(context.Background(), &entity.ImportCatalogRequest{
+			Format: "xlsx",
+			Reader: strings.NewReader(""),
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Nil(t, job)
+	})
+	t.Run("ImportCatalog_csv header has no mandatory column_then return bad request error", func(t *testing.T) {
+		job, err := ucTest.catalogUsecase.ImportCatalog(context.Background(), &entity.ImportCatalogRequest{
+			Format: entity.CatalogFormatCSV,
+			Reader: strings.NewReader("sku,name,price\nSKU-1,Shirt,1000\n"),
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Nil(t, job)
+	})
+	t.Run("ImportCatalog_some rows are invalid_then return report of each invalid row without importing", func(t *testing.T) {
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, &entity.GetWarehousesRequest{
+			Ids: []string{"WRH-1", "WRH-404"},
+		}).Return(&entity.GetWarehousesResponse{
+			Warehouses: []*entity.Warehouse{{Id: "WRH-1"}},
+		}, nil).Once()
+
+		content := "sku,name,price,warehouseId,totalStock\n" +
+			"SKU-1,Shirt,1000,WRH-1,10\n" + // line 2 is valid
+			"SKU-2,Pants,abc,WRH-1,10\n" + // line 3 price is not a number
+			"SKU-3,Hat,500,WRH-404,10\n" + // line 4 warehouse is not found
+			"SKU-1,Shirt,1200,WRH-2,10\n" + // line 5 price is different with line 2
+			",Sock,100,WRH-1,1\n" // line 6 sku is empty
+
+		job, err := ucTest.catalogUsecase.ImportCatalog(context.Background(), &entity.ImportCatalogRequest{
+			Format: entity.CatalogFormatCSV,
+			Reader: strings.NewReader(content),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, entity.CatalogImportJobStatusFailed, job.Status)
+		assert.Empty(t, job.Id)
+		assert.Equal(t, 5, job.TotalRows)
+		assert.Equal(t, 4, job.FailedRows)
+		assert.Equal(t, 1, job.SucceededRows)
+
+		var lines []int
+		for _, rowError := range job.Errors {
+			lines = append(lines, rowError.Line)
+		}
+		assert.Equal(t, []int{3, 4, 5, 6}, lines)
+	})
+	t.Run("ImportCatalog_dry run with valid rows_then return report without importing", func(t *testing.T) {
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: []*entity.Warehouse{{Id: "WRH-1"}, {Id: "WRH-2"}},
+		}, nil).Once()
+
+		content := `{"sku":"SKU-1","name":"Shirt","price":1000,"warehouseId":"WRH-1","totalStock":10}
+{"sku":"SKU-1","name":"Shirt","price":1000,"warehouseId":"WRH-2","totalStock":5}
+`
+		job, err := ucTest.catalogUsecase.ImportCatalog(context.Background(), &entity.ImportCatalogRequest{
+			Format: entity.CatalogFormatJSONL,
+			DryRun: true,
+			Reader: strings.NewReader(content),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, entity.CatalogImportJobStatusSucceeded, job.Status)
+		assert.True(t, job.DryRun)
+		assert.Empty(t, job.Id)
+		assert.Equal(t, 2, job.SucceededRows)
+		assert.Empty(t, job.Errors)
+	})
+	t.Run("ImportCatalog_valid rows_then upsert products by sku in background job", func(t *testing.T) {
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: []*entity.Warehouse{{Id: "WRH-1"}, {Id: "WRH-2"}},
+		}, nil).Once()
+		ucTest.catalogRepo.On("InsertImportJob", mock.Anything, mock.MatchedBy(func(job *entity.CatalogImportJob) bool {
+			return job.Id != "" && job.Status == entity.CatalogImportJobStatusRunning && job.TotalRows == 3
+		})).Return(nil).Once()
+
+		mockUnitOfWork()
+		mockUnitOfWork()
+
+		// SKU-1 is a new product
+		ucTest.catalogRepo.On("GetProductBySku", mock.Anything, "SKU-1").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+		ucTest.inventoryRepo.On("InsertProduct", mock.Anything, mock.MatchedBy(func(product *entity.Product) bool {
+			return product.Sku == "SKU-1" && product.Price == 1000
+		})).Return(nil).Once()
+		ucTest.priceRepo.On("InsertPriceHistory", mock.Anything, mock.MatchedBy(func(history *entity.PriceHistory) bool {
+			return history.Price == 1000 && history.ActorId == "userId"
+		})).Return(nil).Once()
+
+		// SKU-2 exists with the same name & price, only the stock is set
+		ucTest.catalogRepo.On("GetProductBySku", mock.Anything, "SKU-2").Return(&entity.Product{Id: "PRD-2", Sku: "SKU-2", Name: "Pants", Price: 2000}, nil).Once()
+
+		ucTest.catalogRepo.On("SetProductWarehouseStock", mock.Anything, mock.AnythingOfType("*entity.ProductWarehouse")).Return(nil).Times(3)
+
+		done := make(chan *entity.CatalogImportJob, 1)
+		ucTest.catalogRepo.On("UpdateImportJob", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			job := *args.Get(1).(*entity.CatalogImportJob)
+			done <- &job
+		}).Return(nil).Once()
+
+		content := "sku,name,price,warehouseId,totalStock\n" +
+			"SKU-1,Shirt,1000,WRH-1,10\n" +
+			"SKU-2,Pants,2000,WRH-1,3\n" +
+			"SKU-1,Shirt,1000,WRH-2,5\n"
+
+		job, err := ucTest.catalogUsecase.ImportCatalog(context.Background(), &entity.ImportCatalogRequest{
+			Format:  entity.CatalogFormatCSV,
+			Reader:  strings.NewReader(content),
+			ActorId: "userId",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, entity.CatalogImportJobStatusRunning, job.Status)
+
+		select {
+		case finishedJob := <-done:
+			assert.Equal(t, entity.CatalogImportJobStatusSucceeded, finishedJob.Status)
+			assert.Equal(t, 3, finishedJob.ProcessedRows)
+			assert.Equal(t, 3, finishedJob.SucceededRows)
+		case <-time.After(time.Second):
+			t.Fatal("import job is not finished")
+		}
+	})
+	t.Run("ImportCatalog_sku or name is used by another product_then report the conflict of each row", func(t *testing.T) {
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: []*entity.Warehouse{{Id: "WRH-1"}},
+		}, nil).Once()
+		ucTest.catalogRepo.On("InsertImportJob", mock.Anything, mock.Anything).Return(nil).Once()
+
+		mockUnitOfWork()
+		mockUnitOfWork()
+
+		// SKU-1 is inserted by another import after it is read, the name of SKU-2 is used
+		ucTest.catalogRepo.On("GetProductBySku", mock.Anything, "SKU-1").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+		ucTest.inventoryRepo.On("InsertProduct", mock.Anything, mock.MatchedBy(func(product *entity.Product) bool {
+			return product.Sku == "SKU-1"
+		})).Return(errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo insert product: %w", entity.ErrProductSkuIsUsed))).Once()
+		ucTest.catalogRepo.On("GetProductBySku", mock.Anything, "SKU-2").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+		ucTest.inventoryRepo.On("InsertProduct", mock.Anything, mock.MatchedBy(func(product *entity.Product) bool {
+			return product.Sku == "SKU-2"
+		})).Return(errorutil.NewErrorCode(errorutil.ErrUniqueViolation, fmt.Errorf("error repo insert product: %w", entity.ErrProductNameIsUsed))).Once()
+
+		done := make(chan *entity.CatalogImportJob, 1)
+		ucTest.catalogRepo.On("UpdateImportJob", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			job := *args.Get(1).(*entity.CatalogImportJob)
+			done <- &job
+		}).Return(nil).Once()
+
+		content := "sku,name,price,warehouseId,totalStock\n" +
+			"SKU-1,Shirt,1000,WRH-1,10\n" +
+			"SKU-2,Pants,2000,WRH-1,3\n"
+
+		_, err := ucTest.catalogUsecase.ImportCatalog(context.Background(), &entity.ImportCatalogRequest{
+			Format: entity.CatalogFormatCSV,
+			Reader: strings.NewReader(content),
+		})
+		assert.Nil(t, err)
+
+		select {
+		case finishedJob := <-done:
+			assert.Equal(t, entity.CatalogImportJobStatusFailed, finishedJob.Status)
+			assert.Equal(t, 2, finishedJob.FailedRows)
+			assert.Equal(t, "sku 'SKU-1' is imported by another import at the same time, import it again", finishedJob.Errors[0].Error)
+			assert.Equal(t, "name 'Pants' is used by another product", finishedJob.Errors[1].Error)
+		case <-time.After(time.Second):
+			t.Fatal("import job is not finished")
+		}
+	})
+	t.Run("ImportCatalog_server is stopped_then return unavailable error and fail the job without running it", func(t *testing.T) {
+		lifecycle := lifecycleutil.NewManager(logutil.Discard())
+		assert.Nil(t, lifecycle.Shutdown(context.Background()))
+		catalogUsecase := usecase.NewCatalogUsecase(ucTest.inventoryRepo, ucTest.catalogRepo, ucTest.priceRepo, ucTest.unitOfWork, lifecycle, logutil.Discard())
+
+		ucTest.inventoryRepo.On("GetWarehouses", mock.Anything, mock.Anything).Return(&entity.GetWarehousesResponse{
+			Warehouses: []*entity.Warehouse{{Id: "WRH-1"}},
+		}, nil).Once()
+		ucTest.catalogRepo.On("InsertImportJob", mock.Anything, mock.Anything).Return(nil).Once()
+
+		var failedJob entity.CatalogImportJob
+		ucTest.catalogRepo.On("UpdateImportJob", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			failedJob = *args.Get(1).(*entity.CatalogImportJob)
+		}).Return(nil).Once()
+
+		content := "sku,name,price,warehouseId,totalStock\n" +
+			"SKU-1,Shirt,1000,WRH-1,10\n"
+
+		job, err := catalogUsecase.ImportCatalog(context.Background(), &entity.ImportCatalogRequest{
+			Format: entity.CatalogFormatCSV,
+			Reader: strings.NewReader(content),
+		})
+
+		assert.Equal(t, errorutil.ErrUnavailable, errorutil.GetErrorType(err))
+		assert.ErrorIs(t, err, lifecycleutil.ErrStopped)
+		assert.Nil(t, job)
+		assert.Equal(t, entity.CatalogImportJobStatusFailed, failedJob.Status)
+		assert.Equal(t, 0, failedJob.ProcessedRows)
+	})
+}
+
+func TestExportCatalog(t *testing.T) {
+	rows := []*entity.CatalogExportRow{
+		{ProductId: "PRD-1", Sku: "SKU-1", Name: "Shirt", Price: 1000, WarehouseId: "WRH-1", TotalStock: 10},
+		{ProductId: "PRD-1", Sku: "SKU-1", Name: "Shirt", Price: 1000, WarehouseId: "WRH-2", TotalStock: 5},
+	}
+	iterate := func(args mock.Arguments) {
+		fn := args.Get(1).(func(row *entity.CatalogExportRow) error)
+		for _, row := range rows {
+			_ = fn(row)
+		}
+	}
+
+	t.Run("ExportCatalog_csv format_then write header and a row for each warehouse", func(t *testing.T) {
+		ucTest.catalogRepo.On("IterateCatalog", mock.Anything, mock.Anything).Run(iterate).Return(nil).Once()
+
+		var buf bytes.Buffer
+		err := ucTest.catalogUsecase.ExportCatalog(context.Background(), &entity.ExportCatalogRequest{Format: entity.CatalogFormatCSV, Writer: &buf})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "productId,sku,name,price,warehouseId,totalStock\nPRD-1,SKU-1,Shirt,1000,WRH-1,10\nPRD-1,SKU-1,Shirt,1000,WRH-2,5\n", buf.String())
+	})
+	t.Run("ExportCatalog_jsonl format_then write a json line for each warehouse", func(t *testing.T) {
+		ucTest.catalogRepo.On("IterateCatalog", mock.Anything, mock.Anything).Run(iterate).Return(nil).Once()
+
+		var buf bytes.Buffer
+		err := ucTest.catalogUsecase.ExportCatalog(context.Background(), &entity.ExportCatalogRequest{Format: entity.CatalogFormatJSONL, Writer: &buf})
+
+		assert.Nil(t, err)
+		assert.Equal(t, `{"productId":"PRD-1","sku":"SKU-1","name":"Shirt","price":1000,"warehouseId":"WRH-1","totalStock":10}`+"\n"+
+			`{"productId":"PRD-1","sku":"SKU-1","name":"Shirt","price":1000,"warehouseId":"WRH-2","totalStock":5}`+"\n", buf.String())
+	})
+}
+
+func TestAssignRoles(t *testing.T) {
+	t.Run("AssignRoles_empty roles_then return bad request error", func(t *testing.T) {
+		err := ucTest.rbacUsecase.AssignRoles(context.Background(), &entity.AssignRolesRequest{UserId: "USR-1", ActorId: "USR-2"})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("AssignRoles_admin removes own admin role_then return bad request error", func(t *testing.T) {
+		ucTest.rbacRepo.On("GetUserRoles", mock.Anything, "USR-1").Return(&entity.UserRoles{Roles: []string{entity.RoleAdmin}}, nil).Once()
+
+		err := ucTest.rbacUsecase.AssignRoles(context.Background(), &entity.AssignRolesRequest{
+			UserId:  "USR-1",
+			Roles:   []string{entity.RoleMerchant},
+			ActorId: "USR-1",
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("AssignRoles_correct payload_then replace the roles", func(t *testing.T) {
+		mockUnitOfWork()
+		ucTest.rbacRepo.On("SetUserRoles", mock.Anything, "USR-2", []string{entity.RoleMerchant}).Return(nil).Once()
+
+		err := ucTest.rbacUsecase.AssignRoles(context.Background(), &entity.AssignRolesRequest{
+			UserId:  "USR-2",
+			Roles:   []string{entity.RoleMerchant},
+			ActorId: "USR-1",
+		})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	email := "admin@mail.com"
+
+	t.Run("BootstrapAdmin_admin exists_then return bad request error", func(t *testing.T) {
+		ucTest.rbacRepo.On("CountUsersByRole", mock.Anything, entity.RoleAdmin).Return(1, nil).Once()
+
+		userId, err := ucTest.rbacUsecase.BootstrapAdmin(context.Background(), &entity.BootstrapAdminRequest{
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Password:       "password",
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Empty(t, userId)
+	})
+	t.Run("BootstrapAdmin_user is registered_then promote the user and keep its roles", func(t *testing.T) {
+		ucTest.rbacRepo.On("CountUsersByRole", mock.Anything, entity.RoleAdmin).Return(0, nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(&entity.User{Id: "USR-1", Email: email}, nil).Once()
+		ucTest.rbacRepo.On("GetUserRoles", mock.Anything, "USR-1").Return(&entity.UserRoles{Roles: []string{entity.RoleCustomer}}, nil).Once()
+		mockUnitOfWork()
+		ucTest.rbacRepo.On("SetUserRoles", mock.Anything, "USR-1", []string{entity.RoleAdmin, entity.RoleCustomer}).Return(nil).Once()
+
+		userId, err := ucTest.rbacUsecase.BootstrapAdmin(context.Background(), &entity.BootstrapAdminRequest{
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Password:       "password",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "USR-1", userId)
+	})
+	t.Run("BootstrapAdmin_user is not registered_then create the admin", func(t *testing.T) {
+		var user *entity.User
+		ucTest.rbacRepo.On("CountUsersByRole", mock.Anything, entity.RoleAdmin).Return(0, nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, mock.Anything).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+		ucTest.userRepo.On("InsertUser", mock.Anything, mock.AnythingOfType("*entity.User")).Run(func(args mock.Arguments) {
+			user = args.Get(1).(*entity.User)
+		}).Return(nil).Once()
+		mockUnitOfWork()
+		ucTest.rbacRepo.On("SetUserRoles", mock.Anything, mock.AnythingOfType("string"), []string{entity.RoleAdmin}).Return(nil).Once()
+
+		userId, err := ucTest.rbacUsecase.BootstrapAdmin(context.Background(), &entity.BootstrapAdminRequest{
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Password:       "password",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, user.Id, userId)
+		assert.Equal(t, email, user.Email)
+		assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("password")))
+	})
+}
+
+func TestAuthorizeShopMember(t *testing.T) {
+	t.Run("AuthorizeShopMember_user can manage any shop_then return success", func(t *testing.T) {
+		err := ucTest.membershipUsecase.AuthorizeShopMember(context.Background(), &entity.AuthorizeShopMemberRequest{
+			ShopId:  "SHP-1",
+			Actor:   entity.ShopActor{UserId: "USR-1", Permissions: []string{entity.PermissionShopManageAny}},
+			MinRole: entity.ShopMemberRoleOwner,
+		})
+
+		assert.Nil(t, err)
+	})
+	t.Run("AuthorizeShopMember_empty shop id_then return bad request error", func(t *testing.T) {
+		err := ucTest.membershipUsecase.AuthorizeShopMember(context.Background(), &entity.AuthorizeShopMemberRequest{
+			Actor:   entity.ShopActor{UserId: "USR-1"},
+			MinRole: entity.ShopMemberRoleStaff,
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("AuthorizeShopMember_user is not a member_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-1").Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		err := ucTest.membershipUsecase.AuthorizeShopMember(context.Background(), &entity.AuthorizeShopMemberRequest{
+			ShopId:  "SHP-1",
+			Actor:   entity.ShopActor{UserId: "USR-1"},
+			MinRole: entity.ShopMemberRoleStaff,
+		})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+	})
+	t.Run("AuthorizeShopMember_role is lower than the minimum role_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-1").Return(&entity.ShopMember{Role: entity.ShopMemberRoleStaff}, nil).Once()
+
+		err := ucTest.membershipUsecase.AuthorizeShopMember(context.Background(), &entity.AuthorizeShopMemberRequest{
+			ShopId:  "SHP-1",
+			Actor:   entity.ShopActor{UserId: "USR-1"},
+			MinRole: entity.ShopMemberRoleManager,
+		})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+	})
+	t.Run("AuthorizeShopMember_role is higher than the minimum role_then return success", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-1").Return(&entity.ShopMember{Role: entity.ShopMemberRoleOwner}, nil).Once()
+
+		err := ucTest.membershipUsecase.AuthorizeShopMember(context.Background(), &entity.AuthorizeShopMemberRequest{
+			ShopId:  "SHP-1",
+			Actor:   entity.ShopActor{UserId: "USR-1"},
+			MinRole: entity.ShopMemberRoleManager,
+		})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestInviteShopMember(t *testing.T) {
+	email := "staff@mail.com"
+
+	t.Run("InviteShopMember_owner role_then return bad request error", func(t *testing.T) {
+		invitationId, err := ucTest.membershipUsecase.InviteShopMember(context.Background(), &entity.InviteShopMemberRequest{
+			ShopId:         "SHP-1",
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Role:           entity.ShopMemberRoleOwner,
+			Actor:          entity.ShopActor{UserId: "USR-1"},
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Empty(t, invitationId)
+	})
+	t.Run("InviteShopMember_manager invites manager_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-1").Return(&entity.ShopMember{Role: entity.ShopMemberRoleManager}, nil).Once()
+
+		invitationId, err := ucTest.membershipUsecase.InviteShopMember(context.Background(), &entity.InviteShopMemberRequest{
+			ShopId:         "SHP-1",
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Role:           entity.ShopMemberRoleManager,
+			Actor:          entity.ShopActor{UserId: "USR-1"},
+		})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+		assert.Empty(t, invitationId)
+	})
+	t.Run("InviteShopMember_manager invites staff_then send the invitation", func(t *testing.T) {
+		var invitation *entity.ShopInvitation
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-1").Return(&entity.ShopMember{Role: entity.ShopMemberRoleManager}, nil).Once()
+		ucTest.membershipRepo.On("InsertShopInvitation", mock.Anything, mock.AnythingOfType("*entity.ShopInvitation")).Run(func(args mock.Arguments) {
+			invitation = args.Get(1).(*entity.ShopInvitation)
+		}).Return(nil).Once()
+		ucTest.notifier.On("Notify", entity.IdentifierTypeEmail, email, mock.MatchedBy(func(message string) bool {
+			return strings.Contains(message, invitation.Id)
+		})).Return(nil).Once()
+
+		invitationId, err := ucTest.membershipUsecase.InviteShopMember(context.Background(), &entity.InviteShopMemberRequest{
+			ShopId:         "SHP-1",
+			IdentifierType: entity.IdentifierTypeEmail,
+			Identifier:     email,
+			Role:           entity.ShopMemberRoleStaff,
+			Actor:          entity.ShopActor{UserId: "USR-1"},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, invitation.Id, invitationId)
+		assert.Equal(t, "USR-1", invitation.InvitedBy)
+		assert.True(t, invitation.IsPending(time.Now()))
+	})
+}
+
+func TestAcceptShopInvitation(t *testing.T) {
+	email := "staff@mail.com"
+	invitation := &entity.ShopInvitation{
+		Id:             "INV-1",
+		ShopId:         "SHP-1",
+		IdentifierType: entity.IdentifierTypeEmail,
+		Identifier:     email,
+		Role:           entity.ShopMemberRoleStaff,
+		ExpiredAt:      time.Now().Add(time.Hour),
+	}
+
+	t.Run("AcceptShopInvitation_invitation is expired_then return conflict error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopInvitationById", mock.Anything, "INV-1").Return(&entity.ShopInvitation{
+			Id: "INV-1", ExpiredAt: time.Now().Add(-time.Hour),
+		}, nil).Once()
+
+		shopId, err := ucTest.membershipUsecase.AcceptShopInvitation(context.Background(), &entity.AcceptShopInvitationRequest{InvitationId: "INV-1", UserId: "USR-2"})
+
+		assert.Equal(t, errorutil.ErrConflict, errorutil.GetErrorType(err))
+		assert.Empty(t, shopId)
+	})
+	t.Run("AcceptShopInvitation_user is not the invited one_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopInvitationById", mock.Anything, "INV-1").Return(invitation, nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-2"}).Return(&entity.User{Id: "USR-2", Email: "other@mail.com"}, nil).Once()
+
+		shopId, err := ucTest.membershipUsecase.AcceptShopInvitation(context.Background(), &entity.AcceptShopInvitationRequest{InvitationId: "INV-1", UserId: "USR-2"})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+		assert.Empty(t, shopId)
+	})
+	t.Run("AcceptShopInvitation_user is the invited one_then add the member", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopInvitationById", mock.Anything, "INV-1").Return(invitation, nil).Once()
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-2"}).Return(&entity.User{Id: "USR-2", Email: email}, nil).Once()
+		mockUnitOfWork()
+		ucTest.membershipRepo.On("AcceptShopInvitation", mock.Anything, "INV-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.membershipRepo.On("InsertShopMember", mock.Anything, mock.MatchedBy(func(member *entity.ShopMember) bool {
+			return member.ShopId == "SHP-1" && member.UserId == "USR-2" && member.Role == entity.ShopMemberRoleStaff
+		})).Return(nil).Once()
+
+		shopId, err := ucTest.membershipUsecase.AcceptShopInvitation(context.Background(), &entity.AcceptShopInvitationRequest{InvitationId: "INV-1", UserId: "USR-2"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "SHP-1", shopId)
+	})
+}
+
+func TestRemoveShopMember(t *testing.T) {
+	t.Run("RemoveShopMember_member is the owner_then return bad request error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-1").Return(&entity.ShopMember{Role: entity.ShopMemberRoleManager}, nil).Once()
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-2").Return(&entity.ShopMember{Role: entity.ShopMemberRoleOwner}, nil).Once()
+
+		err := ucTest.membershipUsecase.RemoveShopMember(context.Background(), &entity.RemoveShopMemberRequest{ShopId: "SHP-1", UserId: "USR-2", Actor: entity.ShopActor{UserId: "USR-1"}})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("RemoveShopMember_manager removes manager_then return forbidden error", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-1").Return(&entity.ShopMember{Role: entity.ShopMemberRoleManager}, nil).Once()
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-2").Return(&entity.ShopMember{Role: entity.ShopMemberRoleManager}, nil).Once()
+
+		err := ucTest.membershipUsecase.RemoveShopMember(context.Background(), &entity.RemoveShopMemberRequest{ShopId: "SHP-1", UserId: "USR-2", Actor: entity.ShopActor{UserId: "USR-1"}})
+
+		assert.Equal(t, errorutil.ErrForbidden, errorutil.GetErrorType(err))
+	})
+	t.Run("RemoveShopMember_owner removes manager_then return success", func(t *testing.T) {
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-1").Return(&entity.ShopMember{Role: entity.ShopMemberRoleOwner}, nil).Once()
+		ucTest.membershipRepo.On("GetShopMember", mock.Anything, "SHP-1", "USR-2").Return(&entity.ShopMember{Role: entity.ShopMemberRoleManager}, nil).Once()
+		ucTest.membershipRepo.On("DeleteShopMember", mock.Anything, "SHP-1", "USR-2").Return(nil).Once()
+
+		err := ucTest.membershipUsecase.RemoveShopMember(context.Background(), &entity.RemoveShopMemberRequest{ShopId: "SHP-1", UserId: "USR-2", Actor: entity.ShopActor{UserId: "USR-1"}})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	t.Run("CreateAPIKey_scope is not allowed_then return bad request error", func(t *testing.T) {
+		resp, err := ucTest.apiKeyUsecase.CreateAPIKey(context.Background(), &entity.CreateAPIKeyRequest{
+			Name:   "erp",
+			Scopes: []string{entity.PermissionRoleAssign},
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Nil(t, resp)
+	})
+	t.Run("CreateAPIKey_expired at is in the past_then return bad request error", func(t *testing.T) {
+		expiredAt := time.Now().Add(-time.Hour)
+
+		resp, err := ucTest.apiKeyUsecase.CreateAPIKey(context.Background(), &entity.CreateAPIKeyRequest{
+			Name:      "erp",
+			Scopes:    []string{entity.PermissionWarehouseRead},
+			ExpiredAt: &expiredAt,
+		})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Nil(t, resp)
+	})
+	t.Run("CreateAPIKey_correct payload_then return the key and store its hash", func(t *testing.T) {
+		var apiKey *entity.APIKey
+		ucTest.apiKeyRepo.On("InsertAPIKey", mock.Anything, mock.AnythingOfType("*entity.APIKey")).Run(func(args mock.Arguments) {
+			apiKey = args.Get(1).(*entity.APIKey)
+		}).Return(nil).Once()
+
+		resp, err := ucTest.apiKeyUsecase.CreateAPIKey(context.Background(), &entity.CreateAPIKeyRequest{
+			Name:    "erp",
+			Scopes:  []string{entity.PermissionWarehouseRead, entity.PermissionStockWrite},
+			ActorId: "USR-1",
+		})
+
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(resp.Key, apiKey.Prefix))
+		assert.NotEqual(t, resp.Key, apiKey.KeyHash)
+		assert.Equal(t, "USR-1", apiKey.CreatedBy)
+		assert.Nil(t, apiKey.ExpiredAt)
+	})
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	t.Run("VerifyAPIKey_key is unknown_then return unauthorized error", func(t *testing.T) {
+		ucTest.apiKeyRepo.On("GetAPIKeyByHash", mock.Anything, mock.AnythingOfType("string")).Return(nil, errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		apiKey, err := ucTest.apiKeyUsecase.VerifyAPIKey(context.Background(), "xxx")
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, apiKey)
+	})
+	t.Run("VerifyAPIKey_key is expired_then return unauthorized error", func(t *testing.T) {
+		expiredAt := time.Now().Add(-time.Minute)
+		ucTest.apiKeyRepo.On("GetAPIKeyByHash", mock.Anything, mock.AnythingOfType("string")).Return(&entity.APIKey{Id: "APK-1", ExpiredAt: &expiredAt}, nil).Once()
+
+		apiKey, err := ucTest.apiKeyUsecase.VerifyAPIKey(context.Background(), "xxx")
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, apiKey)
+	})
+	t.Run("VerifyAPIKey_key is revoked_then return unauthorized error", func(t *testing.T) {
+		revokedAt := time.Now().Add(-time.Minute)
+		ucTest.apiKeyRepo.On("GetAPIKeyByHash", mock.Anything, mock.AnythingOfType("string")).Return(&entity.APIKey{Id: "APK-1", RevokedAt: &revokedAt}, nil).Once()
+
+		apiKey, err := ucTest.apiKeyUsecase.VerifyAPIKey(context.Background(), "xxx")
+
+		assert.Equal(t, errorutil.ErrUnauthorized, errorutil.GetErrorType(err))
+		assert.Nil(t, apiKey)
+	})
+	t.Run("VerifyAPIKey_key is used just now_then return the key without tracking", func(t *testing.T) {
+		lastUsedAt := time.Now().Add(-time.Second)
+		ucTest.apiKeyRepo.On("GetAPIKeyByHash", mock.Anything, mock.AnythingOfType("string")).Return(&entity.APIKey{Id: "APK-1", LastUsedAt: &lastUsedAt}, nil).Once()
+
+		apiKey, err := ucTest.apiKeyUsecase.VerifyAPIKey(context.Background(), "xxx")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "APK-1", apiKey.Id)
+	})
+	t.Run("VerifyAPIKey_tracking error_then still return the key", func(t *testing.T) {
+		ucTest.apiKeyRepo.On("GetAPIKeyByHash", mock.Anything, mock.AnythingOfType("string")).Return(&entity.APIKey{
+			Id: "APK-1", Scopes: []string{entity.PermissionStockWrite},
+		}, nil).Once()
+		ucTest.apiKeyRepo.On("UpdateAPIKeyLastUsedAt", mock.Anything, "APK-1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(errors.New("")).Once()
+
+		apiKey, err := ucTest.apiKeyUsecase.VerifyAPIKey(context.Background(), "xxx")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{entity.PermissionStockWrite}, apiKey.Scopes)
+	})
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	t.Run("RevokeAPIKey_empty id_then return bad request error", func(t *testing.T) {
+		err := ucTest.apiKeyUsecase.RevokeAPIKey(context.Background(), "")
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("RevokeAPIKey_key is not found_then return not found error", func(t *testing.T) {
+		ucTest.apiKeyRepo.On("RevokeAPIKey", mock.Anything, "APK-1", mock.AnythingOfType("time.Time")).Return(errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New(""))).Once()
+
+		err := ucTest.apiKeyUsecase.RevokeAPIKey(context.Background(), "APK-1")
+
+		assert.Equal(t, errorutil.ErrNotFound, errorutil.GetErrorType(err))
+	})
+}
+
+func TestExportUserData(t *testing.T) {
+	t.Run("ExportUserData_user id is empty_then return bad request error", func(t *testing.T) {
+		export, err := ucTest.privacyUsecase.ExportUserData(context.Background(), &entity.UserDataRequest{Channel: entity.PrivacyRequestChannelAPI})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Nil(t, export)
+	})
+	t.Run("ExportUserData_user has orders_then group items and payments by order and audit the request", func(t *testing.T) {
+		orders := []*entity.Order{{Id: "ORD-1", UserId: "USR-1"}, {Id: "ORD-2", UserId: "USR-1"}}
+
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-1"}).Return(&entity.User{Id: "USR-1", Email: "me@mail.com"}, nil).Once()
+		ucTest.privacyRepo.On("GetAllAddresses", mock.Anything, "USR-1").Return([]*entity.Address{{Id: "ADR-1"}}, nil).Once()
+		ucTest.privacyRepo.On("GetOrdersByUserId", mock.Anything, "USR-1").Return(orders, nil).Once()
+		ucTest.privacyRepo.On("GetOrderItemsByOrderIds", mock.Anything, []string{"ORD-1", "ORD-2"}).Return([]*entity.OrderItem{
+			{OrderId: "ORD-1", ProductId: "PRD-1"}, {OrderId: "ORD-1", ProductId: "PRD-2"}, {OrderId: "ORD-2", ProductId: "PRD-1"},
+		}, nil).Once()
+		ucTest.privacyRepo.On("GetPaymentsByOrderIds", mock.Anything, []string{"ORD-1", "ORD-2"}).Return([]*entity.Payment{{Id: "PAY-1", OrderId: "ORD-2"}}, nil).Once()
+		ucTest.privacyRepo.On("InsertPrivacyRequest", mock.Anything, mock.MatchedBy(func(privacyRequest *entity.PrivacyRequest) bool {
+			return privacyRequest.UserId == "USR-1" && privacyRequest.Type == entity.PrivacyRequestTypeExport &&
+				privacyRequest.RequestedBy == "USR-1" && privacyRequest.CompletedAt != nil
+		})).Return(nil).Once()
+
+		export, err := ucTest.privacyUsecase.ExportUserData(context.Background(), &entity.UserDataRequest{
+			UserId:      "USR-1",
+			Channel:     entity.PrivacyRequestChannelAPI,
+			RequestedBy: "USR-1",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "me@mail.com", export.Profile.Email)
+		assert.Len(t, export.Addresses, 1)
+		assert.Len(t, export.Orders, 2)
+		assert.Len(t, export.Orders[0].Items, 2)
+		assert.Empty(t, export.Orders[0].Payments)
+		assert.Len(t, export.Orders[1].Items, 1)
+		assert.Len(t, export.Orders[1].Payments, 1)
+	})
+}
+
+func TestDeleteUserData(t *testing.T) {
+	user := &entity.User{Id: "USR-1", Email: "me@mail.com"}
+	req := &entity.UserDataRequest{UserId: "USR-1", Channel: entity.PrivacyRequestChannelCLI}
+
+	t.Run("DeleteUserData_user owns a shop_then return bad request error", func(t *testing.T) {
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-1"}).Return(user, nil).Once()
+		ucTest.privacyRepo.On("CountOwnedShops", mock.Anything, "USR-1").Return(1, nil).Once()
+
+		err := ucTest.privacyUsecase.DeleteUserData(context.Background(), req)
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+	})
+	t.Run("DeleteUserData_anonymizing fails_then rollback and keep the request uncompleted", func(t *testing.T) {
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-1"}).Return(user, nil).Once()
+		ucTest.privacyRepo.On("CountOwnedShops", mock.Anything, "USR-1").Return(0, nil).Once()
+		ucTest.privacyRepo.On("InsertPrivacyRequest", mock.Anything, mock.MatchedBy(func(privacyRequest *entity.PrivacyRequest) bool {
+			return privacyRequest.Type == entity.PrivacyRequestTypeDeletion && privacyRequest.CompletedAt == nil
+		})).Return(nil).Once()
+		mockUnitOfWork()
+		ucTest.privacyRepo.On("AnonymizeUser", mock.Anything, "USR-1", mock.AnythingOfType("time.Time")).Return(errors.New("db error")).Once()
+
+		err := ucTest.privacyUsecase.DeleteUserData(context.Background(), req)
+
+		assert.NotNil(t, err)
+	})
+	t.Run("DeleteUserData_user exists_then anonymize, complete the request and revoke sessions", func(t *testing.T) {
+		var privacyRequestId string
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-1"}).Return(user, nil).Once()
+		ucTest.privacyRepo.On("CountOwnedShops", mock.Anything, "USR-1").Return(0, nil).Once()
+		ucTest.privacyRepo.On("InsertPrivacyRequest", mock.Anything, mock.AnythingOfType("*entity.PrivacyRequest")).Run(func(args mock.Arguments) {
+			privacyRequestId = args.Get(1).(*entity.PrivacyRequest).Id
+		}).Return(nil).Once()
+		mockUnitOfWork()
+		ucTest.privacyRepo.On("AnonymizeUser", mock.Anything, "USR-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.privacyRepo.On("AnonymizeAddresses", mock.Anything, "USR-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.privacyRepo.On("DeleteUserRecords", mock.Anything, user, mock.AnythingOfType("time.Time")).Return([]string{"SES-1"}, nil).Once()
+		ucTest.privacyRepo.On("CompletePrivacyRequest", mock.Anything, mock.MatchedBy(func(id string) bool {
+			return id == privacyRequestId
+		}), mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, "SES-1", 15*time.Minute).Return(nil).Once()
+
+		err := ucTest.privacyUsecase.DeleteUserData(context.Background(), req)
+
+		assert.Nil(t, err)
+	})
+	t.Run("DeleteUserData_revocation list is down_then the sessions are still revoked in the deletion", func(t *testing.T) {
+		// the sessions are revoked by DeleteUserRecords in the transaction, only the revocation list write fails
+		ucTest.userRepo.On("GetUser", mock.Anything, &entity.GetUserRequest{Id: "USR-1"}).Return(user, nil).Once()
+		ucTest.privacyRepo.On("CountOwnedShops", mock.Anything, "USR-1").Return(0, nil).Once()
+		ucTest.privacyRepo.On("InsertPrivacyRequest", mock.Anything, mock.AnythingOfType("*entity.PrivacyRequest")).Return(nil).Once()
+		mockUnitOfWork()
+		ucTest.privacyRepo.On("AnonymizeUser", mock.Anything, "USR-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.privacyRepo.On("AnonymizeAddresses", mock.Anything, "USR-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.privacyRepo.On("DeleteUserRecords", mock.Anything, user, mock.AnythingOfType("time.Time")).Return([]string{"SES-1", "SES-2"}, nil).Once()
+		ucTest.privacyRepo.On("CompletePrivacyRequest", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, "SES-1", 15*time.Minute).Return(errors.New("connection refused")).Times(3)
+		ucTest.redisRepo.On("RevokeSession", mock.Anything, "SES-2", 15*time.Minute).Return(nil).Once()
+
+		err := ucTest.privacyUsecase.DeleteUserData(context.Background(), req)
+
+		assert.Nil(t, err)
+		ucTest.privacyRepo.AssertCalled(t, "DeleteUserRecords", mock.Anything, user, mock.AnythingOfType("time.Time"))
+		ucTest.redisRepo.AssertCalled(t, "RevokeSession", mock.Anything, "SES-2", 15*time.Minute)
+	})
+}
+
+func TestAllowRateLimit(t *testing.T) {
+	rule := entity.RateLimitRule{KeyBy: entity.RateLimitKeyUser, Limit: 2, Window: time.Minute}
+
+	t.Run("AllowRateLimit_bad request_then return error", func(t *testing.T) {
+		result, err := ucTest.rateLimitUsecase.AllowRequest(context.Background(), &entity.RateLimitRequest{Rule: rule})
+
+		assert.Equal(t, errorutil.ErrBadRequest, errorutil.GetErrorType(err))
+		assert.Nil(t, result)
+	})
+	t.Run("AllowRateLimit_user rule with logged in user_then count by user", func(t *testing.T) {
+		expected := &entity.RateLimitResult{Allowed: true, Limit: 2, Remaining: 1}
+		ucTest.rateLimitRepo.On("AllowRequest", mock.Anything, "POST /order:user:USR-1", 2, time.Minute, mock.AnythingOfType("time.Time")).
+			Return(expected, nil).Once()
+
+		result, err := ucTest.rateLimitUsecase.AllowRequest(context.Background(), &entity.RateLimitRequest{
+			Route: "POST /order", Rule: rule, IP: "10.0.0.1", UserId: "USR-1",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("AllowRateLimit_user rule with api key_then count by api key", func(t *testing.T) {
+		expected := &entity.RateLimitResult{Allowed: false, Limit: 2, RetryAfter: time.Second}
+		ucTest.rateLimitRepo.On("AllowRequest", mock.Anything, "POST /order:apiKey:APK-1", 2, time.Minute, mock.AnythingOfType("time.Time")).
+			Return(expected, nil).Once()
+
+		result, err := ucTest.rateLimitUsecase.AllowRequest(context.Background(), &entity.RateLimitRequest{
+			Route: "POST /order", Rule: rule, IP: "10.0.0.1", APIKeyId: "APK-1",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("AllowRateLimit_anonymous caller_then count by ip", func(t *testing.T) {
+		expected := &entity.RateLimitResult{Allowed: true, Limit: 2, Remaining: 1}
+		ucTest.rateLimitRepo.On("AllowRequest", mock.Anything, "POST /order:ip:10.0.0.1", 2, time.Minute, mock.AnythingOfType("time.Time")).
+			Return(expected, nil).Once()
+
+		result, err := ucTest.rateLimitUsecase.AllowRequest(context.Background(), &entity.RateLimitRequest{
+			Route: "POST /order", Rule: rule, IP: "10.0.0.1",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("AllowRateLimit_identifier rule_then count by the hash of the identifier in any case", func(t *testing.T) {
+		identifierRule := entity.RateLimitRule{KeyBy: entity.RateLimitKeyIdentifier, Limit: 5, Window: 15 * time.Minute}
+		var keys []string
+		ucTest.rateLimitRepo.On("AllowRequest", mock.Anything, mock.AnythingOfType("string"), 5, 15*time.Minute, mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) {
+				keys = append(keys, args.String(1))
+			}).
+			Return(&entity.RateLimitResult{Allowed: true, Limit: 5, Remaining: 4}, nil).Twice()
+
+		for _, identifier := range []string{"user@mail.com", " User@Mail.com"} {
+			_, err := ucTest.rateLimitUsecase.AllowRequest(context.Background(), &entity.RateLimitRequest{
+				Route: "POST /user/login", Rule: identifierRule, IP: "10.0.0.1", Identifier: identifier,
+			})
+			assert.Nil(t, err)
+		}
+
+		assert.Len(t, keys, 2)
+		assert.Equal(t, keys[0], keys[1])
+		assert.True(t, strings.HasPrefix(keys[0], "POST /user/login:identifier:"))
+		assert.NotContains(t, keys[0], "user@mail.com")
+	})
+	t.Run("AllowRateLimit_limiter is unavailable_then allow the request", func(t *testing.T) {
+		ucTest.rateLimitRepo.On("AllowRequest", mock.Anything, "POST /order:ip:10.0.0.1", 2, time.Minute, mock.AnythingOfType("time.Time")).
+			Return(nil, errors.New("")).Once()
+
+		result, err := ucTest.rateLimitUsecase.AllowRequest(context.Background(), &entity.RateLimitRequest{
+			Route: "POST /order", Rule: rule, IP: "10.0.0.1",
+		})
+
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+}
+
+func TestCheckReadiness(t *testing.T) {
+	mockHealth := func(errDatabase, errRedis error, version *entity.MigrationVersion) {
+		ucTest.healthRepo.On("PingDatabase", mock.Anything).Return(errDatabase).Once()
+		ucTest.healthRepo.On("PingRedis", mock.Anything).Return(errRedis).Once()
+		ucTest.healthRepo.On("GetMigrationVersion", mock.Anything).Return(version, nil).Once()
+	}
+
+	t.Run("CheckReadiness_all dependencies are up_then return ok", func(t *testing.T) {
+		mockHealth(nil, nil, &entity.MigrationVersion{Version: 3})
+
+		report := ucTest.healthUsecase.CheckReadiness(context.Background())
+
+		assert.Equal(t, entity.HealthStatusOk, report.Status)
+		assert.True(t, report.IsReady())
+		assert.Len(t, report.Dependencies, 3)
+	})
+	t.Run("CheckReadiness_redis is down_then return degraded but ready", func(t *testing.T) {
+		mockHealth(nil, errors.New("error cache repo ping redis: connection refused"), &entity.MigrationVersion{Version: 3})
+
+		report := ucTest.healthUsecase.CheckReadiness(context.Background())
+
+		assert.Equal(t, entity.HealthStatusDegraded, report.Status)
+		assert.True(t, report.IsReady())
+		assert.Equal(t, entity.HealthStatusDown, report.Dependencies[entity.HealthDependencyRedis].Status)
+		body, err := json.Marshal(report)
+		assert.Nil(t, err)
+		assert.NotContains(t, string(body), "connection refused")
+	})
+	t.Run("CheckReadiness_schema is not migrated_then return down", func(t *testing.T) {
+		mockHealth(nil, nil, &entity.MigrationVersion{Version: 2})
+
+		report := ucTest.healthUsecase.CheckReadiness(context.Background())
+
+		assert.Equal(t, entity.HealthStatusDown, report.Status)
+		assert.False(t, report.IsReady())
+		assert.Equal(t, "schema version 2 is behind 3", report.Dependencies[entity.HealthDependencyMigration].Error)
+	})
+	t.Run("CheckReadiness_schema is ahead_then return ok", func(t *testing.T) {
+		mockHealth(nil, nil, &entity.MigrationVersion{Version: 4})
+
+		report := ucTest.healthUsecase.CheckReadiness(context.Background())
+
+		assert.Equal(t, entity.HealthStatusOk, report.Status)
+		assert.Equal(t, entity.HealthStatusOk, report.Dependencies[entity.HealthDependencyMigration].Status)
+	})
+	t.Run("CheckReadiness_database does not respond_then return down after the timeout", func(t *testing.T) {
+		ucTest.healthRepo.On("PingDatabase", mock.Anything).Return(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).Once()
+		ucTest.healthRepo.On("PingRedis", mock.Anything).Return(nil).Once()
+		ucTest.healthRepo.On("GetMigrationVersion", mock.Anything).Return(&entity.MigrationVersion{Version: 3}, nil).Once()
+
+		start := time.Now()
+		report := ucTest.healthUsecase.CheckReadiness(context.Background())
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, entity.HealthStatusDown, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies[entity.HealthDependencyDatabase].Error)
+	})
+}
+
+// matchProductDetailsRequest matches the request of the order products, its time is now
+func matchProductDetailsRequest(shopId string, productIds ...string) interface{} {
+	return mock.MatchedBy(func(req *entity.GetProductDetailsByShopIdRequest) bool {
+		return req.ShopId == shopId && slices.Equal(req.ProductIds, productIds) && !req.At.IsZero()
+	})
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mfawzanid/warehouse-commerce/core/entity"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newGetUserRequest(identifierType, identifier string) *entity.GetUserRequest {
+	req := &entity.GetUserRequest{}
+	if identifierType == entity.IdentifierTypeEmail {
+		req.Email = identifier
+	} else if identifierType == entity.IdentifierTypePhoneNumber {
+		req.PhoneNumber = identifier
+	}
+	return req
+}
+
+// newUser returns user with the identifier and the hash of the password, the identifier is verified by the register otp
+func newUser(userId, identifierType, identifier, password string, timeNow time.Time) (*entity.User, error) {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return nil, fmt.Errorf("error create user in hashing password: %v", err.Error())
+	}
+
+	user := &entity.User{
+		Id:           userId,
+		PasswordHash: string(passwordHash),
+		CreatedAt:    timeNow,
+		UpdatedAt:    timeNow,
+	}
+	if identifierType == entity.IdentifierTypeEmail {
+		user.Email = identifier
+		user.EmailVerified = true
+	} else if identifierType == entity.IdentifierTypePhoneNumber {
+		user.PhoneNumber = identifier
+		user.PhoneNumberVerified = true
+	}
+	return user, nil
+}
+
+func newAddress(req *entity.UpsertAddressRequest, timeNow time.Time) *entity.Address {
+	return &entity.Address{
+		Id:            req.Id,
+		UserId:        req.UserId,
+		Label:         req.Label,
+		RecipientName: req.RecipientName,
+		PhoneNumber:   req.PhoneNumber,
+		Street:        req.Street,
+		City:          req.City,
+		Province:      req.Province,
+		PostalCode:    req.PostalCode,
+		Country:       req.Country,
+		IsDefault:     req.IsDefault,
+		CreatedAt:     timeNow,
+		UpdatedAt:     timeNow,
+	}
+}
+
+// newOtp returns otp record with the hash of the code and the code itself to be sent
+func newOtp(identifier, purpose string, timeNow time.Time) (*entity.Otp, string, error) {
+	otpId, err := serialutil.GenerateId(otpPrefixSerial)
+	if err != nil {
+		return nil, "", fmt.Errorf("error create otp in generating uuid: %v", err.Error())
+	}
+
+	code, err := gonanoid.Generate("0123456789", otpCodeLength)
+	if err != nil {
+		return nil, "", fmt.Errorf("error create otp in generating code: %v", err.Error())
+	}
+
+	return &entity.Otp{
+		Id:         otpId,
+		Identifier: identifier,
+		Purpose:    purpose,
+		CodeHash:   hashOtpCode(otpId, code),
+		ExpiredAt:  timeNow.Add(otpExpiredDuration),
+		CreatedAt:  timeNow,
+	}, code, nil
+}
+
+// hashOtpCode hashes the code with the otp id as salt, so the same code of different otps has different hash
+func hashOtpCode(otpId, code string) string {
+	hash := sha256.Sum256([]byte(otpId + ":" + code))
+	return hex.EncodeToString(hash[:])
+}
+
+// verifyOtp verifies the code against the latest otp of the identifier and marks the otp as used
+func (u *userUsecase) verifyOtp(ctx context.Context, identifier, purpose, code string) error {
+	otp, err := u.userRepo.GetLatestOtp(ctx, identifier, purpose)
+	if err != nil {
+		if errorutil.GetErrorType(err) == errorutil.ErrNotFound {
+			return errorutil.NewErrorCode(errorutil.ErrUnauthorized, fmt.Errorf("error verify otp: otp with %s purpose is not requested", purpose))
+		}
+		return err
+	}
+
+	timeNow := time.Now()
+	if !otp.IsUsable(timeNow) {
+		return errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify otp: otp is expired or used, request a new one"))
+	}
+
+	// the attempt is counted before the code is compared, so a wrong code can not be retried more than the max attempts
+	if err := u.userRepo.IncreaseOtpAttempts(ctx, otp.Id, otpMaxAttempts); err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashOtpCode(otp.Id, code)), []byte(otp.CodeHash)) != 1 {
+		return errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error verify otp: otp code is wrong"))
+	}
+
+	return u.userRepo.ConsumeOtp(ctx, otp.Id, timeNow)
+}
@@ -1,85 +1,287 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/notifier"
 	"mfawzanid/warehouse-commerce/core/repository"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
 	serialutil "mfawzanid/warehouse-commerce/utils/serial"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 type UserUsecaseInterface interface {
-	RegisterUser(req *entity.RegisterUserRequest) (token string, err error)
-	Login(req *entity.LoginRequest) (token string, err error)
+	// RequestOtp sends otp code to the identifier, the response does not tell whether the identifier is registered
+	RequestOtp(ctx context.Context, req *entity.RequestOtpRequest) error
+	// RegisterUser returns unique violation error if the identifier is registered, the user should login instead
+	RegisterUser(ctx context.Context, req *entity.RegisterUserRequest) (*entity.AuthToken, error)
+	Login(ctx context.Context, req *entity.LoginRequest) (*entity.AuthToken, error)
+
+	// profile
+	GetProfile(ctx context.Context, userId string) (*entity.User, error)
+	UpdateProfile(ctx context.Context, req *entity.UpdateProfileRequest) (*entity.User, error)
+	// ChangeIdentifier sets the email or phone number verified by an otp with verify purpose
+	ChangeIdentifier(ctx context.Context, req *entity.ChangeIdentifierRequest) (*entity.User, error)
+
+	// address
+	GetAddresses(ctx context.Context, userId string) ([]*entity.Address, error)
+	CreateAddress(ctx context.Context, req *entity.UpsertAddressRequest) (*entity.Address, error)
+	UpdateAddress(ctx context.Context, req *entity.UpsertAddressRequest) (*entity.Address, error)
+	DeleteAddress(ctx context.Context, userId, addressId string) error
 }
 
 type userUsecase struct {
 	userRepo    repository.UserRepositoryInterface
+	unitOfWork  repository.UnitOfWorkInterface
 	authUsecase AuthUsecaseInterface
+	notifier    notifier.NotifierInterface
+	logger      *slog.Logger
 }
 
 const (
-	userPrefixSerial = "USR"
+	userPrefixSerial    = "USR"
+	otpPrefixSerial     = "OTP"
+	addressPrefixSerial = "ADR"
+
+	otpCodeLength         = 6
+	otpExpiredDuration    = 5 * time.Minute
+	otpMaxAttempts        = 5 // max attempts to verify an otp, then a new otp must be requested
+	otpMaxRequests        = 3 // max otp requests of an identifier in the request window
+	otpRequestWindow      = 15 * time.Minute
+	passwordHashCost      = bcrypt.DefaultCost
+	errMsgWrongCredential = "identifier or password is wrong"
 )
 
-func NewUserUsecase(userRepo repository.UserRepositoryInterface, authUsecase AuthUsecaseInterface) UserUsecaseInterface {
-	return &userUsecase{userRepo, authUsecase}
+func NewUserUsecase(userRepo repository.UserRepositoryInterface, unitOfWork repository.UnitOfWorkInterface, authUsecase AuthUsecaseInterface, notifier notifier.NotifierInterface, logger *slog.Logger) UserUsecaseInterface {
+	return &userUsecase{userRepo, unitOfWork, authUsecase, notifier, logger}
+}
+
+func (u *userUsecase) RequestOtp(ctx context.Context, req *entity.RequestOtpRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	timeNow := time.Now()
+
+	count, err := u.userRepo.CountOtpsSince(ctx, req.Identifier, timeNow.Add(-otpRequestWindow))
+	if err != nil {
+		return err
+	}
+	if count >= otpMaxRequests {
+		return errorutil.NewErrorCode(errorutil.ErrTooManyRequests, fmt.Errorf("error request otp: max %d otp requests in %v, try again later", otpMaxRequests, otpRequestWindow))
+	}
+
+	// otp is only sent to register or verify new identifier, or to login registered one
+	_, err = u.userRepo.GetUser(ctx, newGetUserRequest(req.IdentifierType, req.Identifier))
+	if err != nil && errorutil.GetErrorType(err) != errorutil.ErrNotFound {
+		return err
+	}
+	isRegistered := err == nil
+	if isRegistered != (req.Purpose == entity.OtpPurposeLogin) {
+		return nil
+	}
+
+	otp, code, err := newOtp(req.Identifier, req.Purpose, timeNow)
+	if err != nil {
+		return err
+	}
+
+	if err := u.userRepo.InsertOtp(ctx, otp); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("your %s code is %s, it expires in %d minutes", req.Purpose, code, int(otpExpiredDuration.Minutes()))
+	if err := u.notifier.Notify(req.IdentifierType, req.Identifier, message); err != nil {
+		return err
+	}
+
+	return nil
 }
 
-func (u *userUsecase) RegisterUser(req *entity.RegisterUserRequest) (token string, err error) {
+func (u *userUsecase) RegisterUser(ctx context.Context, req *entity.RegisterUserRequest) (*entity.AuthToken, error) {
 	if err := req.Validate(); err != nil {
-		return "", err
+		return nil, err
+	}
+
+	// the otp is verified first, so only the owner of the identifier can know whether it is registered
+	if err := u.verifyOtp(ctx, req.Identifier, entity.OtpPurposeRegister, req.OtpCode); err != nil {
+		return nil, err
+	}
+
+	_, err := u.userRepo.GetUser(ctx, newGetUserRequest(req.IdentifierType, req.Identifier))
+	if err == nil {
+		return nil, errorutil.NewErrorCode(errorutil.ErrUniqueViolation, errors.New("error register user: identifier is registered, login instead"))
+	}
+	if errorutil.GetErrorType(err) != errorutil.ErrNotFound {
+		return nil, err
 	}
 
 	userId, err := serialutil.GenerateId(userPrefixSerial)
 	if err != nil {
-		return "", fmt.Errorf("error register user in generating uuid: %v", err.Error())
+		return nil, fmt.Errorf("error register user in generating uuid: %v", err.Error())
 	}
 
-	var email, phoneNumber string
-	if req.IdentifierType == entity.IdentifierTypeEmail {
-		email = req.Identifier
-	} else if req.IdentifierType == entity.IdentifierTypePhoneNumber {
-		phoneNumber = req.Identifier
+	user, err := newUser(userId, req.IdentifierType, req.Identifier, req.Password, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
-	err = u.userRepo.InsertUser(&entity.User{
-		Id:          userId,
-		Email:       email,
-		PhoneNumber: phoneNumber,
-	})
+	err = u.userRepo.InsertUser(ctx, user)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	token, err = u.authUsecase.CreateToken(userId)
+	u.logger.InfoContext(ctx, "user is registered", "userId", userId, "identifierType", req.IdentifierType)
+
+	return u.authUsecase.CreateSession(ctx, userId)
+}
+
+func (u *userUsecase) Login(ctx context.Context, req *entity.LoginRequest) (*entity.AuthToken, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if req.OtpCode != "" {
+		if err := u.verifyOtp(ctx, req.Identifier, entity.OtpPurposeLogin, req.OtpCode); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := u.userRepo.GetUser(ctx, newGetUserRequest(req.IdentifierType, req.Identifier))
 	if err != nil {
-		return "", err
+		if errorutil.GetErrorType(err) == errorutil.ErrNotFound {
+			return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error login: "+errMsgWrongCredential))
+		}
+		return nil, err
+	}
+
+	if req.Password != "" {
+		if user.PasswordHash == "" {
+			return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error login: password is not set, login with otp"))
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+			return nil, errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("error login: "+errMsgWrongCredential))
+		}
 	}
 
-	return token, nil
+	return u.authUsecase.CreateSession(ctx, user.Id)
 }
 
-func (u *userUsecase) Login(req *entity.LoginRequest) (token string, err error) {
+func (u *userUsecase) GetProfile(ctx context.Context, userId string) (*entity.User, error) {
+	return u.userRepo.GetUser(ctx, &entity.GetUserRequest{Id: userId})
+}
+
+func (u *userUsecase) UpdateProfile(ctx context.Context, req *entity.UpdateProfileRequest) (*entity.User, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.userRepo.UpdateUserName(ctx, req.UserId, req.Name, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return u.userRepo.GetUser(ctx, &entity.GetUserRequest{Id: req.UserId})
+}
+
+func (u *userUsecase) ChangeIdentifier(ctx context.Context, req *entity.ChangeIdentifierRequest) (*entity.User, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.verifyOtp(ctx, req.Identifier, entity.OtpPurposeVerify, req.OtpCode); err != nil {
+		return nil, err
+	}
+
+	if err := u.userRepo.UpdateUserIdentifier(ctx, req.UserId, req.IdentifierType, req.Identifier, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return u.userRepo.GetUser(ctx, &entity.GetUserRequest{Id: req.UserId})
+}
+
+func (u *userUsecase) GetAddresses(ctx context.Context, userId string) ([]*entity.Address, error) {
+	if userId == "" {
+		return nil, errorutil.NewFieldValidationError("error get addresses", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+
+	return u.userRepo.GetAddresses(ctx, userId)
+}
+
+func (u *userUsecase) CreateAddress(ctx context.Context, req *entity.UpsertAddressRequest) (*entity.Address, error) {
 	if err := req.Validate(); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	getUserReq := &entity.GetUserRequest{}
-	if req.IdentifierType == entity.IdentifierTypeEmail {
-		getUserReq.Email = req.Identifier
-	} else if req.IdentifierType == entity.IdentifierTypePhoneNumber {
-		getUserReq.PhoneNumber = req.Identifier
+	addressId, err := serialutil.GenerateId(addressPrefixSerial)
+	if err != nil {
+		return nil, fmt.Errorf("error create address in generating uuid: %v", err.Error())
 	}
+	req.Id = addressId
 
-	user, err := u.userRepo.GetUser(getUserReq)
+	// the first address is the default one
+	addresses, err := u.userRepo.GetAddresses(ctx, req.UserId)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		req.IsDefault = true
+	}
+
+	address := newAddress(req, time.Now())
+
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if address.IsDefault {
+			if err := repos.User.UnsetDefaultAddress(ctx, address.UserId); err != nil {
+				return err
+			}
+		}
+		return repos.User.InsertAddress(ctx, address)
+	}); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (u *userUsecase) UpdateAddress(ctx context.Context, req *entity.UpsertAddressRequest) (*entity.Address, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
 
-	token, err = u.authUsecase.CreateToken(user.Id)
+	existing, err := u.userRepo.GetAddress(ctx, req.UserId, req.Id)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	address := newAddress(req, time.Now())
+	address.CreatedAt = existing.CreatedAt
+
+	if err := u.unitOfWork.Do(ctx, func(repos *repository.Repositories) error {
+		if address.IsDefault {
+			if err := repos.User.UnsetDefaultAddress(ctx, address.UserId); err != nil {
+				return err
+			}
+		}
+		return repos.User.UpdateAddress(ctx, address)
+	}); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (u *userUsecase) DeleteAddress(ctx context.Context, userId, addressId string) error {
+	if userId == "" {
+		return errorutil.NewFieldValidationError("error delete address", &errorutil.FieldError{Field: "userId", Message: "user id is mandatory"})
+	}
+	if addressId == "" {
+		return errorutil.NewFieldValidationError("error delete address", &errorutil.FieldError{Field: "addressId", Message: "address id is mandatory"})
 	}
 
-	return token, nil
+	return u.userRepo.DeleteAddress(ctx, userId, addressId, time.Now())
 }
@@ -12,12 +12,208 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 	"github.com/oapi-codegen/runtime"
 )
 
+// Defines values for AssignRolesRequestRoles.
+const (
+	Admin             AssignRolesRequestRoles = "admin"
+	Customer          AssignRolesRequestRoles = "customer"
+	Merchant          AssignRolesRequestRoles = "merchant"
+	WarehouseOperator AssignRolesRequestRoles = "warehouse_operator"
+)
+
+// Defines values for ChangeIdentifierRequestIdentifierType.
+const (
+	ChangeIdentifierRequestIdentifierTypeEmail       ChangeIdentifierRequestIdentifierType = "email"
+	ChangeIdentifierRequestIdentifierTypePhoneNumber ChangeIdentifierRequestIdentifierType = "phoneNumber"
+)
+
+// Defines values for CreateAPIKeyRequestScopes.
+const (
+	CatalogExport  CreateAPIKeyRequestScopes = "catalog:export"
+	CatalogImport  CreateAPIKeyRequestScopes = "catalog:import"
+	ProductRead    CreateAPIKeyRequestScopes = "product:read"
+	ProductWrite   CreateAPIKeyRequestScopes = "product:write"
+	ShopRead       CreateAPIKeyRequestScopes = "shop:read"
+	StockWrite     CreateAPIKeyRequestScopes = "stock:write"
+	WarehouseRead  CreateAPIKeyRequestScopes = "warehouse:read"
+	WarehouseWrite CreateAPIKeyRequestScopes = "warehouse:write"
+)
+
+// Defines values for DependencyHealthStatus.
+const (
+	DependencyHealthStatusDown DependencyHealthStatus = "down"
+	DependencyHealthStatusOk   DependencyHealthStatus = "ok"
+)
+
+// Defines values for HealthReportStatus.
+const (
+	HealthReportStatusDegraded HealthReportStatus = "degraded"
+	HealthReportStatusDown     HealthReportStatus = "down"
+	HealthReportStatusOk       HealthReportStatus = "ok"
+)
+
+// Defines values for InviteShopMemberRequestIdentifierType.
+const (
+	InviteShopMemberRequestIdentifierTypeEmail       InviteShopMemberRequestIdentifierType = "email"
+	InviteShopMemberRequestIdentifierTypePhoneNumber InviteShopMemberRequestIdentifierType = "phoneNumber"
+)
+
+// Defines values for InviteShopMemberRequestRole.
+const (
+	InviteShopMemberRequestRoleManager InviteShopMemberRequestRole = "manager"
+	InviteShopMemberRequestRoleStaff   InviteShopMemberRequestRole = "staff"
+)
+
+// Defines values for RequestOtpRequestPurpose.
+const (
+	Login    RequestOtpRequestPurpose = "login"
+	Register RequestOtpRequestPurpose = "register"
+	Verify   RequestOtpRequestPurpose = "verify"
+)
+
+// Defines values for ShopMemberRole.
+const (
+	ShopMemberRoleManager ShopMemberRole = "manager"
+	ShopMemberRoleOwner   ShopMemberRole = "owner"
+	ShopMemberRoleStaff   ShopMemberRole = "staff"
+)
+
+// Defines values for ExportCatalogParamsFormat.
+const (
+	ExportCatalogParamsFormatCsv   ExportCatalogParamsFormat = "csv"
+	ExportCatalogParamsFormatJsonl ExportCatalogParamsFormat = "jsonl"
+)
+
+// Defines values for ImportCatalogParamsFormat.
+const (
+	ImportCatalogParamsFormatCsv   ImportCatalogParamsFormat = "csv"
+	ImportCatalogParamsFormatJsonl ImportCatalogParamsFormat = "jsonl"
+)
+
+// APIKey defines model for APIKey.
+type APIKey struct {
+	CreatedAt  time.Time  `json:"createdAt"`
+	CreatedBy  string     `json:"createdBy"`
+	ExpiredAt  *time.Time `json:"expiredAt,omitempty"`
+	Id         string     `json:"id"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Scopes     []string   `json:"scopes"`
+}
+
+// AcceptShopInvitationResponse defines model for AcceptShopInvitationResponse.
+type AcceptShopInvitationResponse struct {
+	ShopId string `json:"shopId"`
+}
+
+// Address defines model for Address.
+type Address struct {
+	City          string    `json:"city"`
+	Country       string    `json:"country"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Id            string    `json:"id"`
+	IsDefault     bool      `json:"isDefault"`
+	Label         string    `json:"label"`
+	PhoneNumber   string    `json:"phoneNumber"`
+	PostalCode    string    `json:"postalCode"`
+	Province      string    `json:"province"`
+	RecipientName string    `json:"recipientName"`
+	Street        string    `json:"street"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// AssignRolesRequest defines model for AssignRolesRequest.
+type AssignRolesRequest struct {
+	Roles []AssignRolesRequestRoles `json:"roles"`
+}
+
+// AssignRolesRequestRoles defines model for AssignRolesRequest.Roles.
+type AssignRolesRequestRoles string
+
+// CatalogImportJob defines model for CatalogImportJob.
+type CatalogImportJob struct {
+	CreatedAt  time.Time         `json:"createdAt"`
+	DryRun     bool              `json:"dryRun"`
+	Errors     []CatalogRowError `json:"errors"`
+	FailedRows int               `json:"failedRows"`
+	Format     string            `json:"format"`
+
+	// Id Empty if the job is not run (dry run or some rows are invalid)
+	Id            *string `json:"id,omitempty"`
+	ProcessedRows int     `json:"processedRows"`
+
+	// Status running, succeeded or failed
+	Status        string    `json:"status"`
+	SucceededRows int       `json:"succeededRows"`
+	TotalRows     int       `json:"totalRows"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// CatalogRowError defines model for CatalogRowError.
+type CatalogRowError struct {
+	Error string  `json:"error"`
+	Line  int     `json:"line"`
+	Sku   *string `json:"sku,omitempty"`
+}
+
+// ChangeIdentifierRequest defines model for ChangeIdentifierRequest.
+type ChangeIdentifierRequest struct {
+	Identifier     string                                `json:"identifier"`
+	IdentifierType ChangeIdentifierRequestIdentifierType `json:"identifierType"`
+	OtpCode        string                                `json:"otpCode"`
+}
+
+// ChangeIdentifierRequestIdentifierType defines model for ChangeIdentifierRequest.IdentifierType.
+type ChangeIdentifierRequestIdentifierType string
+
+// CreateAPIKeyRequest defines model for CreateAPIKeyRequest.
+type CreateAPIKeyRequest struct {
+	// ExpiredAt The key never expires if it is empty
+	ExpiredAt *time.Time                  `json:"expiredAt,omitempty"`
+	Name      string                      `json:"name"`
+	Scopes    []CreateAPIKeyRequestScopes `json:"scopes"`
+}
+
+// CreateAPIKeyRequestScopes defines model for CreateAPIKeyRequest.Scopes.
+type CreateAPIKeyRequestScopes string
+
+// CreateAPIKeyResponse defines model for CreateAPIKeyResponse.
+type CreateAPIKeyResponse struct {
+	CreatedAt time.Time  `json:"createdAt"`
+	CreatedBy string     `json:"createdBy"`
+	ExpiredAt *time.Time `json:"expiredAt,omitempty"`
+	Id        string     `json:"id"`
+
+	// Key Send it in X-API-Key header
+	Key        string     `json:"key"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Scopes     []string   `json:"scopes"`
+}
+
+// CreatePriceListRequest defines model for CreatePriceListRequest.
+type CreatePriceListRequest struct {
+	EndAt   *time.Time      `json:"endAt,omitempty"`
+	Items   []PriceListItem `json:"items"`
+	Name    string          `json:"name"`
+	StartAt time.Time       `json:"startAt"`
+}
+
+// CreatePriceListResponse defines model for CreatePriceListResponse.
+type CreatePriceListResponse struct {
+	Id string `json:"id"`
+}
+
 // CreateProductRequest defines model for CreateProductRequest.
 type CreateProductRequest struct {
 	Enabled     bool   `json:"enabled"`
@@ -52,12 +248,57 @@ type CreateWarehouseResponse struct {
 	Id string `json:"id"`
 }
 
+// DependencyHealth defines model for DependencyHealth.
+type DependencyHealth struct {
+	Error     *string                `json:"error,omitempty"`
+	LatencyMs float32                `json:"latencyMs"`
+	Status    DependencyHealthStatus `json:"status"`
+}
+
+// DependencyHealthStatus defines model for DependencyHealth.Status.
+type DependencyHealthStatus string
+
+// GetAPIKeysResponse defines model for GetAPIKeysResponse.
+type GetAPIKeysResponse struct {
+	ApiKeys []APIKey `json:"apiKeys"`
+}
+
+// GetAddressesResponse defines model for GetAddressesResponse.
+type GetAddressesResponse struct {
+	Addresses []Address `json:"addresses"`
+}
+
+// GetPriceListsResponse defines model for GetPriceListsResponse.
+type GetPriceListsResponse struct {
+	PriceLists []PriceList `json:"priceLists"`
+}
+
+// GetProductPriceResponse defines model for GetProductPriceResponse.
+type GetProductPriceResponse struct {
+	At             time.Time  `json:"at"`
+	ChangedAt      *time.Time `json:"changedAt,omitempty"`
+	ChangedBy      *string    `json:"changedBy,omitempty"`
+	Price          int        `json:"price"`
+	PriceHistoryId *string    `json:"priceHistoryId,omitempty"`
+	PriceListId    *string    `json:"priceListId,omitempty"`
+	ProductId      string     `json:"productId"`
+	ShopId         *string    `json:"shopId,omitempty"`
+
+	// Source base, shop or priceList
+	Source string `json:"source"`
+}
+
 // GetProductsByShopIdResponse defines model for GetProductsByShopIdResponse.
 type GetProductsByShopIdResponse struct {
 	Pagination Pagination `json:"pagination"`
 	Products   []Product  `json:"products"`
 }
 
+// GetShopMembersResponse defines model for GetShopMembersResponse.
+type GetShopMembersResponse struct {
+	Members []ShopMember `json:"members"`
+}
+
 // GetShopsResponse defines model for GetShopsResponse.
 type GetShopsResponse struct {
 	Pagination Pagination `json:"pagination"`
@@ -70,14 +311,80 @@ type GetWarehousesResponse struct {
 	Warehouses []Warehouse `json:"warehouses"`
 }
 
+// HealthReport defines model for HealthReport.
+type HealthReport struct {
+	CheckedAt time.Time `json:"checkedAt"`
+
+	// Dependencies Health of the database, the migration and redis
+	Dependencies map[string]DependencyHealth `json:"dependencies"`
+	Status       HealthReportStatus          `json:"status"`
+}
+
+// HealthReportStatus defines model for HealthReport.Status.
+type HealthReportStatus string
+
+// InviteShopMemberRequest defines model for InviteShopMemberRequest.
+type InviteShopMemberRequest struct {
+	Identifier     string                                `json:"identifier"`
+	IdentifierType InviteShopMemberRequestIdentifierType `json:"identifierType"`
+	Role           InviteShopMemberRequestRole           `json:"role"`
+}
+
+// InviteShopMemberRequestIdentifierType defines model for InviteShopMemberRequest.IdentifierType.
+type InviteShopMemberRequestIdentifierType string
+
+// InviteShopMemberRequestRole defines model for InviteShopMemberRequest.Role.
+type InviteShopMemberRequestRole string
+
+// InviteShopMemberResponse defines model for InviteShopMemberResponse.
+type InviteShopMemberResponse struct {
+	Id string `json:"id"`
+}
+
+// JSONWebKey defines model for JSONWebKey.
+type JSONWebKey struct {
+	Alg string `json:"alg"`
+
+	// Crv Curve of OKP key
+	Crv *string `json:"crv,omitempty"`
+
+	// E RSA exponent
+	E   *string `json:"e,omitempty"`
+	Kid string  `json:"kid"`
+	Kty string  `json:"kty"`
+
+	// N RSA modulus
+	N   *string `json:"n,omitempty"`
+	Use string  `json:"use"`
+
+	// X OKP public key
+	X *string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet defines model for JSONWebKeySet.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
 // LoginRequest defines model for LoginRequest.
 type LoginRequest struct {
 	Identifier     string `json:"identifier"`
 	IdentifierType string `json:"identifierType"`
+
+	// OtpCode Otp code that is requested with login purpose, used if password is not set
+	OtpCode  *string `json:"otpCode,omitempty"`
+	Password *string `json:"password,omitempty"`
 }
 
 // LoginResponse defines model for LoginResponse.
 type LoginResponse struct {
+	// ExpiresIn Access token lifetime in seconds
+	ExpiresIn int `json:"expiresIn"`
+
+	// RefreshToken Refresh token that can be used once
+	RefreshToken string `json:"refreshToken"`
+
+	// Token Access token
 	Token string `json:"token"`
 }
 
@@ -90,6 +397,9 @@ type OrderProductItem struct {
 // OrderProductsRequest defines model for OrderProductsRequest.
 type OrderProductsRequest struct {
 	Items []OrderProductItem `json:"items"`
+
+	// ShippingAddressId Address in the address book of the user
+	ShippingAddressId *string `json:"shippingAddressId,omitempty"`
 }
 
 // OrderProductsResponse defines model for OrderProductsResponse.
@@ -99,10 +409,15 @@ type OrderProductsResponse struct {
 
 // Pagination defines model for Pagination.
 type Pagination struct {
-	Page      int `json:"page"`
-	PageSize  int `json:"pageSize"`
-	Total     int `json:"total"`
-	TotalPage int `json:"totalPage"`
+	// NextCursor Cursor to get the next page, empty if it is the last page.
+	NextCursor *string `json:"nextCursor,omitempty"`
+	Page       int     `json:"page"`
+	PageSize   int     `json:"pageSize"`
+
+	// PrevCursor Cursor to get the previous page, empty if it is the first page.
+	PrevCursor *string `json:"prevCursor,omitempty"`
+	Total      int     `json:"total"`
+	TotalPage  int     `json:"totalPage"`
 }
 
 // PayOrderRequest defines model for PayOrderRequest.
@@ -110,6 +425,46 @@ type PayOrderRequest struct {
 	Amount int `json:"amount"`
 }
 
+// PriceList defines model for PriceList.
+type PriceList struct {
+	EndAt   *time.Time      `json:"endAt,omitempty"`
+	Id      string          `json:"id"`
+	Items   []PriceListItem `json:"items"`
+	Name    string          `json:"name"`
+	ShopId  string          `json:"shopId"`
+	StartAt time.Time       `json:"startAt"`
+}
+
+// PriceListItem defines model for PriceListItem.
+type PriceListItem struct {
+	Price     int    `json:"price"`
+	ProductId string `json:"productId"`
+}
+
+// Problem RFC 7807 problem details
+type Problem struct {
+	// Code Machine-readable code, e.g. bad_request, unauthorized, forbidden, not_found, conflict, unique_violation, too_many_requests, unavailable, timeout or internal
+	Code string `json:"code"`
+
+	// Detail Message of the error, a server error has a generic one
+	Detail *string `json:"detail,omitempty"`
+
+	// Errors Invalid fields of a validation error
+	Errors *[]ProblemFieldError `json:"errors,omitempty"`
+
+	// RequestId Id of the request in X-Request-ID header, it is used to report the error
+	RequestId *string `json:"requestId,omitempty"`
+	Status    int     `json:"status"`
+	Title     string  `json:"title"`
+	Type      string  `json:"type"`
+}
+
+// ProblemFieldError defines model for ProblemFieldError.
+type ProblemFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 // Product defines model for Product.
 type Product struct {
 	Enabled     bool   `json:"enabled"`
@@ -120,23 +475,60 @@ type Product struct {
 	WarehouseId string `json:"warehouseId"`
 }
 
+// RefreshTokenRequest defines model for RefreshTokenRequest.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 // RegisterUserRequest defines model for RegisterUserRequest.
 type RegisterUserRequest struct {
 	Identifier     string `json:"identifier"`
 	IdentifierType string `json:"identifierType"`
+
+	// OtpCode Otp code that is requested with register purpose
+	OtpCode  string `json:"otpCode"`
+	Password string `json:"password"`
 }
 
 // RegisterUserResponse defines model for RegisterUserResponse.
 type RegisterUserResponse struct {
+	// ExpiresIn Access token lifetime in seconds
+	ExpiresIn int `json:"expiresIn"`
+
+	// RefreshToken Refresh token that can be used once
+	RefreshToken string `json:"refreshToken"`
+
+	// Token Access token
 	Token string `json:"token"`
 }
 
+// RequestOtpRequest defines model for RequestOtpRequest.
+type RequestOtpRequest struct {
+	Identifier     string                   `json:"identifier"`
+	IdentifierType string                   `json:"identifierType"`
+	Purpose        RequestOtpRequestPurpose `json:"purpose"`
+}
+
+// RequestOtpRequestPurpose defines model for RequestOtpRequest.Purpose.
+type RequestOtpRequestPurpose string
+
 // Shop defines model for Shop.
 type Shop struct {
 	Id   string `json:"id"`
 	Name string `json:"name"`
 }
 
+// ShopMember defines model for ShopMember.
+type ShopMember struct {
+	CreatedAt time.Time      `json:"createdAt"`
+	Role      ShopMemberRole `json:"role"`
+	ShopId    string         `json:"shopId"`
+	UserId    string         `json:"userId"`
+}
+
+// ShopMemberRole defines model for ShopMember.Role.
+type ShopMemberRole string
+
 // TransferProductRequest defines model for TransferProductRequest.
 type TransferProductRequest struct {
 	DestinationWarehouseId string `json:"destinationWarehouseId"`
@@ -145,17 +537,45 @@ type TransferProductRequest struct {
 	TotalStock             int    `json:"totalStock"`
 }
 
+// UpdateProductPriceRequest defines model for UpdateProductPriceRequest.
+type UpdateProductPriceRequest struct {
+	Price int `json:"price"`
+}
+
 // UpdateProductStockRequest defines model for UpdateProductStockRequest.
 type UpdateProductStockRequest struct {
 	TotalStock  int    `json:"totalStock"`
 	WarehouseId string `json:"warehouseId"`
 }
 
+// UpdateProfileRequest defines model for UpdateProfileRequest.
+type UpdateProfileRequest struct {
+	Name string `json:"name"`
+}
+
 // UpdateWarehouseStatusRequest defines model for UpdateWarehouseStatusRequest.
 type UpdateWarehouseStatusRequest struct {
 	Enabled bool `json:"enabled"`
 }
 
+// UpsertAddressRequest defines model for UpsertAddressRequest.
+type UpsertAddressRequest struct {
+	City          string  `json:"city"`
+	Country       string  `json:"country"`
+	IsDefault     *bool   `json:"isDefault,omitempty"`
+	Label         *string `json:"label,omitempty"`
+	PhoneNumber   string  `json:"phoneNumber"`
+	PostalCode    string  `json:"postalCode"`
+	Province      *string `json:"province,omitempty"`
+	RecipientName string  `json:"recipientName"`
+	Street        string  `json:"street"`
+}
+
+// UpsertShopProductPriceRequest defines model for UpsertShopProductPriceRequest.
+type UpsertShopProductPriceRequest struct {
+	Price int `json:"price"`
+}
+
 // UpsertShopToWarehousesRequest defines model for UpsertShopToWarehousesRequest.
 type UpsertShopToWarehousesRequest struct {
 	Enabled      bool     `json:"enabled"`
@@ -163,6 +583,26 @@ type UpsertShopToWarehousesRequest struct {
 	WarehouseIds []string `json:"warehouseIds"`
 }
 
+// User defines model for User.
+type User struct {
+	CreatedAt           time.Time `json:"createdAt"`
+	Email               *string   `json:"email,omitempty"`
+	EmailVerified       bool      `json:"emailVerified"`
+	Id                  string    `json:"id"`
+	Name                string    `json:"name"`
+	PhoneNumber         *string   `json:"phoneNumber,omitempty"`
+	PhoneNumberVerified bool      `json:"phoneNumberVerified"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+// UserDataExport defines model for UserDataExport.
+type UserDataExport struct {
+	Addresses  []Address                `json:"addresses"`
+	ExportedAt time.Time                `json:"exportedAt"`
+	Orders     []map[string]interface{} `json:"orders"`
+	Profile    User                     `json:"profile"`
+}
+
 // Warehouse defines model for Warehouse.
 type Warehouse struct {
 	Enabled bool   `json:"enabled"`
@@ -170,30 +610,116 @@ type Warehouse struct {
 	Name    string `json:"name"`
 }
 
+// Cursor defines model for Cursor.
+type Cursor = string
+
+// Filter defines model for Filter.
+type Filter = []string
+
+// SkipTotal defines model for SkipTotal.
+type SkipTotal = bool
+
+// Sort defines model for Sort.
+type Sort = string
+
+// ExportCatalogParams defines parameters for ExportCatalog.
+type ExportCatalogParams struct {
+	// Format Default is csv.
+	Format *ExportCatalogParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// ExportCatalogParamsFormat defines parameters for ExportCatalog.
+type ExportCatalogParamsFormat string
+
+// ImportCatalogParams defines parameters for ImportCatalog.
+type ImportCatalogParams struct {
+	// Format csv or jsonl, default is taken from Content-Type.
+	Format *ImportCatalogParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// DryRun Only validate the rows and return the report.
+	DryRun *bool `form:"dryRun,omitempty" json:"dryRun,omitempty"`
+}
+
+// ImportCatalogParamsFormat defines parameters for ImportCatalog.
+type ImportCatalogParamsFormat string
+
+// GetProductPriceParams defines parameters for GetProductPrice.
+type GetProductPriceParams struct {
+	ShopId *string `form:"shopId,omitempty" json:"shopId,omitempty"`
+
+	// At Point in time of the price, default is now.
+	At *time.Time `form:"at,omitempty" json:"at,omitempty"`
+}
+
 // GetShopsParams defines parameters for GetShops.
 type GetShopsParams struct {
-	Page     int `form:"page" json:"page"`
-	PageSize int `form:"pageSize" json:"pageSize"`
+	// Page Page number for offset pagination, it is ignored when cursor is set. Default is 1.
+	Page     *int `form:"page,omitempty" json:"page,omitempty"`
+	PageSize int  `form:"pageSize" json:"pageSize"`
+
+	// Sort Comma separated sort fields, prefix a field with '-' to sort descending (e.g. `-price,name`).
+	Sort *Sort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Filter Repeatable filter in `field:operator:value` form (e.g. `price:gte:1000`). Operators are eq, ne, gt, gte, lt, lte, prefix, contains and in (values separated by '|').
+	Filter *Filter `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Cursor Opaque cursor taken from `nextCursor` or `prevCursor` of previous response. It must be used with the same sort and filter.
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// SkipTotal Skip counting total rows, `total` and `totalPage` are returned as -1.
+	SkipTotal *SkipTotal `form:"skipTotal,omitempty" json:"skipTotal,omitempty"`
 }
 
 // GetProductsByShopIdParams defines parameters for GetProductsByShopId.
 type GetProductsByShopIdParams struct {
-	Page     int `form:"page" json:"page"`
-	PageSize int `form:"pageSize" json:"pageSize"`
+	// Page Page number for offset pagination, it is ignored when cursor is set. Default is 1.
+	Page     *int `form:"page,omitempty" json:"page,omitempty"`
+	PageSize int  `form:"pageSize" json:"pageSize"`
+
+	// Sort Comma separated sort fields, prefix a field with '-' to sort descending (e.g. `-price,name`).
+	Sort *Sort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Filter Repeatable filter in `field:operator:value` form (e.g. `price:gte:1000`). Operators are eq, ne, gt, gte, lt, lte, prefix, contains and in (values separated by '|').
+	Filter *Filter `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Cursor Opaque cursor taken from `nextCursor` or `prevCursor` of previous response. It must be used with the same sort and filter.
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// SkipTotal Skip counting total rows, `total` and `totalPage` are returned as -1.
+	SkipTotal *SkipTotal `form:"skipTotal,omitempty" json:"skipTotal,omitempty"`
 }
 
 // GetWarehousesParams defines parameters for GetWarehouses.
 type GetWarehousesParams struct {
-	Page     int `form:"page" json:"page"`
-	PageSize int `form:"pageSize" json:"pageSize"`
+	// Page Page number for offset pagination, it is ignored when cursor is set. Default is 1.
+	Page     *int `form:"page,omitempty" json:"page,omitempty"`
+	PageSize int  `form:"pageSize" json:"pageSize"`
+
+	// Sort Comma separated sort fields, prefix a field with '-' to sort descending (e.g. `-price,name`).
+	Sort *Sort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Filter Repeatable filter in `field:operator:value` form (e.g. `price:gte:1000`). Operators are eq, ne, gt, gte, lt, lte, prefix, contains and in (values separated by '|').
+	Filter *Filter `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// Cursor Opaque cursor taken from `nextCursor` or `prevCursor` of previous response. It must be used with the same sort and filter.
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// SkipTotal Skip counting total rows, `total` and `totalPage` are returned as -1.
+	SkipTotal *SkipTotal `form:"skipTotal,omitempty" json:"skipTotal,omitempty"`
 }
 
+// CreateAPIKeyJSONRequestBody defines body for CreateAPIKey for application/json ContentType.
+type CreateAPIKeyJSONRequestBody = CreateAPIKeyRequest
+
 // PayOrderJSONRequestBody defines body for PayOrder for application/json ContentType.
 type PayOrderJSONRequestBody = PayOrderRequest
 
 // TransferProductJSONRequestBody defines body for TransferProduct for application/json ContentType.
 type TransferProductJSONRequestBody = TransferProductRequest
 
+// UpdateProductPriceJSONRequestBody defines body for UpdateProductPrice for application/json ContentType.
+type UpdateProductPriceJSONRequestBody = UpdateProductPriceRequest
+
 // UpdateProductStockJSONRequestBody defines body for UpdateProductStock for application/json ContentType.
 type UpdateProductStockJSONRequestBody = UpdateProductStockRequest
 
@@ -206,9 +732,33 @@ type OrderProductsJSONRequestBody = OrderProductsRequest
 // CreateShopJSONRequestBody defines body for CreateShop for application/json ContentType.
 type CreateShopJSONRequestBody = CreateShopRequest
 
+// InviteShopMemberJSONRequestBody defines body for InviteShopMember for application/json ContentType.
+type InviteShopMemberJSONRequestBody = InviteShopMemberRequest
+
+// CreatePriceListJSONRequestBody defines body for CreatePriceList for application/json ContentType.
+type CreatePriceListJSONRequestBody = CreatePriceListRequest
+
+// UpsertShopProductPriceJSONRequestBody defines body for UpsertShopProductPrice for application/json ContentType.
+type UpsertShopProductPriceJSONRequestBody = UpsertShopProductPriceRequest
+
 // UpsertShopToWarehousesJSONRequestBody defines body for UpsertShopToWarehouses for application/json ContentType.
 type UpsertShopToWarehousesJSONRequestBody = UpsertShopToWarehousesRequest
 
+// UpdateProfileJSONRequestBody defines body for UpdateProfile for application/json ContentType.
+type UpdateProfileJSONRequestBody = UpdateProfileRequest
+
+// CreateAddressJSONRequestBody defines body for CreateAddress for application/json ContentType.
+type CreateAddressJSONRequestBody = UpsertAddressRequest
+
+// UpdateAddressJSONRequestBody defines body for UpdateAddress for application/json ContentType.
+type UpdateAddressJSONRequestBody = UpsertAddressRequest
+
+// ChangeIdentifierJSONRequestBody defines body for ChangeIdentifier for application/json ContentType.
+type ChangeIdentifierJSONRequestBody = ChangeIdentifierRequest
+
+// AssignRolesJSONRequestBody defines body for AssignRoles for application/json ContentType.
+type AssignRolesJSONRequestBody = AssignRolesRequest
+
 // CreateWarehouseJSONRequestBody defines body for CreateWarehouse for application/json ContentType.
 type CreateWarehouseJSONRequestBody = CreateWarehouseRequest
 
@@ -218,23 +768,59 @@ type UpdateWarehouseStatusJSONRequestBody = UpdateWarehouseStatusRequest
 // LoginJSONRequestBody defines body for Login for application/json ContentType.
 type LoginJSONRequestBody = LoginRequest
 
+// RequestOtpJSONRequestBody defines body for RequestOtp for application/json ContentType.
+type RequestOtpJSONRequestBody = RequestOtpRequest
+
+// RefreshTokenJSONRequestBody defines body for RefreshToken for application/json ContentType.
+type RefreshTokenJSONRequestBody = RefreshTokenRequest
+
 // RegisterUserJSONRequestBody defines body for RegisterUser for application/json ContentType.
 type RegisterUserJSONRequestBody = RegisterUserRequest
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// This endpoint returns the public keys to verify access tokens
+	// (GET /.well-known/jwks.json)
+	GetJwks(ctx echo.Context) error
+	// Get the api keys without the keys.
+	// (GET /api/v1/api-keys)
+	GetAPIKeys(ctx echo.Context) error
+	// Create an api key for a service, the key is only returned once.
+	// (POST /api/v1/api-keys)
+	CreateAPIKey(ctx echo.Context) error
+	// Revoke an api key.
+	// (DELETE /api/v1/api-keys/{apiKeyId})
+	RevokeAPIKey(ctx echo.Context, apiKeyId string) error
+	// Stream the catalog with stock per warehouse as CSV or JSON Lines.
+	// (GET /api/v1/catalog/export)
+	ExportCatalog(ctx echo.Context, params ExportCatalogParams) error
+	// Import products from CSV or JSON Lines, products are upserted by SKU in a background job.
+	// (POST /api/v1/catalog/import)
+	ImportCatalog(ctx echo.Context, params ImportCatalogParams) error
+	// Get progress and result of a catalog import job.
+	// (GET /api/v1/catalog/import-jobs/{jobId})
+	GetCatalogImportJob(ctx echo.Context, jobId string) error
 	// Pay an order.
 	// (POST /api/v1/order/{orderId}/pay)
 	PayOrder(ctx echo.Context, orderId string) error
 	// This endpoint transfers product from a warehouse to another.
 	// (POST /api/v1/product/transfer)
 	TransferProduct(ctx echo.Context) error
+	// Get product price at a point in time, in a shop if shop id is set.
+	// (GET /api/v1/product/{productId}/price)
+	GetProductPrice(ctx echo.Context, productId string, params GetProductPriceParams) error
+	// This endpoint updates product base price, the change is recorded in price history.
+	// (PUT /api/v1/product/{productId}/price)
+	UpdateProductPrice(ctx echo.Context, productId string) error
 	// This endpoint updates product total stock for a warehouse
 	// (PUT /api/v1/product/{productId}/stock)
 	UpdateProductStock(ctx echo.Context, productId string) error
 	// This endpoint creates product
 	// (POST /api/v1/products)
 	CreateProduct(ctx echo.Context) error
+	// Accept an invitation, the email or phone number of the user must be the invited one.
+	// (POST /api/v1/shop-invitations/{invitationId}/accept)
+	AcceptShopInvitation(ctx echo.Context, invitationId string) error
 	// Order products from a shop.
 	// (POST /api/v1/shop/{shopId}/order)
 	OrderProducts(ctx echo.Context, shopId string) error
@@ -244,12 +830,60 @@ type ServerInterface interface {
 	// This endpoint creates a shop.
 	// (POST /api/v1/shops)
 	CreateShop(ctx echo.Context) error
+	// Invite an email or phone number to be a member of a shop, the user must have a higher role than the invited role.
+	// (POST /api/v1/shops/{shopId}/invitations)
+	InviteShopMember(ctx echo.Context, shopId string) error
+	// Get the members of a shop, the user must be a member of the shop.
+	// (GET /api/v1/shops/{shopId}/members)
+	GetShopMembers(ctx echo.Context, shopId string) error
+	// Remove a member of a shop, the user must have a higher role than the member.
+	// (DELETE /api/v1/shops/{shopId}/members/{userId})
+	RemoveShopMember(ctx echo.Context, shopId string, userId string) error
+	// Get active and upcoming price lists of a shop.
+	// (GET /api/v1/shops/{shopId}/price-lists)
+	GetPriceLists(ctx echo.Context, shopId string) error
+	// Schedule a price list of a shop that is active between start and end time.
+	// (POST /api/v1/shops/{shopId}/price-lists)
+	CreatePriceList(ctx echo.Context, shopId string) error
 	// Get products from a shop.
 	// (GET /api/v1/shops/{shopId}/products)
 	GetProductsByShopId(ctx echo.Context, shopId string, params GetProductsByShopIdParams) error
+	// Set product price override for a shop.
+	// (PUT /api/v1/shops/{shopId}/products/{productId}/price)
+	UpsertShopProductPrice(ctx echo.Context, shopId string, productId string) error
 	// This endpoint sets or unsets shop to warehouses.
 	// (POST /api/v1/upsert-shop-warehouses)
 	UpsertShopToWarehouses(ctx echo.Context) error
+	// Delete the account, the personal data is anonymized while the orders and payments are kept for accounting.
+	// (DELETE /api/v1/users/me)
+	DeleteAccount(ctx echo.Context) error
+	// Get the profile of the user.
+	// (GET /api/v1/users/me)
+	GetProfile(ctx echo.Context) error
+	// Update the profile of the user.
+	// (PUT /api/v1/users/me)
+	UpdateProfile(ctx echo.Context) error
+	// Get the address book of the user, the default address is the first.
+	// (GET /api/v1/users/me/addresses)
+	GetAddresses(ctx echo.Context) error
+	// Add an address to the address book, the first address is the default one.
+	// (POST /api/v1/users/me/addresses)
+	CreateAddress(ctx echo.Context) error
+	// Delete an address of the user.
+	// (DELETE /api/v1/users/me/addresses/{addressId})
+	DeleteAddress(ctx echo.Context, addressId string) error
+	// Update an address of the user.
+	// (PUT /api/v1/users/me/addresses/{addressId})
+	UpdateAddress(ctx echo.Context, addressId string) error
+	// Export everything tied to the user as a JSON archive.
+	// (GET /api/v1/users/me/export)
+	ExportUserData(ctx echo.Context) error
+	// Set the email or phone number of the user, it is verified by an otp requested with verify purpose.
+	// (PUT /api/v1/users/me/identifier)
+	ChangeIdentifier(ctx echo.Context) error
+	// This endpoint replaces the roles of the user
+	// (PUT /api/v1/users/{userId}/roles)
+	AssignRoles(ctx echo.Context, userId string) error
 	// This endpoint gets warehouse list.
 	// (GET /api/v1/warehouses)
 	GetWarehouses(ctx echo.Context, params GetWarehousesParams) error
@@ -259,12 +893,27 @@ type ServerInterface interface {
 	// This endpoint updates warehouse status
 	// (PUT /api/v1/warehouses/{warehouseId}/status)
 	UpdateWarehouseStatus(ctx echo.Context, warehouseId string) error
-	// Health check endpoint
+	// Deprecated alias of /health/live
 	// (GET /health)
 	GetHealth(ctx echo.Context) error
+	// Liveness, the process is up. It does not check the dependencies, so an outage of them does not restart the server
+	// (GET /health/live)
+	GetHealthLive(ctx echo.Context) error
+	// Readiness, the server can take requests. It pings the database and redis and checks the schema version
+	// (GET /health/ready)
+	GetHealthReady(ctx echo.Context) error
 	// This endpoint logs in the user
 	// (POST /user/login)
 	Login(ctx echo.Context) error
+	// This endpoint revokes the session of the access token
+	// (POST /user/logout)
+	Logout(ctx echo.Context) error
+	// This endpoint sends otp code to the identifier to register or to login
+	// (POST /user/otp)
+	RequestOtp(ctx echo.Context) error
+	// This endpoint rotates the refresh token and returns new access token
+	// (POST /user/refresh)
+	RefreshToken(ctx echo.Context) error
 	// This endpoint registers new user
 	// (POST /user/register)
 	RegisterUser(ctx echo.Context) error
@@ -275,6 +924,108 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetJwks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJwks(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJwks(ctx)
+	return err
+}
+
+// GetAPIKeys converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAPIKeys(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAPIKeys(ctx)
+	return err
+}
+
+// CreateAPIKey converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateAPIKey(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateAPIKey(ctx)
+	return err
+}
+
+// RevokeAPIKey converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeAPIKey(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "apiKeyId" -------------
+	var apiKeyId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "apiKeyId", runtime.ParamLocationPath, ctx.Param("apiKeyId"), &apiKeyId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter apiKeyId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RevokeAPIKey(ctx, apiKeyId)
+	return err
+}
+
+// ExportCatalog converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportCatalog(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportCatalogParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ExportCatalog(ctx, params)
+	return err
+}
+
+// ImportCatalog converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportCatalog(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportCatalogParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "dryRun" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dryRun", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dryRun: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImportCatalog(ctx, params)
+	return err
+}
+
+// GetCatalogImportJob converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCatalogImportJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "jobId" -------------
+	var jobId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "jobId", runtime.ParamLocationPath, ctx.Param("jobId"), &jobId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter jobId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCatalogImportJob(ctx, jobId)
+	return err
+}
+
 // PayOrder converts echo context to params.
 func (w *ServerInterfaceWrapper) PayOrder(ctx echo.Context) error {
 	var err error
@@ -300,6 +1051,54 @@ func (w *ServerInterfaceWrapper) TransferProduct(ctx echo.Context) error {
 	return err
 }
 
+// GetProductPrice converts echo context to params.
+func (w *ServerInterfaceWrapper) GetProductPrice(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "productId" -------------
+	var productId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "productId", runtime.ParamLocationPath, ctx.Param("productId"), &productId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetProductPriceParams
+	// ------------- Optional query parameter "shopId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "shopId", ctx.QueryParams(), &params.ShopId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shopId: %s", err))
+	}
+
+	// ------------- Optional query parameter "at" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "at", ctx.QueryParams(), &params.At)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter at: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetProductPrice(ctx, productId, params)
+	return err
+}
+
+// UpdateProductPrice converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateProductPrice(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "productId" -------------
+	var productId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "productId", runtime.ParamLocationPath, ctx.Param("productId"), &productId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateProductPrice(ctx, productId)
+	return err
+}
+
 // UpdateProductStock converts echo context to params.
 func (w *ServerInterfaceWrapper) UpdateProductStock(ctx echo.Context) error {
 	var err error
@@ -325,6 +1124,22 @@ func (w *ServerInterfaceWrapper) CreateProduct(ctx echo.Context) error {
 	return err
 }
 
+// AcceptShopInvitation converts echo context to params.
+func (w *ServerInterfaceWrapper) AcceptShopInvitation(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "invitationId" -------------
+	var invitationId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "invitationId", runtime.ParamLocationPath, ctx.Param("invitationId"), &invitationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter invitationId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AcceptShopInvitation(ctx, invitationId)
+	return err
+}
+
 // OrderProducts converts echo context to params.
 func (w *ServerInterfaceWrapper) OrderProducts(ctx echo.Context) error {
 	var err error
@@ -347,9 +1162,9 @@ func (w *ServerInterfaceWrapper) GetShops(ctx echo.Context) error {
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetShopsParams
-	// ------------- Required query parameter "page" -------------
+	// ------------- Optional query parameter "page" -------------
 
-	err = runtime.BindQueryParameter("form", true, true, "page", ctx.QueryParams(), &params.Page)
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
 	}
@@ -361,6 +1176,34 @@ func (w *ServerInterfaceWrapper) GetShops(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
 	}
 
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "skipTotal" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "skipTotal", ctx.QueryParams(), &params.SkipTotal)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter skipTotal: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetShops(ctx, params)
 	return err
@@ -375,6 +1218,94 @@ func (w *ServerInterfaceWrapper) CreateShop(ctx echo.Context) error {
 	return err
 }
 
+// InviteShopMember converts echo context to params.
+func (w *ServerInterfaceWrapper) InviteShopMember(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shopId" -------------
+	var shopId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shopId", runtime.ParamLocationPath, ctx.Param("shopId"), &shopId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shopId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.InviteShopMember(ctx, shopId)
+	return err
+}
+
+// GetShopMembers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetShopMembers(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shopId" -------------
+	var shopId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shopId", runtime.ParamLocationPath, ctx.Param("shopId"), &shopId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shopId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetShopMembers(ctx, shopId)
+	return err
+}
+
+// RemoveShopMember converts echo context to params.
+func (w *ServerInterfaceWrapper) RemoveShopMember(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shopId" -------------
+	var shopId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shopId", runtime.ParamLocationPath, ctx.Param("shopId"), &shopId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shopId: %s", err))
+	}
+
+	// ------------- Path parameter "userId" -------------
+	var userId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "userId", runtime.ParamLocationPath, ctx.Param("userId"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RemoveShopMember(ctx, shopId, userId)
+	return err
+}
+
+// GetPriceLists converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPriceLists(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shopId" -------------
+	var shopId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shopId", runtime.ParamLocationPath, ctx.Param("shopId"), &shopId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shopId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPriceLists(ctx, shopId)
+	return err
+}
+
+// CreatePriceList converts echo context to params.
+func (w *ServerInterfaceWrapper) CreatePriceList(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shopId" -------------
+	var shopId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shopId", runtime.ParamLocationPath, ctx.Param("shopId"), &shopId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shopId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreatePriceList(ctx, shopId)
+	return err
+}
+
 // GetProductsByShopId converts echo context to params.
 func (w *ServerInterfaceWrapper) GetProductsByShopId(ctx echo.Context) error {
 	var err error
@@ -388,9 +1319,9 @@ func (w *ServerInterfaceWrapper) GetProductsByShopId(ctx echo.Context) error {
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetProductsByShopIdParams
-	// ------------- Required query parameter "page" -------------
+	// ------------- Optional query parameter "page" -------------
 
-	err = runtime.BindQueryParameter("form", true, true, "page", ctx.QueryParams(), &params.Page)
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
 	}
@@ -402,11 +1333,63 @@ func (w *ServerInterfaceWrapper) GetProductsByShopId(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
 	}
 
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "skipTotal" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "skipTotal", ctx.QueryParams(), &params.SkipTotal)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter skipTotal: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetProductsByShopId(ctx, shopId, params)
 	return err
 }
 
+// UpsertShopProductPrice converts echo context to params.
+func (w *ServerInterfaceWrapper) UpsertShopProductPrice(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shopId" -------------
+	var shopId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shopId", runtime.ParamLocationPath, ctx.Param("shopId"), &shopId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shopId: %s", err))
+	}
+
+	// ------------- Path parameter "productId" -------------
+	var productId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "productId", runtime.ParamLocationPath, ctx.Param("productId"), &productId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpsertShopProductPrice(ctx, shopId, productId)
+	return err
+}
+
 // UpsertShopToWarehouses converts echo context to params.
 func (w *ServerInterfaceWrapper) UpsertShopToWarehouses(ctx echo.Context) error {
 	var err error
@@ -416,15 +1399,126 @@ func (w *ServerInterfaceWrapper) UpsertShopToWarehouses(ctx echo.Context) error
 	return err
 }
 
+// DeleteAccount converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAccount(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteAccount(ctx)
+	return err
+}
+
+// GetProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) GetProfile(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetProfile(ctx)
+	return err
+}
+
+// UpdateProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateProfile(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateProfile(ctx)
+	return err
+}
+
+// GetAddresses converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAddresses(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAddresses(ctx)
+	return err
+}
+
+// CreateAddress converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateAddress(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateAddress(ctx)
+	return err
+}
+
+// DeleteAddress converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAddress(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "addressId" -------------
+	var addressId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "addressId", runtime.ParamLocationPath, ctx.Param("addressId"), &addressId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter addressId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteAddress(ctx, addressId)
+	return err
+}
+
+// UpdateAddress converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateAddress(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "addressId" -------------
+	var addressId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "addressId", runtime.ParamLocationPath, ctx.Param("addressId"), &addressId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter addressId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateAddress(ctx, addressId)
+	return err
+}
+
+// ExportUserData converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportUserData(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ExportUserData(ctx)
+	return err
+}
+
+// ChangeIdentifier converts echo context to params.
+func (w *ServerInterfaceWrapper) ChangeIdentifier(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ChangeIdentifier(ctx)
+	return err
+}
+
+// AssignRoles converts echo context to params.
+func (w *ServerInterfaceWrapper) AssignRoles(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "userId", runtime.ParamLocationPath, ctx.Param("userId"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AssignRoles(ctx, userId)
+	return err
+}
+
 // GetWarehouses converts echo context to params.
 func (w *ServerInterfaceWrapper) GetWarehouses(ctx echo.Context) error {
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetWarehousesParams
-	// ------------- Required query parameter "page" -------------
+	// ------------- Optional query parameter "page" -------------
 
-	err = runtime.BindQueryParameter("form", true, true, "page", ctx.QueryParams(), &params.Page)
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
 	}
@@ -436,6 +1530,34 @@ func (w *ServerInterfaceWrapper) GetWarehouses(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
 	}
 
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", ctx.QueryParams(), &params.Filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter filter: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "skipTotal" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "skipTotal", ctx.QueryParams(), &params.SkipTotal)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter skipTotal: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetWarehouses(ctx, params)
 	return err
@@ -475,6 +1597,24 @@ func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
 	return err
 }
 
+// GetHealthLive converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealthLive(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealthLive(ctx)
+	return err
+}
+
+// GetHealthReady converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealthReady(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealthReady(ctx)
+	return err
+}
+
 // Login converts echo context to params.
 func (w *ServerInterfaceWrapper) Login(ctx echo.Context) error {
 	var err error
@@ -484,6 +1624,33 @@ func (w *ServerInterfaceWrapper) Login(ctx echo.Context) error {
 	return err
 }
 
+// Logout converts echo context to params.
+func (w *ServerInterfaceWrapper) Logout(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Logout(ctx)
+	return err
+}
+
+// RequestOtp converts echo context to params.
+func (w *ServerInterfaceWrapper) RequestOtp(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RequestOtp(ctx)
+	return err
+}
+
+// RefreshToken converts echo context to params.
+func (w *ServerInterfaceWrapper) RefreshToken(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RefreshToken(ctx)
+	return err
+}
+
 // RegisterUser converts echo context to params.
 func (w *ServerInterfaceWrapper) RegisterUser(ctx echo.Context) error {
 	var err error
@@ -521,20 +1688,51 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/.well-known/jwks.json", wrapper.GetJwks)
+	router.GET(baseURL+"/api/v1/api-keys", wrapper.GetAPIKeys)
+	router.POST(baseURL+"/api/v1/api-keys", wrapper.CreateAPIKey)
+	router.DELETE(baseURL+"/api/v1/api-keys/:apiKeyId", wrapper.RevokeAPIKey)
+	router.GET(baseURL+"/api/v1/catalog/export", wrapper.ExportCatalog)
+	router.POST(baseURL+"/api/v1/catalog/import", wrapper.ImportCatalog)
+	router.GET(baseURL+"/api/v1/catalog/import-jobs/:jobId", wrapper.GetCatalogImportJob)
 	router.POST(baseURL+"/api/v1/order/:orderId/pay", wrapper.PayOrder)
 	router.POST(baseURL+"/api/v1/product/transfer", wrapper.TransferProduct)
+	router.GET(baseURL+"/api/v1/product/:productId/price", wrapper.GetProductPrice)
+	router.PUT(baseURL+"/api/v1/product/:productId/price", wrapper.UpdateProductPrice)
 	router.PUT(baseURL+"/api/v1/product/:productId/stock", wrapper.UpdateProductStock)
 	router.POST(baseURL+"/api/v1/products", wrapper.CreateProduct)
+	router.POST(baseURL+"/api/v1/shop-invitations/:invitationId/accept", wrapper.AcceptShopInvitation)
 	router.POST(baseURL+"/api/v1/shop/:shopId/order", wrapper.OrderProducts)
 	router.GET(baseURL+"/api/v1/shops", wrapper.GetShops)
 	router.POST(baseURL+"/api/v1/shops", wrapper.CreateShop)
+	router.POST(baseURL+"/api/v1/shops/:shopId/invitations", wrapper.InviteShopMember)
+	router.GET(baseURL+"/api/v1/shops/:shopId/members", wrapper.GetShopMembers)
+	router.DELETE(baseURL+"/api/v1/shops/:shopId/members/:userId", wrapper.RemoveShopMember)
+	router.GET(baseURL+"/api/v1/shops/:shopId/price-lists", wrapper.GetPriceLists)
+	router.POST(baseURL+"/api/v1/shops/:shopId/price-lists", wrapper.CreatePriceList)
 	router.GET(baseURL+"/api/v1/shops/:shopId/products", wrapper.GetProductsByShopId)
+	router.PUT(baseURL+"/api/v1/shops/:shopId/products/:productId/price", wrapper.UpsertShopProductPrice)
 	router.POST(baseURL+"/api/v1/upsert-shop-warehouses", wrapper.UpsertShopToWarehouses)
+	router.DELETE(baseURL+"/api/v1/users/me", wrapper.DeleteAccount)
+	router.GET(baseURL+"/api/v1/users/me", wrapper.GetProfile)
+	router.PUT(baseURL+"/api/v1/users/me", wrapper.UpdateProfile)
+	router.GET(baseURL+"/api/v1/users/me/addresses", wrapper.GetAddresses)
+	router.POST(baseURL+"/api/v1/users/me/addresses", wrapper.CreateAddress)
+	router.DELETE(baseURL+"/api/v1/users/me/addresses/:addressId", wrapper.DeleteAddress)
+	router.PUT(baseURL+"/api/v1/users/me/addresses/:addressId", wrapper.UpdateAddress)
+	router.GET(baseURL+"/api/v1/users/me/export", wrapper.ExportUserData)
+	router.PUT(baseURL+"/api/v1/users/me/identifier", wrapper.ChangeIdentifier)
+	router.PUT(baseURL+"/api/v1/users/:userId/roles", wrapper.AssignRoles)
 	router.GET(baseURL+"/api/v1/warehouses", wrapper.GetWarehouses)
 	router.POST(baseURL+"/api/v1/warehouses", wrapper.CreateWarehouse)
 	router.PUT(baseURL+"/api/v1/warehouses/:warehouseId/status", wrapper.UpdateWarehouseStatus)
 	router.GET(baseURL+"/health", wrapper.GetHealth)
+	router.GET(baseURL+"/health/live", wrapper.GetHealthLive)
+	router.GET(baseURL+"/health/ready", wrapper.GetHealthReady)
 	router.POST(baseURL+"/user/login", wrapper.Login)
+	router.POST(baseURL+"/user/logout", wrapper.Logout)
+	router.POST(baseURL+"/user/otp", wrapper.RequestOtp)
+	router.POST(baseURL+"/user/refresh", wrapper.RefreshToken)
 	router.POST(baseURL+"/user/register", wrapper.RegisterUser)
 
 }
@@ -542,31 +1740,107 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+RZT2/buBP9KgR/P2AvbpTu3nxsD11jt2jQuOihyIGRxhZbmVQ4VAuv4e++IKk/lE1K",
-	"chDVC/TUxqSGM2/43gzJA03lrpQChEa6PFBMc9gx+9+3CpiGOyWzKtUf4akC1Ob3UskSlOZgZ4FgjwVk",
-	"5r96XwJd0kcpC2CCHhdUsB14I6gVF1szUCqe+iNcaNiCMkNaalbca5l+C4//YApyWSGssoDp44IqeKq4",
-	"Mi59cQ70bC5aj/umGp8eFo1J+fgVUm2WPEECSykQzqHgExzi2cAK97kso0BHsAwFPLbCbAF8bgCdNwpv",
-	"mRlCeQe6TjW+2RvAVll8nZJtuWCaS2H++r+CDV3S/yUdqZKaUcldN9MSwK1gfdWww9HP3QeWIc5jphTb",
-	"nwXVGl74vkXiNNHhyweHxuzkyIwTo2E5k1NiajfHDIG1gjE9utad0RA946Nx/i23XERJxjMQmm84qKD4",
-	"dsNrOzTOk95838CQczHwtfwGYnxVNy1k/4PKQNV8WGnYBfJbD2bB+J8qJjTX+1B9CbPJ1of2szGfMJ6Y",
-	"ZsdM2jpncY7tIGd1gnszqOZdj1JndIsUezNyz/8ZagUGhu4idk+TaKZ5a/lfN4uEQ9pb3KLpZDtZCT3B",
-	"g3picJFa1y9rrHh4Z1+93+KGKHXT5dac1HyFgPkIW44a1CccyMCVla7v42yCZyvkNLIuJnZYXaJCC64V",
-	"E7hpFSMKfwaoa9Z/HtwmixFJRlmpFMZsDG/VAek+N7+I+d5bJYTNpzLrDgN2WhSeF6XWj8udbOO610xX",
-	"+Jwz3IkTzczwmgjKtpRr6Tdhzzk4om27g5vAw6FfSs+3y1hDuTrRIfQEKhRh18vNqdYDkhp3znzFxUYa",
-	"ewVPoRYitwR9v1pbQLguzJ/r9fsVeZuzogBha+B3UGgrN319c3tza+bKEgQrOV3SP+xPpn7q3MaasJIn",
-	"318n0hTH5GD/WWXHpGS2pSqlS7eBx9LLJLItptaQYjvQoJAuvxwoN+sa402cS1qbpD4SWlWwqO8nQqg9",
-	"uMmA+o3MrCOpFBpcfWZlWfDUepN8RdejdKaGDwD9JuDYz4/xyv7gxN/i8/vtbS2OqeKla4noR9CVEgQt",
-	"E22SsdrtmNo7bAgTxEZ9Y8caiGsRS3QtyHF8TySbzoNGpDA8F5TaDEEjZIQL4oSaMJERT59Jy1HCFJDK",
-	"ilt2AuI650hAZKXkQpMGMCQ1hGSj5I4wz5SWhAmp8xjmh7aCHBNsRLysAtifl4RJu9yvUFff5/Gy9kLJ",
-	"xUl5c3O6rNk6V5vYSOUnMJQ0jBOkd4k3Ez2CV6aT8Hs9lw91U2qdCOeHI0ntN8OZcXPazPTQN8U0ObiS",
-	"enR1IZ6I3ll0ElHaWn11lgSP+dMJMocP8QQPlRxrpMklNuJogL45y6yNYAuBVDZXiJEsPlWg9p7euQP3",
-	"aBK9jj5uqD7JX2DsYcacnN2lDqQjlyUpOOpBtm1Boz91Mahq9oA4p6T5LxNX0bPew0UAXDN+qZJF93sn",
-	"ZX5ZiTHg9LFgLklb/Iqsir7ExAnWtA6GOORxT3QOjkn8dFu8Az1BAit7rn1lBl71HwDClAyfg+lcbdvQ",
-	"ofu5rZtlE4Jpv4gX8hCp0AiWVKQS2EpX7+s+qH0gY9T63H8S+cUrTOBlK86C7pwzrdb059+MVZzuKmTO",
-	"snP2nnyV2nP+3ByAvZ10eRUKn2g6hiQH747KnEVtPzd8GD25+ptUk/o3jP+RE2nkDvO5ytaa+w3rxvjS",
-	"k2lHFK+xTnJghc6HtOxPN2OKkx/+OvHEfUvSHNJvrUdu4QpBJYXcchGvSPZBdiai9l6if/JJqP/QHCCl",
-	"nUCwSlNA3FTFYIILuUXChe0XDKoevqp+54lD7L8EzYR06EHsJ+th8L0rgLsZn6qDDbZIBPxogDfzQX1v",
-	"xKpSBV3SXOtymSSFTFmRmywcH47/BgAA//8NruSRwCYAAA==",
+	"H4sIAAAAAAAC/+x9e3PbNrb4V8Hw95tpO5e2nLQ7u+v/0nQf3jaNx063d2Y300DkkYSYAhgAlK319Xe/",
+	"cwAQfAGUZFNOe3f/6DQWQeC8cHBwXrxPMrEuBQeuVXJ+n5RU0jVokOav15VUQuK/clCZZKVmgifnyduS",
+	"fqqAZOYx0fQGOFlIsSYfONxp+9YHIiT5UErY+L8XBP9kolJEgioFV3BKLjRZV0qTOZBKQU5umV4RvQKi",
+	"6BqIElITynOyYIUGeZqkCUMIPlUgt0macLqG5DyxkCRporIVrClCrLclPlFaMr5MHh7S5M9miiE2V1AC",
+	"1XRegFuFME4+LBgU+bkoQVIt5PmGFhV8IAsh1+RLOF2eIm4sg/OlhvMXZ2dnH746JW/daEWoBAKfUsIh",
+	"JUuN/0FKCo3/QYpkWLC7lGSCa8q4MhgyTr40qyiiAPmgISfzLfnif774CvGGu7IQOSTnWlYQJoMFv0MG",
+	"pmGtAvRI6x+olHSLfyu9LcwkQq7x7+sbVr4TmhZDiuEjkomKa8aXROMgIsWtSskH88cHg5D99yVdwgdD",
+	"Dwm6khxyQhU5eRFjpfLLBrg5F6IAyg07r4XUQ9Bei/WatghoBMjwUtV0J9T+YEXti5MviBZ2HE4FPEek",
+	"HI9PDJNThO3DV1GQEZIx2XtIk1rgDS8upZgXsMZ/oggAN4jQsixYRhGRWWlH/NdHhVjdt+b+/xIWyXny",
+	"/2bNxp3Zp2pWz2tW7NLlT1IKiVsQt5aETxUonZo/MpEDYcr8W3AwxLhlOlsRYQntZsfFX11efA9b/Fcp",
+	"cWtoZhHKJCC1Xxk8UICoTs6TnGo40WwNSToUP/fKt9ugcMJdyeRhE7I8OFNBlf5JHTaV5WtgMitAwUcS",
+	"NuLmsGVUJkpQh+xSs86nCkmTnP8DUXbAetD8rG0Kpy0GvfdzivlHyDQu8irLoNTXK1Fe8A3TRgSvnMAO",
+	"ma1wXB7WsW3o3LjggnkuQamAIDEdFgijbWTk2eHSFxEWpr6DBa0KHdI5KEtzKMKCsRIcfqzWc5Dh50Jp",
+	"Wrw2+jv0WIoN4xlEJCtjJQOuf4yJpdISQAcfVWV+GG1CImbx7kPSxdpDkVomtpDqoN+wsk3uNhPbQAeF",
+	"Rym25FeiAHVlFdlQjqQo7D/8zgJerREhmq8ZT9JkDTJbUY7L3VIJK1Ep+KU+7hGeSmmxBpm8H5Box660",
+	"a4cgf001LcTyYl0Kqf8m5pMo0lxuryoeFliQUsguHcbODwfflbg1x0XISlhQVkB+JW7b6opxDUsw42uY",
+	"72N7rncsrUu9JcweSx/FHA8iLjSRFSdf5nJr/iEkUWINxsQwlgTjG1qw/KsQOUopMlBqDEalqa7UEBhZ",
+	"cc74MiWqyjKAHHJc2mIcWsoPiy9lTKD446fuToeJJ7uXhvbKfZr0Ae8w1cvMITuyLzcDsYb65+HxzDhE",
+	"uHRT7T5jzOsO5jBoK8qXcJED12zBQEY1BvNDIsJbP35nHjUKBdaUFT1lGNIZQpcR/T9QuZ2l2msnzTRB",
+	"ZA3HrJEWRbRjWnU3wLsVkBvYEg4bkMQOVLg7mcaNCbhZk3QvQR0xogJmT01Lr4nPJdC8rZrPbyXTuA5a",
+	"FfXTUoq8ynT/Tz9Ui+zG/5VZIT1nRvu2foA788PBet4ZXg6d3fxo7ClaFG8Xyfk/xrWxfS95SPscvIHt",
+	"kHfXgHdIjdfI/z55dXlx8j1syQpobqRmXOJwwiH87z0Gl5Jl8ANTOi5U/DDzq2b8XseSX/5Cwzp0KMVF",
+	"TVOpH61dawa7SWqw45xu0SlmPLN8HwUwuoYR8hFOoCcjD9sDIxcblsHIEXaNWyn83G/Rfe4EjqStOVMP",
+	"cXeqGqY9KHE0WuONKEroCC1DCO9a4WgI/FwT9LhYtJY5AirfQQk8B55t/wq00KsR8wLu6Lo0XiyrO8gG",
+	"pGKCk5dErURV5Ohm/DqkkQqqcYU3qjPNi9OXv/ODuT3bOwZkfXAJFORc3PLAMRKz2ZolQ1j/BbQ9AFSc",
+	"qLRkOGBvRepPlPGDrZ42Bpa9vMMYYPWQ/UGzb+yGzc8cgc7r4BHwSj/m8DNoJ4StyaMgGsVlphyh4SHO",
+	"NGPl5q8OfyXifxs5D8yjvzKlhdxe5PG3zYEde24IEHka9S+liRKVzGBo/MypghS3eIk3Nr/8zhO+ASRN",
+	"zPXJ4u0XGmeg+nZrfGb5iKTRJePUQrlDwJqRDYUOkU/zwh7S6SZO27BF8ETs3gBqvZHNtLYD9oa0mXQn",
+	"sPXUI+Cp6WmPYnQYOjsRsVPuQ3J/lB4BMW9e7Y+dB2cniq3Jd+Jpz/ErKF0Ip4tetoLsQE96XpsIzfnD",
+	"cHVaXHamHkN1YGYMYij29zqIkmPM0Ogd/GvNltIgbEJfEnKmkgDmMcsBlpLmkB9uRHQwT1u0C9HdePeh",
+	"2YCfzxkiRdF5b005XTovMl0sdhNg3Elipt+PAkcwWP92/fbHn2EejJTRYhmJYWwCwcxKbgDl7e33l+iY",
+	"CYl+4DS8un6Fzhsj36FXbiLRj5tI7IWHl1iLvCoqFVqhUuFr5l0gl+D7S1JW84JlYQz7rgrjgboxYQmk",
+	"pV1rnAvXEBDxm0MM52aunXrwJmY5/yCWjE+348b8iz0K69IGefWKGj+eC//WyRYFAkbKSpYCtZnJwmAL",
+	"UlKlboXMa5e8gqAw1cOe5tccIVhsgzr35EVAOjGeqRTRAhNTCrYAPDfQM6YgEzxviWzLrpWwkKBW7/Cl",
+	"gMDbp25OQ8mMcp+1Imyca0AdHZ6tDeBOka9HdQBMW/iHiPdW5iCdUWjcZoFL0JgR/qmiXHeDsZ5WI0a0",
+	"f20XTPHQ3WG+wQGeocyWFStLxpfujnkRiEO5RygjeJq7WyaZC3FTn/eV2sOTGvcQ9pA/wrFz2TELu9M2",
+	"eVnBU8akcQmyBG1QxdGkpEtIrc+/CQHgU8yoME9Pw/pgGbs40iVcs3/FnvpMsX0g9IlkUSgXTI6BqevM",
+	"pojD8zKCRl/2cVgLtfbb7t8RXm2NQER3AV1jlHwPCNzA4CL+Hvxkf30kXeK53PgjHoEnefiNGeFmTw/x",
+	"93dxCTuYYnIeV7sjijXuDG8lk/WOrD+/Jr//w9nviUsmIzloygoXEG5fuIJmwxuarRiHEwk0N8mROCwl",
+	"Ji9uTvNffBJZxWmlV0Kyf0GeYobknOU58BSthl8WouK5yXRcFCwzo9mnCn7ZMFEYXZUSLcQva8q39YzK",
+	"TLmhrMBlU4KcFJUmQhKko+QmN7Bx9baACd8NEesAfqAUXUKt3o0POSWUKJAm+ol/kxVVhJIlcJAsI4IH",
+	"d0eTZdFd4cKmKbj0Q1yIEvOLvSia15J0b/8O8vDPOFU0OcNRIXTAXeS95D8bKHT65+TiOxcrTJ0KNVaN",
+	"FkSaS3pDoRD+rUttzZRvzs5CNpZmuoDOwORbmpOrOPf0tuy9QOei0ufzgvKb3aaTNTTtsmlza85iEfQh",
+	"mQd723CzC5K3gQMIrK2Y7d7tdt7mhQh4xst3WNQvor0/ezCwm75oHa97RAZDhLlq2cbxpLCehT8OXWd0",
+	"eM0lUxrkT2pKT8p09zrp4Kuvdrtub2vGfwC+1Kvk/A9Pc7+09sNYukqXgP+55z3mnucE760ujyiCtQC1",
+	"HHa1cCVpYvwHSZpsQLLF9qmuu3qtELLG177flSndM7Ld6KDYgi5WMUWqZt/rKW65wXm393PUBq4UyAMS",
+	"sv0LDqBd2eHvJOVq4S+vUTnLQWl3Af159BjYGQA0wbddc4wfRSOm9HD6NAZ7Z5UQbX4yeZHdgG6EPNFT",
+	"NRQ/3r2YgSm62KTn9O2jKLJgxe60kzW9q8+cFy2T8dA0FLuo59y1sfMekyLVW7AeGV5TgayTIaJrPaqg",
+	"4f9aGcLAsjqojCBUOxBnCKrsZ9uP9YLvRDtu+5jMvBH13tp+TykW8tq/M186LuNqopPPxgXP7yNP/o6m",
+	"A5vs/rJrFzTPxxeepHjGXXC6eIaBOCTnHlnzHdX0T3fhKPqEKVjGBhXyQJYLmffTQsJheVvP2rvb4Mv2",
+	"GsO0ImYGE1kv6XaNUIeC632oS3sI7cLbCPlA8zcYNxOlLap6BEPM8drgqDf1MWmLbmp8i/GFwPkKloG7",
+	"c9klkjcX71p+muTduzcX5PWKFgVw49F2+ZTJefLi9Oz0zPC5BE5LlpwnX5uf8PqnVwbX2ektFMXJDRe3",
+	"fPbx9kad1vWsS3tO2HorJjiqPkx/+dvtjUp61bIvz85GKmUPq5DtBoUDdbI4gPwMc4Lp82YMjvCHcWhu",
+	"D2yr/jZNVLVeUzzfk3crpgjwvBSMa1cFbWMUTdxbES2IvUAR2ro0KjPXjJZstnmB/zup49YxCrqk0WMS",
+	"MZCaGqDklUGU0JIZBCcg5F9c8Kee0ugHUdkf8YfT2sYZUqZdipF4T+m3It9ORpZQ9c1Dd5OiqnsYcObF",
+	"kUCI8+aVpSA6jdyJMwF77OKEep5jLMA51Fnm8qTcqoIX26YhgOAZnAYlfXZvk5Ev8gd7xSxAw5C7V6b4",
+	"2nO33cXiH/e2Zh+VUlOyX8+a9LkzVsb/PrynoqR1JeETkNbi1yJtl1qukmkG3hYIagdrKrhivSGZuni4",
+	"G4gREbWJtT7wdYcN1WrnRqY2SZqg/BYhn9D7gxTU3QnPh1theB5quNMzXHl03GA/uAsDMRVjpETToz7B",
+	"MSQkxa2RZaDZqnkyAV+vtQS6NhvD8dDaPAE4CFXk9fXfiZDEnFE/MA4qLAauwu383uvCXtEtooE4MQxu",
+	"lR3cGSe0taiBJhNFteaKqJsqJcj41KZYp6R1lTDWWeMbOP0nf1UUTcmui3tBbgPzJji4wkYbTBGs0NPA",
+	"MX4frPM9/SdP0p4sX6wPkOVMbZBuRhZTkjeS3Wpd89oK3gn6JKeX9nTgvEcFWBPFxuUM1jx3atH+ZqJv",
+	"MXB8re9In5T3+551k++wXafedPbIoKw+aI3gQ4yBuqpy3L0vz14+KxD2YV3nbvIMIE9dQotYmpQjF23Q",
+	"kmY3tgGQL403J8k3n41wbjcaQZ1A9zli1AUJbhf2NVzaDECNUBmXi6XL9fc/WXU1p9nNUmKiAdJpTCee",
+	"fBRzNbv/KObOoIhZ0QPK7GNUmGmnsCiekble1TAvmxNZ6l6irU5TqHJNCkR90jUrdllm7tWze/O/i/xh",
+	"VtJt+yzr8qpOpNqLQW7Kw1k0/WWhnwC2v8oMctBlNjydc5d0i2amoVSXLW4fzrQLB8V50gsYHem6FQlL",
+	"PZaQl30zyIaJjPS2okNtg8zoo3yi21PXS1ATWXnzzKjHtmmmBaFoRMX4dO9jXg8z7+uO6bu2x3yvrdQO",
+	"qO2/mdL7cAO12jU9+maPX4ZOjJv0sDq3yZmlLQuPi9uY+dSz5Pby774/rlslWBk6rrdr+TCoWyO8JgSR",
+	"kAmZ+/yQSkE+nXJvr6rxHtFmSGpPZmSsMevN/00pgQJt3TRVQBKH4dQjCuORNHs8JPxU1WSJjYw8ktax",
+	"8zY6Z06V31Pmjmpqhq1rAyULTNdKC9bKVgTv1kWqjkrvloA6D+y3LAGdOP1Eh9PzSYDt7WmXtd68lvtj",
+	"yGgVNwo6PTuO6oF9jEHw4lgwxHV4zdNJfbBdbtp5PTc7HEOVfMJ8u0c1u2/+wG1KTU/IOD9DPSP32qrt",
+	"ZX41l6XRDpgB5mHM0LrPbI16bYAoVw3+RDZacNAQb6hlVbAJI5smByvBgfCqvXqFUNXdlPEH87ZxsPf8",
+	"6wjn7N7aXQ/2xhVndad0aS8ee4Pus+viYM3ZM/unwqVfcbOuucx98/KPgS5tQhCsl7CXtDrrV6FQeCHA",
+	"ugWJ7mqw9uCa3pHStTY2r01xVzR49Rw41uYbCttozPLat0cYceJiTVUt7wvTy3ihwFR4uatZXbrAllxI",
+	"TIJeAa97lDvDk7SiGtEO1K6mayCqrQSh++iLrgpsp9x3JgsRv6HFzHS63mOca3C+x0hXaLfP2r4d97Hv",
+	"Pt1uHiO7A+8ShevEM+lxuQStutOPmTII71HtmHb3sc9ixHSakwUYcm1udcc3X6I6pTnBWoZM/BzrN3/4",
+	"jR1lse4dzywb0RYaoYCHZ4vVwHyKTWsBQOMobAxpgeZP2zKzApT2bKQV3eCwFVuuQBIpClNJwzuGE/46",
+	"Knit7kdjp9sbN+yYEndc1dzvAxVX0I4kE5vEdfJPa/YwW3u8r0HYh4uze1sZsSPXZC02z6BF0uBETenG",
+	"tGkrFhfr2kH8pslawZmeuBPtu6PcMw6ok6Lu5xf3cfvGfL/NbRhobRjfhdYrZ4hyhJ1IM8021t1clZlY",
+	"472it6I/tdMdHqGmWd9v6TSOdEb+TN6mft/hoL+pZs+0Nts1XjKrAkwqkV/CC4CPPjiRmYO+BeA2+8EI",
+	"EGDMgq1hxx5vHIw7gli+LeMRNfN/rqb/TlfTaL/PMfVrXrGbwSXxuCjYtCG4XX6X4RYKR4Yj0ZhQSdXz",
+	"Wjy/stDOWI3ZY8M7OF8vpGq1whTqeRCtFRuQkuVQp2cP5MYmWZ2YCEG3Z2j4IA/XwSXH5kCo6O5JHFCg",
+	"8fLYQnlqj4YC66etuPK+ps6KPUYo1HNrGLuOfGd+f5WZyshkrwx1OxZFzE5qc3cZAgRKMcGV+16fT1//",
+	"JjSPiYGIW147aCaglkXGFnlYKF12JEiFZWOm6aoxJrjg2zV2HCK3K1bYV6xju1MmZhC5gVJbYc/qjxYa",
+	"s3TEjnDlXkc7VFzN2cjpgRB0Ws9Nd4UOzL5HSoYnyRED5u2a8WcO0uzgiAu4H4kzlgAjzAkohVmnwjNa",
+	"DuYHHbkgbPBRgDgtPeBHku9Y90arS9wiflS7X+DuAjL70lHPtl5LgWe+UdYYhqrGGopNd318leemrsnN",
+	"rcWAhWnDnz7Tal4O4tvDTTK7p3X7z4c9zlPP5z1qyep5j1BM1qDrjurpDtkW0Q84CJ6JML+mvXX2zHtr",
+	"utwud6xEOR3aMHvVENbNB45tIrUaHOw0ltLmXElrU9A1EOg3D5j43LEQEtiA3NqiNs1s98R6EWLaSJqq",
+	"FiqzFdvE1FW3V1dwH/a/aHisGHTkw4m/KbPsm7M/hlph1jj5RpfzLTGZ9FNJxLWzRHZma9WOwY1rAYKQ",
+	"UE6ELvvd/FxzANceLSQ+dfBq5j9+G5Sf1udz99Lij404TS+SgQ//PrpsBicxl0NqJnVleFv7E0Josuaa",
+	"bti2Sb5pQo0lo65Hn5WxryN3Y9c4nxaFuLX6wC5GLIOm7yxRFjQD5apJi7593RKYrlcndnf4uftJl//k",
+	"Zv3bOsADXyWK6+SmROl4WVrdNXZe2zz8R03YGnxt8bMEA4cfYwywyg96nvytcNGA/1XN7ludBB5mLgF2",
+	"vEak1+1vr7Os28nwV1IoEmlb+NijzU/3hXKZxM9RMNJsyDp7GRm98h/sdGdMDqWEjOoao3R46rivb+2D",
+	"6tvvJ7kO1yARWjBqDk0H+KxgG2hjYn8YOTIt8D/gqGdDAFfjoJRvJJD5i+QpudAkF2ANEfNxMOc3ab4a",
+	"lhIljM1Z6aYd/rp5S4IN1eN7tjd+hyISaL7dTZIrM+yIp1Tnm3LBw4nmW0uiZlegf1CS+utr5Evz3TZ8",
+	"gF9h88WUC1oU2GPAtSBQkH+FbPvd2dfPBv2PhhEeg/rbcx5UIW38xkxXG572m3SQ9+QFKcEagbE8NX0n",
+	"sB2KrycwsoOfzVHdJf337cy/jEzZEd1P7lohqRTImbGd4+FD852nIx3MnY9uPfPVtfv9qgBTzQCiKtPv",
+	"bVEVuys+7C2k5tDkyrwQS/8JpObOUPNQVHqUifh8r1irDTJO2yGrfxXCeZ1YuuXcXajdXa+Fn9BlHLmm",
+	"yfqRxHTYxX0vWX058lEAl29NmEW7cSyZnY4Hte1AaLut75S8lkfiGGFxnisiatC16IOsRfM1A/shKKtT",
+	"Gv7VF/IRHna66h+Hi8OPUPzadI4Bzm49A2wd33+x61MITNUNgFJiP0mQIyskTNRVoLeBhTZmpW2C1Yaj",
+	"6ZClCIfb2H6uxWVMIJqvTxxNIIZfCHnmO2HwGxsjBa2tu+AeztOaypCn7mxiXGmg+W6F4vfz0bRKvYKV",
+	"k8o7kq3BY++IlSyS82SldXk+mxUio8VKIJPeP/zvANAFK60alQAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
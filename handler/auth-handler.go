@@ -2,46 +2,106 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"mfawzanid/warehouse-commerce/core/entity"
 	"mfawzanid/warehouse-commerce/core/usecase"
 	errorutil "mfawzanid/warehouse-commerce/utils/error"
-	generalutil "mfawzanid/warehouse-commerce/utils/general"
-	"net/http"
 
 	"github.com/labstack/echo/v4"
 )
 
 type AuthHandler interface {
 	VerifyToken(next echo.HandlerFunc) echo.HandlerFunc
+	// Authorize checks the permission of the route, it must be used after VerifyToken
+	Authorize(next echo.HandlerFunc) echo.HandlerFunc
 }
 
 type authHandler struct {
-	authUsecase usecase.AuthUsecaseInterface
+	authUsecase   usecase.AuthUsecaseInterface
+	apiKeyUsecase usecase.APIKeyUsecaseInterface
+	logger        *slog.Logger
 }
 
-func NewAuthHandler(authUsecase usecase.AuthUsecaseInterface) AuthHandler {
-	return &authHandler{authUsecase}
+func NewAuthHandler(authUsecase usecase.AuthUsecaseInterface, apiKeyUsecase usecase.APIKeyUsecaseInterface, logger *slog.Logger) AuthHandler {
+	return &authHandler{authUsecase, apiKeyUsecase, logger}
 }
 
+// VerifyToken accepts the access token of a user or the api key of a service in `X-API-Key` header
 func (h *authHandler) VerifyToken(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		if apiKey := c.Request().Header.Get(entity.HeaderAPIKey); apiKey != "" {
+			return h.verifyAPIKey(c, next, apiKey)
+		}
+
 		token := c.Request().Header.Get("Authorization")
 
 		if len(token) == 0 {
-			return c.JSON(http.StatusUnauthorized, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusUnauthorized, errors.New("token is empty")),
-			})
+			return errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("token is empty"))
 		}
 
-		userId, err := h.authUsecase.VerifyToken(token)
+		claims, err := h.authUsecase.VerifyToken(c.Request().Context(), token)
 		if err != nil {
-			return c.JSON(http.StatusUnauthorized, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-			})
+			return err
 		}
 
-		c.Set(entity.ContextUserId, userId)
+		c.Set(entity.ContextUserId, claims.UserId)
+		c.Set(entity.ContextSessionId, claims.SessionId)
+		c.Set(entity.ContextRoles, claims.Roles)
+		c.Set(entity.ContextPermissions, claims.Permissions)
 
 		return next(c)
 	}
 }
+
+// verifyAPIKey sets the key id and its scopes as the permissions, the service is not a user so the user id is not set,
+// the user that created the key is set as the actor of the changes
+func (h *authHandler) verifyAPIKey(c echo.Context, next echo.HandlerFunc, key string) error {
+	apiKey, err := h.apiKeyUsecase.VerifyAPIKey(c.Request().Context(), key)
+	if err != nil {
+		return err
+	}
+
+	c.Set(entity.ContextAPIKeyId, apiKey.Id)
+	c.Set(entity.ContextAPIKeyCreatedBy, apiKey.CreatedBy)
+	c.Set(entity.ContextPermissions, apiKey.Scopes)
+
+	return next(c)
+}
+
+func (h *authHandler) Authorize(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		route, ok := routePermissions[routeKey(c.Request().Method, c.Path())]
+		if !ok {
+			h.logger.ErrorContext(c.Request().Context(), "error authorize: route has no permission, it is denied", "method", c.Request().Method, "route", c.Path())
+			return errorutil.NewErrorCode(errorutil.ErrForbidden, errors.New("route is not allowed"))
+		}
+
+		permissions, _ := c.Get(entity.ContextPermissions).([]string)
+		if route.permission != "" && !entity.HasPermission(permissions, route.permission) {
+			return errorutil.NewErrorCode(errorutil.ErrForbidden, fmt.Errorf("permission '%s' is required", route.permission))
+		}
+
+		return next(c)
+	}
+}
+
+// shopActor gets the user of the token that calls a shop-scoped usecase
+func shopActor(c echo.Context) entity.ShopActor {
+	userId, _ := c.Get(entity.ContextUserId).(string)
+	permissions, _ := c.Get(entity.ContextPermissions).([]string)
+
+	return entity.ShopActor{
+		UserId:      userId,
+		Permissions: permissions,
+	}
+}
+
+// actorId gets the user that is recorded for a change, it is the user of the token or the user that created the api key
+func actorId(c echo.Context) string {
+	if userId, _ := c.Get(entity.ContextUserId).(string); userId != "" {
+		return userId
+	}
+	createdBy, _ := c.Get(entity.ContextAPIKeyCreatedBy).(string)
+	return createdBy
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/mocks"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifyToken(t *testing.T) {
+	t.Run("VerifyToken_api key_then the creator of the key is the actor", func(t *testing.T) {
+		apiKeyUsecase := mocks.NewAPIKeyUsecaseInterface(t)
+		apiKeyUsecase.On("VerifyAPIKey", mock.Anything, "wck_key").
+			Return(&entity.APIKey{Id: "AK-1", Scopes: []string{entity.PermissionProductWrite}, CreatedBy: "USR-1"}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/product/PRD-1/price", nil)
+		req.Header.Set(entity.HeaderAPIKey, "wck_key")
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		var actor, userId string
+		err := NewAuthHandler(nil, apiKeyUsecase, logutil.Discard()).VerifyToken(func(c echo.Context) error {
+			actor = actorId(c)
+			userId, _ = c.Get(entity.ContextUserId).(string)
+			return nil
+		})(c)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "USR-1", actor)
+		assert.Empty(t, userId)
+	})
+}
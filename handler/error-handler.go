@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	mimeProblemJSON = "application/problem+json"
+
+	statusClientClosedRequest = 499 // nginx convention, the client is gone so it is only logged
+)
+
+// Problem is the RFC 7807 error response, code is the one for the client to switch on since the detail may change
+type Problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Code      string                  `json:"code"`
+	RequestId string                  `json:"requestId,omitempty"`
+	Errors    []*errorutil.FieldError `json:"errors,omitempty"`
+}
+
+type problemKind struct {
+	err    error
+	status int
+	code   string
+}
+
+// problemKinds are matched in order by errors.Is, so a specific error must be before its general one
+var problemKinds = []problemKind{
+	{errorutil.ErrUniqueViolation, http.StatusConflict, "unique_violation"},
+	{errorutil.ErrBadRequest, http.StatusBadRequest, "bad_request"},
+	{errorutil.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{errorutil.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{errorutil.ErrNotFound, http.StatusNotFound, "not_found"},
+	{errorutil.ErrConflict, http.StatusConflict, "conflict"},
+	{errorutil.ErrTooManyRequests, http.StatusTooManyRequests, "too_many_requests"},
+	{errorutil.ErrUnavailable, http.StatusServiceUnavailable, "unavailable"},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, "timeout"},
+	{context.Canceled, statusClientClosedRequest, "canceled"},
+	{errorutil.ErrInternal, http.StatusInternalServerError, "internal"},
+}
+
+// serverErrorDetails replace the detail of a server error, the error itself (e.g. a query) must not be seen by the client
+var serverErrorDetails = map[int]string{
+	http.StatusInternalServerError: "Something went wrong, report it with the request id",
+	http.StatusServiceUnavailable:  "The service is unavailable, try again later",
+	http.StatusGatewayTimeout:      "The request took too long, try again later",
+}
+
+type ErrorHandler interface {
+	// HandleError responds the error returned by a handler or a middleware as problem+json, it is the echo HTTPErrorHandler
+	HandleError(err error, c echo.Context)
+}
+
+type errorHandler struct {
+	logger *slog.Logger
+}
+
+func NewErrorHandler(logger *slog.Logger) ErrorHandler {
+	return &errorHandler{logger}
+}
+
+func (h *errorHandler) HandleError(err error, c echo.Context) {
+	problem := newProblem(err, c)
+
+	if problem.Status >= http.StatusInternalServerError {
+		h.logger.ErrorContext(c.Request().Context(), "error request", "method", c.Request().Method, "path", c.Request().URL.Path, "error", err)
+	}
+
+	// e.g. a streamed export fails in the middle, the status is already sent
+	if c.Response().Committed || problem.Status == statusClientClosedRequest {
+		return
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, mimeProblemJSON)
+
+	var errResponse error
+	if c.Request().Method == http.MethodHead {
+		errResponse = c.NoContent(problem.Status)
+	} else {
+		errResponse = c.JSON(problem.Status, problem)
+	}
+	if errResponse != nil {
+		h.logger.ErrorContext(c.Request().Context(), "error respond problem", "error", errResponse)
+	}
+}
+
+func newProblem(err error, c echo.Context) *Problem {
+	problem := &Problem{
+		Type:      "about:blank",
+		Status:    http.StatusInternalServerError,
+		Code:      "internal",
+		RequestId: requestId(c),
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		// errors of echo and the generated server, e.g. route not found or invalid parameter
+		problem.Status = httpErr.Code
+		problem.Code = codeOfStatus(httpErr.Code)
+		problem.Detail = fmt.Sprint(httpErr.Message)
+
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(httpErr.Internal, &typeErr) {
+			problem.Detail = "request body is not valid"
+			problem.Errors = []*errorutil.FieldError{{
+				Field:   typeErr.Field,
+				Message: fmt.Sprintf("must be %s", typeErr.Type.String()),
+			}}
+		}
+	} else {
+		for _, kind := range problemKinds {
+			if errors.Is(err, kind.err) {
+				problem.Status = kind.status
+				problem.Code = kind.code
+				break
+			}
+		}
+		// the deadline error of a query is not always wrapped, the request context tells it
+		if problem.Status >= http.StatusInternalServerError && errors.Is(c.Request().Context().Err(), context.DeadlineExceeded) {
+			problem.Status = http.StatusGatewayTimeout
+			problem.Code = "timeout"
+		}
+
+		problem.Detail = errorutil.GetOriginalError(err).Error()
+		problem.Errors = errorutil.GetFields(err)
+	}
+
+	if problem.Status >= http.StatusInternalServerError {
+		problem.Detail = serverErrorDetails[problem.Status]
+		if problem.Detail == "" {
+			problem.Detail = serverErrorDetails[http.StatusInternalServerError]
+		}
+	}
+	problem.Title = http.StatusText(problem.Status)
+	if problem.Title == "" {
+		problem.Title = "Client Closed Request"
+	}
+
+	return problem
+}
+
+// codeOfStatus returns the code of an echo error by its status text, e.g. 'method_not_allowed'
+func codeOfStatus(status int) string {
+	if status >= http.StatusInternalServerError {
+		return "internal"
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// requestId is set by the request id middleware, the client sends it when it reports the error
+func requestId(c echo.Context) string {
+	return c.Response().Header().Get(echo.HeaderXRequestID)
+}
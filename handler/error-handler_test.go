@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleError(t *testing.T) {
+	handleError := func(req *http.Request, err error) (*httptest.ResponseRecorder, *Problem) {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.Response().Header().Set(echo.HeaderXRequestID, "REQ-1")
+
+		NewErrorHandler(logutil.Discard()).HandleError(err, c)
+
+		var problem Problem
+		_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+		return rec, &problem
+	}
+
+	t.Run("HandleError_validation error_then respond 400 with the fields", func(t *testing.T) {
+		err := errorutil.NewValidationError(errors.New("error register user validation: identifier is mandatory"),
+			&errorutil.FieldError{Field: "identifier", Message: "identifier is mandatory"})
+
+		rec, problem := handleError(httptest.NewRequest(http.MethodPost, "/user/register", nil), err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, mimeProblemJSON, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "bad_request", problem.Code)
+		assert.Equal(t, "REQ-1", problem.RequestId)
+		assert.Equal(t, "error register user validation: identifier is mandatory", problem.Detail)
+		assert.Equal(t, []*errorutil.FieldError{{Field: "identifier", Message: "identifier is mandatory"}}, problem.Errors)
+	})
+	t.Run("HandleError_unique violation_then respond 409", func(t *testing.T) {
+		err := errorutil.NewErrorCode(errorutil.ErrUniqueViolation, errors.New("identifier is already registered"))
+
+		rec, problem := handleError(httptest.NewRequest(http.MethodPost, "/user/register", nil), err)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "unique_violation", problem.Code)
+		assert.True(t, errors.Is(err, errorutil.ErrConflict))
+	})
+	t.Run("HandleError_wrapped not found_then respond 404", func(t *testing.T) {
+		err := fmt.Errorf("error get order: %w", errorutil.NewErrorCode(errorutil.ErrNotFound, errors.New("order is not found")))
+
+		rec, problem := handleError(httptest.NewRequest(http.MethodGet, "/orders/1", nil), err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "not_found", problem.Code)
+		assert.Equal(t, "order is not found", problem.Detail)
+	})
+	t.Run("HandleError_internal error_then respond 500 without the error", func(t *testing.T) {
+		err := errors.New("error repo get user: pq: relation \"users\" does not exist")
+
+		rec, problem := handleError(httptest.NewRequest(http.MethodGet, "/users/me", nil), err)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "internal", problem.Code)
+		assert.NotContains(t, rec.Body.String(), "pq:")
+	})
+	t.Run("HandleError_request deadline exceeded_then respond 504", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		<-ctx.Done()
+		req := httptest.NewRequest(http.MethodGet, "/users/me/export", nil).WithContext(ctx)
+
+		rec, problem := handleError(req, errors.New("error repo get orders: pq: canceling statement due to user request"))
+
+		assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+		assert.Equal(t, "timeout", problem.Code)
+	})
+	t.Run("HandleError_echo error_then respond its status", func(t *testing.T) {
+		rec, problem := handleError(httptest.NewRequest(http.MethodPut, "/health", nil), echo.ErrMethodNotAllowed)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "method_not_allowed", problem.Code)
+	})
+}
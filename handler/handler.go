@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 
@@ -15,22 +17,85 @@ import (
 )
 
 type handler struct {
+	authUsecase        usecase.AuthUsecaseInterface
 	userUsecase        usecase.UserUsecaseInterface
 	inventoryUsecase   usecase.InventoryUsecaseInterface
 	transactionUsecase usecase.TransactionUsecaseInterface
+	priceUsecase       usecase.PriceUsecaseInterface
+	catalogUsecase     usecase.CatalogUsecaseInterface
+	rbacUsecase        usecase.RbacUsecaseInterface
+	membershipUsecase  usecase.MembershipUsecaseInterface
+	apiKeyUsecase      usecase.APIKeyUsecaseInterface
+	privacyUsecase     usecase.PrivacyUsecaseInterface
+	healthUsecase      usecase.HealthUsecaseInterface
+	logger             *slog.Logger
 }
 
-func NewServer(userUsecase usecase.UserUsecaseInterface, inventoryUsecase usecase.InventoryUsecaseInterface, transactionUsecase usecase.TransactionUsecaseInterface) *handler {
+func NewServer(authUsecase usecase.AuthUsecaseInterface, userUsecase usecase.UserUsecaseInterface, inventoryUsecase usecase.InventoryUsecaseInterface, transactionUsecase usecase.TransactionUsecaseInterface, priceUsecase usecase.PriceUsecaseInterface, catalogUsecase usecase.CatalogUsecaseInterface, rbacUsecase usecase.RbacUsecaseInterface, membershipUsecase usecase.MembershipUsecaseInterface, apiKeyUsecase usecase.APIKeyUsecaseInterface, privacyUsecase usecase.PrivacyUsecaseInterface, healthUsecase usecase.HealthUsecaseInterface, logger *slog.Logger) *handler {
 	return &handler{
+		authUsecase:        authUsecase,
 		userUsecase:        userUsecase,
 		inventoryUsecase:   inventoryUsecase,
 		transactionUsecase: transactionUsecase,
+		priceUsecase:       priceUsecase,
+		catalogUsecase:     catalogUsecase,
+		rbacUsecase:        rbacUsecase,
+		membershipUsecase:  membershipUsecase,
+		apiKeyUsecase:      apiKeyUsecase,
+		privacyUsecase:     privacyUsecase,
+		healthUsecase:      healthUsecase,
+		logger:             logger,
 	}
 }
 
+const (
+	maxCatalogImportBytes = 20 << 20 // 20 MB
+)
+
+// GetHealth is kept for the probes that are not moved to /health/live yet
 func (h *handler) GetHealth(ctx echo.Context) error {
+	return h.GetHealthLive(ctx)
+}
+
+func (h *handler) GetHealthLive(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		"status": entity.HealthStatusOk,
+	})
+}
+
+// GetHealthReady responds 503 only if the server is down, a degraded server still takes the requests
+func (h *handler) GetHealthReady(ctx echo.Context) error {
+	report := h.healthUsecase.CheckReadiness(ctx.Request().Context())
+
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	if !report.IsReady() {
+		return ctx.JSON(http.StatusServiceUnavailable, report)
+	}
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// GetJwks returns the public keys, they are cached shortly by the client since a new key is added before it signs
+func (h *handler) GetJwks(ctx echo.Context) error {
+	ctx.Response().Header().Set("Cache-Control", "public, max-age=300")
 	return ctx.JSON(http.StatusOK, generalutil.MapAny{
-		"status": "ok",
+		"keys": h.authUsecase.GetJSONWebKeys(),
+	})
+}
+
+func (h *handler) RequestOtp(ctx echo.Context) error {
+	var req entity.RequestOtpRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+
+	err := h.userUsecase.RequestOtp(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusAccepted, generalutil.MapAny{
+		errorutil.Message: "Otp code is sent if the identifier can use the purpose",
 	})
 }
 
@@ -38,31 +103,18 @@ func (h *handler) RegisterUser(ctx echo.Context) error {
 	var req entity.RegisterUserRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	token, err := h.userUsecase.RegisterUser(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error register user: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+
+	authToken, err := h.userUsecase.RegisterUser(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, generated.RegisterUserResponse{
-		Token: token,
+		Token:        authToken.AccessToken,
+		RefreshToken: authToken.RefreshToken,
+		ExpiresIn:    authToken.ExpiredIn,
 	})
 }
 
@@ -70,35 +122,242 @@ func (h *handler) Login(ctx echo.Context) error {
 	var req entity.LoginRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	token, err := h.userUsecase.Login(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		case errorutil.ErrNotFound:
-			return ctx.JSON(http.StatusNotFound, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusNotFound, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error login: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+
+	authToken, err := h.userUsecase.Login(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generated.LoginResponse{
+		Token:        authToken.AccessToken,
+		RefreshToken: authToken.RefreshToken,
+		ExpiresIn:    authToken.ExpiredIn,
+	})
+}
+
+func (h *handler) RefreshToken(ctx echo.Context) error {
+	var req entity.RefreshSessionRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+
+	authToken, err := h.authUsecase.RefreshSession(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, generated.LoginResponse{
-		Token: token,
+		Token:        authToken.AccessToken,
+		RefreshToken: authToken.RefreshToken,
+		ExpiresIn:    authToken.ExpiredIn,
+	})
+}
+
+func (h *handler) Logout(ctx echo.Context) error {
+	sessionId, _ := ctx.Get(entity.ContextSessionId).(string)
+
+	err := h.authUsecase.RevokeSession(ctx.Request().Context(), sessionId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		errorutil.Message: "Successfully logged out",
+	})
+}
+
+func (h *handler) GetProfile(ctx echo.Context) error {
+	userId, _ := ctx.Get(entity.ContextUserId).(string)
+
+	user, err := h.userUsecase.GetProfile(ctx.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (h *handler) UpdateProfile(ctx echo.Context) error {
+	var req entity.UpdateProfileRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	req.UserId, _ = ctx.Get(entity.ContextUserId).(string)
+
+	user, err := h.userUsecase.UpdateProfile(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (h *handler) ChangeIdentifier(ctx echo.Context) error {
+	var req entity.ChangeIdentifierRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	req.UserId, _ = ctx.Get(entity.ContextUserId).(string)
+
+	user, err := h.userUsecase.ChangeIdentifier(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (h *handler) ExportUserData(ctx echo.Context) error {
+	userId, _ := ctx.Get(entity.ContextUserId).(string)
+
+	export, err := h.privacyUsecase.ExportUserData(ctx.Request().Context(), &entity.UserDataRequest{
+		UserId:      userId,
+		Channel:     entity.PrivacyRequestChannelAPI,
+		RequestedBy: userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=user-%s.json", userId))
+	return ctx.JSON(http.StatusOK, export)
+}
+
+func (h *handler) DeleteAccount(ctx echo.Context) error {
+	userId, _ := ctx.Get(entity.ContextUserId).(string)
+
+	err := h.privacyUsecase.DeleteUserData(ctx.Request().Context(), &entity.UserDataRequest{
+		UserId:      userId,
+		Channel:     entity.PrivacyRequestChannelAPI,
+		RequestedBy: userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		errorutil.Message: "Successfully deleted the account",
+	})
+}
+
+func (h *handler) GetAddresses(ctx echo.Context) error {
+	userId, _ := ctx.Get(entity.ContextUserId).(string)
+
+	addresses, err := h.userUsecase.GetAddresses(ctx.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		"addresses": addresses,
+	})
+}
+
+func (h *handler) CreateAddress(ctx echo.Context) error {
+	var req entity.UpsertAddressRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	req.UserId, _ = ctx.Get(entity.ContextUserId).(string)
+
+	address, err := h.userUsecase.CreateAddress(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, address)
+}
+
+func (h *handler) UpdateAddress(ctx echo.Context, addressId string) error {
+	var req entity.UpsertAddressRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	req.Id = addressId
+	req.UserId, _ = ctx.Get(entity.ContextUserId).(string)
+
+	address, err := h.userUsecase.UpdateAddress(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, address)
+}
+
+func (h *handler) DeleteAddress(ctx echo.Context, addressId string) error {
+	userId, _ := ctx.Get(entity.ContextUserId).(string)
+
+	err := h.userUsecase.DeleteAddress(ctx.Request().Context(), userId, addressId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		errorutil.Message: "Successfully deleted the address",
+	})
+}
+
+func (h *handler) AssignRoles(ctx echo.Context, userId string) error {
+	var req entity.AssignRolesRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	req.UserId = userId
+	req.ActorId, _ = ctx.Get(entity.ContextUserId).(string)
+
+	err := h.rbacUsecase.AssignRoles(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		errorutil.Message: "Successfully assigned the roles",
+	})
+}
+
+func (h *handler) CreateAPIKey(ctx echo.Context) error {
+	var req entity.CreateAPIKeyRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	req.ActorId, _ = ctx.Get(entity.ContextUserId).(string)
+
+	resp, err := h.apiKeyUsecase.CreateAPIKey(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, resp)
+}
+
+func (h *handler) GetAPIKeys(ctx echo.Context) error {
+	apiKeys, err := h.apiKeyUsecase.GetAPIKeys(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		"apiKeys": apiKeys,
+	})
+}
+
+func (h *handler) RevokeAPIKey(ctx echo.Context, apiKeyId string) error {
+	err := h.apiKeyUsecase.RevokeAPIKey(ctx.Request().Context(), apiKeyId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		errorutil.Message: "Successfully revoked the api key",
 	})
 }
 
@@ -106,29 +365,12 @@ func (h *handler) CreateWarehouse(ctx echo.Context) error {
 	var req entity.CreateWarehouseRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	warehouseId, err := h.inventoryUsecase.CreateWarehouse(&req)
-	if err != nil {
-		log.Printf("error create warehouse: %v\n", errorutil.GetOriginalError(err))
-
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error create warehouse: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+
+	warehouseId, err := h.inventoryUsecase.CreateWarehouse(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusCreated, generated.CreateWarehouseResponse{
@@ -141,26 +383,11 @@ func (h *handler) UpdateWarehouseStatus(ctx echo.Context, warehouseId string) er
 	req.Id = warehouseId
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	if err := h.inventoryUsecase.UpdateWarehouseStatus(&req); err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error update warehouse status: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+
+	if err := h.inventoryUsecase.UpdateWarehouseStatus(ctx.Request().Context(), &req); err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, generalutil.MapAny{
@@ -170,21 +397,25 @@ func (h *handler) UpdateWarehouseStatus(ctx echo.Context, warehouseId string) er
 
 func (h *handler) GetWarehouses(ctx echo.Context, req generated.GetWarehousesParams) error {
 	pagination := entity.ParseToPagination(req.Page, req.PageSize)
+	pagination.SetCursor(req.Cursor, req.SkipTotal)
+
+	sorts, err := entity.ParseToSorts(req.Sort)
+	if err != nil {
+		return err
+	}
 
-	resp, err := h.inventoryUsecase.GetWarehouses(&entity.GetWarehousesRequest{
+	filters, err := entity.ParseToFilters(req.Filter)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.inventoryUsecase.GetWarehouses(ctx.Request().Context(), &entity.GetWarehousesRequest{
 		Pagination: pagination,
+		Sorts:      sorts,
+		Filters:    filters,
 	})
 	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error get warehouses: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
@@ -194,27 +425,13 @@ func (h *handler) CreateShop(ctx echo.Context) error {
 	var req entity.CreateShopRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	shopId, err := h.inventoryUsecase.CreateShop(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error create shop: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+	req.OwnerId, _ = ctx.Get(entity.ContextUserId).(string)
+
+	shopId, err := h.inventoryUsecase.CreateShop(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusCreated, generated.CreateShopResponse{
@@ -224,21 +441,25 @@ func (h *handler) CreateShop(ctx echo.Context) error {
 
 func (h *handler) GetShops(ctx echo.Context, req generated.GetShopsParams) error {
 	pagination := entity.ParseToPagination(req.Page, req.PageSize)
+	pagination.SetCursor(req.Cursor, req.SkipTotal)
+
+	sorts, err := entity.ParseToSorts(req.Sort)
+	if err != nil {
+		return err
+	}
+
+	filters, err := entity.ParseToFilters(req.Filter)
+	if err != nil {
+		return err
+	}
 
-	resp, err := h.inventoryUsecase.GetShops(&entity.GetShopsRequest{
+	resp, err := h.inventoryUsecase.GetShops(ctx.Request().Context(), &entity.GetShopsRequest{
 		Pagination: pagination,
+		Sorts:      sorts,
+		Filters:    filters,
 	})
 	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error get shops: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
@@ -248,27 +469,14 @@ func (h *handler) UpsertShopToWarehouses(ctx echo.Context) error {
 	var req entity.UpsertShopToWarehousesRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	err := h.inventoryUsecase.UpsertShopToWarehouses(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error upsert shop to warehouses: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+
+	req.Actor = shopActor(ctx)
+
+	err := h.inventoryUsecase.UpsertShopToWarehouses(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusCreated, generalutil.MapAny{
@@ -280,27 +488,15 @@ func (h *handler) CreateProduct(ctx echo.Context) error {
 	var req entity.CreateProductRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	productId, err := h.inventoryUsecase.CreateProduct(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error create product: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+
+	// the actor is recorded in price history of the initial price
+	req.ActorId = actorId(ctx)
+
+	productId, err := h.inventoryUsecase.CreateProduct(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusCreated, generated.CreateProductResponse{
@@ -312,29 +508,14 @@ func (h *handler) UpdateProductStock(ctx echo.Context, productId string) error {
 	var req entity.UpdateProductWarehouseTotalStockRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
+		return err
 	}
 
 	req.ProductId = productId
 
-	err := h.inventoryUsecase.UpdateProductStock(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error update product stock: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+	err := h.inventoryUsecase.UpdateProductStock(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, generalutil.MapAny{
@@ -346,27 +527,12 @@ func (h *handler) TransferProduct(ctx echo.Context) error {
 	var req entity.TransferProductRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	err := h.inventoryUsecase.TransferProduct(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error transfer product: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+
+	err := h.inventoryUsecase.TransferProduct(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, generalutil.MapAny{
@@ -374,68 +540,280 @@ func (h *handler) TransferProduct(ctx echo.Context) error {
 	})
 }
 
+func (h *handler) ImportCatalog(ctx echo.Context, params generated.ImportCatalogParams) error {
+	req := entity.ImportCatalogRequest{
+		Format: catalogFormatFromContentType(ctx.Request().Header.Get(echo.HeaderContentType)),
+		Reader: http.MaxBytesReader(ctx.Response(), ctx.Request().Body, maxCatalogImportBytes),
+	}
+	if params.Format != nil {
+		req.Format = string(*params.Format)
+	}
+	if params.DryRun != nil {
+		req.DryRun = *params.DryRun
+	}
+	req.ActorId = actorId(ctx)
+
+	job, err := h.catalogUsecase.ImportCatalog(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case job.Status == entity.CatalogImportJobStatusFailed:
+		return ctx.JSON(http.StatusBadRequest, job)
+	case job.DryRun:
+		return ctx.JSON(http.StatusOK, job)
+	default:
+		return ctx.JSON(http.StatusAccepted, job)
+	}
+}
+
+func (h *handler) GetCatalogImportJob(ctx echo.Context, jobId string) error {
+	job, err := h.catalogUsecase.GetImportJob(ctx.Request().Context(), jobId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, job)
+}
+
+func (h *handler) ExportCatalog(ctx echo.Context, params generated.ExportCatalogParams) error {
+	req := entity.ExportCatalogRequest{
+		Format: entity.CatalogFormatCSV,
+		Writer: ctx.Response(),
+	}
+	if params.Format != nil {
+		req.Format = string(*params.Format)
+	}
+
+	contentType := "text/csv"
+	if req.Format == entity.CatalogFormatJSONL {
+		contentType = "application/x-ndjson"
+	}
+	ctx.Response().Header().Set(echo.HeaderContentType, contentType)
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=catalog.%s", req.Format))
+
+	if err := h.catalogUsecase.ExportCatalog(ctx.Request().Context(), &req); err != nil {
+		// the rows are streamed, so error can only be responded if nothing is written yet
+		if ctx.Response().Committed {
+			h.logger.ErrorContext(ctx.Request().Context(), "error export catalog after the rows are written", "error", err)
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// catalogFormatFromContentType returns catalog format of the content type, csv is the default
+func catalogFormatFromContentType(contentType string) string {
+	if strings.HasPrefix(contentType, "application/x-ndjson") || strings.HasPrefix(contentType, "application/jsonl") {
+		return entity.CatalogFormatJSONL
+	}
+	return entity.CatalogFormatCSV
+}
+
 func (h *handler) GetProductsByShopId(ctx echo.Context, shopId string, params generated.GetProductsByShopIdParams) error {
 	pagination := entity.ParseToPagination(params.Page, params.PageSize)
+	pagination.SetCursor(params.Cursor, params.SkipTotal)
+
+	sorts, err := entity.ParseToSorts(params.Sort)
+	if err != nil {
+		return err
+	}
+
+	filters, err := entity.ParseToFilters(params.Filter)
+	if err != nil {
+		return err
+	}
 
-	resp, err := h.transactionUsecase.GetProductDetailsByShopId(&entity.GetProductDetailsByShopIdRequest{
+	resp, err := h.transactionUsecase.GetProductDetailsByShopId(ctx.Request().Context(), &entity.GetProductDetailsByShopIdRequest{
 		ShopId:     shopId,
 		Pagination: pagination,
+		Sorts:      sorts,
+		Filters:    filters,
 	})
 	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error get products by shop id: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
 }
 
+func (h *handler) UpdateProductPrice(ctx echo.Context, productId string) error {
+	var req entity.UpdateProductPriceRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+
+	req.ProductId = productId
+	req.ActorId = actorId(ctx)
+
+	if err := h.priceUsecase.UpdateProductPrice(ctx.Request().Context(), &req); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		errorutil.Message: "Product price is updated",
+	})
+}
+
+func (h *handler) GetProductPrice(ctx echo.Context, productId string, params generated.GetProductPriceParams) error {
+	req := entity.GetProductPriceAtRequest{
+		ProductId: productId,
+		At:        params.At,
+	}
+	if params.ShopId != nil {
+		req.ShopId = *params.ShopId
+	}
+
+	resp, err := h.priceUsecase.GetProductPriceAt(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *handler) UpsertShopProductPrice(ctx echo.Context, shopId string, productId string) error {
+	var req entity.UpsertShopProductPriceRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+
+	req.ShopId = shopId
+	req.ProductId = productId
+	req.ActorId = actorId(ctx)
+	req.Actor = shopActor(ctx)
+
+	if err := h.priceUsecase.UpsertShopProductPrice(ctx.Request().Context(), &req); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		errorutil.Message: "Shop product price is set",
+	})
+}
+
+func (h *handler) CreatePriceList(ctx echo.Context, shopId string) error {
+	var req entity.CreatePriceListRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+
+	req.ShopId = shopId
+	req.Actor = shopActor(ctx)
+
+	priceListId, err := h.priceUsecase.CreatePriceList(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, generated.CreatePriceListResponse{
+		Id: priceListId,
+	})
+}
+
+func (h *handler) GetPriceLists(ctx echo.Context, shopId string) error {
+	resp, err := h.priceUsecase.GetPriceLists(ctx.Request().Context(), &entity.GetPriceListsRequest{
+		ShopId: shopId,
+		Actor:  shopActor(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *handler) GetShopMembers(ctx echo.Context, shopId string) error {
+	members, err := h.membershipUsecase.GetShopMembers(ctx.Request().Context(), &entity.GetShopMembersRequest{
+		ShopId: shopId,
+		Actor:  shopActor(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		"members": members,
+	})
+}
+
+func (h *handler) RemoveShopMember(ctx echo.Context, shopId string, userId string) error {
+	err := h.membershipUsecase.RemoveShopMember(ctx.Request().Context(), &entity.RemoveShopMemberRequest{
+		ShopId: shopId,
+		UserId: userId,
+		Actor:  shopActor(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		errorutil.Message: "Successfully removed the shop member",
+	})
+}
+
+func (h *handler) InviteShopMember(ctx echo.Context, shopId string) error {
+	var req entity.InviteShopMemberRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	req.ShopId = shopId
+	req.Actor = shopActor(ctx)
+
+	invitationId, err := h.membershipUsecase.InviteShopMember(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, generalutil.MapAny{
+		"id": invitationId,
+	})
+}
+
+func (h *handler) AcceptShopInvitation(ctx echo.Context, invitationId string) error {
+	userId, _ := ctx.Get(entity.ContextUserId).(string)
+
+	shopId, err := h.membershipUsecase.AcceptShopInvitation(ctx.Request().Context(), &entity.AcceptShopInvitationRequest{
+		InvitationId: invitationId,
+		UserId:       userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, generalutil.MapAny{
+		"shopId": shopId,
+	})
+}
+
 func (h *handler) OrderProducts(ctx echo.Context, shopId string) error {
 	userIdInterface := ctx.Get(entity.ContextUserId)
 	userId, ok := userIdInterface.(string)
 	if !ok {
-		return ctx.JSON(http.StatusUnauthorized, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, fmt.Errorf("order products: invalid user id in context")),
-		})
+		return errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("user is not authenticated"))
 	}
 
 	var req entity.OrderProductsRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+
+	// user and shop are set after binding, so the body can not order as other user
 	req.UserId = userId
 	req.ShopId = shopId
 
-	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	orderId, err := h.transactionUsecase.OrderProducts(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error order products: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+	orderId, err := h.transactionUsecase.OrderProducts(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, generated.OrderProductsResponse{
@@ -447,38 +825,22 @@ func (h *handler) PayOrder(ctx echo.Context, orderId string) error {
 	userIdInterface := ctx.Get(entity.ContextUserId)
 	userId, ok := userIdInterface.(string)
 	if !ok {
-		return ctx.JSON(http.StatusUnauthorized, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, fmt.Errorf("order products: invalid user id in context")),
-		})
+		return errorutil.NewErrorCode(errorutil.ErrUnauthorized, errors.New("user is not authenticated"))
 	}
 
-	req := entity.PayOrderRequest{
-		UserId:  userId,
-		OrderId: orderId,
-	}
+	var req entity.PayOrderRequest
 
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-			errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-		})
-	}
-
-	err := h.transactionUsecase.PayOrder(&req)
-	if err != nil {
-		switch errorutil.GetErrorType(err) {
-		case errorutil.ErrBadRequest:
-			return ctx.JSON(http.StatusBadRequest, generalutil.MapAny{
-				errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusBadRequest, errorutil.GetOriginalError(err)),
-			})
-		default:
-			if ctx != nil {
-				return ctx.JSON(http.StatusInternalServerError, generalutil.MapAny{
-					errorutil.Error: errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err),
-				})
-			}
-			log.Printf("error pay order: %v\n", errorutil.CombineHTTPErrorMessage(http.StatusInternalServerError, err))
-			return nil
-		}
+		return err
+	}
+
+	// user and order are set after binding, so the body can not pay as other user
+	req.UserId = userId
+	req.OrderId = orderId
+
+	err := h.transactionUsecase.PayOrder(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, generalutil.MapAny{
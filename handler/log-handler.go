@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"log/slog"
+	"mfawzanid/warehouse-commerce/core/entity"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// requestIdPattern limits the request id sent by the client, so it can not inject anything to the logs
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type LogHandler interface {
+	// RequestId keeps the X-Request-ID of the client or assigns a new one, it is in the response and in the logs of the request
+	RequestId(next echo.HandlerFunc) echo.HandlerFunc
+	// AccessLog logs every request with its status and latency, it must be used after RequestId
+	AccessLog(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type logHandler struct {
+	logger *slog.Logger
+}
+
+func NewLogHandler(logger *slog.Logger) LogHandler {
+	return &logHandler{logger}
+}
+
+func (h *logHandler) RequestId(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestId := c.Request().Header.Get(echo.HeaderXRequestID)
+		if !requestIdPattern.MatchString(requestId) {
+			requestId = logutil.NewRequestId()
+		}
+
+		c.Response().Header().Set(echo.HeaderXRequestID, requestId)
+		c.SetRequest(c.Request().WithContext(logutil.WithRequestId(c.Request().Context(), requestId)))
+
+		return next(c)
+	}
+}
+
+func (h *logHandler) AccessLog(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+
+		// the error is responded here, so the status is known by the log
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+
+		req, res := c.Request(), c.Response()
+		level := slog.LevelInfo
+		switch {
+		case res.Status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case res.Status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.String("route", c.Path()),
+			slog.Int("status", res.Status),
+			slog.Duration("latency", time.Since(start)),
+			slog.Int64("bytes", res.Size),
+			slog.String("ip", c.RealIP()),
+		}
+		if userId, _ := c.Get(entity.ContextUserId).(string); userId != "" {
+			attrs = append(attrs, slog.String("userId", userId))
+		}
+		if apiKeyId, _ := c.Get(entity.ContextAPIKeyId).(string); apiKeyId != "" {
+			attrs = append(attrs, slog.String("apiKeyId", apiKeyId))
+		}
+
+		h.logger.LogAttrs(req.Context(), level, "access", attrs...)
+
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestId(t *testing.T) {
+	serve := func(requestId string) (string, string) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set(echo.HeaderXRequestID, requestId)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+
+		var contextRequestId string
+		_ = NewLogHandler(logutil.Discard()).RequestId(func(c echo.Context) error {
+			contextRequestId = logutil.RequestId(c.Request().Context())
+			return nil
+		})(c)
+
+		return rec.Header().Get(echo.HeaderXRequestID), contextRequestId
+	}
+
+	t.Run("RequestId_client sends the id_then keep it", func(t *testing.T) {
+		responseRequestId, contextRequestId := serve("order-flow-1")
+
+		assert.Equal(t, "order-flow-1", responseRequestId)
+		assert.Equal(t, "order-flow-1", contextRequestId)
+	})
+	t.Run("RequestId_client sends invalid id_then assign new one", func(t *testing.T) {
+		responseRequestId, contextRequestId := serve("bad id\nfake log line")
+
+		assert.Len(t, responseRequestId, 32)
+		assert.Equal(t, responseRequestId, contextRequestId)
+	})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"mfawzanid/warehouse-commerce/core/metrics"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// routeUnmatched is the route of the requests that are not routed, so an unknown path does not add a new series
+const routeUnmatched = "unmatched"
+
+type MetricsHandler interface {
+	// Metrics records the latency of every request by its route template (e.g. /orders/:orderId) and its status
+	Metrics(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type metricsHandler struct {
+	metrics *metrics.Metrics
+}
+
+func NewMetricsHandler(metrics *metrics.Metrics) MetricsHandler {
+	return &metricsHandler{metrics}
+}
+
+func (h *metricsHandler) Metrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+
+		// the error is responded here, so the status is known
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+
+		route := c.Path()
+		if route == "" {
+			route = routeUnmatched
+		}
+
+		h.metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request().Method, route, strconv.Itoa(c.Response().Status)).
+			Observe(time.Since(start).Seconds())
+
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"mfawzanid/warehouse-commerce/core/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	// serve returns the labels of the recorded request
+	serve := func(path string) map[string]string {
+		m := metrics.NewMetrics()
+		e := echo.New()
+		e.Use(NewMetricsHandler(m).Metrics)
+		e.GET("/orders/:orderId", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+
+		labels := map[string]string{}
+		families, _ := m.Registry.Gather()
+		for _, family := range families {
+			if family.GetName() != "warehouse_http_request_duration_seconds" {
+				continue
+			}
+			assert.Len(t, family.GetMetric(), 1)
+			for _, label := range family.GetMetric()[0].GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+		}
+		return labels
+	}
+
+	t.Run("Metrics_routed request_then record it by the route template", func(t *testing.T) {
+		labels := serve("/orders/ORD-1")
+
+		assert.Equal(t, map[string]string{"method": http.MethodGet, "route": "/orders/:orderId", "status": "200"}, labels)
+	})
+	t.Run("Metrics_unknown path_then record it as unmatched", func(t *testing.T) {
+		labels := serve("/unknown/path")
+
+		assert.Equal(t, map[string]string{"method": http.MethodGet, "route": routeUnmatched, "status": "404"}, labels)
+	})
+}
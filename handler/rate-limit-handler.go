@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mfawzanid/warehouse-commerce/core/entity"
+	"mfawzanid/warehouse-commerce/core/usecase"
+	errorutil "mfawzanid/warehouse-commerce/utils/error"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+
+	maxIdentifierBodySize = 4 << 10 // the identifier is read from the start of the body, an auth body is small
+)
+
+type RateLimitHandler interface {
+	// RateLimit throttles the route by its rules, it must be used after VerifyToken to count by the user or the api key
+	RateLimit(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type rateLimitHandler struct {
+	rateLimitUsecase usecase.RateLimitUsecaseInterface
+	routeRateLimits  map[string][]entity.RateLimitRule // rules of each throttled route keyed by `METHOD path`, a request must be allowed by all of them
+}
+
+func NewRateLimitHandler(rateLimitUsecase usecase.RateLimitUsecaseInterface, routeRateLimits map[string][]entity.RateLimitRule) RateLimitHandler {
+	return &rateLimitHandler{rateLimitUsecase, routeRateLimits}
+}
+
+func (h *rateLimitHandler) RateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		route := routeKey(c.Request().Method, c.Path())
+
+		rules, ok := h.routeRateLimits[route]
+		if !ok {
+			return next(c)
+		}
+
+		userId, _ := c.Get(entity.ContextUserId).(string)
+		apiKeyId, _ := c.Get(entity.ContextAPIKeyId).(string)
+
+		var identifier string
+		for _, rule := range rules {
+			if rule.KeyBy == entity.RateLimitKeyIdentifier {
+				identifier = requestIdentifier(c)
+				break
+			}
+		}
+
+		for i, rule := range rules {
+			result, err := h.rateLimitUsecase.AllowRequest(c.Request().Context(), &entity.RateLimitRequest{
+				Route:      fmt.Sprintf("%s:%d", route, i),
+				Rule:       rule,
+				IP:         c.RealIP(),
+				UserId:     userId,
+				APIKeyId:   apiKeyId,
+				Identifier: identifier,
+			})
+			if err != nil {
+				return err
+			}
+
+			// the headers of the first rule are returned, it is the tightest one
+			if i == 0 {
+				c.Response().Header().Set(headerRateLimitLimit, strconv.Itoa(result.Limit))
+				c.Response().Header().Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+			}
+
+			if !result.Allowed {
+				retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(retryAfter, 1)))
+				return errorutil.NewErrorCode(errorutil.ErrTooManyRequests, errors.New("too many requests, try again later"))
+			}
+		}
+
+		return next(c)
+	}
+}
+
+// requestIdentifier reads the identifier (email or phone number) from the json body of an auth request,
+// the body is restored so the handler can bind it. It is empty if the body has no identifier.
+func requestIdentifier(c echo.Context) string {
+	request := c.Request()
+	if request.Body == nil {
+		return ""
+	}
+
+	head, err := io.ReadAll(io.LimitReader(request.Body, maxIdentifierBodySize))
+	request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), request.Body), request.Body}
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		Identifier string `json:"identifier"`
+	}
+	if err := json.Unmarshal(head, &body); err != nil {
+		return ""
+	}
+
+	return body.Identifier
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIdentifier(t *testing.T) {
+	t.Run("RequestIdentifier_login body_then return the identifier and keep the body for the handler", func(t *testing.T) {
+		body := `{"identifierType":"email","identifier":"user@mail.com","password":"secret"}`
+		req := httptest.NewRequest(http.MethodPost, "/user/login", strings.NewReader(body))
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		identifier := requestIdentifier(c)
+
+		assert.Equal(t, "user@mail.com", identifier)
+		restored, err := io.ReadAll(c.Request().Body)
+		assert.Nil(t, err)
+		assert.Equal(t, body, string(restored))
+	})
+	t.Run("RequestIdentifier_body is not json_then return empty", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/user/login", strings.NewReader("identifier=user@mail.com"))
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		assert.Empty(t, requestIdentifier(c))
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"mfawzanid/warehouse-commerce/core/entity"
+	"net/http"
+)
+
+type routePermission struct {
+	permission string // empty permission only needs the user to be logged in
+}
+
+// routePermissions maps each protected route to its required permission, a route that is not mapped is denied
+var routePermissions = map[string]routePermission{
+	routeKey(http.MethodPost, "/user/logout"):                            {},
+	routeKey(http.MethodGet, "/api/v1/users/me"):                         {}, // the user of the token
+	routeKey(http.MethodPut, "/api/v1/users/me"):                         {},
+	routeKey(http.MethodDelete, "/api/v1/users/me"):                      {},
+	routeKey(http.MethodGet, "/api/v1/users/me/export"):                  {},
+	routeKey(http.MethodPut, "/api/v1/users/me/identifier"):              {},
+	routeKey(http.MethodGet, "/api/v1/users/me/addresses"):               {},
+	routeKey(http.MethodPost, "/api/v1/users/me/addresses"):              {},
+	routeKey(http.MethodPut, "/api/v1/users/me/addresses/:addressId"):    {},
+	routeKey(http.MethodDelete, "/api/v1/users/me/addresses/:addressId"): {},
+
+	routeKey(http.MethodGet, "/api/v1/warehouses"):                              {permission: entity.PermissionWarehouseRead},
+	routeKey(http.MethodPost, "/api/v1/warehouses"):                             {permission: entity.PermissionWarehouseWrite},
+	routeKey(http.MethodPut, "/api/v1/warehouses/:warehouseId/status"):          {permission: entity.PermissionWarehouseWrite},
+	routeKey(http.MethodGet, "/api/v1/shops"):                                   {permission: entity.PermissionShopRead},
+	routeKey(http.MethodPost, "/api/v1/shops"):                                  {permission: entity.PermissionShopWrite},
+	routeKey(http.MethodPost, "/api/v1/upsert-shop-warehouses"):                 {}, // shop is in body, the membership is checked by the usecase
+	routeKey(http.MethodGet, "/api/v1/shops/:shopId/products"):                  {permission: entity.PermissionShopRead},
+	routeKey(http.MethodPost, "/api/v1/products"):                               {permission: entity.PermissionProductWrite},
+	routeKey(http.MethodGet, "/api/v1/product/:productId/price"):                {permission: entity.PermissionProductRead},
+	routeKey(http.MethodPut, "/api/v1/product/:productId/price"):                {permission: entity.PermissionProductWrite},
+	routeKey(http.MethodPut, "/api/v1/product/:productId/stock"):                {permission: entity.PermissionStockWrite},
+	routeKey(http.MethodPost, "/api/v1/product/transfer"):                       {permission: entity.PermissionStockWrite},
+	routeKey(http.MethodPut, "/api/v1/shops/:shopId/products/:productId/price"): {permission: entity.PermissionPriceWrite}, // the membership is checked by the usecase
+	routeKey(http.MethodGet, "/api/v1/shops/:shopId/price-lists"):               {permission: entity.PermissionPriceWrite},
+	routeKey(http.MethodPost, "/api/v1/shops/:shopId/price-lists"):              {permission: entity.PermissionPriceWrite},
+	routeKey(http.MethodGet, "/api/v1/shops/:shopId/members"):                   {}, // the membership is checked by the usecase
+	routeKey(http.MethodDelete, "/api/v1/shops/:shopId/members/:userId"):        {},
+	routeKey(http.MethodPost, "/api/v1/shops/:shopId/invitations"):              {},
+	routeKey(http.MethodPost, "/api/v1/shop-invitations/:invitationId/accept"):  {}, // the user must be the invited one
+	routeKey(http.MethodPost, "/api/v1/catalog/import"):                         {permission: entity.PermissionCatalogImport},
+	routeKey(http.MethodGet, "/api/v1/catalog/import-jobs/:jobId"):              {permission: entity.PermissionCatalogImport},
+	routeKey(http.MethodGet, "/api/v1/catalog/export"):                          {permission: entity.PermissionCatalogExport},
+	routeKey(http.MethodPost, "/api/v1/shop/:shopId/order"):                     {permission: entity.PermissionOrderCreate},
+	routeKey(http.MethodPost, "/api/v1/order/:orderId/pay"):                     {permission: entity.PermissionOrderPay},
+	routeKey(http.MethodPut, "/api/v1/users/:userId/roles"):                     {permission: entity.PermissionRoleAssign},
+	routeKey(http.MethodGet, "/api/v1/api-keys"):                                {permission: entity.PermissionAPIKeyManage},
+	routeKey(http.MethodPost, "/api/v1/api-keys"):                               {permission: entity.PermissionAPIKeyManage},
+	routeKey(http.MethodDelete, "/api/v1/api-keys/:apiKeyId"):                   {permission: entity.PermissionAPIKeyManage},
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
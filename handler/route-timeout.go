@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+)
+
+// routeTimeouts overrides the default request timeout of the routes that take longer,
+// zero means the route has no deadline and is only cancelled if the client is gone (e.g. streaming)
+var routeTimeouts = map[string]time.Duration{
+	routeKey(http.MethodPost, "/api/v1/catalog/import"): 2 * time.Minute, // the file is validated before the job is started
+	routeKey(http.MethodGet, "/api/v1/catalog/export"):  0,
+	routeKey(http.MethodGet, "/api/v1/users/me/export"): time.Minute,
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TimeoutHandler interface {
+	// Timeout sets the deadline of the request context, so the db and redis work of a slow request is cancelled
+	Timeout(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type timeoutHandler struct {
+	defaultTimeout time.Duration
+	routeTimeouts  map[string]time.Duration
+}
+
+func NewTimeoutHandler(defaultTimeout time.Duration) TimeoutHandler {
+	return &timeoutHandler{defaultTimeout, routeTimeouts}
+}
+
+func (h *timeoutHandler) Timeout(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		timeout, ok := h.routeTimeouts[routeKey(c.Request().Method, c.Path())]
+		if !ok {
+			timeout = h.defaultTimeout
+		}
+		if timeout <= 0 {
+			return next(c)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+		defer cancel()
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		return next(c)
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"fmt"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	traceutil "mfawzanid/warehouse-commerce/utils/trace"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TraceHandler interface {
+	// Trace starts the span of every request, it continues the trace of the traceparent header of the client.
+	// It must be used after RequestId and before AccessLog, so the access log has the trace id.
+	Trace(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type traceHandler struct{}
+
+func NewTraceHandler() TraceHandler {
+	return &traceHandler{}
+}
+
+func (h *traceHandler) Trace(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+		route := c.Path()
+		if route == "" {
+			route = routeUnmatched
+		}
+
+		ctx, span := traceutil.Tracer().Start(ctx, fmt.Sprintf("%s %s", req.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(req.URL.Path),
+				attribute.String(logutil.KeyRequestId, logutil.RequestId(ctx)),
+			),
+		)
+		defer span.End()
+		c.SetRequest(req.WithContext(ctx))
+
+		// the error is responded here, so the status is known
+		if err := next(c); err != nil {
+			span.RecordError(err)
+			c.Error(err)
+		}
+
+		status := c.Response().Status
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"errors"
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	serve := func(req *http.Request) sdktrace.ReadOnlySpan {
+		e := echo.New()
+		e.HTTPErrorHandler = NewErrorHandler(logutil.Discard()).HandleError
+		e.Use(NewTraceHandler().Trace)
+		e.GET("/orders/:orderId", func(c echo.Context) error {
+			if c.Param("orderId") == "broken" {
+				return errors.New("error repo get order: connection refused")
+			}
+			return c.NoContent(http.StatusOK)
+		})
+
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		spans := recorder.Ended()
+		return spans[len(spans)-1]
+	}
+
+	t.Run("Trace_client sends traceparent_then continue its trace", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/orders/ORD-1", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		span := serve(req)
+
+		assert.Equal(t, "GET /orders/:orderId", span.Name())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	})
+	t.Run("Trace_server error_then the span is error", func(t *testing.T) {
+		span := serve(httptest.NewRequest(http.MethodGet, "/orders/broken", nil))
+
+		assert.Equal(t, codes.Error, span.Status().Code)
+	})
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS product_warehouses;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS shop_warehouses;
+DROP TABLE IF EXISTS shops;
+DROP TABLE IF EXISTS warehouses;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,9 @@
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS fk_item_price_list;
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS fk_item_price_history;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_list_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_history_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_source;
+DROP TABLE IF EXISTS price_history;
+DROP TABLE IF EXISTS price_list_items;
+DROP TABLE IF EXISTS price_lists;
+DROP TABLE IF EXISTS shop_product_prices;
@@ -0,0 +1,54 @@
+-- price override of a product in a shop, replaces products.price for the shop
+CREATE TABLE shop_product_prices (
+    shop_id VARCHAR(20) NOT NULL,
+    product_id VARCHAR(20) NOT NULL,
+    price INTEGER NOT NULL,
+    UNIQUE(shop_id, product_id),
+    CONSTRAINT fk_spp_shop FOREIGN KEY (shop_id) REFERENCES shops(id),
+    CONSTRAINT fk_spp_product FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+-- scheduled prices of a shop (e.g. weekend sale), only active between start_at and end_at
+CREATE TABLE price_lists (
+    id VARCHAR(20) PRIMARY KEY,
+    shop_id VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP, -- null means no end
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_pl_shop FOREIGN KEY (shop_id) REFERENCES shops(id)
+);
+CREATE INDEX idx_price_lists_shop_id_start_at ON price_lists(shop_id, start_at);
+
+-- product prices of a price list
+CREATE TABLE price_list_items (
+    price_list_id VARCHAR(20) NOT NULL,
+    product_id VARCHAR(20) NOT NULL,
+    price INTEGER NOT NULL,
+    UNIQUE(price_list_id, product_id),
+    CONSTRAINT fk_pli_price_list FOREIGN KEY (price_list_id) REFERENCES price_lists(id),
+    CONSTRAINT fk_pli_product FOREIGN KEY (product_id) REFERENCES products(id)
+);
+CREATE INDEX idx_price_list_items_product_id ON price_list_items(product_id);
+
+-- every change of base price (shop_id is null) or shop override price
+CREATE TABLE price_history (
+    id VARCHAR(20) PRIMARY KEY,
+    product_id VARCHAR(20) NOT NULL,
+    shop_id VARCHAR(20),
+    source VARCHAR(20) NOT NULL, -- base or shop
+    price INTEGER NOT NULL,
+    actor_id VARCHAR(20), -- user that changed the price
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_ph_product FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT fk_ph_shop FOREIGN KEY (shop_id) REFERENCES shops(id),
+    CONSTRAINT fk_ph_actor FOREIGN KEY (actor_id) REFERENCES users(id)
+);
+CREATE INDEX idx_price_history_product_id_shop_id_created_at ON price_history(product_id, shop_id, created_at DESC); -- there is need to get price at a time
+
+-- price record that is used by the order item, the existing items have none
+ALTER TABLE order_items ADD COLUMN price_source VARCHAR(20); -- base, shop or priceList
+ALTER TABLE order_items ADD COLUMN price_history_id VARCHAR(20); -- price record that is used if price source is base or shop
+ALTER TABLE order_items ADD COLUMN price_list_id VARCHAR(20); -- price record that is used if price source is price list
+ALTER TABLE order_items ADD CONSTRAINT fk_item_price_history FOREIGN KEY (price_history_id) REFERENCES price_history(id);
+ALTER TABLE order_items ADD CONSTRAINT fk_item_price_list FOREIGN KEY (price_list_id) REFERENCES price_lists(id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS catalog_import_jobs;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_sku_key;
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
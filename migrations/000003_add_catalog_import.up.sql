@@ -0,0 +1,19 @@
+-- merchant identifier of the product, used to upsert by catalog import
+ALTER TABLE products ADD COLUMN sku VARCHAR(50);
+ALTER TABLE products ADD CONSTRAINT products_sku_key UNIQUE (sku);
+
+-- catalog import that is run in background, the progress is saved periodically
+CREATE TABLE catalog_import_jobs (
+    id VARCHAR(20) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL, -- running, succeeded or failed
+    format VARCHAR(10) NOT NULL, -- csv or jsonl
+    total_rows INTEGER NOT NULL,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    succeeded_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]', -- error of each failed row
+    actor_id VARCHAR(20),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_cij_actor FOREIGN KEY (actor_id) REFERENCES users(id)
+);
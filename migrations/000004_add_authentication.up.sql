@@ -0,0 +1,36 @@
+-- bcrypt hash, user without password logs in with otp
+ALTER TABLE users ADD COLUMN password_hash VARCHAR(100);
+
+-- one time password to verify the identifier (email or phone number), only the hash of the code is stored
+CREATE TABLE user_otps (
+    id VARCHAR(20) PRIMARY KEY,
+    identifier VARCHAR(50) NOT NULL,
+    purpose VARCHAR(20) NOT NULL, -- register, login
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expired_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_user_otps_identifier_created_at ON user_otps(identifier, created_at DESC); -- there is need to get the latest otp and count the requests
+
+-- login session of a user, revoking it revokes all access and refresh tokens of the login
+CREATE TABLE user_sessions (
+    id VARCHAR(20) PRIMARY KEY,
+    user_id VARCHAR(20) NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_session_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- refresh tokens rotated in a session, used token is kept to detect reuse
+CREATE TABLE refresh_tokens (
+    id VARCHAR(20) PRIMARY KEY,
+    session_id VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expired_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_refresh_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_refresh_token_session FOREIGN KEY (session_id) REFERENCES user_sessions(id)
+);
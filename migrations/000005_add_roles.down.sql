@@ -0,0 +1,6 @@
+ALTER TABLE shops DROP CONSTRAINT IF EXISTS fk_shop_owner;
+ALTER TABLE shops DROP COLUMN IF EXISTS owner_id;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
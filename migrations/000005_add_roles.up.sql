@@ -0,0 +1,80 @@
+-- roles and permissions, a user can have some roles and the permissions of the roles are put in the access token
+CREATE TABLE roles (
+    id VARCHAR(30) PRIMARY KEY,
+    description VARCHAR(200)
+);
+
+CREATE TABLE permissions (
+    id VARCHAR(30) PRIMARY KEY,
+    description VARCHAR(200)
+);
+
+CREATE TABLE role_permissions (
+    role_id VARCHAR(30),
+    permission_id VARCHAR(30),
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permission_role FOREIGN KEY (role_id) REFERENCES roles(id),
+    CONSTRAINT fk_role_permission_permission FOREIGN KEY (permission_id) REFERENCES permissions(id)
+);
+
+CREATE TABLE user_roles (
+    user_id VARCHAR(20),
+    role_id VARCHAR(30),
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_role_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_user_role_role FOREIGN KEY (role_id) REFERENCES roles(id)
+);
+
+INSERT INTO roles (id, description) VALUES
+    ('admin', 'manage all resources and assign roles'),
+    ('merchant', 'manage own shops and their prices'),
+    ('warehouse_operator', 'manage warehouses, products and stocks'),
+    ('customer', 'order and pay products, default role of registered user');
+
+INSERT INTO permissions (id, description) VALUES
+    ('warehouse:read', 'read warehouses'),
+    ('warehouse:write', 'create and enable or disable warehouses'),
+    ('shop:read', 'read shops'),
+    ('shop:write', 'create shops and manage own shops'),
+    ('shop:manage_any', 'manage shops owned by other users'),
+    ('product:read', 'read products'),
+    ('product:write', 'create products'),
+    ('stock:write', 'add and transfer stocks'),
+    ('price:write', 'manage prices and price lists'),
+    ('catalog:import', 'import catalog'),
+    ('catalog:export', 'export catalog'),
+    ('order:create', 'order products'),
+    ('order:pay', 'pay own orders'),
+    ('role:assign', 'assign roles of users');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 'admin', id FROM permissions;
+
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+    ('merchant', 'warehouse:read'),
+    ('merchant', 'shop:read'),
+    ('merchant', 'shop:write'),
+    ('merchant', 'product:read'),
+    ('merchant', 'price:write'),
+    ('merchant', 'order:create'),
+    ('merchant', 'order:pay'),
+    ('warehouse_operator', 'warehouse:read'),
+    ('warehouse_operator', 'warehouse:write'),
+    ('warehouse_operator', 'shop:read'),
+    ('warehouse_operator', 'product:read'),
+    ('warehouse_operator', 'product:write'),
+    ('warehouse_operator', 'stock:write'),
+    ('warehouse_operator', 'catalog:import'),
+    ('warehouse_operator', 'catalog:export'),
+    ('customer', 'shop:read'),
+    ('customer', 'product:read'),
+    ('customer', 'order:create'),
+    ('customer', 'order:pay');
+
+-- the existing users get the default role, like a registered user
+INSERT INTO user_roles (user_id, role_id)
+SELECT id, 'customer' FROM users;
+
+-- user that created the shop, the existing shops have no owner so only admin can manage them
+ALTER TABLE shops ADD COLUMN owner_id VARCHAR(20);
+ALTER TABLE shops ADD CONSTRAINT fk_shop_owner FOREIGN KEY (owner_id) REFERENCES users(id);
@@ -0,0 +1,26 @@
+-- staff of a shop, the owner is inserted when the shop is created
+CREATE TABLE shop_members (
+    shop_id VARCHAR(20),
+    user_id VARCHAR(20),
+    role VARCHAR(20) NOT NULL, -- owner, manager, staff
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shop_id, user_id),
+    CONSTRAINT fk_shop_member_shop FOREIGN KEY (shop_id) REFERENCES shops(id),
+    CONSTRAINT fk_shop_member_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX idx_shop_members_user_id ON shop_members(user_id); -- there is need to get shops of a user
+
+-- invitation to be a member of a shop, it is accepted by the user that has the invited email or phone number
+CREATE TABLE shop_invitations (
+    id VARCHAR(20) PRIMARY KEY,
+    shop_id VARCHAR(20) NOT NULL,
+    identifier_type VARCHAR(20) NOT NULL, -- email, phoneNumber
+    identifier VARCHAR(50) NOT NULL,
+    role VARCHAR(20) NOT NULL, -- manager, staff
+    invited_by VARCHAR(20) NOT NULL,
+    expired_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_shop_invitation_shop FOREIGN KEY (shop_id) REFERENCES shops(id),
+    CONSTRAINT fk_shop_invitation_user FOREIGN KEY (invited_by) REFERENCES users(id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS api_keys;
+DELETE FROM role_permissions WHERE permission_id = 'api_key:manage';
+DELETE FROM permissions WHERE id = 'api_key:manage';
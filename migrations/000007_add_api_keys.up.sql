@@ -0,0 +1,21 @@
+INSERT INTO permissions (id, description) VALUES
+    ('api_key:manage', 'create and revoke api keys of services');
+
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+    ('admin', 'api_key:manage');
+
+-- api keys of services (e.g. erp, fulfillment partner), the scopes are permissions, only the hash of the key is stored
+CREATE TABLE api_keys (
+    id VARCHAR(20) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    scopes VARCHAR(30)[] NOT NULL,
+    created_by VARCHAR(20) NOT NULL,
+    expired_at TIMESTAMP, -- never expires if it is null
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_api_key_hash UNIQUE (key_hash),
+    CONSTRAINT fk_api_key_user FOREIGN KEY (created_by) REFERENCES users(id)
+);
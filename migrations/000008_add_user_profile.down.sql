@@ -0,0 +1,11 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS fk_order_shipping_address;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_address_id;
+DROP TABLE IF EXISTS user_addresses;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS unique_phone_number;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS unique_email;
+ALTER TABLE users ADD CONSTRAINT unique_email_phone UNIQUE (email, phone_number);
+ALTER TABLE users DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_number_verified;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
+ALTER TABLE users DROP COLUMN IF EXISTS name;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS privacy_requests;
+-- the deleted users have no contact, so the old check can not be restored while they exist
+ALTER TABLE users DROP CONSTRAINT IF EXISTS at_least_one_contact;
+ALTER TABLE users ADD CONSTRAINT at_least_one_contact CHECK (email IS NOT NULL OR phone_number IS NOT NULL);
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,16 @@
+-- personal fields of deleted user are anonymized, the row is kept for the orders
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE users DROP CONSTRAINT at_least_one_contact;
+ALTER TABLE users ADD CONSTRAINT at_least_one_contact CHECK (email IS NOT NULL OR phone_number IS NOT NULL OR deleted_at IS NOT NULL);
+
+-- audit of data subject requests (export or deletion), the user id is kept after the user is anonymized
+CREATE TABLE privacy_requests (
+    id VARCHAR(20) PRIMARY KEY,
+    user_id VARCHAR(20) NOT NULL,
+    type VARCHAR(20) NOT NULL, -- export, deletion
+    channel VARCHAR(20) NOT NULL, -- api, cli
+    requested_by VARCHAR(20), -- null if an operator runs the cli
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP, -- null if the request fails
+    CONSTRAINT fk_privacy_request_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
@@ -0,0 +1,2 @@
+-- there is need to count the pending orders of a user on every order
+CREATE INDEX IF NOT EXISTS idx_orders_user_id_status ON orders(user_id, status);
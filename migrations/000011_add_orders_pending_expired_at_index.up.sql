@@ -0,0 +1,2 @@
+-- the expired orders are counted on every metrics scrape
+CREATE INDEX IF NOT EXISTS idx_orders_pending_expired_at ON orders(expired_at) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+UPDATE order_items SET price_history_id = NULL WHERE price_history_id LIKE 'PRH-SEED-%';
+DELETE FROM price_history WHERE id LIKE 'PRH-SEED-%';
@@ -0,0 +1,12 @@
+-- prices that are set before price history exists have no history record, they are recorded from now
+-- so a time before the first record of a product has no price
+INSERT INTO price_history (id, product_id, shop_id, source, price, created_at)
+SELECT 'PRH-SEED-' || upper(substr(md5(random()::text || p.id), 1, 8)), p.id, NULL, 'base', p.price, CURRENT_TIMESTAMP
+FROM products p
+WHERE p.price IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM price_history ph WHERE ph.product_id = p.id AND ph.shop_id IS NULL);
+
+INSERT INTO price_history (id, product_id, shop_id, source, price, created_at)
+SELECT 'PRH-SEED-' || upper(substr(md5(random()::text || spp.shop_id || spp.product_id), 1, 8)), spp.product_id, spp.shop_id, 'shop', spp.price, CURRENT_TIMESTAMP
+FROM shop_product_prices spp
+WHERE NOT EXISTS (SELECT 1 FROM price_history ph WHERE ph.product_id = spp.product_id AND ph.shop_id = spp.shop_id);
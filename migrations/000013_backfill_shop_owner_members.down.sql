@@ -0,0 +1 @@
+-- the owner members are kept, they can not be told apart from the ones that are inserted when the shop is created
@@ -0,0 +1,6 @@
+-- shops that are created before shop members exist have no owner member, the owner is taken from shops.owner_id
+INSERT INTO shop_members (shop_id, user_id, role)
+SELECT s.id, s.owner_id, 'owner'
+FROM shops s
+WHERE s.owner_id IS NOT NULL
+ON CONFLICT (shop_id, user_id) DO UPDATE SET role = EXCLUDED.role;
@@ -7,13 +7,21 @@ import (
 	"fmt"
 	"mfawzanid/warehouse-commerce/core/entity"
 	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
 const (
-	email = "email_test@mail.com"
+	email    = "email_test@mail.com"
+	password = "password_test"
+
+	// otp codes are written to this file by the app in docker compose
+	otpNotifierFile = "../tmp/otp.log"
 
 	firstTotalStock      = 100
 	updatedTotalStock    = 40
@@ -86,7 +94,7 @@ const apiURL = "http://localhost:3000"
 /*
 We test the API using success flow in CreateSuccesTestCaseSteps():
 1. Register new user
-2. Login using email that registered before
+2. Promote the user to admin, then login using email that registered before
 3. Create warehouse, using token in step 2 (Login)
 4. Update status warehouse (that created in step 3) become enable
 5. Get warehouses, expect only return one warehosue that created in step 3
@@ -127,16 +135,20 @@ func getTestCases() []TestCase {
 
 /*
 1. Register new user
-2. Login using email that registered before
+2. Promote the user to admin, then login using email that registered before
 */
 func RegisterLoginTestCaseStep() []TestCaseStep {
 	return []TestCaseStep{
-		// 1. Register new user
+		// 1. Register new user, the email is verified by otp
 		{
 			Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+				otpCode := requestOtpCode(t, entity.OtpPurposeRegister)
+
 				payload := entity.RegisterUserRequest{
 					IdentifierType: "email",
 					Identifier:     email,
+					Password:       password,
+					OtpCode:        otpCode,
 				}
 
 				jsonBody, err := json.Marshal(payload)
@@ -153,12 +165,15 @@ func RegisterLoginTestCaseStep() []TestCaseStep {
 				require.NotEmpty(t, tokenStr)
 			},
 		},
-		// 2. Login using email that registered before
+		// 2. Promote the user to admin, then login using email that registered before
 		{
 			Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+				promoteToAdmin(t)
+
 				payload := entity.LoginRequest{
 					IdentifierType: "email",
 					Identifier:     email,
+					Password:       password,
 				}
 
 				jsonBody, err := json.Marshal(payload)
@@ -178,6 +193,42 @@ func RegisterLoginTestCaseStep() []TestCaseStep {
 	}
 }
 
+// requestOtpCode requests otp of the email then reads the code from the otp notifier file
+func requestOtpCode(t *testing.T, purpose string) string {
+	jsonBody, err := json.Marshal(entity.RequestOtpRequest{
+		IdentifierType: "email",
+		Identifier:     email,
+		Purpose:        purpose,
+	})
+	require.NoError(t, err)
+
+	resp, err := http.Post(apiURL+"/user/otp", "application/json", bytes.NewReader(jsonBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	content, err := os.ReadFile(otpNotifierFile)
+	require.NoError(t, err)
+
+	var otpCode string
+	for _, line := range strings.Split(string(content), "\n") {
+		if match := otpCodePattern.FindStringSubmatch(line); match != nil && strings.Contains(line, "'"+email+"'") && strings.Contains(line, "your "+purpose) {
+			otpCode = match[1]
+		}
+	}
+	require.NotEmpty(t, otpCode)
+
+	return otpCode
+}
+
+var otpCodePattern = regexp.MustCompile(`code is (\d+)`)
+
+// promoteToAdmin runs the create-admin command in the app container, registered user only has customer role
+func promoteToAdmin(t *testing.T) {
+	output, err := exec.Command("docker", "compose", "exec", "-T", "app", "./main", "create-admin", "-email", email, "-password", password).CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
 /*
 3. Create warehouse, using token in step 2 (Login)
 4. Update status warehouse (that created in step 3) become enable
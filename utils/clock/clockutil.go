@@ -0,0 +1,46 @@
+package clockutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock returns the current time, it is injected where the time decides the result (e.g. an expiration),
+// so the tests can move the time instead of sleeping
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+// Real returns the clock of the system
+func Real() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is the clock that only moves when it is told to, it is safe for concurrent use
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the time forward by d
+func (c *Fake) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
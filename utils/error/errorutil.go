@@ -3,17 +3,23 @@ package errorutil
 import (
 	"errors"
 	"fmt"
-	"net/http"
 )
 
 const (
-	Error   = "error"
 	Message = "message"
 )
 
+// CustomError is an error of a type (e.g. ErrNotFound), errors.Is matches both the type and the original error
 type CustomError struct {
 	ErrorType     error
 	OriginalError error
+	Fields        []*FieldError // invalid fields of a validation error
+}
+
+// FieldError is the invalid field of a request, the field is the json name of it
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 func NewErrorCode(errorType, originalError error) *CustomError {
@@ -23,31 +29,75 @@ func NewErrorCode(errorType, originalError error) *CustomError {
 	}
 }
 
+// NewValidationError returns bad request error with the invalid fields, so the client can show them next to the fields
+func NewValidationError(originalError error, fields ...*FieldError) *CustomError {
+	return &CustomError{
+		ErrorType:     ErrBadRequest,
+		OriginalError: originalError,
+		Fields:        fields,
+	}
+}
+
+// NewFieldValidationError returns the validation error of a field, the prefix is the context of the message
+func NewFieldValidationError(prefix string, field *FieldError) *CustomError {
+	return NewValidationError(fmt.Errorf("%s: %s", prefix, field.Message), field)
+}
+
 func GetErrorType(err error) error {
-	if e, ok := err.(*CustomError); ok {
+	var e *CustomError
+	if errors.As(err, &e) {
 		return e.ErrorType
 	}
 	return err
 }
 
 func GetOriginalError(err error) error {
-	if e, ok := err.(*CustomError); ok {
+	var e *CustomError
+	if errors.As(err, &e) {
 		return e.OriginalError
 	}
 	return err
 }
 
+// GetFields returns the invalid fields if err is a validation error
+func GetFields(err error) []*FieldError {
+	var e *CustomError
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
+
 func (e *CustomError) Error() string {
 	return fmt.Sprintf("%s: %s", e.ErrorType.Error(), e.OriginalError.Error())
 }
 
+func (e *CustomError) Unwrap() []error {
+	return []error{e.ErrorType, e.OriginalError}
+}
+
+// kindError is an error that belongs to a more general error type, e.g. unique violation is a conflict
+type kindError struct {
+	message string
+	kind    error
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
 var (
-	ErrBadRequest      = errors.New("bad request")
+	ErrBadRequest      = errors.New("bad request") // validation error, it may have the invalid fields
 	ErrNotFound        = errors.New("not found")
 	ErrUnauthorized    = errors.New("unauthorized")
-	ErrUniqueViolation = errors.New("unique violation")
+	ErrForbidden       = errors.New("forbidden")
+	ErrConflict        = errors.New("conflict")
+	ErrUniqueViolation = &kindError{message: "unique violation", kind: ErrConflict}
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrUnavailable     = errors.New("unavailable") // a dependency (e.g. database) can not serve now, the client can retry later
+	ErrInternal        = errors.New("internal")
 )
-
-func CombineHTTPErrorMessage(httpStatusCode int, err error) string {
-	return fmt.Sprintf("%s: %s", http.StatusText(httpStatusCode), err.Error())
-}
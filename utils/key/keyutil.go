@@ -0,0 +1,217 @@
+package keyutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey is a key to sign and verify tokens, a key without private key (e.g. rotated out key)
+// can only verify the tokens that are signed before
+type SigningKey struct {
+	Id         string
+	Algorithm  string
+	PrivateKey interface{} // []byte secret for HS256
+	PublicKey  interface{} // []byte secret for HS256
+}
+
+func (k *SigningKey) CanSign() bool {
+	return k.PrivateKey != nil
+}
+
+func NewHMACKey(id string, secret []byte) *SigningKey {
+	return &SigningKey{
+		Id:         id,
+		Algorithm:  AlgorithmHS256,
+		PrivateKey: secret,
+		PublicKey:  secret,
+	}
+}
+
+// ParsePEMKey parses RSA or Ed25519 key, the algorithm is taken from the key type.
+// A private key can sign and verify, a public key can only verify.
+func ParsePEMKey(id string, content []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("error parse key '%s': content is not pem", id)
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("error parse key '%s': pem type '%s' is not supported", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parse key '%s': %v", id, err.Error())
+	}
+
+	signingKey := &SigningKey{Id: id}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signingKey.Algorithm = AlgorithmRS256
+		signingKey.PrivateKey = k
+		signingKey.PublicKey = &k.PublicKey
+	case *rsa.PublicKey:
+		signingKey.Algorithm = AlgorithmRS256
+		signingKey.PublicKey = k
+	case ed25519.PrivateKey:
+		signingKey.Algorithm = AlgorithmEdDSA
+		signingKey.PrivateKey = k
+		signingKey.PublicKey = k.Public()
+	case ed25519.PublicKey:
+		signingKey.Algorithm = AlgorithmEdDSA
+		signingKey.PublicKey = k
+	default:
+		return nil, fmt.Errorf("error parse key '%s': key type %T is not supported, use rsa or ed25519", id, key)
+	}
+
+	return signingKey, nil
+}
+
+// LoadPEMKeys loads keys from comma separated `keyId=path` of pem files, e.g. `2025-01=/keys/2025-01.pem,2024-07=/keys/2024-07.pub.pem`
+func LoadPEMKeys(specs string) ([]*SigningKey, error) {
+	var keys []*SigningKey
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		id, path, ok := strings.Cut(spec, "=")
+		if !ok || id == "" || path == "" {
+			return nil, fmt.Errorf("error load key: '%s' should be keyId=path", spec)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error load key '%s': %v", id, err.Error())
+		}
+
+		key, err := ParsePEMKey(id, content)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// KeySet is the keys that are trusted to verify tokens, only the active key signs new tokens.
+// To rotate, add a new key as active and keep the old one until the tokens it signed are expired.
+type KeySet struct {
+	activeKey *SigningKey
+	keys      map[string]*SigningKey
+	ids       []string
+}
+
+func NewKeySet(activeKeyId string, keys []*SigningKey) (*KeySet, error) {
+	keySet := &KeySet{
+		keys: make(map[string]*SigningKey),
+	}
+	for _, key := range keys {
+		if key.Id == "" {
+			return nil, errors.New("error key set: key id is mandatory")
+		}
+		if _, ok := keySet.keys[key.Id]; ok {
+			return nil, fmt.Errorf("error key set: key id '%s' is duplicated", key.Id)
+		}
+		keySet.keys[key.Id] = key
+		keySet.ids = append(keySet.ids, key.Id)
+	}
+
+	activeKey, ok := keySet.keys[activeKeyId]
+	if !ok {
+		return nil, fmt.Errorf("error key set: active key '%s' is not found", activeKeyId)
+	}
+	if !activeKey.CanSign() {
+		return nil, fmt.Errorf("error key set: active key '%s' has no private key", activeKeyId)
+	}
+	keySet.activeKey = activeKey
+
+	return keySet, nil
+}
+
+func (s *KeySet) ActiveKey() *SigningKey {
+	return s.activeKey
+}
+
+func (s *KeySet) GetKey(id string) (*SigningKey, bool) {
+	key, ok := s.keys[id]
+	return key, ok
+}
+
+// Algorithms returns the algorithms of the keys, tokens with other algorithms are rejected
+func (s *KeySet) Algorithms() []string {
+	var algorithms []string
+	seen := make(map[string]bool)
+	for _, id := range s.ids {
+		if algorithm := s.keys[id].Algorithm; !seen[algorithm] {
+			seen[algorithm] = true
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+	return algorithms
+}
+
+// JSONWebKey is public key in JWK format (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeys returns the public keys, symmetric keys are secret so they are never published
+func (s *KeySet) JSONWebKeys() []JSONWebKey {
+	jwks := []JSONWebKey{}
+	for _, id := range s.ids {
+		key := s.keys[id]
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwks = append(jwks, JSONWebKey{
+				Kty: "RSA",
+				Kid: key.Id,
+				Alg: key.Algorithm,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks = append(jwks, JSONWebKey{
+				Kty: "OKP",
+				Kid: key.Id,
+				Alg: key.Algorithm,
+				Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+	return jwks
+}
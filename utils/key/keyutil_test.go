@@ -0,0 +1,99 @@
+package keyutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePEMKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	_, edKey, err := ed25519.GenerateKey(nil)
+	assert.Nil(t, err)
+	edPrivateBytes, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.Nil(t, err)
+	edPublicBytes, err := x509.MarshalPKIXPublicKey(edKey.Public())
+	assert.Nil(t, err)
+
+	t.Run("ParsePEMKey_rsa private key_then return RS256 key that can sign", func(t *testing.T) {
+		content := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+		key, err := ParsePEMKey("rsa", content)
+
+		assert.Nil(t, err)
+		assert.Equal(t, AlgorithmRS256, key.Algorithm)
+		assert.True(t, key.CanSign())
+		assert.Equal(t, &rsaKey.PublicKey, key.PublicKey)
+	})
+	t.Run("ParsePEMKey_ed25519 private key_then return EdDSA key that can sign", func(t *testing.T) {
+		content := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edPrivateBytes})
+
+		key, err := ParsePEMKey("ed", content)
+
+		assert.Nil(t, err)
+		assert.Equal(t, AlgorithmEdDSA, key.Algorithm)
+		assert.True(t, key.CanSign())
+	})
+	t.Run("ParsePEMKey_ed25519 public key_then return EdDSA key that can only verify", func(t *testing.T) {
+		content := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: edPublicBytes})
+
+		key, err := ParsePEMKey("ed", content)
+
+		assert.Nil(t, err)
+		assert.Equal(t, AlgorithmEdDSA, key.Algorithm)
+		assert.False(t, key.CanSign())
+	})
+	t.Run("ParsePEMKey_content is not pem_then return error", func(t *testing.T) {
+		_, err := ParsePEMKey("xxx", []byte("xxx"))
+
+		assert.NotNil(t, err)
+	})
+	t.Run("LoadPEMKeys_key id and path_then load the keys in order", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "new.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edPrivateBytes}), 0600))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "old.pub.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: edPublicBytes}), 0600))
+
+		keys, err := LoadPEMKeys("new=" + filepath.Join(dir, "new.pem") + ", old=" + filepath.Join(dir, "old.pub.pem"))
+
+		assert.Nil(t, err)
+		assert.Len(t, keys, 2)
+		assert.Equal(t, "new", keys[0].Id)
+		assert.Equal(t, "old", keys[1].Id)
+	})
+}
+
+func TestNewKeySet(t *testing.T) {
+	signKey := NewHMACKey("secret", []byte("secret"))
+	verifyOnlyKey := &SigningKey{Id: "old", Algorithm: AlgorithmEdDSA, PublicKey: ed25519.PublicKey{}}
+
+	t.Run("NewKeySet_active key is not found_then return error", func(t *testing.T) {
+		_, err := NewKeySet("xxx", []*SigningKey{signKey})
+
+		assert.NotNil(t, err)
+	})
+	t.Run("NewKeySet_active key can not sign_then return error", func(t *testing.T) {
+		_, err := NewKeySet("old", []*SigningKey{signKey, verifyOnlyKey})
+
+		assert.NotNil(t, err)
+	})
+	t.Run("NewKeySet_key id is duplicated_then return error", func(t *testing.T) {
+		_, err := NewKeySet("secret", []*SigningKey{signKey, signKey})
+
+		assert.NotNil(t, err)
+	})
+	t.Run("NewKeySet_valid keys_then return key set with the algorithms", func(t *testing.T) {
+		keySet, err := NewKeySet("secret", []*SigningKey{signKey, verifyOnlyKey})
+
+		assert.Nil(t, err)
+		assert.Equal(t, signKey, keySet.ActiveKey())
+		assert.Equal(t, []string{AlgorithmHS256, AlgorithmEdDSA}, keySet.Algorithms())
+	})
+}
@@ -0,0 +1,184 @@
+package lifecycleutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	traceutil "mfawzanid/warehouse-commerce/utils/trace"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultCancelGracePeriod = 5 * time.Second
+)
+
+// ErrStopped is returned by Go when the resources are closed, so the task can not be run anymore
+var ErrStopped = errors.New("lifecycle is stopped")
+
+// Manager tracks the background tasks of the server (e.g. post payment actions, catalog import jobs),
+// so a shutdown waits for them before the resources they use (redis, database) are closed
+type Manager struct {
+	mu       sync.Mutex
+	stopping bool          // the shutdown is waiting for the tasks, a new task is still run and waited
+	stopped  bool          // the resources are closed or being closed, a new task is rejected
+	idle     chan struct{} // closed when no task is running after the shutdown starts
+	nextId   int
+	running  map[int]string // name of the running tasks, to log the unfinished ones
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	closers []*closer
+
+	cancelGracePeriod time.Duration // time for the tasks to return after their context is cancelled
+
+	logger *slog.Logger
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+func NewManager(logger *slog.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		running:           map[int]string{},
+		ctx:               ctx,
+		cancel:            cancel,
+		cancelGracePeriod: defaultCancelGracePeriod,
+		logger:            logger,
+	}
+}
+
+// Go runs the task in background. The context of the task is only cancelled if the shutdown times out,
+// so a long task (e.g. import job) must check it at a safe point and stop there. It keeps the values of ctx
+// (e.g. the request id), but not its deadline since the request is done before the task.
+// The task has its own trace that is linked to the span of ctx, so it does not stretch the trace of the request.
+// A task that is started while the shutdown waits (e.g. by a request that is still drained) is waited as well.
+// Once the waiting is over the resources are closed, so the task is not run and ErrStopped is returned.
+func (m *Manager) Go(ctx context.Context, name string, task func(ctx context.Context)) error {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return fmt.Errorf("error run task '%s': %w", name, ErrStopped)
+	}
+	id := m.nextId
+	m.nextId++
+	m.running[id] = name
+	m.mu.Unlock()
+
+	taskCtx := &taskContext{Context: m.ctx, values: ctx, name: name}
+	go func() {
+		defer m.finish(id)
+
+		spanCtx, span := traceutil.Tracer().Start(taskCtx, name, trace.WithNewRoot(), trace.WithLinks(trace.LinkFromContext(ctx)))
+		defer span.End()
+		task(spanCtx)
+	}()
+
+	return nil
+}
+
+// finish removes the task from the running ones, the last one of a shutdown stops the manager
+func (m *Manager) finish(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.running, id)
+	if m.stopping && !m.stopped && len(m.running) == 0 {
+		m.stopped = true
+		close(m.idle)
+	}
+}
+
+type taskNameKey struct{}
+
+// taskContext is cancelled by the manager, its values are the ones of the caller and the name of the task
+type taskContext struct {
+	context.Context
+	values context.Context
+	name   string
+}
+
+func (c *taskContext) Value(key any) any {
+	if _, ok := key.(taskNameKey); ok {
+		return c.name
+	}
+	return c.values.Value(key)
+}
+
+// TaskName returns the name of the background task that runs with ctx, it is empty in a request
+func TaskName(ctx context.Context) string {
+	name, _ := ctx.Value(taskNameKey{}).(string)
+	return name
+}
+
+// OnStop registers a resource to close on shutdown, resources are closed in the reverse order after the tasks are done
+func (m *Manager) OnStop(name string, close func() error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closers = append(m.closers, &closer{name: name, close: close})
+}
+
+// Shutdown waits for the running tasks until ctx is done, then cancels them and closes the resources.
+// No task is started after it stops waiting, so a task does not run with the closed resources.
+// It returns error if some tasks are not finished or some resources can not be closed.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.stopping = true
+	m.idle = make(chan struct{})
+	if len(m.running) == 0 {
+		m.stopped = true
+		close(m.idle)
+	}
+	idle := m.idle
+	m.mu.Unlock()
+
+	errs := []error{}
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		m.logger.Warn("shutdown timeout, cancelling background tasks", "tasks", m.runningTasks())
+		m.cancel()
+		select {
+		case <-idle:
+		case <-time.After(m.cancelGracePeriod):
+			errs = append(errs, fmt.Errorf("error shutdown: background tasks are not finished: %v", m.runningTasks()))
+		}
+	}
+	m.cancel()
+
+	// the tasks that are not finished after the grace period are left, a new one is rejected
+	m.mu.Lock()
+	m.stopped = true
+	m.mu.Unlock()
+
+	m.mu.Lock()
+	closers := m.closers
+	m.mu.Unlock()
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].close(); err != nil {
+			errs = append(errs, fmt.Errorf("error shutdown in closing %s: %v", closers[i].name, err.Error()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Manager) runningTasks() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.running))
+	for _, name := range m.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
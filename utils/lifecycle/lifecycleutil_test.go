@@ -0,0 +1,185 @@
+package lifecycleutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	logutil "mfawzanid/warehouse-commerce/utils/log"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("Shutdown_running tasks_then wait for them before closing the resources", func(t *testing.T) {
+		manager := NewManager(logutil.Discard())
+
+		var finished atomic.Bool
+		closed := []string{}
+		manager.OnStop("database", func() error {
+			assert.True(t, finished.Load())
+			closed = append(closed, "database")
+			return nil
+		})
+		manager.OnStop("redis", func() error {
+			closed = append(closed, "redis")
+			return nil
+		})
+
+		manager.Go(context.Background(), "task", func(ctx context.Context) {
+			time.Sleep(50 * time.Millisecond)
+			finished.Store(true)
+		})
+
+		err := manager.Shutdown(context.Background())
+
+		assert.Nil(t, err)
+		assert.True(t, finished.Load())
+		assert.Equal(t, []string{"redis", "database"}, closed)
+	})
+	t.Run("Shutdown_timeout_then cancel the tasks", func(t *testing.T) {
+		manager := NewManager(logutil.Discard())
+
+		cancelled := make(chan struct{})
+		manager.Go(context.Background(), "import job", func(ctx context.Context) {
+			<-ctx.Done()
+			close(cancelled)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := manager.Shutdown(ctx)
+
+		assert.Nil(t, err)
+		select {
+		case <-cancelled:
+		default:
+			t.Fatal("task is not cancelled")
+		}
+	})
+	t.Run("Shutdown_task ignores the cancellation_then return error", func(t *testing.T) {
+		manager := NewManager(logutil.Discard())
+		manager.cancelGracePeriod = 10 * time.Millisecond
+
+		release := make(chan struct{})
+		defer close(release)
+		manager.Go(context.Background(), "stuck task", func(ctx context.Context) {
+			<-release
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := manager.Shutdown(ctx)
+
+		assert.ErrorContains(t, err, "stuck task")
+	})
+	t.Run("Shutdown_close error_then return error and close the others", func(t *testing.T) {
+		manager := NewManager(logutil.Discard())
+
+		var redisClosed bool
+		manager.OnStop("database", func() error {
+			return errors.New("connection busy")
+		})
+		manager.OnStop("redis", func() error {
+			redisClosed = true
+			return nil
+		})
+
+		err := manager.Shutdown(context.Background())
+
+		assert.ErrorContains(t, err, "closing database")
+		assert.True(t, redisClosed)
+	})
+	t.Run("Go_caller context is done_then the task keeps its values but not its deadline", func(t *testing.T) {
+		manager := NewManager(logutil.Discard())
+
+		ctx, cancel := context.WithCancel(logutil.WithRequestId(context.Background(), "REQ-1"))
+		cancel()
+
+		var requestId, taskName string
+		var errTask error
+		manager.Go(ctx, "task", func(ctx context.Context) {
+			requestId = logutil.RequestId(ctx)
+			taskName = TaskName(ctx)
+			errTask = ctx.Err()
+		})
+		assert.Nil(t, manager.Shutdown(context.Background()))
+
+		assert.Equal(t, "REQ-1", requestId)
+		assert.Equal(t, "task", taskName)
+		assert.Nil(t, errTask)
+	})
+	t.Run("Go_traced caller_then the task has its own trace linked to the caller", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		otel.SetTracerProvider(provider)
+		manager := NewManager(logutil.Discard())
+
+		ctx, requestSpan := provider.Tracer("test").Start(context.Background(), "POST /api/v1/orders/:orderId/pay")
+		manager.Go(ctx, "task", func(ctx context.Context) {})
+		requestSpan.End()
+		assert.Nil(t, manager.Shutdown(context.Background()))
+
+		spans := recorder.Ended()
+		var taskSpan sdktrace.ReadOnlySpan
+		for _, span := range spans {
+			if span.Name() == "task" {
+				taskSpan = span
+			}
+		}
+		assert.NotNil(t, taskSpan)
+		assert.NotEqual(t, requestSpan.SpanContext().TraceID(), taskSpan.SpanContext().TraceID())
+		assert.Len(t, taskSpan.Links(), 1)
+		assert.Equal(t, requestSpan.SpanContext().SpanID(), taskSpan.Links()[0].SpanContext.SpanID())
+	})
+	t.Run("Go_while the shutdown waits_then the task is waited before closing the resources", func(t *testing.T) {
+		manager := NewManager(logutil.Discard())
+
+		var finished atomic.Bool
+		manager.OnStop("database", func() error {
+			assert.True(t, finished.Load())
+			return nil
+		})
+
+		// a drained request starts a task after the shutdown is started
+		started := make(chan struct{})
+		assert.Nil(t, manager.Go(context.Background(), "request", func(ctx context.Context) {
+			<-started
+			assert.Nil(t, manager.Go(ctx, "task", func(ctx context.Context) {
+				time.Sleep(20 * time.Millisecond)
+				finished.Store(true)
+			}))
+		}))
+
+		shutdownErr := make(chan error)
+		go func() {
+			shutdownErr <- manager.Shutdown(context.Background())
+		}()
+		assert.Eventually(t, func() bool {
+			manager.mu.Lock()
+			defer manager.mu.Unlock()
+			return manager.stopping
+		}, time.Second, time.Millisecond)
+		close(started)
+
+		assert.Nil(t, <-shutdownErr)
+		assert.True(t, finished.Load())
+	})
+	t.Run("Go_after shutdown_then return error without running the task", func(t *testing.T) {
+		manager := NewManager(logutil.Discard())
+		assert.Nil(t, manager.Shutdown(context.Background()))
+
+		var run bool
+		err := manager.Go(context.Background(), "task", func(ctx context.Context) {
+			run = true
+		})
+
+		assert.ErrorIs(t, err, ErrStopped)
+		assert.False(t, run)
+	})
+}
@@ -0,0 +1,88 @@
+package logutil
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	traceutil "mfawzanid/warehouse-commerce/utils/trace"
+	"os"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	KeyRequestId = "requestId"
+)
+
+type requestIdKey struct{}
+
+// New returns the logger of the server, a record logged with a context has the request id and the trace id of the context
+func New(format, level string) (*slog.Logger, error) {
+	var slogLevel slog.Level
+	if err := slogLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("error new logger: level '%s' should be 'debug', 'info', 'warn' or 'error'", level)
+	}
+	options := &slog.HandlerOptions{Level: slogLevel}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(os.Stdout, options)
+	case FormatText:
+		handler = slog.NewTextHandler(os.Stdout, options)
+	default:
+		return nil, fmt.Errorf("error new logger: format '%s' should be '%s' or '%s'", format, FormatJSON, FormatText)
+	}
+
+	return slog.New(&contextHandler{handler}), nil
+}
+
+// Discard returns the logger that writes nothing, e.g. for the tests
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// WithRequestId returns the context of a request, it is kept by the background work started by the request
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+func RequestId(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+// NewRequestId returns a random id of 32 hex characters
+func NewRequestId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// contextHandler adds the request id and the trace id of the context to the record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestId := RequestId(ctx); requestId != "" {
+		record.AddAttrs(slog.String(KeyRequestId, requestId))
+	}
+	if traceId := traceutil.TraceId(ctx); traceId != "" {
+		record.AddAttrs(slog.String(traceutil.KeyTraceId, traceId))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}